@@ -0,0 +1,73 @@
+package bootstrap
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	gossipAdapter "github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-network-go/synchronization"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+)
+
+type gossipPeersMetrics struct {
+	connected  *metric.Gauge
+	connecting *metric.Gauge
+}
+
+type gossipPeersUpdater struct {
+	transport    gossipAdapter.DynamicPeersTransport
+	blockStorage services.BlockStorage
+	config       config.GossipTransportConfig
+	logger       log.BasicLogger
+	metrics      *gossipPeersMetrics
+}
+
+// the gossip peers follow the federation as of the last committed block, the connection states of the peers are
+// reported as metrics on the same interval
+func startUpdatingGossipPeers(ctx context.Context, transport gossipAdapter.DynamicPeersTransport, blockStorage services.BlockStorage, config config.GossipTransportConfig, logger log.BasicLogger, metricFactory metric.Factory) interface{} {
+	u := newGossipPeersUpdater(transport, blockStorage, config, logger, metricFactory)
+
+	synchronization.NewPeriodicalTrigger(ctx, config.GossipPeersUpdateInterval(), logger, func() {
+		u.updatePeers(ctx)
+		u.reportPeerConnectionStates()
+	}, nil)
+
+	return u
+}
+
+func newGossipPeersUpdater(transport gossipAdapter.DynamicPeersTransport, blockStorage services.BlockStorage, config config.GossipTransportConfig, logger log.BasicLogger, metricFactory metric.Factory) *gossipPeersUpdater {
+	return &gossipPeersUpdater{
+		transport:    transport,
+		blockStorage: blockStorage,
+		config:       config,
+		logger:       logger.WithTags(gossipAdapter.LogTag),
+		metrics: &gossipPeersMetrics{
+			connected:  metricFactory.NewGauge("Gossip.Peers.Connected.Count"),
+			connecting: metricFactory.NewGauge("Gossip.Peers.Connecting.Count"),
+		},
+	}
+}
+
+func (u *gossipPeersUpdater) updatePeers(ctx context.Context) {
+	out, err := u.blockStorage.GetLastCommittedBlockHeight(ctx, &services.GetLastCommittedBlockHeightInput{})
+	if err != nil {
+		u.logger.Info("cannot update gossip peers without the last committed block height", log.Error(err))
+		return
+	}
+	u.transport.UpdatePeers(u.config.GossipPeers(uint64(out.LastCommittedBlockHeight)))
+}
+
+func (u *gossipPeersUpdater) reportPeerConnectionStates() {
+	var connected, connecting int64
+	for _, state := range u.transport.PeerConnectionStates() {
+		switch state {
+		case gossipAdapter.PEER_CONNECTION_STATE_CONNECTED:
+			connected++
+		case gossipAdapter.PEER_CONNECTION_STATE_CONNECTING:
+			connecting++
+		}
+	}
+	u.metrics.connected.Update(connected)
+	u.metrics.connecting.Update(connecting)
+}
@@ -0,0 +1,95 @@
+package bootstrap
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	gossipAdapter "github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type peersRecordingTransport struct {
+	gossipAdapter.Transport
+	updatedPeers map[string]config.GossipPeer
+	states       map[string]gossipAdapter.PeerConnectionState
+}
+
+func (t *peersRecordingTransport) UpdatePeers(peers map[string]config.GossipPeer) {
+	t.updatedPeers = peers
+}
+
+func (t *peersRecordingTransport) PeerConnectionStates() map[string]gossipAdapter.PeerConnectionState {
+	return t.states
+}
+
+func newGossipPeersUpdaterForTests(transport gossipAdapter.DynamicPeersTransport, gossipPeers map[string]config.GossipPeer) *gossipPeersUpdater {
+	blockStorage := &services.MockBlockStorage{}
+	blockStorage.When("GetLastCommittedBlockHeight", mock.Any, mock.Any).Return(&services.GetLastCommittedBlockHeightOutput{LastCommittedBlockHeight: 3}, nil)
+
+	return newGossipPeersUpdater(transport, blockStorage, config.ForDirectTransportTests(gossipPeers, "none"), log.GetLogger(), metric.NewRegistry())
+}
+
+func TestGossipPeersUpdater_UpdatesTransportWithFederationPeers(t *testing.T) {
+	gossipPeers := map[string]config.GossipPeer{
+		keys.Ed25519KeyPairForTests(1).PublicKey().KeyForMap(): config.NewHardCodedGossipPeer(4401, "127.0.0.1"),
+		keys.Ed25519KeyPairForTests(2).PublicKey().KeyForMap(): config.NewHardCodedGossipPeer(4402, "127.0.0.1"),
+	}
+	transport := &peersRecordingTransport{}
+	u := newGossipPeersUpdaterForTests(transport, gossipPeers)
+
+	u.updatePeers(context.Background())
+
+	require.Equal(t, gossipPeers, transport.updatedPeers, "transport should be updated with the peers as of the last committed block")
+}
+
+type blockStorageAtHeight struct {
+	services.BlockStorage
+	height primitives.BlockHeight
+}
+
+func (s *blockStorageAtHeight) GetLastCommittedBlockHeight(ctx context.Context, input *services.GetLastCommittedBlockHeightInput) (*services.GetLastCommittedBlockHeightOutput, error) {
+	return &services.GetLastCommittedBlockHeightOutput{LastCommittedBlockHeight: s.height}, nil
+}
+
+func TestGossipPeersUpdater_FollowsFederationChangesByBlockHeight(t *testing.T) {
+	gossipPeers := map[string]config.GossipPeer{
+		keys.Ed25519KeyPairForTests(1).PublicKey().KeyForMap(): config.NewHardCodedGossipPeer(4401, "127.0.0.1"),
+		keys.Ed25519KeyPairForTests(2).PublicKey().KeyForMap(): config.NewHardCodedGossipPeer(4402, "127.0.0.1"),
+	}
+	gossipPeersAsOfBlock10 := map[string]config.GossipPeer{
+		keys.Ed25519KeyPairForTests(2).PublicKey().KeyForMap(): config.NewHardCodedGossipPeer(4402, "127.0.0.1"),
+		keys.Ed25519KeyPairForTests(3).PublicKey().KeyForMap(): config.NewHardCodedGossipPeer(4403, "127.0.0.1"),
+	}
+	transport := &peersRecordingTransport{}
+	blockStorage := &blockStorageAtHeight{height: 9}
+	u := newGossipPeersUpdater(transport, blockStorage, config.ForGossipPeersUpdaterTests(gossipPeers, 10, gossipPeersAsOfBlock10), log.GetLogger(), metric.NewRegistry())
+
+	u.updatePeers(context.Background())
+	require.Equal(t, gossipPeers, transport.updatedPeers, "transport should keep the initial peers before the change")
+
+	blockStorage.height = 10
+	u.updatePeers(context.Background())
+	require.Equal(t, gossipPeersAsOfBlock10, transport.updatedPeers, "transport should add and remove peers once the change is committed")
+}
+
+func TestGossipPeersUpdater_ReportsPeerConnectionStates(t *testing.T) {
+	transport := &peersRecordingTransport{
+		states: map[string]gossipAdapter.PeerConnectionState{
+			"a": gossipAdapter.PEER_CONNECTION_STATE_CONNECTED,
+			"b": gossipAdapter.PEER_CONNECTION_STATE_CONNECTED,
+			"c": gossipAdapter.PEER_CONNECTION_STATE_CONNECTING,
+		},
+	}
+	u := newGossipPeersUpdaterForTests(transport, nil)
+
+	u.reportPeerConnectionStates()
+
+	require.EqualValues(t, 2, u.metrics.connected.Value())
+	require.EqualValues(t, 1, u.metrics.connecting.Value())
+}
@@ -0,0 +1,387 @@
+package httpserver
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// block queries respond with a list of membuffers framed as [num chunks][chunk size][chunk]... (uint32 little endian),
+// a block pair is laid out txHeader, txMetadata, txProof, rxHeader, rxProof followed by the transactions, receipts and
+// state diffs counted in its headers; clients sending "Accept: application/json" get the same membuffers as hex in json
+const (
+	MAX_BLOCKS_IN_RANGE_QUERY   = 100
+	membuffersChunksContentType = "application/vnd.membuffers.chunks"
+)
+
+type transactionsBlockJson struct {
+	Header             string
+	Metadata           string
+	BlockProof         string
+	SignedTransactions []string `json:",omitempty"`
+}
+
+type resultsBlockJson struct {
+	Header              string
+	BlockProof          string
+	TransactionReceipts []string `json:",omitempty"`
+	ContractStateDiffs  []string `json:",omitempty"`
+}
+
+type blockPairJson struct {
+	BlockHeight       uint64
+	BlockTimestamp    uint64
+	TransactionsBlock *transactionsBlockJson
+	ResultsBlock      *resultsBlockJson
+}
+
+// the receipt is proven by the sibling hashes from its leaf up to the receipts root hash in the signed results block header,
+// see digest.VerifyReceiptProof
+type receiptProofJson struct {
+	BlockHeight        uint64
+	BlockTimestamp     uint64
+	ResultsBlockHeader string
+	ResultsBlockProof  string
+	TransactionReceipt string
+	ReceiptIndex       uint32
+	ReceiptProof       []string
+}
+
+// GET /api/v1/get-block?height=<height>
+func (s *server) getBlockHandler(w http.ResponseWriter, r *http.Request) {
+	height, e := readBlockHeight(r, "height")
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	s.logger.Info("http server received get-block", log.BlockHeight(height))
+	blocks, _, _, err := s.blockPersistence.GetBlocks(height, height)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), "failed to read block"})
+		return
+	}
+	if len(blocks) == 0 {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotFound, nil, fmt.Sprintf("block %d was not committed yet", height)})
+		return
+	}
+
+	s.writeChunksResponse(w, r, blockPairChunks(blocks[0]), blockPairToJson(blocks[0]))
+}
+
+// GET /api/v1/get-block-range?first=<height>&last=<height>, at most MAX_BLOCKS_IN_RANGE_QUERY blocks are returned
+// and the returned range is reported in the X-ORBS-FIRST-BLOCK-HEIGHT and X-ORBS-LAST-BLOCK-HEIGHT headers
+func (s *server) getBlockRangeHandler(w http.ResponseWriter, r *http.Request) {
+	first, last, e := readBlockRange(r)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	s.logger.Info("http server received get-block-range", log.Uint64("first-block-height", uint64(first)), log.Uint64("last-block-height", uint64(last)))
+	blocks, firstReturned, lastReturned, err := s.blockPersistence.GetBlocks(first, last)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), "failed to read blocks"})
+		return
+	}
+	if len(blocks) == 0 {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotFound, nil, fmt.Sprintf("block %d was not committed yet", first)})
+		return
+	}
+
+	var chunks [][]byte
+	documents := make([]*blockPairJson, 0, len(blocks))
+	for _, blockPair := range blocks {
+		chunks = append(chunks, blockPairChunks(blockPair)...)
+		documents = append(documents, blockPairToJson(blockPair))
+	}
+
+	w.Header().Set("X-ORBS-FIRST-BLOCK-HEIGHT", strconv.FormatUint(uint64(firstReturned), 10))
+	w.Header().Set("X-ORBS-LAST-BLOCK-HEIGHT", strconv.FormatUint(uint64(lastReturned), 10))
+	s.writeChunksResponse(w, r, chunks, documents)
+}
+
+// GET /api/v1/get-block-header?height=<height>, the headers, metadata and proofs of a block pair without its body
+func (s *server) getBlockHeaderHandler(w http.ResponseWriter, r *http.Request) {
+	height, e := readBlockHeight(r, "height")
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	s.logger.Info("http server received get-block-header", log.BlockHeight(height))
+	if e := s.requireCommittedBlock(r, height); e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	txHeader, err := s.blockStorage.GetTransactionsBlockHeader(r.Context(), &services.GetTransactionsBlockHeaderInput{BlockHeight: height})
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), "failed to read transactions block header"})
+		return
+	}
+	rxHeader, err := s.blockStorage.GetResultsBlockHeader(r.Context(), &services.GetResultsBlockHeaderInput{BlockHeight: height})
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), "failed to read results block header"})
+		return
+	}
+
+	headersOnly := &protocol.BlockPairContainer{
+		TransactionsBlock: &protocol.TransactionsBlockContainer{
+			Header:     txHeader.TransactionsBlockHeader,
+			Metadata:   txHeader.TransactionsBlockMetadata,
+			BlockProof: txHeader.TransactionsBlockProof,
+		},
+		ResultsBlock: &protocol.ResultsBlockContainer{
+			Header:     rxHeader.ResultsBlockHeader,
+			BlockProof: rxHeader.ResultsBlockProof,
+		},
+	}
+	s.writeChunksResponse(w, r, blockPairChunks(headersOnly), blockPairToJson(headersOnly))
+}
+
+// GET /api/v1/get-transaction-receipt-proof?txhash=<hex>&timestamp=<transaction timestamp nano>, responds with the
+// results block header, its proof, the receipt, its index in the block (uint32 little endian) and its merkle proof hashes
+func (s *server) getTransactionReceiptProofHandler(w http.ResponseWriter, r *http.Request) {
+	txHash, err := hex.DecodeString(strings.TrimPrefix(r.URL.Query().Get("txhash"), "0x"))
+	if err != nil || len(txHash) == 0 {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, nil, "txhash must be given as hex"})
+		return
+	}
+	timestamp, err := strconv.ParseUint(r.URL.Query().Get("timestamp"), 10, 64)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), "timestamp must be given as the transaction timestamp in nanoseconds"})
+		return
+	}
+
+	s.logger.Info("http server received get-transaction-receipt-proof", log.Transaction(txHash))
+	receipt, err := s.blockStorage.GetTransactionReceipt(r.Context(), &services.GetTransactionReceiptInput{
+		Txhash:               txHash,
+		TransactionTimestamp: primitives.TimestampNano(timestamp),
+	})
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotFound, log.Error(err), fmt.Sprintf("receipt of transaction %x was not found", txHash)})
+		return
+	}
+	if receipt.TransactionReceipt == nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotFound, nil, fmt.Sprintf("receipt of transaction %x was not found", txHash)})
+		return
+	}
+
+	blocks, _, _, err := s.blockPersistence.GetBlocks(receipt.BlockHeight, receipt.BlockHeight)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), "failed to read block of the receipt"})
+		return
+	}
+	if len(blocks) == 0 {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotFound, nil, fmt.Sprintf("block %d of the receipt was not found", receipt.BlockHeight)})
+		return
+	}
+	resultsBlock := blocks[0].ResultsBlock
+
+	index, proof, e := receiptProof(resultsBlock, txHash)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	chunks := [][]byte{resultsBlock.Header.Raw(), resultsBlock.BlockProof.Raw(), resultsBlock.TransactionReceipts[index].Raw(), encodeUint32(index)}
+	document := &receiptProofJson{
+		BlockHeight:        uint64(resultsBlock.Header.BlockHeight()),
+		BlockTimestamp:     uint64(resultsBlock.Header.Timestamp()),
+		ResultsBlockHeader: hex.EncodeToString(resultsBlock.Header.Raw()),
+		ResultsBlockProof:  hex.EncodeToString(resultsBlock.BlockProof.Raw()),
+		TransactionReceipt: hex.EncodeToString(resultsBlock.TransactionReceipts[index].Raw()),
+		ReceiptIndex:       index,
+		ReceiptProof:       []string{},
+	}
+	for _, sibling := range proof {
+		chunks = append(chunks, sibling)
+		document.ReceiptProof = append(document.ReceiptProof, hex.EncodeToString(sibling))
+	}
+	s.writeChunksResponse(w, r, chunks, document)
+}
+
+// the proof is only served if it verifies against the committed header, a block whose header does not commit to its
+// receipts cannot prove any of them
+func receiptProof(resultsBlock *protocol.ResultsBlockContainer, txHash primitives.Sha256) (uint32, []primitives.Sha256, *httpErr) {
+	for i, receipt := range resultsBlock.TransactionReceipts {
+		if !receipt.Txhash().Equal(txHash) {
+			continue
+		}
+
+		proof, err := digest.CalcReceiptProof(resultsBlock.TransactionReceipts, i)
+		if err != nil {
+			return 0, nil, &httpErr{http.StatusInternalServerError, log.Error(err), "failed to calculate receipt proof"}
+		}
+		if !digest.VerifyReceiptProof(resultsBlock.Header.ReceiptsRootHash(), receipt, uint32(i), uint32(len(resultsBlock.TransactionReceipts)), proof) {
+			return 0, nil, &httpErr{http.StatusInternalServerError, nil, fmt.Sprintf("block %d does not commit to its receipts", resultsBlock.Header.BlockHeight())}
+		}
+		return uint32(i), proof, nil
+	}
+	return 0, nil, &httpErr{http.StatusNotFound, nil, fmt.Sprintf("receipt of transaction %x is missing from block %d", txHash, resultsBlock.Header.BlockHeight())}
+}
+
+// block storage waits for blocks that are about to be committed, a query should not
+func (s *server) requireCommittedBlock(r *http.Request, height primitives.BlockHeight) *httpErr {
+	out, err := s.blockStorage.GetLastCommittedBlockHeight(r.Context(), &services.GetLastCommittedBlockHeightInput{})
+	if err != nil {
+		return &httpErr{http.StatusInternalServerError, log.Error(err), "failed to read last committed block height"}
+	}
+	if height > out.LastCommittedBlockHeight {
+		return &httpErr{http.StatusNotFound, nil, fmt.Sprintf("block %d was not committed yet", height)}
+	}
+	return nil
+}
+
+func readBlockHeight(r *http.Request, param string) (primitives.BlockHeight, *httpErr) {
+	height, err := strconv.ParseUint(r.URL.Query().Get(param), 10, 64)
+	if err != nil || height == 0 {
+		return 0, &httpErr{http.StatusBadRequest, nil, fmt.Sprintf("%s must be a block height greater than zero", param)}
+	}
+	return primitives.BlockHeight(height), nil
+}
+
+// the range is cut down to MAX_BLOCKS_IN_RANGE_QUERY blocks starting from first
+func readBlockRange(r *http.Request) (primitives.BlockHeight, primitives.BlockHeight, *httpErr) {
+	first, e := readBlockHeight(r, "first")
+	if e != nil {
+		return 0, 0, e
+	}
+	last, e := readBlockHeight(r, "last")
+	if e != nil {
+		return 0, 0, e
+	}
+	if last < first {
+		return 0, 0, &httpErr{http.StatusBadRequest, nil, fmt.Sprintf("last block %d is before first block %d", last, first)}
+	}
+	if last-first >= MAX_BLOCKS_IN_RANGE_QUERY {
+		last = first + MAX_BLOCKS_IN_RANGE_QUERY - 1
+	}
+	return first, last, nil
+}
+
+func (s *server) writeChunksResponse(w http.ResponseWriter, r *http.Request, chunks [][]byte, document interface{}) {
+	var body []byte
+	if respondsWithJson(r) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		body, _ = json.Marshal(document)
+	} else {
+		w.Header().Set("Content-Type", membuffersChunksContentType)
+		body = encodeChunks(chunks)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
+func encodeChunks(chunks [][]byte) []byte {
+	size := 4
+	for _, chunk := range chunks {
+		size += 4 + len(chunk)
+	}
+
+	encoded := make([]byte, size)
+	binary.LittleEndian.PutUint32(encoded, uint32(len(chunks)))
+	offset := 4
+	for _, chunk := range chunks {
+		binary.LittleEndian.PutUint32(encoded[offset:], uint32(len(chunk)))
+		offset += 4
+		offset += copy(encoded[offset:], chunk)
+	}
+	return encoded
+}
+
+func encodeUint32(value uint32) []byte {
+	encoded := make([]byte, 4)
+	binary.LittleEndian.PutUint32(encoded, value)
+	return encoded
+}
+
+// decodes at most maxChunks chunks framed as by encodeChunks
+func decodeChunks(data []byte, maxChunks int) ([][]byte, error) {
+	if len(data) < 4 {
+		return nil, errors.New("number of chunks is missing")
+	}
+	numChunks := binary.LittleEndian.Uint32(data)
+	if numChunks > uint32(maxChunks) {
+		return nil, errors.Errorf("%d chunks exceed the limit of %d", numChunks, maxChunks)
+	}
+
+	chunks := make([][]byte, 0, numChunks)
+	data = data[4:]
+	for i := uint32(0); i < numChunks; i++ {
+		if len(data) < 4 {
+			return nil, errors.Errorf("size of chunk %d is missing", i)
+		}
+		size := binary.LittleEndian.Uint32(data)
+		data = data[4:]
+		if uint32(len(data)) < size {
+			return nil, errors.Errorf("chunk %d is truncated", i)
+		}
+		chunks = append(chunks, data[:size])
+		data = data[size:]
+	}
+	if len(data) != 0 {
+		return nil, errors.Errorf("%d bytes follow the last chunk", len(data))
+	}
+	return chunks, nil
+}
+
+func blockPairChunks(blockPair *protocol.BlockPairContainer) [][]byte {
+	chunks := [][]byte{
+		blockPair.TransactionsBlock.Header.Raw(),
+		blockPair.TransactionsBlock.Metadata.Raw(),
+		blockPair.TransactionsBlock.BlockProof.Raw(),
+		blockPair.ResultsBlock.Header.Raw(),
+		blockPair.ResultsBlock.BlockProof.Raw(),
+	}
+	for _, tx := range blockPair.TransactionsBlock.SignedTransactions {
+		chunks = append(chunks, tx.Raw())
+	}
+	for _, receipt := range blockPair.ResultsBlock.TransactionReceipts {
+		chunks = append(chunks, receipt.Raw())
+	}
+	for _, sdiff := range blockPair.ResultsBlock.ContractStateDiffs {
+		chunks = append(chunks, sdiff.Raw())
+	}
+	return chunks
+}
+
+func blockPairToJson(blockPair *protocol.BlockPairContainer) *blockPairJson {
+	document := &blockPairJson{
+		BlockHeight:    uint64(blockPair.TransactionsBlock.Header.BlockHeight()),
+		BlockTimestamp: uint64(blockPair.TransactionsBlock.Header.Timestamp()),
+		TransactionsBlock: &transactionsBlockJson{
+			Header:     hex.EncodeToString(blockPair.TransactionsBlock.Header.Raw()),
+			Metadata:   hex.EncodeToString(blockPair.TransactionsBlock.Metadata.Raw()),
+			BlockProof: hex.EncodeToString(blockPair.TransactionsBlock.BlockProof.Raw()),
+		},
+		ResultsBlock: &resultsBlockJson{
+			Header:     hex.EncodeToString(blockPair.ResultsBlock.Header.Raw()),
+			BlockProof: hex.EncodeToString(blockPair.ResultsBlock.BlockProof.Raw()),
+		},
+	}
+	for _, tx := range blockPair.TransactionsBlock.SignedTransactions {
+		document.TransactionsBlock.SignedTransactions = append(document.TransactionsBlock.SignedTransactions, hex.EncodeToString(tx.Raw()))
+	}
+	for _, receipt := range blockPair.ResultsBlock.TransactionReceipts {
+		document.ResultsBlock.TransactionReceipts = append(document.ResultsBlock.TransactionReceipts, hex.EncodeToString(receipt.Raw()))
+	}
+	for _, sdiff := range blockPair.ResultsBlock.ContractStateDiffs {
+		document.ResultsBlock.ContractStateDiffs = append(document.ResultsBlock.ContractStateDiffs, hex.EncodeToString(sdiff.Raw()))
+	}
+	return document
+}
@@ -0,0 +1,205 @@
+package httpserver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	harnessBlockStorageAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/blockstorage/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func persistenceWithBlocks(t *testing.T, numBlocks int) harnessBlockStorageAdapter.InMemoryBlockPersistence {
+	persistence := harnessBlockStorageAdapter.NewInMemoryBlockPersistence()
+	for h := 1; h <= numBlocks; h++ {
+		require.NoError(t, persistence.WriteNextBlock(builders.BlockPair().WithHeight(primitives.BlockHeight(h)).Build()))
+	}
+	return persistence
+}
+
+func requireChunks(t *testing.T, body []byte) [][]byte {
+	chunks, err := decodeChunks(body, 1000)
+	require.NoError(t, err, "response should hold exactly the chunks it declares")
+	return chunks
+}
+
+func serveBlockQuery(handler http.HandlerFunc, url string, acceptJson bool) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	if acceptJson {
+		req.Header.Set("Accept", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestHttpServerGetBlock_ReturnsBlockPairAsMembuffers(t *testing.T) {
+	persistence := persistenceWithBlocks(t, 2)
+	s := makeServerWithBlocks(&services.MockPublicApi{}, &services.MockBlockStorage{}, persistence).(*server)
+
+	rec := serveBlockQuery(s.getBlockHandler, "/api/v1/get-block?height=2", false)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	require.Equal(t, membuffersChunksContentType, rec.Header().Get("Content-Type"))
+	blocks, _, _, _ := persistence.GetBlocks(2, 2)
+	chunks := requireChunks(t, rec.Body.Bytes())
+	require.Len(t, chunks, 8, "block pair should be 5 headers and proofs followed by 1 transaction, 1 receipt and 1 state diff")
+	require.Equal(t, []byte(blocks[0].TransactionsBlock.Header.Raw()), chunks[0])
+	require.Equal(t, []byte(blocks[0].ResultsBlock.Header.Raw()), chunks[3])
+}
+
+func TestHttpServerGetBlock_ReturnsJsonWhenAccepted(t *testing.T) {
+	s := makeServerWithBlocks(&services.MockPublicApi{}, &services.MockBlockStorage{}, persistenceWithBlocks(t, 1)).(*server)
+
+	rec := serveBlockQuery(s.getBlockHandler, "/api/v1/get-block?height=1", true)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	document := &blockPairJson{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), document))
+	require.EqualValues(t, 1, document.BlockHeight)
+	require.Len(t, document.TransactionsBlock.SignedTransactions, 1)
+	require.Len(t, document.ResultsBlock.TransactionReceipts, 1)
+}
+
+func TestHttpServerGetBlock_RejectsInvalidAndUncommittedHeights(t *testing.T) {
+	s := makeServerWithBlocks(&services.MockPublicApi{}, &services.MockBlockStorage{}, persistenceWithBlocks(t, 1)).(*server)
+
+	require.Equal(t, http.StatusBadRequest, serveBlockQuery(s.getBlockHandler, "/api/v1/get-block", false).Code, "missing height should fail with 400")
+	require.Equal(t, http.StatusBadRequest, serveBlockQuery(s.getBlockHandler, "/api/v1/get-block?height=0", false).Code, "height 0 should fail with 400")
+	require.Equal(t, http.StatusNotFound, serveBlockQuery(s.getBlockHandler, "/api/v1/get-block?height=2", false).Code, "uncommitted height should fail with 404")
+}
+
+func TestHttpServerGetBlockRange_ReturnsCommittedPartOfRange(t *testing.T) {
+	s := makeServerWithBlocks(&services.MockPublicApi{}, &services.MockBlockStorage{}, persistenceWithBlocks(t, 3)).(*server)
+
+	rec := serveBlockQuery(s.getBlockRangeHandler, "/api/v1/get-block-range?first=2&last=10", true)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	require.Equal(t, "2", rec.Header().Get("X-ORBS-FIRST-BLOCK-HEIGHT"))
+	require.Equal(t, "3", rec.Header().Get("X-ORBS-LAST-BLOCK-HEIGHT"))
+	var documents []*blockPairJson
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &documents))
+	require.Len(t, documents, 2)
+	require.EqualValues(t, 2, documents[0].BlockHeight)
+	require.EqualValues(t, 3, documents[1].BlockHeight)
+}
+
+func TestHttpServerGetBlockRange_RejectsReversedRange(t *testing.T) {
+	s := makeServerWithBlocks(&services.MockPublicApi{}, &services.MockBlockStorage{}, persistenceWithBlocks(t, 3)).(*server)
+
+	rec := serveBlockQuery(s.getBlockRangeHandler, "/api/v1/get-block-range?first=3&last=2", false)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code, "should fail with 400")
+}
+
+func TestHttpServerGetBlockHeader_ReturnsHeadersWithoutBody(t *testing.T) {
+	blockPair := builders.BlockPair().WithHeight(3).Build()
+	blockStorage := &services.MockBlockStorage{}
+	blockStorage.When("GetLastCommittedBlockHeight", mock.Any, mock.Any).Return(&services.GetLastCommittedBlockHeightOutput{LastCommittedBlockHeight: 3}, nil)
+	blockStorage.When("GetTransactionsBlockHeader", mock.Any, mock.Any).Return(&services.GetTransactionsBlockHeaderOutput{
+		TransactionsBlockHeader:   blockPair.TransactionsBlock.Header,
+		TransactionsBlockMetadata: blockPair.TransactionsBlock.Metadata,
+		TransactionsBlockProof:    blockPair.TransactionsBlock.BlockProof,
+	}, nil).Times(1)
+	blockStorage.When("GetResultsBlockHeader", mock.Any, mock.Any).Return(&services.GetResultsBlockHeaderOutput{
+		ResultsBlockHeader: blockPair.ResultsBlock.Header,
+		ResultsBlockProof:  blockPair.ResultsBlock.BlockProof,
+	}, nil).Times(1)
+	s := makeServerWithBlocks(&services.MockPublicApi{}, blockStorage, nil).(*server)
+
+	rec := serveBlockQuery(s.getBlockHeaderHandler, "/api/v1/get-block-header?height=3", false)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	chunks := requireChunks(t, rec.Body.Bytes())
+	require.Len(t, chunks, 5, "headers should not include the block body")
+	require.Equal(t, []byte(blockPair.TransactionsBlock.Header.Raw()), chunks[0])
+	require.Equal(t, []byte(blockPair.ResultsBlock.BlockProof.Raw()), chunks[4])
+	_, err := blockStorage.Verify()
+	require.NoError(t, err)
+}
+
+func TestHttpServerGetBlockHeader_DoesNotWaitForUncommittedBlock(t *testing.T) {
+	blockStorage := &services.MockBlockStorage{}
+	blockStorage.When("GetLastCommittedBlockHeight", mock.Any, mock.Any).Return(&services.GetLastCommittedBlockHeightOutput{LastCommittedBlockHeight: 3}, nil)
+	blockStorage.Never("GetTransactionsBlockHeader", mock.Any, mock.Any)
+	s := makeServerWithBlocks(&services.MockPublicApi{}, blockStorage, nil).(*server)
+
+	rec := serveBlockQuery(s.getBlockHeaderHandler, "/api/v1/get-block-header?height=4", false)
+
+	require.Equal(t, http.StatusNotFound, rec.Code, "should fail with 404")
+	_, err := blockStorage.Verify()
+	require.NoError(t, err)
+}
+
+func TestHttpServerGetTransactionReceiptProof_ReturnsMerkleProofOfReceiptInSignedResultsHeader(t *testing.T) {
+	receipts := []*protocol.TransactionReceipt{
+		builders.TransactionReceipt().WithRandomHash().Build(),
+		builders.TransactionReceipt().WithRandomHash().Build(),
+		builders.TransactionReceipt().WithRandomHash().Build(),
+	}
+	blockPair := builders.BlockPair().WithReceipts(0).WithReceipt(receipts[0]).WithReceipt(receipts[1]).WithReceipt(receipts[2]).Build()
+	persistence := harnessBlockStorageAdapter.NewInMemoryBlockPersistence()
+	require.NoError(t, persistence.WriteNextBlock(blockPair))
+	blockStorage := &services.MockBlockStorage{}
+	blockStorage.When("GetTransactionReceipt", mock.Any, mock.Any).Return(&services.GetTransactionReceiptOutput{
+		TransactionReceipt: receipts[1],
+		BlockHeight:        1,
+		BlockTimestamp:     blockPair.ResultsBlock.Header.Timestamp(),
+	}, nil).Times(1)
+	s := makeServerWithBlocks(&services.MockPublicApi{}, blockStorage, persistence).(*server)
+
+	rec := serveBlockQuery(s.getTransactionReceiptProofHandler, "/api/v1/get-transaction-receipt-proof?txhash=0x"+receipts[1].Txhash().String()+"&timestamp=1", true)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	document := &receiptProofJson{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), document))
+	require.EqualValues(t, 1, document.BlockHeight)
+	require.EqualValues(t, 1, document.ReceiptIndex)
+	require.Equal(t, hex.EncodeToString(blockPair.ResultsBlock.Header.Raw()), document.ResultsBlockHeader)
+	var proof []primitives.Sha256
+	for _, sibling := range document.ReceiptProof {
+		decoded, err := hex.DecodeString(sibling)
+		require.NoError(t, err)
+		proof = append(proof, decoded)
+	}
+	rootHash := blockPair.ResultsBlock.Header.ReceiptsRootHash()
+	require.True(t, digest.VerifyReceiptProof(rootHash, receipts[1], document.ReceiptIndex, 3, proof), "proof should verify against the receipts root hash of the header")
+	require.False(t, digest.VerifyReceiptProof(rootHash, receipts[0], document.ReceiptIndex, 3, proof), "proof should not verify another receipt")
+	_, err := blockStorage.Verify()
+	require.NoError(t, err)
+}
+
+func TestHttpServerGetTransactionReceiptProof_ReturnsProofAsMembuffers(t *testing.T) {
+	blockPair := builders.BlockPair().Build()
+	receipt := blockPair.ResultsBlock.TransactionReceipts[0]
+	persistence := harnessBlockStorageAdapter.NewInMemoryBlockPersistence()
+	require.NoError(t, persistence.WriteNextBlock(blockPair))
+	blockStorage := &services.MockBlockStorage{}
+	blockStorage.When("GetTransactionReceipt", mock.Any, mock.Any).Return(&services.GetTransactionReceiptOutput{TransactionReceipt: receipt, BlockHeight: 1}, nil)
+	s := makeServerWithBlocks(&services.MockPublicApi{}, blockStorage, persistence).(*server)
+
+	rec := serveBlockQuery(s.getTransactionReceiptProofHandler, "/api/v1/get-transaction-receipt-proof?txhash="+receipt.Txhash().String()+"&timestamp=1", false)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	chunks := requireChunks(t, rec.Body.Bytes())
+	require.Len(t, chunks, 4, "proof of the only receipt in a block should have no sibling hashes")
+	require.Equal(t, []byte(blockPair.ResultsBlock.Header.Raw()), chunks[0])
+	require.Equal(t, []byte(receipt.Raw()), chunks[2])
+	require.Equal(t, []byte{0, 0, 0, 0}, chunks[3], "receipt index should be 0")
+}
+
+func TestHttpServerGetTransactionReceiptProof_UnknownTransaction(t *testing.T) {
+	blockStorage := &services.MockBlockStorage{}
+	blockStorage.When("GetTransactionReceipt", mock.Any, mock.Any).Return(&services.GetTransactionReceiptOutput{}, nil)
+	s := makeServerWithBlocks(&services.MockPublicApi{}, blockStorage, nil).(*server)
+
+	require.Equal(t, http.StatusBadRequest, serveBlockQuery(s.getTransactionReceiptProofHandler, "/api/v1/get-transaction-receipt-proof?txhash=zz&timestamp=1", false).Code, "invalid txhash should fail with 400")
+	require.Equal(t, http.StatusNotFound, serveBlockQuery(s.getTransactionReceiptProofHandler, "/api/v1/get-transaction-receipt-proof?txhash=0102&timestamp=1", false).Code, "unknown transaction should fail with 404")
+}
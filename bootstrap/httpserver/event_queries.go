@@ -0,0 +1,69 @@
+package httpserver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"net/http"
+	"strconv"
+)
+
+type matchingEventJson struct {
+	BlockHeight    uint64
+	BlockTimestamp uint64
+	Txhash         string
+	eventJson
+}
+
+// GET /api/v1/get-events?contract=<name>&event=<name>&first=<height>&last=<height>, event is optional and the blocks
+// are scanned like in get-block-range, every matching event is returned as a pair of chunks: the receipt it was
+// emitted in followed by the event itself
+func (s *server) getEventsHandler(w http.ResponseWriter, r *http.Request) {
+	contractName := r.URL.Query().Get("contract")
+	if contractName == "" {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, nil, "contract must be given"})
+		return
+	}
+	eventName := r.URL.Query().Get("event")
+	first, last, e := readBlockRange(r)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	s.logger.Info("http server received get-events", log.String("contract", contractName), log.String("event", eventName), log.Uint64("first-block-height", uint64(first)), log.Uint64("last-block-height", uint64(last)))
+	blocks, firstReturned, lastReturned, err := s.blockPersistence.GetBlocks(first, last)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), "failed to read blocks"})
+		return
+	}
+	if len(blocks) == 0 {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotFound, nil, fmt.Sprintf("block %d was not committed yet", first)})
+		return
+	}
+
+	chunks := [][]byte{}
+	documents := []*matchingEventJson{}
+	for _, blockPair := range blocks {
+		for _, receipt := range blockPair.ResultsBlock.TransactionReceipts {
+			for i := protocol.EventsArrayReader(receipt.RawOutputEventsArrayWithHeader()).EventsIterator(); i.HasNext(); {
+				event := i.NextEvents()
+				if string(event.ContractName()) != contractName || (eventName != "" && event.EventName() != eventName) {
+					continue
+				}
+				chunks = append(chunks, receipt.Raw(), event.Raw())
+				documents = append(documents, &matchingEventJson{
+					BlockHeight:    uint64(blockPair.ResultsBlock.Header.BlockHeight()),
+					BlockTimestamp: uint64(blockPair.ResultsBlock.Header.Timestamp()),
+					Txhash:         hex.EncodeToString(receipt.Txhash()),
+					eventJson:      *eventToJson(event),
+				})
+			}
+		}
+	}
+
+	w.Header().Set("X-ORBS-FIRST-BLOCK-HEIGHT", strconv.FormatUint(uint64(firstReturned), 10))
+	w.Header().Set("X-ORBS-LAST-BLOCK-HEIGHT", strconv.FormatUint(uint64(lastReturned), 10))
+	s.writeChunksResponse(w, r, chunks, documents)
+}
@@ -0,0 +1,66 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	harnessBlockStorageAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/blockstorage/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func persistenceWithEvents(t *testing.T) harnessBlockStorageAdapter.InMemoryBlockPersistence {
+	persistence := harnessBlockStorageAdapter.NewInMemoryBlockPersistence()
+	require.NoError(t, persistence.WriteNextBlock(builders.BlockPair().WithHeight(1).WithReceipts(0).
+		WithReceipt(builders.TransactionReceipt().WithEvent("Contract1", "Transfer", uint64(17)).WithEvent("Contract2", "Transfer", uint64(18)).Build()).
+		Build()))
+	require.NoError(t, persistence.WriteNextBlock(builders.BlockPair().WithHeight(2).WithReceipts(0).
+		WithReceipt(builders.TransactionReceipt().WithEvent("Contract1", "Approval", "hello").Build()).
+		WithReceipt(builders.TransactionReceipt().WithEvent("Contract1", "Transfer", uint64(19)).Build()).
+		Build()))
+	return persistence
+}
+
+func TestHttpServerGetEvents_ReturnsMatchingEventsAsMembuffers(t *testing.T) {
+	persistence := persistenceWithEvents(t)
+	s := makeServerWithBlocks(&services.MockPublicApi{}, &services.MockBlockStorage{}, persistence).(*server)
+
+	rec := serveBlockQuery(s.getEventsHandler, "/api/v1/get-events?contract=Contract1&event=Transfer&first=1&last=2", false)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	require.Equal(t, membuffersChunksContentType, rec.Header().Get("Content-Type"))
+	require.Equal(t, "1", rec.Header().Get("X-ORBS-FIRST-BLOCK-HEIGHT"))
+	require.Equal(t, "2", rec.Header().Get("X-ORBS-LAST-BLOCK-HEIGHT"))
+	blocks, _, _, _ := persistence.GetBlocks(1, 2)
+	chunks := requireChunks(t, rec.Body.Bytes())
+	require.Len(t, chunks, 4, "each of the 2 matching events should be its receipt followed by the event")
+	require.Equal(t, []byte(blocks[0].ResultsBlock.TransactionReceipts[0].Raw()), chunks[0])
+	require.Equal(t, []byte(blocks[1].ResultsBlock.TransactionReceipts[1].Raw()), chunks[2])
+}
+
+func TestHttpServerGetEvents_ReturnsJsonWhenAccepted(t *testing.T) {
+	s := makeServerWithBlocks(&services.MockPublicApi{}, &services.MockBlockStorage{}, persistenceWithEvents(t)).(*server)
+
+	rec := serveBlockQuery(s.getEventsHandler, "/api/v1/get-events?contract=Contract1&first=1&last=2", true)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	documents := []*matchingEventJson{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &documents))
+	require.Len(t, documents, 3, "all events of the contract should be returned when no event name is given")
+	require.EqualValues(t, 1, documents[0].BlockHeight)
+	require.Equal(t, "Contract1", documents[0].ContractName)
+	require.Equal(t, "Transfer", documents[0].EventName)
+	require.Equal(t, METHOD_ARGUMENT_TYPE_UINT64, documents[0].Arguments[0].Type)
+	require.EqualValues(t, 17, documents[0].Arguments[0].Value)
+	require.Equal(t, "Approval", documents[1].EventName)
+	require.EqualValues(t, 2, documents[2].BlockHeight)
+}
+
+func TestHttpServerGetEvents_RejectsInvalidQueries(t *testing.T) {
+	s := makeServerWithBlocks(&services.MockPublicApi{}, &services.MockBlockStorage{}, persistenceWithEvents(t)).(*server)
+
+	require.Equal(t, http.StatusBadRequest, serveBlockQuery(s.getEventsHandler, "/api/v1/get-events?first=1&last=2", false).Code, "missing contract should fail with 400")
+	require.Equal(t, http.StatusBadRequest, serveBlockQuery(s.getEventsHandler, "/api/v1/get-events?contract=Contract1&first=2&last=1", false).Code, "reversed range should fail with 400")
+	require.Equal(t, http.StatusNotFound, serveBlockQuery(s.getEventsHandler, "/api/v1/get-events?contract=Contract1&first=3&last=4", false).Code, "uncommitted range should fail with 404")
+}
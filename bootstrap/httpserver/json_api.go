@@ -0,0 +1,370 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// the canonical json mapping of the client membuffers: field names follow the membuffer fields, bytes are hex (an optional
+// 0x prefix is accepted), enums are their names and method argument values are typed by the argument's Type
+const (
+	METHOD_ARGUMENT_TYPE_UINT32 = "uint32"
+	METHOD_ARGUMENT_TYPE_UINT64 = "uint64"
+	METHOD_ARGUMENT_TYPE_STRING = "string"
+	METHOD_ARGUMENT_TYPE_BYTES  = "bytes"
+)
+
+type methodArgumentJson struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+type eddsaSignerJson struct {
+	NetworkType     string
+	SignerPublicKey string
+}
+
+type signerJson struct {
+	Scheme string
+	Eddsa  *eddsaSignerJson
+}
+
+type transactionJson struct {
+	ProtocolVersion uint32
+	VirtualChainId  uint32
+	Timestamp       uint64
+	Signer          *signerJson
+	ContractName    string
+	MethodName      string
+	InputArguments  []*methodArgumentJson
+}
+
+type signedTransactionJson struct {
+	Transaction *transactionJson
+	Signature   string
+}
+
+type sendTransactionRequestJson struct {
+	SignedTransaction *signedTransactionJson
+}
+
+type callMethodRequestJson struct {
+	Transaction *transactionJson
+}
+
+type getTransactionStatusRequestJson struct {
+	TransactionTimestamp uint64
+	Txhash               string
+}
+
+type eventJson struct {
+	ContractName string
+	EventName    string
+	Arguments    []*methodArgumentJson
+}
+
+type transactionReceiptJson struct {
+	Txhash          string
+	ExecutionResult string
+	OutputArguments []*methodArgumentJson
+	OutputEvents    []*eventJson
+}
+
+// send-transaction and get-transaction-status share their response layout
+type transactionStatusResponseJson struct {
+	RequestStatus      string
+	TransactionReceipt *transactionReceiptJson `json:",omitempty"`
+	TransactionStatus  string
+	BlockHeight        uint64
+	BlockTimestamp     uint64
+}
+
+type callMethodResponseJson struct {
+	RequestStatus    string
+	OutputArguments  []*methodArgumentJson
+	CallMethodResult string
+	BlockHeight      uint64
+	BlockTimestamp   uint64
+}
+
+func isJsonRequest(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Content-Type"), "application/json")
+}
+
+// json requests are answered in json, membuffers requests may ask for json with "Accept: application/json"
+func respondsWithJson(r *http.Request) bool {
+	return isJsonRequest(r) || strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func decodeJson(data []byte, document interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(document)
+}
+
+func sendTransactionRequestFromJson(data []byte) (*client.SendTransactionRequest, error) {
+	document := &sendTransactionRequestJson{}
+	if err := decodeJson(data, document); err != nil {
+		return nil, err
+	}
+	return sendTransactionRequestFromJsonDocument(document)
+}
+
+func sendTransactionRequestFromJsonDocument(document *sendTransactionRequestJson) (*client.SendTransactionRequest, error) {
+	if document == nil || document.SignedTransaction == nil {
+		return nil, errors.New("SignedTransaction is missing")
+	}
+
+	transaction, err := transactionFromJson(document.SignedTransaction.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := bytesFromJson("Signature", document.SignedTransaction.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&client.SendTransactionRequestBuilder{
+		SignedTransaction: &protocol.SignedTransactionBuilder{
+			Transaction: transaction,
+			Signature:   signature,
+		},
+	}).Build(), nil
+}
+
+func callMethodRequestFromJson(data []byte) (*client.CallMethodRequest, error) {
+	document := &callMethodRequestJson{}
+	if err := decodeJson(data, document); err != nil {
+		return nil, err
+	}
+
+	transaction, err := transactionFromJson(document.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&client.CallMethodRequestBuilder{Transaction: transaction}).Build(), nil
+}
+
+func getTransactionStatusRequestFromJson(data []byte) (*client.GetTransactionStatusRequest, error) {
+	document := &getTransactionStatusRequestJson{}
+	if err := decodeJson(data, document); err != nil {
+		return nil, err
+	}
+
+	txHash, err := bytesFromJson("Txhash", document.Txhash)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&client.GetTransactionStatusRequestBuilder{
+		TransactionTimestamp: primitives.TimestampNano(document.TransactionTimestamp),
+		Txhash:               txHash,
+	}).Build(), nil
+}
+
+func transactionFromJson(document *transactionJson) (*protocol.TransactionBuilder, error) {
+	if document == nil {
+		return nil, errors.New("Transaction is missing")
+	}
+
+	signer, err := signerFromJson(document.Signer)
+	if err != nil {
+		return nil, err
+	}
+	inputArguments, err := methodArgumentsFromJson(document.InputArguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocol.TransactionBuilder{
+		ProtocolVersion:    primitives.ProtocolVersion(document.ProtocolVersion),
+		VirtualChainId:     primitives.VirtualChainId(document.VirtualChainId),
+		Timestamp:          primitives.TimestampNano(document.Timestamp),
+		Signer:             signer,
+		ContractName:       primitives.ContractName(document.ContractName),
+		MethodName:         primitives.MethodName(document.MethodName),
+		InputArgumentArray: (&protocol.MethodArgumentArrayBuilder{Arguments: inputArguments}).Build().RawArgumentsArray(),
+	}, nil
+}
+
+func signerFromJson(document *signerJson) (*protocol.SignerBuilder, error) {
+	if document == nil {
+		return nil, errors.New("Signer is missing")
+	}
+	if document.Scheme != protocol.SIGNER_SCHEME_EDDSA.String() || document.Eddsa == nil {
+		return nil, errors.Errorf("Signer scheme %s is not supported", document.Scheme)
+	}
+
+	signer := &protocol.SignerBuilder{
+		Scheme: protocol.SIGNER_SCHEME_EDDSA,
+		Eddsa:  &protocol.EdDSA01SignerBuilder{},
+	}
+	switch document.Eddsa.NetworkType {
+	case protocol.NETWORK_TYPE_MAIN_NET.String():
+		signer.Eddsa.NetworkType = protocol.NETWORK_TYPE_MAIN_NET
+	case protocol.NETWORK_TYPE_TEST_NET.String():
+		signer.Eddsa.NetworkType = protocol.NETWORK_TYPE_TEST_NET
+	default:
+		return nil, errors.Errorf("Signer network type %s is not supported", document.Eddsa.NetworkType)
+	}
+
+	publicKey, err := bytesFromJson("SignerPublicKey", document.Eddsa.SignerPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signer.Eddsa.SignerPublicKey = publicKey
+	return signer, nil
+}
+
+func methodArgumentsFromJson(documents []*methodArgumentJson) ([]*protocol.MethodArgumentBuilder, error) {
+	var arguments []*protocol.MethodArgumentBuilder
+	for _, document := range documents {
+		if document == nil {
+			return nil, errors.New("method argument is missing")
+		}
+
+		argument := &protocol.MethodArgumentBuilder{Name: document.Name}
+		value := fmt.Sprint(document.Value)
+		switch document.Type {
+		case METHOD_ARGUMENT_TYPE_UINT32:
+			n, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, errors.Wrapf(err, "argument %s is not a uint32", document.Name)
+			}
+			argument.Type = protocol.METHOD_ARGUMENT_TYPE_UINT_32_VALUE
+			argument.Uint32Value = uint32(n)
+		case METHOD_ARGUMENT_TYPE_UINT64:
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "argument %s is not a uint64", document.Name)
+			}
+			argument.Type = protocol.METHOD_ARGUMENT_TYPE_UINT_64_VALUE
+			argument.Uint64Value = n
+		case METHOD_ARGUMENT_TYPE_STRING:
+			s, ok := document.Value.(string)
+			if !ok {
+				return nil, errors.Errorf("argument %s is not a string", document.Name)
+			}
+			argument.Type = protocol.METHOD_ARGUMENT_TYPE_STRING_VALUE
+			argument.StringValue = s
+		case METHOD_ARGUMENT_TYPE_BYTES:
+			s, ok := document.Value.(string)
+			if !ok {
+				return nil, errors.Errorf("argument %s is not a hex string", document.Name)
+			}
+			b, err := bytesFromJson(document.Name, s)
+			if err != nil {
+				return nil, err
+			}
+			argument.Type = protocol.METHOD_ARGUMENT_TYPE_BYTES_VALUE
+			argument.BytesValue = b
+		default:
+			return nil, errors.Errorf("argument %s has unknown type %s", document.Name, document.Type)
+		}
+		arguments = append(arguments, argument)
+	}
+	return arguments, nil
+}
+
+func bytesFromJson(field string, value string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s is not valid hex", field)
+	}
+	return b, nil
+}
+
+func sendTransactionResponseToJson(response *client.SendTransactionResponse) *transactionStatusResponseJson {
+	return &transactionStatusResponseJson{
+		RequestStatus:      response.RequestStatus().String(),
+		TransactionReceipt: transactionReceiptToJson(response.TransactionReceipt()),
+		TransactionStatus:  response.TransactionStatus().String(),
+		BlockHeight:        uint64(response.BlockHeight()),
+		BlockTimestamp:     uint64(response.BlockTimestamp()),
+	}
+}
+
+func getTransactionStatusResponseToJson(response *client.GetTransactionStatusResponse) *transactionStatusResponseJson {
+	return &transactionStatusResponseJson{
+		RequestStatus:      response.RequestStatus().String(),
+		TransactionReceipt: transactionReceiptToJson(response.TransactionReceipt()),
+		TransactionStatus:  response.TransactionStatus().String(),
+		BlockHeight:        uint64(response.BlockHeight()),
+		BlockTimestamp:     uint64(response.BlockTimestamp()),
+	}
+}
+
+func callMethodResponseToJson(response *client.CallMethodResponse) *callMethodResponseJson {
+	return &callMethodResponseJson{
+		RequestStatus:    response.RequestStatus().String(),
+		OutputArguments:  methodArgumentsToJson(protocol.MethodArgumentArrayReader(response.RawOutputArgumentArrayWithHeader())),
+		CallMethodResult: response.CallMethodResult().String(),
+		BlockHeight:      uint64(response.BlockHeight()),
+		BlockTimestamp:   uint64(response.BlockTimestamp()),
+	}
+}
+
+// a response without a receipt holds an empty receipt membuffer
+func transactionReceiptToJson(receipt *protocol.TransactionReceipt) *transactionReceiptJson {
+	if receipt == nil || len(receipt.Txhash()) == 0 {
+		return nil
+	}
+	return &transactionReceiptJson{
+		Txhash:          hex.EncodeToString(receipt.Txhash()),
+		ExecutionResult: receipt.ExecutionResult().String(),
+		OutputArguments: methodArgumentsToJson(protocol.MethodArgumentArrayReader(receipt.RawOutputArgumentArrayWithHeader())),
+		OutputEvents:    eventsToJson(protocol.EventsArrayReader(receipt.RawOutputEventsArrayWithHeader())),
+	}
+}
+
+func eventsToJson(eventsArray *protocol.EventsArray) []*eventJson {
+	documents := []*eventJson{}
+	for i := eventsArray.EventsIterator(); i.HasNext(); {
+		documents = append(documents, eventToJson(i.NextEvents()))
+	}
+	return documents
+}
+
+func eventToJson(event *protocol.Event) *eventJson {
+	return &eventJson{
+		ContractName: string(event.ContractName()),
+		EventName:    event.EventName(),
+		Arguments:    methodArgumentsToJson(protocol.MethodArgumentArrayReader(event.RawOutputArgumentArrayWithHeader())),
+	}
+}
+
+func methodArgumentsToJson(argumentArray *protocol.MethodArgumentArray) []*methodArgumentJson {
+	documents := []*methodArgumentJson{}
+	for i := argumentArray.ArgumentsIterator(); i.HasNext(); {
+		argument := i.NextArguments()
+		document := &methodArgumentJson{Name: argument.Name()}
+		switch argument.Type() {
+		case protocol.METHOD_ARGUMENT_TYPE_UINT_32_VALUE:
+			document.Type = METHOD_ARGUMENT_TYPE_UINT32
+			document.Value = argument.Uint32Value()
+		case protocol.METHOD_ARGUMENT_TYPE_UINT_64_VALUE:
+			document.Type = METHOD_ARGUMENT_TYPE_UINT64
+			document.Value = argument.Uint64Value()
+		case protocol.METHOD_ARGUMENT_TYPE_STRING_VALUE:
+			document.Type = METHOD_ARGUMENT_TYPE_STRING
+			document.Value = argument.StringValue()
+		case protocol.METHOD_ARGUMENT_TYPE_BYTES_VALUE:
+			document.Type = METHOD_ARGUMENT_TYPE_BYTES
+			document.Value = hex.EncodeToString(argument.BytesValue())
+		}
+		documents = append(documents, document)
+	}
+	return documents
+}
@@ -0,0 +1,187 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func transferTransactionJson(publicKey primitives.Ed25519PublicKey, amountType string, amountValue string) string {
+	return fmt.Sprintf(`{"ProtocolVersion":1,"VirtualChainId":42,"Timestamp":1540000000000000001,
+		"Signer":{"Scheme":"%s","Eddsa":{"NetworkType":"%s","SignerPublicKey":"0x%s"}},
+		"ContractName":"BenchmarkToken","MethodName":"transfer",
+		"InputArguments":[{"Name":"amount","Type":"%s","Value":%s},{"Name":"targetAddress","Type":"bytes","Value":"0102ff"}]}`,
+		protocol.SIGNER_SCHEME_EDDSA.String(), protocol.NETWORK_TYPE_TEST_NET.String(), hex.EncodeToString(publicKey), amountType, amountValue)
+}
+
+func serveJsonRequest(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestJsonApi_SendTransactionRequestMapsToTheSameMembuffers(t *testing.T) {
+	publicKey := keys.Ed25519KeyPairForTests(1).PublicKey()
+	document := fmt.Sprintf(`{"SignedTransaction":{"Transaction":%s,"Signature":"abcd"}}`, transferTransactionJson(publicKey, "uint64", "18446744073709551615"))
+
+	request, err := sendTransactionRequestFromJson([]byte(document))
+	require.NoError(t, err)
+
+	expected := (&client.SendTransactionRequestBuilder{
+		SignedTransaction: &protocol.SignedTransactionBuilder{
+			Transaction: &protocol.TransactionBuilder{
+				ProtocolVersion: 1,
+				VirtualChainId:  42,
+				Timestamp:       1540000000000000001,
+				Signer: &protocol.SignerBuilder{
+					Scheme: protocol.SIGNER_SCHEME_EDDSA,
+					Eddsa: &protocol.EdDSA01SignerBuilder{
+						NetworkType:     protocol.NETWORK_TYPE_TEST_NET,
+						SignerPublicKey: publicKey,
+					},
+				},
+				ContractName: "BenchmarkToken",
+				MethodName:   "transfer",
+				InputArgumentArray: (&protocol.MethodArgumentArrayBuilder{Arguments: []*protocol.MethodArgumentBuilder{
+					{Name: "amount", Type: protocol.METHOD_ARGUMENT_TYPE_UINT_64_VALUE, Uint64Value: 18446744073709551615},
+					{Name: "targetAddress", Type: protocol.METHOD_ARGUMENT_TYPE_BYTES_VALUE, BytesValue: []byte{0x01, 0x02, 0xff}},
+				}}).Build().RawArgumentsArray(),
+			},
+			Signature: []byte{0xab, 0xcd},
+		},
+	}).Build()
+	require.Equal(t, expected.Raw(), request.Raw(), "json request should map to the membuffers the client signed")
+}
+
+func TestJsonApi_RejectsInvalidDocuments(t *testing.T) {
+	publicKey := keys.Ed25519KeyPairForTests(1).PublicKey()
+
+	_, err := sendTransactionRequestFromJson([]byte(`{"SignedTransaction":{"Transaction":` + transferTransactionJson(publicKey, "uint32", "4294967296") + `,"Signature":""}}`))
+	require.Error(t, err, "uint32 argument out of range should be rejected")
+
+	_, err = callMethodRequestFromJson([]byte(`{"Transaction":` + transferTransactionJson(publicKey, "float", "1") + `}`))
+	require.Error(t, err, "unknown argument type should be rejected")
+
+	_, err = callMethodRequestFromJson([]byte(`{"Transaction":{"ContractName":"BenchmarkToken"},"Unknown":1}`))
+	require.Error(t, err, "unknown fields should be rejected")
+
+	_, err = getTransactionStatusRequestFromJson([]byte(`{"Txhash":"not hex"}`))
+	require.Error(t, err, "bytes that are not hex should be rejected")
+}
+
+func TestHttpServerSendTxHandler_Json(t *testing.T) {
+	papiMock := &services.MockPublicApi{}
+	response := &client.SendTransactionResponseBuilder{
+		RequestStatus: protocol.REQUEST_STATUS_COMPLETED,
+		TransactionReceipt: &protocol.TransactionReceiptBuilder{
+			Txhash:              []byte{0x01, 0x02},
+			ExecutionResult:     protocol.EXECUTION_RESULT_SUCCESS,
+			OutputArgumentArray: builders.MethodArgumentsArray(uint64(17), []byte{0x03}).RawArgumentsArray(),
+		},
+		TransactionStatus: protocol.TRANSACTION_STATUS_COMMITTED,
+		BlockHeight:       3,
+		BlockTimestamp:    1540000000000000001,
+	}
+	papiMock.When("SendTransaction", mock.Any, mock.Any).Times(1).Return(&services.SendTransactionOutput{ClientResponse: response.Build()})
+	s := makeServer(papiMock).(*server)
+
+	document := fmt.Sprintf(`{"SignedTransaction":{"Transaction":%s,"Signature":"abcd"}}`, transferTransactionJson(keys.Ed25519KeyPairForTests(1).PublicKey(), "uint64", "10"))
+	rec := serveJsonRequest(s.sendTransactionHandler, document)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	require.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	result := &transactionStatusResponseJson{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), result))
+	require.Equal(t, protocol.REQUEST_STATUS_COMPLETED.String(), result.RequestStatus)
+	require.Equal(t, protocol.TRANSACTION_STATUS_COMMITTED.String(), result.TransactionStatus)
+	require.EqualValues(t, 1540000000000000001, result.BlockTimestamp)
+	require.Equal(t, "0102", result.TransactionReceipt.Txhash)
+	require.Len(t, result.TransactionReceipt.OutputArguments, 2)
+	require.Equal(t, METHOD_ARGUMENT_TYPE_UINT64, result.TransactionReceipt.OutputArguments[0].Type)
+	require.EqualValues(t, 17, result.TransactionReceipt.OutputArguments[0].Value)
+	require.Equal(t, "03", result.TransactionReceipt.OutputArguments[1].Value)
+	_, err := papiMock.Verify()
+	require.NoError(t, err)
+}
+
+func TestHttpServerSendTxHandler_InvalidJson(t *testing.T) {
+	papiMock := &services.MockPublicApi{}
+	papiMock.Never("SendTransaction", mock.Any, mock.Any)
+	s := makeServer(papiMock).(*server)
+
+	rec := serveJsonRequest(s.sendTransactionHandler, `{"SignedTransaction":`)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code, "should fail with 400")
+	_, err := papiMock.Verify()
+	require.NoError(t, err)
+}
+
+func TestHttpServerCallMethod_MembuffersRequestAcceptingJson(t *testing.T) {
+	papiMock := &services.MockPublicApi{}
+	response := &client.CallMethodResponseBuilder{
+		RequestStatus:       protocol.REQUEST_STATUS_COMPLETED,
+		OutputArgumentArray: builders.MethodArgumentsArray("bar").RawArgumentsArray(),
+		CallMethodResult:    protocol.EXECUTION_RESULT_SUCCESS,
+		BlockHeight:         3,
+	}
+	papiMock.When("CallMethod", mock.Any, mock.Any).Times(1).Return(&services.CallMethodOutput{ClientResponse: response.Build()})
+	s := makeServer(papiMock).(*server)
+
+	request := (&client.CallMethodRequestBuilder{
+		Transaction: builders.GetBalanceTransaction().Builder().Transaction,
+	}).Build()
+	req, _ := http.NewRequest("POST", "", bytes.NewReader(request.Raw()))
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	s.callMethodHandler(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	result := &callMethodResponseJson{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), result))
+	require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS.String(), result.CallMethodResult)
+	require.Len(t, result.OutputArguments, 1)
+	require.Equal(t, "bar", result.OutputArguments[0].Value)
+}
+
+func TestHttpServerGetTransactionStatus_Json(t *testing.T) {
+	papiMock := &services.MockPublicApi{}
+	response := &client.GetTransactionStatusResponseBuilder{
+		RequestStatus:     protocol.REQUEST_STATUS_IN_PROCESS,
+		TransactionStatus: protocol.TRANSACTION_STATUS_PENDING,
+	}
+	papiMock.When("GetTransactionStatus", mock.Any, mock.Any).Times(1).Return(&services.GetTransactionStatusOutput{ClientResponse: response.Build()})
+	s := makeServer(papiMock).(*server)
+
+	rec := serveJsonRequest(s.getTransactionStatusHandler, `{"TransactionTimestamp":1540000000000000001,"Txhash":"0x0102"}`)
+
+	require.Equal(t, http.StatusAccepted, rec.Code, "should be accepted while pending")
+	result := &transactionStatusResponseJson{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), result))
+	require.Equal(t, protocol.TRANSACTION_STATUS_PENDING.String(), result.TransactionStatus)
+	require.Nil(t, result.TransactionReceipt, "pending transaction should have no receipt")
+}
+
+func TestJsonApi_ReceiptIncludesEvents(t *testing.T) {
+	receipt := builders.TransactionReceipt().WithEvent("Contract1", "Transfer", uint64(17)).Build()
+
+	document := transactionReceiptToJson(receipt)
+
+	require.Len(t, document.OutputEvents, 1)
+	require.Equal(t, "Contract1", document.OutputEvents[0].ContractName)
+	require.Equal(t, "Transfer", document.OutputEvents[0].EventName)
+	require.Equal(t, uint64(17), document.OutputEvents[0].Arguments[0].Value)
+}
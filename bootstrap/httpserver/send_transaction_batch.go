@@ -0,0 +1,103 @@
+package httpserver
+
+import (
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const MAX_TRANSACTIONS_IN_BATCH = 1000
+
+// POST /api/v1/send-transaction-batch?wait-for-commit=true&timeout=<duration>, the body holds SendTransactionRequest
+// membuffers framed as chunks (see block queries) or a json array of send transaction requests; the response holds a
+// SendTransactionResponse per request in the same order, when waiting for commit all transactions share one deadline
+func (s *server) sendTransactionBatchHandler(w http.ResponseWriter, r *http.Request) {
+	sender, ok := s.publicApi.(publicapi.TransactionBatchSender)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotImplemented, nil, "transaction batches are not supported"})
+		return
+	}
+
+	bytes, e := readInput(r)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	input, e := readSendTransactionBatch(r, bytes)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	s.logger.Info("http server received send-transaction-batch", log.Int("transactions", len(input.ClientRequests)), log.String("wait-for-commit", strconv.FormatBool(input.WaitForCommit)))
+	result, err := sender.SendTransactionBatch(r.Context(), input)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), err.Error()})
+		return
+	}
+
+	chunks := make([][]byte, 0, len(result.ClientResponses))
+	documents := make([]*transactionStatusResponseJson, 0, len(result.ClientResponses))
+	for _, response := range result.ClientResponses {
+		chunks = append(chunks, response.Raw())
+		documents = append(documents, sendTransactionResponseToJson(response))
+	}
+	s.writeChunksResponse(w, r, chunks, documents)
+}
+
+func readSendTransactionBatch(r *http.Request, data []byte) (*publicapi.SendTransactionBatchInput, *httpErr) {
+	input := &publicapi.SendTransactionBatchInput{}
+
+	query := r.URL.Query()
+	if waitForCommit := query.Get("wait-for-commit"); waitForCommit != "" {
+		var err error
+		if input.WaitForCommit, err = strconv.ParseBool(waitForCommit); err != nil {
+			return nil, &httpErr{http.StatusBadRequest, log.Error(err), "wait-for-commit must be true or false"}
+		}
+	}
+	if timeout := query.Get("timeout"); timeout != "" {
+		var err error
+		if input.WaitTimeout, err = time.ParseDuration(timeout); err != nil || input.WaitTimeout <= 0 {
+			return nil, &httpErr{http.StatusBadRequest, nil, "timeout must be a positive duration such as 5s"}
+		}
+	}
+
+	if isJsonRequest(r) {
+		var documents []*sendTransactionRequestJson
+		if err := decodeJson(data, &documents); err != nil {
+			return nil, &httpErr{http.StatusBadRequest, log.Error(err), "http request is not valid json: " + err.Error()}
+		}
+		if len(documents) > MAX_TRANSACTIONS_IN_BATCH {
+			return nil, &httpErr{http.StatusBadRequest, nil, fmt.Sprintf("batch of %d transactions exceeds the limit of %d", len(documents), MAX_TRANSACTIONS_IN_BATCH)}
+		}
+		for i, document := range documents {
+			clientRequest, err := sendTransactionRequestFromJsonDocument(document)
+			if err != nil {
+				return nil, &httpErr{http.StatusBadRequest, log.Error(err), fmt.Sprintf("transaction %d is not valid json: %s", i, err.Error())}
+			}
+			input.ClientRequests = append(input.ClientRequests, clientRequest)
+		}
+	} else {
+		chunks, err := decodeChunks(data, MAX_TRANSACTIONS_IN_BATCH)
+		if err != nil {
+			return nil, &httpErr{http.StatusBadRequest, log.Error(err), "http request is not a valid batch: " + err.Error()}
+		}
+		for i, chunk := range chunks {
+			clientRequest := client.SendTransactionRequestReader(chunk)
+			if !clientRequest.IsValid() {
+				return nil, &httpErr{http.StatusBadRequest, nil, fmt.Sprintf("transaction %d is not a valid membuffer", i)}
+			}
+			input.ClientRequests = append(input.ClientRequests, clientRequest)
+		}
+	}
+
+	if len(input.ClientRequests) == 0 {
+		return nil, &httpErr{http.StatusBadRequest, nil, "batch holds no transactions"}
+	}
+	return input, nil
+}
@@ -0,0 +1,113 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeTransactionBatchSender struct {
+	services.MockPublicApi
+	receivedInput *publicapi.SendTransactionBatchInput
+}
+
+func (f *fakeTransactionBatchSender) SendTransactionBatch(ctx context.Context, input *publicapi.SendTransactionBatchInput) (*publicapi.SendTransactionBatchOutput, error) {
+	f.receivedInput = input
+	output := &publicapi.SendTransactionBatchOutput{}
+	for range input.ClientRequests {
+		output.ClientResponses = append(output.ClientResponses, (&client.SendTransactionResponseBuilder{
+			RequestStatus:     protocol.REQUEST_STATUS_IN_PROCESS,
+			TransactionStatus: protocol.TRANSACTION_STATUS_PENDING,
+		}).Build())
+	}
+	return output, nil
+}
+
+func serveSendTransactionBatch(s *server, query string, contentType string, body []byte) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/api/v1/send-transaction-batch"+query, bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	s.createRouter().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHttpServerSendTransactionBatch_Membuffers(t *testing.T) {
+	papi := &fakeTransactionBatchSender{}
+	s := makeServer(papi).(*server)
+
+	requests := [][]byte{
+		(&client.SendTransactionRequestBuilder{SignedTransaction: builders.TransferTransaction().Builder()}).Build().Raw(),
+		(&client.SendTransactionRequestBuilder{SignedTransaction: builders.TransferTransaction().Builder()}).Build().Raw(),
+	}
+	rec := serveSendTransactionBatch(s, "?wait-for-commit=true&timeout=3s", membuffersChunksContentType, encodeChunks(requests))
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	require.Len(t, papi.receivedInput.ClientRequests, 2, "every framed request should be admitted")
+	require.Equal(t, requests[1], []byte(papi.receivedInput.ClientRequests[1].Raw()))
+	require.True(t, papi.receivedInput.WaitForCommit, "wait-for-commit was not parsed from the query")
+	require.Equal(t, 3*time.Second, papi.receivedInput.WaitTimeout, "timeout was not parsed from the query")
+
+	responses := requireChunks(t, rec.Body.Bytes())
+	require.Len(t, responses, 2, "expected a response per transaction")
+	require.Equal(t, protocol.TRANSACTION_STATUS_PENDING, client.SendTransactionResponseReader(responses[0]).TransactionStatus())
+}
+
+func TestHttpServerSendTransactionBatch_Json(t *testing.T) {
+	papi := &fakeTransactionBatchSender{}
+	s := makeServer(papi).(*server)
+
+	document := fmt.Sprintf(`[{"SignedTransaction":{"Transaction":%s,"Signature":"abcd"}}]`, transferTransactionJson(keys.Ed25519KeyPairForTests(1).PublicKey(), "uint64", "10"))
+	rec := serveSendTransactionBatch(s, "", "application/json", []byte(document))
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	require.False(t, papi.receivedInput.WaitForCommit, "should not wait for commit unless asked to")
+	var results []*transactionStatusResponseJson
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+	require.Len(t, results, 1, "expected a response per transaction")
+	require.Equal(t, protocol.TRANSACTION_STATUS_PENDING.String(), results[0].TransactionStatus)
+}
+
+func TestHttpServerSendTransactionBatch_RejectsInvalidBatches(t *testing.T) {
+	s := makeServer(&fakeTransactionBatchSender{}).(*server)
+	validRequest := (&client.SendTransactionRequestBuilder{SignedTransaction: builders.TransferTransaction().Builder()}).Build().Raw()
+
+	tooMany := make([][]byte, MAX_TRANSACTIONS_IN_BATCH+1)
+	for i := range tooMany {
+		tooMany[i] = validRequest
+	}
+
+	for name, body := range map[string][]byte{
+		"empty batch":         encodeChunks(nil),
+		"truncated batch":     encodeChunks([][]byte{validRequest})[:10],
+		"too many":            encodeChunks(tooMany),
+		"invalid transaction": encodeChunks([][]byte{{0x01}}),
+	} {
+		rec := serveSendTransactionBatch(s, "", "", body)
+		require.Equal(t, http.StatusBadRequest, rec.Code, "%s should fail with 400", name)
+	}
+
+	rec := serveSendTransactionBatch(s, "?timeout=soon", "", encodeChunks([][]byte{validRequest}))
+	require.Equal(t, http.StatusBadRequest, rec.Code, "invalid timeout should fail with 400")
+}
+
+func TestHttpServerSendTransactionBatch_NotSupportedByPublicApi(t *testing.T) {
+	s := makeServer(&services.MockPublicApi{}).(*server)
+
+	rec := serveSendTransactionBatch(s, "", "", encodeChunks(nil))
+
+	require.Equal(t, http.StatusNotImplemented, rec.Code, "should fail with 501")
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/orbs-network/membuffers/go"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	blockStorageAdapter "github.com/orbs-network/orbs-network-go/services/blockstorage/adapter"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
 	"github.com/orbs-network/orbs-spec/types/go/services"
@@ -31,11 +32,13 @@ type HttpServer interface {
 }
 
 type server struct {
-	httpServer     *http.Server
-	logger         log.BasicLogger
-	publicApi      services.PublicApi
-	metricRegistry metric.Registry
-	port           int
+	httpServer       *http.Server
+	logger           log.BasicLogger
+	publicApi        services.PublicApi
+	blockStorage     services.BlockStorage
+	blockPersistence blockStorageAdapter.BlockPersistence
+	metricRegistry   metric.Registry
+	port             int
 }
 
 type tcpKeepAliveListener struct {
@@ -58,11 +61,13 @@ func (ln tcpKeepAliveListener) Accept() (net.Conn, error) {
 	return tc, nil
 }
 
-func NewHttpServer(address string, logger log.BasicLogger, publicApi services.PublicApi, metricRegistry metric.Registry) HttpServer {
+func NewHttpServer(address string, logger log.BasicLogger, publicApi services.PublicApi, blockStorage services.BlockStorage, blockPersistence blockStorageAdapter.BlockPersistence, metricRegistry metric.Registry) HttpServer {
 	server := &server{
-		logger:         logger.WithTags(LogTag),
-		publicApi:      publicApi,
-		metricRegistry: metricRegistry,
+		logger:           logger.WithTags(LogTag),
+		publicApi:        publicApi,
+		blockStorage:     blockStorage,
+		blockPersistence: blockPersistence,
+		metricRegistry:   metricRegistry,
 	}
 
 	if listener, err := server.listen(address); err != nil {
@@ -106,9 +111,17 @@ func (s *server) GracefulShutdown(timeout time.Duration) {
 func (s *server) createRouter() http.Handler {
 	router := http.NewServeMux()
 	router.Handle("/api/v1/send-transaction", http.HandlerFunc(s.sendTransactionHandler))
+	router.Handle("/api/v1/send-transaction-batch", http.HandlerFunc(s.sendTransactionBatchHandler))
 	router.Handle("/api/v1/call-method", http.HandlerFunc(s.callMethodHandler))
 	router.Handle("/api/v1/get-transaction-status", http.HandlerFunc(s.getTransactionStatusHandler))
+	router.Handle("/api/v1/subscribe-transaction-status", http.HandlerFunc(s.subscribeTransactionStatusHandler))
+	router.Handle("/api/v1/get-block", http.HandlerFunc(s.getBlockHandler))
+	router.Handle("/api/v1/get-block-header", http.HandlerFunc(s.getBlockHeaderHandler))
+	router.Handle("/api/v1/get-block-range", http.HandlerFunc(s.getBlockRangeHandler))
+	router.Handle("/api/v1/get-transaction-receipt-proof", http.HandlerFunc(s.getTransactionReceiptProofHandler))
+	router.Handle("/api/v1/get-events", http.HandlerFunc(s.getEventsHandler))
 	router.Handle("/metrics", http.HandlerFunc(s.dumpMetrics))
+	router.Handle("/metrics/prometheus", http.HandlerFunc(s.dumpMetricsAsPrometheus))
 	return router
 }
 
@@ -121,6 +134,14 @@ func (s *server) dumpMetrics(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *server) dumpMetricsAsPrometheus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, err := w.Write([]byte(s.metricRegistry.ExportPrometheus()))
+	if err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
 func (s *server) sendTransactionHandler(w http.ResponseWriter, r *http.Request) {
 	bytes, e := readInput(r)
 	if e != nil {
@@ -129,6 +150,13 @@ func (s *server) sendTransactionHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	clientRequest := client.SendTransactionRequestReader(bytes)
+	if isJsonRequest(r) {
+		var err error
+		if clientRequest, err = sendTransactionRequestFromJson(bytes); err != nil {
+			s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), "http request is not valid json: " + err.Error()})
+			return
+		}
+	}
 	if e := validate(clientRequest); e != nil {
 		s.writeErrorResponseAndLog(w, e)
 		return
@@ -137,7 +165,11 @@ func (s *server) sendTransactionHandler(w http.ResponseWriter, r *http.Request)
 	s.logger.Info("http server received send-transaction", log.Stringable("request", clientRequest))
 	result, err := s.publicApi.SendTransaction(r.Context(), &services.SendTransactionInput{ClientRequest: clientRequest})
 	if result != nil && result.ClientResponse != nil {
-		s.writeMembuffResponse(w, result.ClientResponse, translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringTransactionStatus())
+		if respondsWithJson(r) {
+			s.writeJsonResponse(w, sendTransactionResponseToJson(result.ClientResponse), translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringTransactionStatus())
+		} else {
+			s.writeMembuffResponse(w, result.ClientResponse, translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringTransactionStatus())
+		}
 	} else {
 		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), err.Error()})
 	}
@@ -151,6 +183,13 @@ func (s *server) callMethodHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	clientRequest := client.CallMethodRequestReader(bytes)
+	if isJsonRequest(r) {
+		var err error
+		if clientRequest, err = callMethodRequestFromJson(bytes); err != nil {
+			s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), "http request is not valid json: " + err.Error()})
+			return
+		}
+	}
 	if e := validate(clientRequest); e != nil {
 		s.writeErrorResponseAndLog(w, e)
 		return
@@ -159,7 +198,11 @@ func (s *server) callMethodHandler(w http.ResponseWriter, r *http.Request) {
 	s.logger.Info("http server received call-method", log.Stringable("request", clientRequest))
 	result, err := s.publicApi.CallMethod(r.Context(), &services.CallMethodInput{ClientRequest: clientRequest})
 	if result != nil && result.ClientResponse != nil {
-		s.writeMembuffResponse(w, result.ClientResponse, translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringCallMethodResult())
+		if respondsWithJson(r) {
+			s.writeJsonResponse(w, callMethodResponseToJson(result.ClientResponse), translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringCallMethodResult())
+		} else {
+			s.writeMembuffResponse(w, result.ClientResponse, translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringCallMethodResult())
+		}
 	} else {
 		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), err.Error()})
 	}
@@ -173,6 +216,13 @@ func (s *server) getTransactionStatusHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	clientRequest := client.GetTransactionStatusRequestReader(bytes)
+	if isJsonRequest(r) {
+		var err error
+		if clientRequest, err = getTransactionStatusRequestFromJson(bytes); err != nil {
+			s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), "http request is not valid json: " + err.Error()})
+			return
+		}
+	}
 	if e := validate(clientRequest); e != nil {
 		s.writeErrorResponseAndLog(w, e)
 		return
@@ -181,7 +231,11 @@ func (s *server) getTransactionStatusHandler(w http.ResponseWriter, r *http.Requ
 	s.logger.Info("http server received get-transaction-status", log.Stringable("request", clientRequest))
 	result, err := s.publicApi.GetTransactionStatus(r.Context(), &services.GetTransactionStatusInput{ClientRequest: clientRequest})
 	if result != nil && result.ClientResponse != nil {
-		s.writeMembuffResponse(w, result.ClientResponse, translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringTransactionStatus())
+		if respondsWithJson(r) {
+			s.writeJsonResponse(w, getTransactionStatusResponseToJson(result.ClientResponse), translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringTransactionStatus())
+		} else {
+			s.writeMembuffResponse(w, result.ClientResponse, translateStatusToHttpCode(result.ClientResponse.RequestStatus()), result.ClientResponse.StringTransactionStatus())
+		}
 	} else {
 		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), err.Error()})
 	}
@@ -236,6 +290,17 @@ func (s *server) writeMembuffResponse(w http.ResponseWriter, message membuffers.
 	}
 }
 
+func (s *server) writeJsonResponse(w http.ResponseWriter, document interface{}, httpCode int, orbsText string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-ORBS-CODE-NAME", orbsText)
+	w.WriteHeader(httpCode)
+	bytes, _ := json.Marshal(document)
+	_, err := w.Write(bytes)
+	if err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
 func (s *server) writeErrorResponseAndLog(w http.ResponseWriter, m *httpErr) {
 	if m.logField == nil {
 		s.logger.Info(m.message)
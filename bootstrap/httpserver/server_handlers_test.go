@@ -5,6 +5,7 @@ import (
 	"github.com/orbs-network/go-mock"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	blockStorageAdapter "github.com/orbs-network/orbs-network-go/services/blockstorage/adapter"
 	"github.com/orbs-network/orbs-network-go/test/builders"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -19,10 +20,14 @@ import (
 	"time"
 )
 
-func makeServer(papiMock *services.MockPublicApi) HttpServer {
+func makeServer(papi services.PublicApi) HttpServer {
+	return makeServerWithBlocks(papi, nil, nil)
+}
+
+func makeServerWithBlocks(papi services.PublicApi, blockStorage services.BlockStorage, blockPersistence blockStorageAdapter.BlockPersistence) HttpServer {
 	logger := log.GetLogger().WithOutput(log.NewFormattingOutput(os.Stdout, log.NewHumanReadableFormatter()))
 
-	return NewHttpServer("", logger, papiMock, metric.NewRegistry())
+	return NewHttpServer("", logger, papi, blockStorage, blockPersistence, metric.NewRegistry())
 }
 
 func TestHttpServerSendTxHandler_Basic(t *testing.T) {
@@ -149,3 +154,16 @@ func TestHttpServerGetTx_Error(t *testing.T) {
 
 	require.Equal(t, http.StatusInternalServerError, rec.Code, "should fail with 500")
 }
+
+func TestHttpServerDumpMetricsAsPrometheus(t *testing.T) {
+	s := makeServer(&services.MockPublicApi{})
+	s.(*server).metricRegistry.NewGauge("BlockStorage.BlockHeight").Update(3)
+
+	req, _ := http.NewRequest("GET", "/metrics/prometheus", nil)
+	rec := httptest.NewRecorder()
+	s.(*server).createRouter().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Equal(t, "# TYPE blockstorage_blockheight gauge\nblockstorage_blockheight 3\n", rec.Body.String())
+}
@@ -0,0 +1,115 @@
+package httpserver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// one server-sent event per status update, the receipt is the hex of its membuffer
+type transactionStatusEvent struct {
+	Txhash             string
+	RequestStatus      string
+	TransactionStatus  string
+	TransactionReceipt string `json:",omitempty"`
+	BlockHeight        uint64
+	BlockTimestamp     uint64
+}
+
+// GET /api/v1/subscribe-transaction-status?txhash=<hex>&timestamp=<nanos>&txhash=<hex>&timestamp=<nanos>&contract=<name>
+// streams text/event-stream until all watched transactions are resolved, or until the client disconnects when watching
+// a contract; the timestamps are optional but when given there must be one per txhash
+func (s *server) subscribeTransactionStatusHandler(w http.ResponseWriter, r *http.Request) {
+	subscriber, ok := s.publicApi.(publicapi.TransactionStatusSubscriber)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotImplemented, nil, "transaction status subscriptions are not supported"})
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, nil, "http response does not support streaming"})
+		return
+	}
+
+	filter, e := readTransactionStatusFilter(r)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	s.logger.Info("http server received subscribe-transaction-status", log.Int("transactions", len(filter.Txhashes)), log.String("contract", string(filter.ContractName)))
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	err := subscriber.SubscribeToTransactionStatus(r.Context(), filter, func(update *publicapi.TransactionStatusUpdate) error {
+		data, err := json.Marshal(toTransactionStatusEvent(update))
+		if err != nil {
+			return err
+		}
+		return writeServerSentEvent(w, flusher, "transaction-status", string(data))
+	})
+	if err != nil {
+		s.logger.Info("transaction status subscription failed", log.Error(err))
+		if err := writeServerSentEvent(w, flusher, "error", err.Error()); err != nil {
+			s.logger.Info("error writing response", log.Error(err))
+		}
+	}
+}
+
+func readTransactionStatusFilter(r *http.Request) (*publicapi.TransactionStatusFilter, *httpErr) {
+	query := r.URL.Query()
+	filter := &publicapi.TransactionStatusFilter{ContractName: primitives.ContractName(query.Get("contract"))}
+	for _, txHashHex := range query["txhash"] {
+		txHash, err := hex.DecodeString(strings.TrimPrefix(txHashHex, "0x"))
+		if err != nil {
+			return nil, &httpErr{http.StatusBadRequest, log.Error(err), fmt.Sprintf("txhash %s is not valid hex", txHashHex)}
+		}
+		filter.Txhashes = append(filter.Txhashes, txHash)
+	}
+	for _, timestamp := range query["timestamp"] {
+		nanos, err := strconv.ParseUint(timestamp, 10, 64)
+		if err != nil {
+			return nil, &httpErr{http.StatusBadRequest, log.Error(err), fmt.Sprintf("timestamp %s is not a number", timestamp)}
+		}
+		filter.TransactionTimestamps = append(filter.TransactionTimestamps, primitives.TimestampNano(nanos))
+	}
+	if len(filter.TransactionTimestamps) > 0 && len(filter.TransactionTimestamps) != len(filter.Txhashes) {
+		return nil, &httpErr{http.StatusBadRequest, nil, "subscription must have a timestamp for every txhash or none at all"}
+	}
+
+	if len(filter.Txhashes) == 0 && filter.ContractName == "" {
+		return nil, &httpErr{http.StatusBadRequest, nil, "subscription must watch at least one txhash or a contract"}
+	}
+	return filter, nil
+}
+
+func toTransactionStatusEvent(update *publicapi.TransactionStatusUpdate) *transactionStatusEvent {
+	event := &transactionStatusEvent{
+		Txhash:            hex.EncodeToString(update.Txhash),
+		RequestStatus:     update.RequestStatus.String(),
+		TransactionStatus: update.TransactionStatus.String(),
+		BlockHeight:       uint64(update.BlockHeight),
+		BlockTimestamp:    uint64(update.BlockTimestamp),
+	}
+	if update.TransactionReceipt != nil {
+		event.TransactionReceipt = hex.EncodeToString(update.TransactionReceipt.Raw())
+	}
+	return event
+}
+
+func writeServerSentEvent(w http.ResponseWriter, flusher http.Flusher, event string, data string) error {
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, strings.Replace(data, "\n", " ", -1)); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
@@ -0,0 +1,75 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/hex"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeTransactionStatusSubscriber struct {
+	services.MockPublicApi
+	updates        []*publicapi.TransactionStatusUpdate
+	receivedFilter *publicapi.TransactionStatusFilter
+}
+
+func (f *fakeTransactionStatusSubscriber) SubscribeToTransactionStatus(ctx context.Context, filter *publicapi.TransactionStatusFilter, onStatus func(update *publicapi.TransactionStatusUpdate) error) error {
+	f.receivedFilter = filter
+	for _, update := range f.updates {
+		if err := onStatus(update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestHttpServerSubscribeTransactionStatus_StreamsUpdatesAsServerSentEvents(t *testing.T) {
+	txHash := primitives.Sha256{0x01, 0x02}
+	receipt := builders.TransactionReceipt().Build()
+	papi := &fakeTransactionStatusSubscriber{updates: []*publicapi.TransactionStatusUpdate{
+		{Txhash: txHash, RequestStatus: protocol.REQUEST_STATUS_COMPLETED, TransactionStatus: protocol.TRANSACTION_STATUS_COMMITTED, TransactionReceipt: receipt, BlockHeight: 7, BlockTimestamp: 8},
+	}}
+	s := makeServer(papi)
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscribe-transaction-status?txhash=0x0102&timestamp=1500&contract=BenchmarkToken", nil)
+	rec := httptest.NewRecorder()
+	s.(*server).createRouter().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+	require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	require.Equal(t, []primitives.Sha256{txHash}, papi.receivedFilter.Txhashes, "txhash was not parsed from the query")
+	require.Equal(t, []primitives.TimestampNano{1500}, papi.receivedFilter.TransactionTimestamps, "timestamp was not parsed from the query")
+	require.EqualValues(t, "BenchmarkToken", papi.receivedFilter.ContractName, "contract was not parsed from the query")
+	require.Equal(t, "event: transaction-status\n"+
+		`data: {"Txhash":"0102","RequestStatus":"REQUEST_STATUS_COMPLETED","TransactionStatus":"TRANSACTION_STATUS_COMMITTED","TransactionReceipt":"`+hex.EncodeToString(receipt.Raw())+`","BlockHeight":7,"BlockTimestamp":8}`+"\n\n",
+		rec.Body.String())
+}
+
+func TestHttpServerSubscribeTransactionStatus_RejectsInvalidFilter(t *testing.T) {
+	s := makeServer(&fakeTransactionStatusSubscriber{})
+
+	for _, query := range []string{"", "?txhash=not-hex", "?txhash=0102&timestamp=not-a-number", "?txhash=0102&txhash=0304&timestamp=1500"} {
+		req, _ := http.NewRequest("GET", "/api/v1/subscribe-transaction-status"+query, nil)
+		rec := httptest.NewRecorder()
+		s.(*server).createRouter().ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code, "should fail with 400 for query '%s'", query)
+	}
+}
+
+func TestHttpServerSubscribeTransactionStatus_NotImplementedWithoutSubscriber(t *testing.T) {
+	s := makeServer(&services.MockPublicApi{})
+
+	req, _ := http.NewRequest("GET", "/api/v1/subscribe-transaction-status?txhash=0102", nil)
+	rec := httptest.NewRecorder()
+	s.(*server).createRouter().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNotImplemented, rec.Code, "should fail with 501")
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/orbs-network/orbs-network-go/test/harness/contracts"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	ethereumAdapter "github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/adapter"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	nativeProcessorAdapter "github.com/orbs-network/orbs-network-go/services/processor/native/adapter"
+	txPoolAdapter "github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
 	"github.com/orbs-network/orbs-network-go/synchronization/supervised"
 	"github.com/orbs-network/orbs-network-go/test"
 	"github.com/orbs-network/orbs-network-go/test/builders"
@@ -25,13 +27,16 @@ import (
 type NetworkDriver interface {
 	contracts.ContractAPI
 	PublicApi(nodeIndex int) services.PublicApi
+	BlockStorage(nodeIndex int) services.BlockStorage
+	GetBlockPersistence(nodeIndex int) blockStorageAdapter.InMemoryBlockPersistence
 	Size() int
 }
 
 type Network struct {
-	Nodes     []*Node
-	Logger    log.BasicLogger
-	Transport adapter.Transport
+	Nodes              []*Node
+	Logger             log.BasicLogger
+	Transport          adapter.Transport
+	EthereumConnection ethereumAdapter.EthereumConnection // shared by all nodes since they read the same ethereum chain
 }
 
 type Node struct {
@@ -40,13 +45,14 @@ type Node struct {
 	config           config.NodeConfig
 	blockPersistence blockStorageAdapter.InMemoryBlockPersistence
 	statePersistence stateStorageAdapter.TamperingStatePersistence
+	txPoolJournal    txPoolAdapter.PendingTransactionJournal
 	nativeCompiler   nativeProcessorAdapter.Compiler
 	nodeLogic        bootstrap.NodeLogic
 	metricRegistry   metric.Registry
 }
 
-func NewNetwork(logger log.BasicLogger, transport adapter.Transport) Network {
-	return Network{Logger: logger, Transport: transport}
+func NewNetwork(logger log.BasicLogger, transport adapter.Transport, ethereumConnection ethereumAdapter.EthereumConnection) Network {
+	return Network{Logger: logger, Transport: transport, EthereumConnection: ethereumConnection}
 }
 
 func (n *Network) AddNode(nodeKeyPair *keys.Ed25519KeyPair, cfg config.NodeConfig, compiler nativeProcessorAdapter.Compiler) {
@@ -55,9 +61,10 @@ func (n *Network) AddNode(nodeKeyPair *keys.Ed25519KeyPair, cfg config.NodeConfi
 	node.name = fmt.Sprintf("%s", nodeKeyPair.PublicKey()[:3])
 	node.config = cfg
 	node.statePersistence = stateStorageAdapter.NewTamperingStatePersistence()
+	node.txPoolJournal = txPoolAdapter.NewInMemoryPendingTransactionJournal()
 	node.blockPersistence = blockStorageAdapter.NewInMemoryBlockPersistence()
 	node.nativeCompiler = compiler
-	node.metricRegistry = metric.NewRegistry()
+	node.metricRegistry = metric.NewRegistry().WithVirtualChainId(cfg.VirtualChainId()).WithNodePublicKey(nodeKeyPair.PublicKey())
 
 	n.Nodes = append(n.Nodes, node)
 }
@@ -69,7 +76,9 @@ func (n *Network) CreateAndStartNodes(ctx context.Context) {
 			n.Transport,
 			node.blockPersistence,
 			node.statePersistence,
+			node.txPoolJournal,
 			node.nativeCompiler,
+			n.EthereumConnection,
 			n.Logger.WithTags(log.Node(node.name)),
 			node.metricRegistry,
 			node.config,
@@ -98,6 +107,10 @@ func (n *Network) PublicApi(nodeIndex int) services.PublicApi {
 	return n.Nodes[nodeIndex].nodeLogic.PublicApi()
 }
 
+func (n *Network) BlockStorage(nodeIndex int) services.BlockStorage {
+	return n.Nodes[nodeIndex].nodeLogic.BlockStorage()
+}
+
 func (n *Network) GetBlockPersistence(nodeIndex int) blockStorageAdapter.InMemoryBlockPersistence {
 	return n.Nodes[nodeIndex].blockPersistence
 }
@@ -6,10 +6,12 @@ import (
 	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	blockStorageAdapter "github.com/orbs-network/orbs-network-go/services/blockstorage/adapter"
+	ethereumAdapter "github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/adapter"
 	gossipAdapter "github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	nativeProcessorAdapter "github.com/orbs-network/orbs-network-go/services/processor/native/adapter"
 	stateStorageAdapter "github.com/orbs-network/orbs-network-go/services/statestorage/adapter"
-	blockStorageAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/blockstorage/adapter"
+	txPoolAdapter "github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
 	"sync"
 	"time"
 )
@@ -20,30 +22,50 @@ type Node interface {
 }
 
 type node struct {
-	httpServer   httpserver.HttpServer
-	logic        NodeLogic
-	shutdownCond *sync.Cond
-	ctxCancel    context.CancelFunc
+	httpServer         httpserver.HttpServer
+	logic              NodeLogic
+	gossipPeersUpdater interface{} // only needed so that the updater doesn't get GCed
+	shutdownCond       *sync.Cond
+	ctxCancel          context.CancelFunc
 }
 
 func NewNode(nodeConfig config.NodeConfig, logger log.BasicLogger, httpAddress string) Node {
 	ctx, ctxCancel := context.WithCancel(context.Background())
 
 	nodeLogger := logger.WithTags(log.Node(nodeConfig.NodePublicKey().String()))
-	metricRegistry := metric.NewRegistry()
+	metricRegistry := metric.NewRegistry().WithVirtualChainId(nodeConfig.VirtualChainId()).WithNodePublicKey(nodeConfig.NodePublicKey())
 
 	transport := gossipAdapter.NewDirectTransport(ctx, nodeConfig, nodeLogger)
-	blockPersistence := blockStorageAdapter.NewInMemoryBlockPersistence()
-	statePersistence := stateStorageAdapter.NewInMemoryStatePersistence()
+	blockPersistence, err := blockStorageAdapter.NewFilesystemBlockPersistence(nodeConfig, nodeLogger)
+	if err != nil {
+		nodeLogger.Error("failed to open block persistence", log.Error(err))
+		panic(err)
+	}
+	statePersistence, err := stateStorageAdapter.NewFilesystemStatePersistence(nodeConfig, nodeLogger)
+	if err != nil {
+		nodeLogger.Error("failed to open state persistence", log.Error(err))
+		panic(err)
+	}
+	var transactionPoolJournal txPoolAdapter.PendingTransactionJournal // the journal is optional, without it pending transactions are lost on restart
+	if nodeConfig.TransactionPoolJournalDataDir() != "" {
+		transactionPoolJournal, err = txPoolAdapter.NewFilesystemPendingTransactionJournal(nodeConfig, nodeLogger)
+		if err != nil {
+			nodeLogger.Error("failed to open transaction pool journal", log.Error(err))
+			panic(err)
+		}
+	}
 	nativeCompiler := nativeProcessorAdapter.NewNativeCompiler(nodeConfig, nodeLogger)
-	nodeLogic := NewNodeLogic(ctx, transport, blockPersistence, statePersistence, nativeCompiler, nodeLogger, metricRegistry, nodeConfig)
-	httpServer := httpserver.NewHttpServer(httpAddress, nodeLogger, nodeLogic.PublicApi(), metricRegistry)
+	ethereumConnection := ethereumAdapter.NewEthereumRpcConnection(nodeConfig, nodeLogger)
+	nodeLogic := NewNodeLogic(ctx, transport, blockPersistence, statePersistence, transactionPoolJournal, nativeCompiler, ethereumConnection, nodeLogger, metricRegistry, nodeConfig)
+	httpServer := httpserver.NewHttpServer(httpAddress, nodeLogger, nodeLogic.PublicApi(), nodeLogic.BlockStorage(), blockPersistence, metricRegistry)
+	gossipPeersUpdater := startUpdatingGossipPeers(ctx, transport, nodeLogic.BlockStorage(), nodeConfig, nodeLogger, metricRegistry)
 
 	return &node{
-		logic:        nodeLogic,
-		httpServer:   httpServer,
-		shutdownCond: sync.NewCond(&sync.Mutex{}),
-		ctxCancel:    ctxCancel,
+		logic:              nodeLogic,
+		httpServer:         httpServer,
+		gossipPeersUpdater: gossipPeersUpdater,
+		shutdownCond:       sync.NewCond(&sync.Mutex{}),
+		ctxCancel:          ctxCancel,
 	}
 }
 
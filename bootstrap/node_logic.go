@@ -2,6 +2,7 @@ package bootstrap
 
 import (
 	"context"
+	"fmt"
 	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
@@ -11,6 +12,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/services/consensusalgo/leanhelixconsensus"
 	"github.com/orbs-network/orbs-network-go/services/consensuscontext"
 	"github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum"
+	ethereumAdapter "github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/adapter"
 	"github.com/orbs-network/orbs-network-go/services/gossip"
 	gossipAdapter "github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	"github.com/orbs-network/orbs-network-go/services/processor/native"
@@ -19,17 +21,21 @@ import (
 	"github.com/orbs-network/orbs-network-go/services/statestorage"
 	stateStorageAdapter "github.com/orbs-network/orbs-network-go/services/statestorage/adapter"
 	"github.com/orbs-network/orbs-network-go/services/transactionpool"
+	txPoolAdapter "github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
 	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
 	"github.com/orbs-network/orbs-spec/types/go/services"
 )
 
 type NodeLogic interface {
 	PublicApi() services.PublicApi
+	BlockStorage() services.BlockStorage
 }
 
 type nodeLogic struct {
 	publicApi       services.PublicApi
+	blockStorage    services.BlockStorage
 	consensusAlgos  []services.ConsensusAlgo
 	runtimeReporter interface{} // only needed so that the runtime reporter doesn't get GCed
 }
@@ -39,7 +45,9 @@ func NewNodeLogic(
 	gossipTransport gossipAdapter.Transport,
 	blockPersistence blockStorageAdapter.BlockPersistence,
 	statePersistence stateStorageAdapter.StatePersistence,
+	transactionPoolJournal txPoolAdapter.PendingTransactionJournal,
 	nativeCompiler nativeProcessorAdapter.Compiler,
+	ethereumConnection ethereumAdapter.EthereumConnection,
 	logger log.BasicLogger,
 	metricRegistry metric.Registry,
 	nodeConfig config.NodeConfig,
@@ -49,32 +57,35 @@ func NewNodeLogic(
 	processors[protocol.PROCESSOR_TYPE_NATIVE] = native.NewNativeProcessor(nativeCompiler, logger, metricRegistry)
 
 	crosschainConnectors := make(map[protocol.CrosschainConnectorType]services.CrosschainConnector)
-	crosschainConnectors[protocol.CROSSCHAIN_CONNECTOR_TYPE_ETHEREUM] = ethereum.NewEthereumCrosschainConnector()
+	crosschainConnectors[protocol.CROSSCHAIN_CONNECTOR_TYPE_ETHEREUM] = ethereum.NewEthereumCrosschainConnector(ethereumConnection, nodeConfig, logger)
 
 	gossipService := gossip.NewGossip(gossipTransport, nodeConfig, logger)
 	stateStorageService := statestorage.NewStateStorage(nodeConfig, statePersistence, logger)
-	virtualMachineService := virtualmachine.NewVirtualMachine(stateStorageService, processors, crosschainConnectors, logger)
-	transactionPoolService := transactionpool.NewTransactionPool(ctx, gossipService, virtualMachineService, nodeConfig, logger, metricRegistry)
+	virtualMachineService := virtualmachine.NewVirtualMachine(nodeConfig, stateStorageService, processors, crosschainConnectors, logger)
+	transactionPoolService := transactionpool.NewTransactionPool(ctx, gossipService, virtualMachineService, transactionPoolJournal, blockStorageAdapter.NewBlockPersistenceTransactionLookup(blockPersistence, nodeConfig), nodeConfig, logger, metricRegistry)
 	blockStorageService := blockstorage.NewBlockStorage(ctx, nodeConfig, blockPersistence, stateStorageService, gossipService, transactionPoolService, logger, metricRegistry)
-	publicApiService := publicapi.NewPublicApi(nodeConfig, transactionPoolService, virtualMachineService, blockStorageService, logger, metricRegistry)
-	consensusContextService := consensuscontext.NewConsensusContext(transactionPoolService, virtualMachineService, nil, nodeConfig, logger, metricRegistry)
+	publicApiService := publicapi.NewPublicApi(nodeConfig, transactionPoolService, virtualMachineService, blockStorageService, blockPersistence, logger, metricRegistry)
+	consensusContextService := consensuscontext.NewConsensusContext(transactionPoolService, virtualMachineService, stateStorageService, nodeConfig, logger, metricRegistry)
 
-	// TODO Uncomment and append to consensusAlgo when you want to integrate Lean Helix.
-	// TODO For now, NewLeanHelixConsensusAlgo() is executed to ensure compilation
-	/*leanHelixAlgo := */
-	leanhelixconsensus.NewLeanHelixConsensusAlgo(ctx, gossipService, blockStorageService, consensusContextService, logger, nodeConfig, metricRegistry)
-	benchmarkConsensusAlgo := benchmarkconsensus.NewBenchmarkConsensusAlgo(ctx, gossipService, blockStorageService, consensusContextService, logger, nodeConfig, metricRegistry)
-
-	// TODO: Restore this when lean-helix-go submodule is integrated
+	// only the active algo is created, block storage hands each committed block to the first algo that accepts it
 	consensusAlgos := make([]services.ConsensusAlgo, 0)
-	//consensusAlgos = append(consensusAlgos, leanHelixAlgo)
-	consensusAlgos = append(consensusAlgos, benchmarkConsensusAlgo)
+	switch nodeConfig.ActiveConsensusAlgo() {
+	case consensus.CONSENSUS_ALGO_TYPE_LEAN_HELIX:
+		leanHelixAlgo := leanhelixconsensus.NewLeanHelixConsensusAlgo(ctx, gossipService, blockStorageService, consensusContextService, logger, nodeConfig, metricRegistry)
+		consensusAlgos = append(consensusAlgos, leanHelixAlgo)
+	case consensus.CONSENSUS_ALGO_TYPE_BENCHMARK_CONSENSUS:
+		benchmarkConsensusAlgo := benchmarkconsensus.NewBenchmarkConsensusAlgo(ctx, gossipService, blockStorageService, consensusContextService, logger, nodeConfig, metricRegistry)
+		consensusAlgos = append(consensusAlgos, benchmarkConsensusAlgo)
+	default:
+		panic(fmt.Sprintf("unsupported consensus algo %s", nodeConfig.ActiveConsensusAlgo()))
+	}
 
 	runtimeReporter := metric.NewRuntimeReporter(ctx, metricRegistry, logger)
 	metricRegistry.ReportEvery(ctx, nodeConfig.MetricsReportInterval(), logger)
 
 	return &nodeLogic{
 		publicApi:       publicApiService,
+		blockStorage:    blockStorageService,
 		consensusAlgos:  consensusAlgos,
 		runtimeReporter: runtimeReporter,
 	}
@@ -83,3 +94,7 @@ func NewNodeLogic(
 func (n *nodeLogic) PublicApi() services.PublicApi {
 	return n.publicApi
 }
+
+func (n *nodeLogic) BlockStorage() services.BlockStorage {
+	return n.blockStorage
+}
@@ -35,9 +35,12 @@ type NodeConfig interface {
 	BlockTransactionReceiptQueryGraceEnd() time.Duration
 	BlockTransactionReceiptQueryExpirationWindow() time.Duration
 	BlockSyncCollectChunksTimeout() time.Duration
+	BlockStorageDataDir() string
 
 	// state storage
 	StateStorageHistorySnapshotNum() uint32
+	StateStorageDataDir() string
+	StateStorageMerkleCacheSize() uint32
 
 	// block tracker
 	BlockTrackerGraceDistance() uint32
@@ -47,6 +50,7 @@ type NodeConfig interface {
 	ConsensusContextMinimalBlockTime() time.Duration
 	ConsensusContextMinimumTransactionsInBlock() uint32
 	ConsensusContextMaximumTransactionsInBlock() uint32
+	ConsensusContextSystemTimestampAllowedJitter() time.Duration
 
 	// transaction pool
 	TransactionPoolPendingPoolSizeInBytes() uint32
@@ -56,18 +60,42 @@ type NodeConfig interface {
 	TransactionPoolCommittedPoolClearExpiredInterval() time.Duration
 	TransactionPoolPropagationBatchSize() uint16
 	TransactionPoolPropagationBatchingTimeout() time.Duration
+	TransactionPoolSignatureCacheSize() uint32
+	TransactionPoolOrderingPolicy() string
+	TransactionPoolPrioritySigners() string
+	TransactionPoolMaxPendingTransactionsPerSigner() uint32
+	TransactionPoolPreOrderFailureMinBackoff() time.Duration
+	TransactionPoolPreOrderFailureMaxBackoff() time.Duration
+	TransactionPoolJournalDataDir() string
 
 	// gossip
 	GossipListenPort() uint16
 	GossipConnectionKeepAliveInterval() time.Duration
 	GossipNetworkTimeout() time.Duration
+	GossipTransportSecurity() string
+	GossipPeersUpdateInterval() time.Duration
 
 	// public api
 	SendTransactionTimeout() time.Duration
 
+	// virtual machine
+	VirtualMachineMaxCallStackDepth() uint32
+	VirtualMachineSdkCallBudget() uint32
+	VirtualMachineStateReadBudgetInBytes() uint32
+	VirtualMachineStateWriteBudgetInBytes() uint32
+	VirtualMachineInstructionBudget() uint32
+	VirtualMachineAllocationBudgetInBytes() uint32
+	VirtualMachineMaxParallelTransactions() uint32
+	VirtualMachineProcessTransactionSetTimeout() time.Duration
+
 	// processor
 	ProcessorArtifactPath() string
 
+	// ethereum crosschain connector
+	EthereumEndpoint() string
+	EthereumFinalityBlocksComponent() uint32
+	EthereumFinalityTimeComponent() time.Duration
+
 	// metrics
 	MetricsReportInterval() time.Duration
 }
@@ -80,6 +108,7 @@ type mutableNodeConfig interface {
 	SetString(key string, value string) mutableNodeConfig
 	SetFederationNodes(nodes map[string]FederationNode) mutableNodeConfig
 	SetGossipPeers(peers map[string]GossipPeer) mutableNodeConfig
+	SetFederationNodesAsOfBlock(asOfBlock uint64, nodes map[string]FederationNode, peers map[string]GossipPeer) mutableNodeConfig
 	SetNodePublicKey(key primitives.Ed25519PublicKey) mutableNodeConfig
 	SetNodePrivateKey(key primitives.Ed25519PrivateKey) mutableNodeConfig
 	SetConstantConsensusLeader(key primitives.Ed25519PublicKey) mutableNodeConfig
@@ -106,20 +135,37 @@ type BlockStorageConfig interface {
 	BlockTransactionReceiptQueryExpirationWindow() time.Duration
 }
 
+type FilesystemBlockPersistenceConfig interface {
+	BlockStorageDataDir() string
+}
+
+type FilesystemStatePersistenceConfig interface {
+	StateStorageDataDir() string
+}
+
+type FilesystemPendingTransactionJournalConfig interface {
+	TransactionPoolJournalDataDir() string
+}
+
 type GossipTransportConfig interface {
 	NodePublicKey() primitives.Ed25519PublicKey
+	NodePrivateKey() primitives.Ed25519PrivateKey
 	GossipPeers(asOfBlock uint64) map[string]GossipPeer
 	GossipListenPort() uint16
 	GossipConnectionKeepAliveInterval() time.Duration
 	GossipNetworkTimeout() time.Duration
+	GossipTransportSecurity() string
+	GossipPeersUpdateInterval() time.Duration
 }
 
 // TODO See if more config props needed here, based on:
 // https://github.com/orbs-network/orbs-spec/blob/master/behaviors/config/services.md#consensus-context
 type ConsensusContextConfig interface {
+	VirtualChainId() primitives.VirtualChainId
 	ConsensusContextMaximumTransactionsInBlock() uint32
 	ConsensusContextMinimumTransactionsInBlock() uint32
 	ConsensusContextMinimalBlockTime() time.Duration
+	ConsensusContextSystemTimestampAllowedJitter() time.Duration
 	FederationNodes(asOfBlock uint64) map[string]FederationNode
 	ConsensusMinimumCommitteeSize() uint32
 }
@@ -129,8 +175,20 @@ type PublicApiConfig interface {
 	VirtualChainId() primitives.VirtualChainId
 }
 
+type VirtualMachineConfig interface {
+	VirtualMachineMaxCallStackDepth() uint32
+	VirtualMachineSdkCallBudget() uint32
+	VirtualMachineStateReadBudgetInBytes() uint32
+	VirtualMachineStateWriteBudgetInBytes() uint32
+	VirtualMachineInstructionBudget() uint32
+	VirtualMachineAllocationBudgetInBytes() uint32
+	VirtualMachineMaxParallelTransactions() uint32
+	VirtualMachineProcessTransactionSetTimeout() time.Duration
+}
+
 type StateStorageConfig interface {
 	StateStorageHistorySnapshotNum() uint32
+	StateStorageMerkleCacheSize() uint32
 	BlockTrackerGraceDistance() uint32
 	BlockTrackerGraceTimeout() time.Duration
 }
@@ -148,6 +206,18 @@ type TransactionPoolConfig interface {
 	TransactionPoolCommittedPoolClearExpiredInterval() time.Duration
 	TransactionPoolPropagationBatchSize() uint16
 	TransactionPoolPropagationBatchingTimeout() time.Duration
+	TransactionPoolSignatureCacheSize() uint32
+	TransactionPoolOrderingPolicy() string
+	TransactionPoolPrioritySigners() string
+	TransactionPoolMaxPendingTransactionsPerSigner() uint32
+	TransactionPoolPreOrderFailureMinBackoff() time.Duration
+	TransactionPoolPreOrderFailureMaxBackoff() time.Duration
+}
+
+type EthereumCrosschainConnectorConfig interface {
+	EthereumEndpoint() string
+	EthereumFinalityBlocksComponent() uint32
+	EthereumFinalityTimeComponent() time.Duration
 }
 
 type FederationNode interface {
@@ -76,6 +76,33 @@ func parseNodesAndPeers(value interface{}) (nodes map[string]FederationNode, pee
 	return nodes, peers, nil
 }
 
+// each change is {"as-of-block": <height>, "federation-nodes": [...]} and replaces the whole federation from that height on
+func parseFederationChanges(cfg mutableNodeConfig, value interface{}) error {
+	changes, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("federation changes must be a list")
+	}
+
+	for _, item := range changes {
+		kv, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("federation change must be an object")
+		}
+		asOfBlock, ok := kv["as-of-block"].(float64)
+		if !ok {
+			return fmt.Errorf("federation change is missing as-of-block")
+		}
+
+		nodes, peers, err := parseNodesAndPeers(kv["federation-nodes"])
+		if err != nil {
+			return err
+		}
+		cfg.SetFederationNodesAsOfBlock(uint64(asOfBlock), nodes, peers)
+	}
+
+	return nil
+}
+
 func populateConfig(cfg mutableNodeConfig, data map[string]interface{}) (error) {
 	for key, value := range data {
 		var duration time.Duration
@@ -120,6 +147,41 @@ func populateConfig(cfg mutableNodeConfig, data map[string]interface{}) (error)
 			cfg.SetNodePrivateKey(primitives.Ed25519PrivateKey(privateKey))
 		}
 
+		if key == "block-storage-data-dir" {
+			err = nil
+			cfg.SetString(BLOCK_STORAGE_DATA_DIR, value.(string))
+		}
+
+		if key == "state-storage-data-dir" {
+			err = nil
+			cfg.SetString(STATE_STORAGE_DATA_DIR, value.(string))
+		}
+
+		if key == "transaction-pool-journal-data-dir" {
+			err = nil
+			cfg.SetString(TRANSACTION_POOL_JOURNAL_DATA_DIR, value.(string))
+		}
+
+		if key == "ethereum-endpoint" {
+			err = nil
+			cfg.SetString(ETHEREUM_ENDPOINT, value.(string))
+		}
+
+		if key == "transaction-pool-ordering-policy" {
+			err = nil
+			cfg.SetString(TRANSACTION_POOL_ORDERING_POLICY, value.(string))
+		}
+
+		if key == "transaction-pool-priority-signers" {
+			err = nil
+			cfg.SetString(TRANSACTION_POOL_PRIORITY_SIGNERS, value.(string))
+		}
+
+		if key == "gossip-transport-security" {
+			err = nil
+			cfg.SetString(GOSSIP_TRANSPORT_SECURITY, value.(string))
+		}
+
 		if key == "gossip-port" {
 			var gossipPort uint32
 			gossipPort, err = parseUint32(value.(float64))
@@ -135,6 +197,10 @@ func populateConfig(cfg mutableNodeConfig, data map[string]interface{}) (error)
 			cfg.SetGossipPeers(peers)
 		}
 
+		if key == "federation-changes" {
+			err = parseFederationChanges(cfg, value)
+		}
+
 		if err != nil {
 			return fmt.Errorf("could not decode value for config key %s: %s", key, err)
 		}
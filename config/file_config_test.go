@@ -114,6 +114,35 @@ func TestSetGossipPeers(t *testing.T) {
 	require.EqualValues(t, node1, cfg.GossipPeers(0)[keyPair.PublicKey().KeyForMap()])
 }
 
+func TestSetFederationChanges(t *testing.T) {
+	cfg, err := newEmptyFileConfig(`{
+	"federation-nodes": [
+		{"Key":"dfc06c5be24a67adee80b35ab4f147bb1a35c55ff85eda69f40ef827bddec173","IP":"192.168.199.2","Port":4400},
+		{"Key":"92d469d7c004cc0b24a192d9457836bf38effa27536627ef60718b00b0f33152","IP":"192.168.199.3","Port":4400}
+	],
+	"federation-changes": [
+		{"as-of-block": 20, "federation-nodes": [
+			{"Key":"a899b318e65915aa2de02841eeb72fe51fddad96014b73800ca788a547f8cce0","IP":"192.168.199.4","Port":4400}
+		]},
+		{"as-of-block": 10, "federation-nodes": [
+			{"Key":"dfc06c5be24a67adee80b35ab4f147bb1a35c55ff85eda69f40ef827bddec173","IP":"192.168.199.2","Port":4400},
+			{"Key":"92d469d7c004cc0b24a192d9457836bf38effa27536627ef60718b00b0f33152","IP":"192.168.199.3","Port":4400},
+			{"Key":"a899b318e65915aa2de02841eeb72fe51fddad96014b73800ca788a547f8cce0","IP":"192.168.199.4","Port":4400}
+		]}
+	]
+}`)
+
+	require.NotNil(t, cfg)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, len(cfg.FederationNodes(9)), "initial federation should hold until the first change")
+	require.EqualValues(t, 3, len(cfg.FederationNodes(10)), "node should be added as of block 10")
+	require.EqualValues(t, 3, len(cfg.GossipPeers(19)))
+	require.EqualValues(t, 1, cfg.NetworkSize(20), "nodes should be removed as of block 20")
+
+	keyPair := keys.Ed25519KeyPairForTests(2)
+	require.EqualValues(t, &hardCodedGossipPeer{gossipEndpoint: "192.168.199.4", gossipPort: 4400}, cfg.GossipPeers(20)[keyPair.PublicKey().KeyForMap()])
+}
+
 func TestSetGossipPort(t *testing.T) {
 	cfg, err := newEmptyFileConfig(`{"gossip-port": 4500}`)
 
@@ -122,6 +151,58 @@ func TestSetGossipPort(t *testing.T) {
 	require.EqualValues(t, 4500, cfg.GossipListenPort())
 }
 
+func TestSetBlockStorageDataDir(t *testing.T) {
+	cfg, err := newEmptyFileConfig(`{"block-storage-data-dir": "/var/lib/orbs/blocks"}`)
+
+	require.NotNil(t, cfg)
+	require.NoError(t, err)
+	require.EqualValues(t, "/var/lib/orbs/blocks", cfg.BlockStorageDataDir())
+}
+
+func TestSetStateStorageDataDir(t *testing.T) {
+	cfg, err := newEmptyFileConfig(`{"state-storage-data-dir": "/var/lib/orbs/state"}`)
+
+	require.NotNil(t, cfg)
+	require.NoError(t, err)
+	require.EqualValues(t, "/var/lib/orbs/state", cfg.StateStorageDataDir())
+}
+
+func TestSetTransactionPoolJournalDataDir(t *testing.T) {
+	cfg, err := newEmptyFileConfig(`{"transaction-pool-journal-data-dir": "/var/lib/orbs/transaction-pool-journal"}`)
+
+	require.NotNil(t, cfg)
+	require.NoError(t, err)
+	require.EqualValues(t, "/var/lib/orbs/transaction-pool-journal", cfg.TransactionPoolJournalDataDir())
+}
+
+func TestSetEthereumEndpoint(t *testing.T) {
+	cfg, err := newEmptyFileConfig(`{"ethereum-endpoint": "http://172.31.1.100:8545", "ethereum-finality-blocks-component": 50, "ethereum-finality-time-component": "2m"}`)
+
+	require.NotNil(t, cfg)
+	require.NoError(t, err)
+	require.EqualValues(t, "http://172.31.1.100:8545", cfg.EthereumEndpoint())
+	require.EqualValues(t, 50, cfg.EthereumFinalityBlocksComponent())
+	require.EqualValues(t, 2*time.Minute, cfg.EthereumFinalityTimeComponent())
+}
+
+func TestSetTransactionPoolOrderingPolicy(t *testing.T) {
+	cfg, err := newEmptyFileConfig(`{"transaction-pool-ordering-policy": "priority-signers", "transaction-pool-priority-signers": "dfc06c5be24a67adee80b35ab4f147bb1a35c55ff85eda69f40ef827bddec173", "transaction-pool-max-pending-transactions-per-signer": 200}`)
+
+	require.NotNil(t, cfg)
+	require.NoError(t, err)
+	require.EqualValues(t, "priority-signers", cfg.TransactionPoolOrderingPolicy())
+	require.EqualValues(t, "dfc06c5be24a67adee80b35ab4f147bb1a35c55ff85eda69f40ef827bddec173", cfg.TransactionPoolPrioritySigners())
+	require.EqualValues(t, 200, cfg.TransactionPoolMaxPendingTransactionsPerSigner())
+}
+
+func TestSetGossipTransportSecurity(t *testing.T) {
+	cfg, err := newEmptyFileConfig(`{"gossip-transport-security": "none"}`)
+
+	require.NotNil(t, cfg)
+	require.NoError(t, err)
+	require.EqualValues(t, "none", cfg.GossipTransportSecurity())
+}
+
 func TestMergeWithFileConfig(t *testing.T) {
 	nodes := make(map[string]FederationNode)
 	peers := make(map[string]GossipPeer)
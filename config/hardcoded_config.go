@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
+	"sort"
 	"time"
 )
 
@@ -15,6 +16,14 @@ type hardCodedGossipPeer struct {
 	gossipEndpoint string
 }
 
+// the federation from the given block height on, every node has the same schedule so they agree on the federation of
+// each block and follow a change without restarting
+type federationChange struct {
+	asOfBlock       uint64
+	federationNodes map[string]FederationNode
+	gossipPeers     map[string]GossipPeer
+}
+
 type NodeConfigValue struct {
 	Uint32Value   uint32
 	DurationValue time.Duration
@@ -25,6 +34,7 @@ type config struct {
 	kv                      map[string]NodeConfigValue
 	federationNodes         map[string]FederationNode
 	gossipPeers             map[string]GossipPeer
+	federationChanges       []*federationChange // sorted by block height
 	nodePublicKey           primitives.Ed25519PublicKey
 	nodePrivateKey          primitives.Ed25519PrivateKey
 	constantConsensusLeader primitives.Ed25519PublicKey
@@ -32,26 +42,31 @@ type config struct {
 }
 
 const (
-	VIRTUAL_CHAIN_ID                     = "VIRTUAL_CHAIN_ID"
-	BENCHMARK_CONSENSUS_RETRY_INTERVAL   = "BENCHMARK_CONSENSUS_RETRY_INTERVAL"
-	LEAN_HELIX_CONSENSUS_RETRY_INTERVAL  = "LEAN_HELIX_CONSENSUS_RETRY_INTERVAL"
-	CONSENSUS_REQUIRED_QUORUM_PERCENTAGE = "CONSENSUS_REQUIRED_QUORUM_PERCENTAGE"
-	CONSENSUS_MINIMUM_COMMITTEE_SIZE     = "CONSENSUS_MINIMUM_COMMITTEE_SIZE"
+	VIRTUAL_CHAIN_ID                            = "VIRTUAL_CHAIN_ID"
+	BENCHMARK_CONSENSUS_RETRY_INTERVAL          = "BENCHMARK_CONSENSUS_RETRY_INTERVAL"
+	LEAN_HELIX_CONSENSUS_ROUND_TIMEOUT_INTERVAL = "LEAN_HELIX_CONSENSUS_ROUND_TIMEOUT_INTERVAL"
+	CONSENSUS_REQUIRED_QUORUM_PERCENTAGE        = "CONSENSUS_REQUIRED_QUORUM_PERCENTAGE"
+	CONSENSUS_MINIMUM_COMMITTEE_SIZE            = "CONSENSUS_MINIMUM_COMMITTEE_SIZE"
 
 	BLOCK_SYNC_BATCH_SIZE               = "BLOCK_SYNC_BATCH_SIZE"
 	BLOCK_SYNC_INTERVAL                 = "BLOCK_SYNC_INTERVAL"
 	BLOCK_SYNC_COLLECT_RESPONSE_TIMEOUT = "BLOCK_SYNC_COLLECT_RESPONSE_TIMEOUT"
 	BLOCK_SYNC_COLLECT_CHUNKS_TIMEOUT   = "BLOCK_SYNC_COLLECT_CHUNKS_TIMEOUT"
 
+	BLOCK_STORAGE_DATA_DIR = "BLOCK_STORAGE_DATA_DIR"
+
 	BLOCK_TRANSACTION_RECEIPT_QUERY_GRACE_START       = "BLOCK_TRANSACTION_RECEIPT_QUERY_GRACE_START"
 	BLOCK_TRANSACTION_RECEIPT_QUERY_GRACE_END         = "BLOCK_TRANSACTION_RECEIPT_QUERY_GRACE_END"
 	BLOCK_TRANSACTION_RECEIPT_QUERY_EXPIRATION_WINDOW = "BLOCK_TRANSACTION_RECEIPT_QUERY_EXPIRATION_WINDOW"
 
-	CONSENSUS_CONTEXT_MINIMAL_BLOCK_TIME            = "CONSENSUS_CONTEXT_MINIMAL_BLOCK_TIME"
-	CONSENSUS_CONTEXT_MINIMUM_TRANSACTIONS_IN_BLOCK = "CONSENSUS_CONTEXT_MINIMUM_TRANSACTIONS_IN_BLOCK"
-	CONSENSUS_CONTEXT_MAXIMUM_TRANSACTIONS_IN_BLOCK = "CONSENSUS_CONTEXT_MAXIMUM_TRANSACTIONS_IN_BLOCK"
+	CONSENSUS_CONTEXT_MINIMAL_BLOCK_TIME              = "CONSENSUS_CONTEXT_MINIMAL_BLOCK_TIME"
+	CONSENSUS_CONTEXT_MINIMUM_TRANSACTIONS_IN_BLOCK   = "CONSENSUS_CONTEXT_MINIMUM_TRANSACTIONS_IN_BLOCK"
+	CONSENSUS_CONTEXT_MAXIMUM_TRANSACTIONS_IN_BLOCK   = "CONSENSUS_CONTEXT_MAXIMUM_TRANSACTIONS_IN_BLOCK"
+	CONSENSUS_CONTEXT_SYSTEM_TIMESTAMP_ALLOWED_JITTER = "CONSENSUS_CONTEXT_SYSTEM_TIMESTAMP_ALLOWED_JITTER"
 
 	STATE_STORAGE_HISTORY_SNAPSHOT_NUM = "STATE_STORAGE_HISTORY_SNAPSHOT_NUM"
+	STATE_STORAGE_DATA_DIR             = "STATE_STORAGE_DATA_DIR"
+	STATE_STORAGE_MERKLE_CACHE_SIZE    = "STATE_STORAGE_MERKLE_CACHE_SIZE"
 
 	BLOCK_TRACKER_GRACE_DISTANCE = "BLOCK_TRACKER_GRACE_DISTANCE"
 	BLOCK_TRACKER_GRACE_TIMEOUT  = "BLOCK_TRACKER_GRACE_TIMEOUT"
@@ -63,15 +78,37 @@ const (
 	TRANSACTION_POOL_COMMITTED_POOL_CLEAR_EXPIRED_INTERVAL = "TRANSACTION_POOL_COMMITTED_POOL_CLEAR_EXPIRED_INTERVAL"
 	TRANSACTION_POOL_PROPAGATION_BATCH_SIZE                = "TRANSACTION_POOL_PROPAGATION_BATCH_SIZE"
 	TRANSACTION_POOL_PROPAGATION_BATCHING_TIMEOUT          = "TRANSACTION_POOL_PROPAGATION_BATCHING_TIMEOUT"
+	TRANSACTION_POOL_SIGNATURE_CACHE_SIZE                  = "TRANSACTION_POOL_SIGNATURE_CACHE_SIZE"
+	TRANSACTION_POOL_ORDERING_POLICY                       = "TRANSACTION_POOL_ORDERING_POLICY"
+	TRANSACTION_POOL_PRIORITY_SIGNERS                      = "TRANSACTION_POOL_PRIORITY_SIGNERS"
+	TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER   = "TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER"
+	TRANSACTION_POOL_JOURNAL_DATA_DIR                      = "TRANSACTION_POOL_JOURNAL_DATA_DIR"
+	TRANSACTION_POOL_PRE_ORDER_FAILURE_MIN_BACKOFF         = "TRANSACTION_POOL_PRE_ORDER_FAILURE_MIN_BACKOFF"
+	TRANSACTION_POOL_PRE_ORDER_FAILURE_MAX_BACKOFF         = "TRANSACTION_POOL_PRE_ORDER_FAILURE_MAX_BACKOFF"
 
 	GOSSIP_LISTEN_PORT                    = "GOSSIP_LISTEN_PORT"
 	GOSSIP_CONNECTION_KEEP_ALIVE_INTERVAL = "GOSSIP_CONNECTION_KEEP_ALIVE_INTERVAL"
 	GOSSIP_NETWORK_TIMEOUT                = "GOSSIP_NETWORK_TIMEOUT"
+	GOSSIP_TRANSPORT_SECURITY             = "GOSSIP_TRANSPORT_SECURITY"
+	GOSSIP_PEERS_UPDATE_INTERVAL          = "GOSSIP_PEERS_UPDATE_INTERVAL"
 
 	PUBLIC_API_SEND_TRANSACTION_TIMEOUT = "PUBLIC_API_SEND_TRANSACTION_TIMEOUT"
 
+	VIRTUAL_MACHINE_MAX_CALL_STACK_DEPTH            = "VIRTUAL_MACHINE_MAX_CALL_STACK_DEPTH"
+	VIRTUAL_MACHINE_SDK_CALL_BUDGET                 = "VIRTUAL_MACHINE_SDK_CALL_BUDGET"
+	VIRTUAL_MACHINE_STATE_READ_BUDGET_IN_BYTES      = "VIRTUAL_MACHINE_STATE_READ_BUDGET_IN_BYTES"
+	VIRTUAL_MACHINE_STATE_WRITE_BUDGET_IN_BYTES     = "VIRTUAL_MACHINE_STATE_WRITE_BUDGET_IN_BYTES"
+	VIRTUAL_MACHINE_INSTRUCTION_BUDGET              = "VIRTUAL_MACHINE_INSTRUCTION_BUDGET"
+	VIRTUAL_MACHINE_ALLOCATION_BUDGET_IN_BYTES      = "VIRTUAL_MACHINE_ALLOCATION_BUDGET_IN_BYTES"
+	VIRTUAL_MACHINE_MAX_PARALLEL_TRANSACTIONS       = "VIRTUAL_MACHINE_MAX_PARALLEL_TRANSACTIONS"
+	VIRTUAL_MACHINE_PROCESS_TRANSACTION_SET_TIMEOUT = "VIRTUAL_MACHINE_PROCESS_TRANSACTION_SET_TIMEOUT"
+
 	PROCESSOR_ARTIFACT_PATH = "PROCESSOR_ARTIFACT_PATH"
 
+	ETHEREUM_ENDPOINT                  = "ETHEREUM_ENDPOINT"
+	ETHEREUM_FINALITY_BLOCKS_COMPONENT = "ETHEREUM_FINALITY_BLOCKS_COMPONENT"
+	ETHEREUM_FINALITY_TIME_COMPONENT   = "ETHEREUM_FINALITY_TIME_COMPONENT"
+
 	METRICS_REPORT_INTERVAL = "METRICS_REPORT_INTERVAL"
 )
 
@@ -138,6 +175,28 @@ func (c *config) SetGossipPeers(gossipPeers map[string]GossipPeer) mutableNodeCo
 	return c
 }
 
+func (c *config) SetFederationNodesAsOfBlock(asOfBlock uint64, nodes map[string]FederationNode, gossipPeers map[string]GossipPeer) mutableNodeConfig {
+	c.federationChanges = append(c.federationChanges, &federationChange{
+		asOfBlock:       asOfBlock,
+		federationNodes: nodes,
+		gossipPeers:     gossipPeers,
+	})
+	sort.SliceStable(c.federationChanges, func(i, j int) bool {
+		return c.federationChanges[i].asOfBlock < c.federationChanges[j].asOfBlock
+	})
+	return c
+}
+
+// the last change in effect by the given block, nil while the initial federation is
+func (c *config) federationChangeAsOf(asOfBlock uint64) *federationChange {
+	for i := len(c.federationChanges) - 1; i >= 0; i-- {
+		if c.federationChanges[i].asOfBlock <= asOfBlock {
+			return c.federationChanges[i]
+		}
+	}
+	return nil
+}
+
 func (c *hardCodedFederationNode) NodePublicKey() primitives.Ed25519PublicKey {
 	return c.nodePublicKey
 }
@@ -163,14 +222,20 @@ func (c *config) VirtualChainId() primitives.VirtualChainId {
 }
 
 func (c *config) NetworkSize(asOfBlock uint64) uint32 {
-	return uint32(len(c.federationNodes))
+	return uint32(len(c.FederationNodes(asOfBlock)))
 }
 
 func (c *config) FederationNodes(asOfBlock uint64) map[string]FederationNode {
+	if change := c.federationChangeAsOf(asOfBlock); change != nil {
+		return change.federationNodes
+	}
 	return c.federationNodes
 }
 
 func (c *config) GossipPeers(asOfBlock uint64) map[string]GossipPeer {
+	if change := c.federationChangeAsOf(asOfBlock); change != nil {
+		return change.gossipPeers
+	}
 	return c.gossipPeers
 }
 
@@ -187,7 +252,7 @@ func (c *config) BenchmarkConsensusRetryInterval() time.Duration {
 }
 
 func (c *config) LeanHelixConsensusRoundTimeoutInterval() time.Duration {
-	return c.kv[LEAN_HELIX_CONSENSUS_RETRY_INTERVAL].DurationValue
+	return c.kv[LEAN_HELIX_CONSENSUS_ROUND_TIMEOUT_INTERVAL].DurationValue
 }
 
 func (c *config) BlockSyncBatchSize() uint32 {
@@ -202,6 +267,10 @@ func (c *config) BlockSyncCollectResponseTimeout() time.Duration {
 	return c.kv[BLOCK_SYNC_COLLECT_RESPONSE_TIMEOUT].DurationValue
 }
 
+func (c *config) BlockStorageDataDir() string {
+	return c.kv[BLOCK_STORAGE_DATA_DIR].StringValue
+}
+
 func (c *config) BlockTransactionReceiptQueryGraceStart() time.Duration {
 	return c.kv[BLOCK_TRANSACTION_RECEIPT_QUERY_GRACE_START].DurationValue
 }
@@ -226,10 +295,22 @@ func (c *config) ConsensusContextMaximumTransactionsInBlock() uint32 {
 	return c.kv[CONSENSUS_CONTEXT_MAXIMUM_TRANSACTIONS_IN_BLOCK].Uint32Value
 }
 
+func (c *config) ConsensusContextSystemTimestampAllowedJitter() time.Duration {
+	return c.kv[CONSENSUS_CONTEXT_SYSTEM_TIMESTAMP_ALLOWED_JITTER].DurationValue
+}
+
 func (c *config) StateStorageHistorySnapshotNum() uint32 {
 	return c.kv[STATE_STORAGE_HISTORY_SNAPSHOT_NUM].Uint32Value
 }
 
+func (c *config) StateStorageDataDir() string {
+	return c.kv[STATE_STORAGE_DATA_DIR].StringValue
+}
+
+func (c *config) StateStorageMerkleCacheSize() uint32 {
+	return c.kv[STATE_STORAGE_MERKLE_CACHE_SIZE].Uint32Value
+}
+
 func (c *config) BlockTrackerGraceDistance() uint32 {
 	return c.kv[BLOCK_TRACKER_GRACE_DISTANCE].Uint32Value
 }
@@ -266,6 +347,34 @@ func (c *config) TransactionPoolPropagationBatchingTimeout() time.Duration {
 	return c.kv[TRANSACTION_POOL_PROPAGATION_BATCHING_TIMEOUT].DurationValue
 }
 
+func (c *config) TransactionPoolSignatureCacheSize() uint32 {
+	return c.kv[TRANSACTION_POOL_SIGNATURE_CACHE_SIZE].Uint32Value
+}
+
+func (c *config) TransactionPoolOrderingPolicy() string {
+	return c.kv[TRANSACTION_POOL_ORDERING_POLICY].StringValue
+}
+
+func (c *config) TransactionPoolPrioritySigners() string {
+	return c.kv[TRANSACTION_POOL_PRIORITY_SIGNERS].StringValue
+}
+
+func (c *config) TransactionPoolMaxPendingTransactionsPerSigner() uint32 {
+	return c.kv[TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER].Uint32Value
+}
+
+func (c *config) TransactionPoolPreOrderFailureMinBackoff() time.Duration {
+	return c.kv[TRANSACTION_POOL_PRE_ORDER_FAILURE_MIN_BACKOFF].DurationValue
+}
+
+func (c *config) TransactionPoolPreOrderFailureMaxBackoff() time.Duration {
+	return c.kv[TRANSACTION_POOL_PRE_ORDER_FAILURE_MAX_BACKOFF].DurationValue
+}
+
+func (c *config) TransactionPoolJournalDataDir() string {
+	return c.kv[TRANSACTION_POOL_JOURNAL_DATA_DIR].StringValue
+}
+
 func (c *config) SendTransactionTimeout() time.Duration {
 	return c.kv[PUBLIC_API_SEND_TRANSACTION_TIMEOUT].DurationValue
 }
@@ -274,10 +383,54 @@ func (c *config) BlockSyncCollectChunksTimeout() time.Duration {
 	return c.kv[BLOCK_SYNC_COLLECT_CHUNKS_TIMEOUT].DurationValue
 }
 
+func (c *config) VirtualMachineMaxCallStackDepth() uint32 {
+	return c.kv[VIRTUAL_MACHINE_MAX_CALL_STACK_DEPTH].Uint32Value
+}
+
+func (c *config) VirtualMachineSdkCallBudget() uint32 {
+	return c.kv[VIRTUAL_MACHINE_SDK_CALL_BUDGET].Uint32Value
+}
+
+func (c *config) VirtualMachineStateReadBudgetInBytes() uint32 {
+	return c.kv[VIRTUAL_MACHINE_STATE_READ_BUDGET_IN_BYTES].Uint32Value
+}
+
+func (c *config) VirtualMachineStateWriteBudgetInBytes() uint32 {
+	return c.kv[VIRTUAL_MACHINE_STATE_WRITE_BUDGET_IN_BYTES].Uint32Value
+}
+
+func (c *config) VirtualMachineInstructionBudget() uint32 {
+	return c.kv[VIRTUAL_MACHINE_INSTRUCTION_BUDGET].Uint32Value
+}
+
+func (c *config) VirtualMachineAllocationBudgetInBytes() uint32 {
+	return c.kv[VIRTUAL_MACHINE_ALLOCATION_BUDGET_IN_BYTES].Uint32Value
+}
+
+func (c *config) VirtualMachineMaxParallelTransactions() uint32 {
+	return c.kv[VIRTUAL_MACHINE_MAX_PARALLEL_TRANSACTIONS].Uint32Value
+}
+
+func (c *config) VirtualMachineProcessTransactionSetTimeout() time.Duration {
+	return c.kv[VIRTUAL_MACHINE_PROCESS_TRANSACTION_SET_TIMEOUT].DurationValue
+}
+
 func (c *config) ProcessorArtifactPath() string {
 	return c.kv[PROCESSOR_ARTIFACT_PATH].StringValue
 }
 
+func (c *config) EthereumEndpoint() string {
+	return c.kv[ETHEREUM_ENDPOINT].StringValue
+}
+
+func (c *config) EthereumFinalityBlocksComponent() uint32 {
+	return c.kv[ETHEREUM_FINALITY_BLOCKS_COMPONENT].Uint32Value
+}
+
+func (c *config) EthereumFinalityTimeComponent() time.Duration {
+	return c.kv[ETHEREUM_FINALITY_TIME_COMPONENT].DurationValue
+}
+
 func (c *config) GossipListenPort() uint16 {
 	return uint16(c.kv[GOSSIP_LISTEN_PORT].Uint32Value)
 }
@@ -290,6 +443,14 @@ func (c *config) GossipNetworkTimeout() time.Duration {
 	return c.kv[GOSSIP_NETWORK_TIMEOUT].DurationValue
 }
 
+func (c *config) GossipTransportSecurity() string {
+	return c.kv[GOSSIP_TRANSPORT_SECURITY].StringValue
+}
+
+func (c *config) GossipPeersUpdateInterval() time.Duration {
+	return c.kv[GOSSIP_PEERS_UPDATE_INTERVAL].DurationValue
+}
+
 func (c *config) MetricsReportInterval() time.Duration {
 	return c.kv[METRICS_REPORT_INTERVAL].DurationValue
 }
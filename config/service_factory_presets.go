@@ -3,28 +3,64 @@ package config
 import (
 	"github.com/orbs-network/orbs-network-go/crypto/keys"
 	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
-	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"time"
 )
 
-func ForDirectTransportTests(gossipPeers map[string]GossipPeer) GossipTransportConfig {
+func ForDirectTransportTests(gossipPeers map[string]GossipPeer, transportSecurity string) GossipTransportConfig {
 	cfg := emptyConfig()
 	cfg.SetNodePublicKey(testKeys.Ed25519KeyPairForTests(0).PublicKey())
+	cfg.SetNodePrivateKey(testKeys.Ed25519KeyPairForTests(0).PrivateKey())
 	cfg.SetGossipPeers(gossipPeers)
 
 	cfg.SetDuration(GOSSIP_CONNECTION_KEEP_ALIVE_INTERVAL, 20*time.Millisecond)
 	cfg.SetDuration(GOSSIP_NETWORK_TIMEOUT, 20*time.Millisecond)
+	cfg.SetString(GOSSIP_TRANSPORT_SECURITY, transportSecurity)
 	return cfg
 }
 
-func ForGossipAdapterTests(publicKey primitives.Ed25519PublicKey, gossipListenPort uint16, gossipPeers map[string]GossipPeer) GossipTransportConfig {
+func ForGossipPeersUpdaterTests(gossipPeers map[string]GossipPeer, asOfBlock uint64, gossipPeersAsOfBlock map[string]GossipPeer) GossipTransportConfig {
 	cfg := emptyConfig()
-	cfg.SetNodePublicKey(publicKey)
+	cfg.SetGossipPeers(gossipPeers)
+	cfg.SetFederationNodesAsOfBlock(asOfBlock, nil, gossipPeersAsOfBlock)
+	return cfg
+}
+
+func ForGossipAdapterTests(keyPair *keys.Ed25519KeyPair, gossipListenPort uint16, gossipPeers map[string]GossipPeer, transportSecurity string) GossipTransportConfig {
+	cfg := emptyConfig()
+	cfg.SetNodePublicKey(keyPair.PublicKey())
+	cfg.SetNodePrivateKey(keyPair.PrivateKey())
 	cfg.SetGossipPeers(gossipPeers)
 
 	cfg.SetUint32(GOSSIP_LISTEN_PORT, uint32(gossipListenPort))
 	cfg.SetDuration(GOSSIP_CONNECTION_KEEP_ALIVE_INTERVAL, 20*time.Millisecond)
 	cfg.SetDuration(GOSSIP_NETWORK_TIMEOUT, 1*time.Second)
+	cfg.SetString(GOSSIP_TRANSPORT_SECURITY, transportSecurity)
+	return cfg
+}
+
+func ForFilesystemBlockPersistenceTests(dataDir string) FilesystemBlockPersistenceConfig {
+	cfg := emptyConfig()
+	cfg.SetString(BLOCK_STORAGE_DATA_DIR, dataDir)
+	return cfg
+}
+
+func ForFilesystemStatePersistenceTests(dataDir string) FilesystemStatePersistenceConfig {
+	cfg := emptyConfig()
+	cfg.SetString(STATE_STORAGE_DATA_DIR, dataDir)
+	return cfg
+}
+
+func ForFilesystemPendingTransactionJournalTests(dataDir string) FilesystemPendingTransactionJournalConfig {
+	cfg := emptyConfig()
+	cfg.SetString(TRANSACTION_POOL_JOURNAL_DATA_DIR, dataDir)
+	return cfg
+}
+
+func ForEthereumCrosschainConnectorTests(endpoint string, finalityBlocks uint32, finalityTime time.Duration) EthereumCrosschainConnectorConfig {
+	cfg := emptyConfig()
+	cfg.SetString(ETHEREUM_ENDPOINT, endpoint)
+	cfg.SetUint32(ETHEREUM_FINALITY_BLOCKS_COMPONENT, finalityBlocks)
+	cfg.SetDuration(ETHEREUM_FINALITY_TIME_COMPONENT, finalityTime)
 	return cfg
 }
 
@@ -33,7 +69,9 @@ func ForConsensusContextTests(federationNodes map[string]FederationNode) Consens
 
 	cfg.SetDuration(CONSENSUS_CONTEXT_MINIMAL_BLOCK_TIME, 1*time.Millisecond)
 	cfg.SetUint32(CONSENSUS_CONTEXT_MINIMUM_TRANSACTIONS_IN_BLOCK, 2)
+	cfg.SetDuration(CONSENSUS_CONTEXT_SYSTEM_TIMESTAMP_ALLOWED_JITTER, 2*time.Second)
 	cfg.SetUint32(CONSENSUS_MINIMUM_COMMITTEE_SIZE, 4)
+	cfg.SetUint32(VIRTUAL_CHAIN_ID, 42)
 	if federationNodes != nil {
 		cfg.SetFederationNodes(federationNodes)
 	}
@@ -48,10 +86,25 @@ func ForPublicApiTests(virtualChain uint32, txTimeout time.Duration) PublicApiCo
 	return cfg
 }
 
+func ForVirtualMachineTests(maxCallStackDepth uint32, sdkCallBudget uint32, stateReadBudget uint32, stateWriteBudget uint32, instructionBudget uint32, allocationBudget uint32, maxParallelTransactions uint32, processTransactionSetTimeout time.Duration) VirtualMachineConfig {
+	cfg := emptyConfig()
+
+	cfg.SetUint32(VIRTUAL_MACHINE_MAX_CALL_STACK_DEPTH, maxCallStackDepth)
+	cfg.SetUint32(VIRTUAL_MACHINE_SDK_CALL_BUDGET, sdkCallBudget)
+	cfg.SetUint32(VIRTUAL_MACHINE_STATE_READ_BUDGET_IN_BYTES, stateReadBudget)
+	cfg.SetUint32(VIRTUAL_MACHINE_STATE_WRITE_BUDGET_IN_BYTES, stateWriteBudget)
+	cfg.SetUint32(VIRTUAL_MACHINE_INSTRUCTION_BUDGET, instructionBudget)
+	cfg.SetUint32(VIRTUAL_MACHINE_ALLOCATION_BUDGET_IN_BYTES, allocationBudget)
+	cfg.SetUint32(VIRTUAL_MACHINE_MAX_PARALLEL_TRANSACTIONS, maxParallelTransactions)
+	cfg.SetDuration(VIRTUAL_MACHINE_PROCESS_TRANSACTION_SET_TIMEOUT, processTransactionSetTimeout)
+	return cfg
+}
+
 func ForStateStorageTest(numOfStateRevisionsToRetain uint32, graceBlockDiff uint32, graceTimeoutMillis uint64) StateStorageConfig {
 	cfg := emptyConfig()
 
 	cfg.SetUint32(STATE_STORAGE_HISTORY_SNAPSHOT_NUM, numOfStateRevisionsToRetain)
+	cfg.SetUint32(STATE_STORAGE_MERKLE_CACHE_SIZE, 1000)
 	cfg.SetDuration(BLOCK_TRACKER_GRACE_TIMEOUT, time.Duration(graceTimeoutMillis)*time.Millisecond)
 	cfg.SetUint32(BLOCK_TRACKER_GRACE_DISTANCE, graceBlockDiff)
 	return cfg
@@ -72,5 +125,11 @@ func ForTransactionPoolTests(sizeLimit uint32, keyPair *keys.Ed25519KeyPair) Tra
 	cfg.SetDuration(TRANSACTION_POOL_COMMITTED_POOL_CLEAR_EXPIRED_INTERVAL, 30*time.Millisecond)
 	cfg.SetUint32(TRANSACTION_POOL_PROPAGATION_BATCH_SIZE, 1)
 	cfg.SetDuration(TRANSACTION_POOL_PROPAGATION_BATCHING_TIMEOUT, 50*time.Millisecond)
+	cfg.SetUint32(TRANSACTION_POOL_SIGNATURE_CACHE_SIZE, 1000)
+	cfg.SetString(TRANSACTION_POOL_ORDERING_POLICY, "fifo")
+	cfg.SetString(TRANSACTION_POOL_PRIORITY_SIGNERS, "")
+	cfg.SetUint32(TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER, 0)
+	cfg.SetDuration(TRANSACTION_POOL_PRE_ORDER_FAILURE_MIN_BACKOFF, 200*time.Millisecond)
+	cfg.SetDuration(TRANSACTION_POOL_PRE_ORDER_FAILURE_MAX_BACKOFF, 1*time.Second)
 	return cfg
 }
@@ -15,10 +15,12 @@ func defaultProductionConfig() mutableNodeConfig {
 	cfg.SetUint32(GOSSIP_LISTEN_PORT, 4400)
 	cfg.SetActiveConsensusAlgo(consensus.CONSENSUS_ALGO_TYPE_BENCHMARK_CONSENSUS)
 	cfg.SetDuration(BENCHMARK_CONSENSUS_RETRY_INTERVAL, 2*time.Second)
+	cfg.SetDuration(LEAN_HELIX_CONSENSUS_ROUND_TIMEOUT_INTERVAL, 4*time.Second)
 	cfg.SetDuration(CONSENSUS_CONTEXT_MINIMAL_BLOCK_TIME, 1*time.Second) // this is the time between empty blocks when no transactions, need to be large so we don't close infinite blocks on idle
 	cfg.SetUint32(CONSENSUS_REQUIRED_QUORUM_PERCENTAGE, 66)
 	cfg.SetUint32(CONSENSUS_CONTEXT_MINIMUM_TRANSACTIONS_IN_BLOCK, 10)
 	cfg.SetUint32(CONSENSUS_CONTEXT_MAXIMUM_TRANSACTIONS_IN_BLOCK, 100)
+	cfg.SetDuration(CONSENSUS_CONTEXT_SYSTEM_TIMESTAMP_ALLOWED_JITTER, 2*time.Second)
 	cfg.SetUint32(CONSENSUS_MINIMUM_COMMITTEE_SIZE, 4)
 	cfg.SetUint32(BLOCK_TRACKER_GRACE_DISTANCE, 3)
 	cfg.SetDuration(BLOCK_TRACKER_GRACE_TIMEOUT, 100*time.Millisecond)
@@ -31,6 +33,7 @@ func defaultProductionConfig() mutableNodeConfig {
 	cfg.SetDuration(BLOCK_TRANSACTION_RECEIPT_QUERY_GRACE_END, 5*time.Second)
 	cfg.SetDuration(BLOCK_TRANSACTION_RECEIPT_QUERY_EXPIRATION_WINDOW, 3*time.Minute)
 	cfg.SetUint32(STATE_STORAGE_HISTORY_SNAPSHOT_NUM, 5)
+	cfg.SetUint32(STATE_STORAGE_MERKLE_CACHE_SIZE, 100000)
 	cfg.SetUint32(TRANSACTION_POOL_PENDING_POOL_SIZE_IN_BYTES, 20*1024*1024)
 	cfg.SetDuration(TRANSACTION_POOL_TRANSACTION_EXPIRATION_WINDOW, 30*time.Minute)
 	cfg.SetDuration(TRANSACTION_POOL_FUTURE_TIMESTAMP_GRACE_TIMEOUT, 5*time.Second)
@@ -38,10 +41,32 @@ func defaultProductionConfig() mutableNodeConfig {
 	cfg.SetDuration(TRANSACTION_POOL_COMMITTED_POOL_CLEAR_EXPIRED_INTERVAL, 30*time.Second)
 	cfg.SetUint32(TRANSACTION_POOL_PROPAGATION_BATCH_SIZE, 100)
 	cfg.SetDuration(TRANSACTION_POOL_PROPAGATION_BATCHING_TIMEOUT, 100*time.Millisecond)
+	cfg.SetUint32(TRANSACTION_POOL_SIGNATURE_CACHE_SIZE, 100000)
+	cfg.SetString(TRANSACTION_POOL_ORDERING_POLICY, "round-robin-signers")
+	cfg.SetString(TRANSACTION_POOL_PRIORITY_SIGNERS, "")
+	cfg.SetUint32(TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER, 10000)
+	cfg.SetDuration(TRANSACTION_POOL_PRE_ORDER_FAILURE_MIN_BACKOFF, 100*time.Millisecond)
+	cfg.SetDuration(TRANSACTION_POOL_PRE_ORDER_FAILURE_MAX_BACKOFF, 5*time.Second)
 	cfg.SetDuration(GOSSIP_CONNECTION_KEEP_ALIVE_INTERVAL, 1*time.Second)
 	cfg.SetDuration(GOSSIP_NETWORK_TIMEOUT, 30*time.Second)
+	cfg.SetString(GOSSIP_TRANSPORT_SECURITY, "authenticated-encryption")
+	cfg.SetDuration(GOSSIP_PEERS_UPDATE_INTERVAL, 5*time.Second)
 	cfg.SetDuration(METRICS_REPORT_INTERVAL, 30*time.Second)
+	cfg.SetString(ETHEREUM_ENDPOINT, "http://localhost:8545")
+	cfg.SetUint32(ETHEREUM_FINALITY_BLOCKS_COMPONENT, 100)           // calls are pinned this many blocks further back to avoid reading data that may still be reorganized
+	cfg.SetDuration(ETHEREUM_FINALITY_TIME_COMPONENT, 5*time.Minute) // every node's ethereum node is assumed to have seen the blocks mined this long before the orbs block
+	cfg.SetUint32(VIRTUAL_MACHINE_MAX_CALL_STACK_DEPTH, 16)
+	cfg.SetUint32(VIRTUAL_MACHINE_SDK_CALL_BUDGET, 100000) // counted the same on every node, unlike time
+	cfg.SetUint32(VIRTUAL_MACHINE_STATE_READ_BUDGET_IN_BYTES, 10*1024*1024)
+	cfg.SetUint32(VIRTUAL_MACHINE_STATE_WRITE_BUDGET_IN_BYTES, 1024*1024)
+	cfg.SetUint32(VIRTUAL_MACHINE_INSTRUCTION_BUDGET, 10000000) // counted by the metering the native compiler injects into deployed contracts
+	cfg.SetUint32(VIRTUAL_MACHINE_ALLOCATION_BUDGET_IN_BYTES, 64*1024*1024)
+	cfg.SetUint32(VIRTUAL_MACHINE_MAX_PARALLEL_TRANSACTIONS, 1)                      // 1 executes transaction sets sequentially
+	cfg.SetDuration(VIRTUAL_MACHINE_PROCESS_TRANSACTION_SET_TIMEOUT, 10*time.Second) // a block taking longer is not built rather than stalling the federation
 	cfg.SetString(PROCESSOR_ARTIFACT_PATH, filepath.Join(GetProjectSourceTmpPath(), "processor-artifacts"))
+	cfg.SetString(BLOCK_STORAGE_DATA_DIR, filepath.Join(GetProjectSourceTmpPath(), "blocks"))
+	cfg.SetString(STATE_STORAGE_DATA_DIR, filepath.Join(GetProjectSourceTmpPath(), "state"))
+	cfg.SetString(TRANSACTION_POOL_JOURNAL_DATA_DIR, filepath.Join(GetProjectSourceTmpPath(), "transaction-pool-journal"))
 	return cfg
 }
 
@@ -60,6 +85,7 @@ func ForE2E(processorArtifactPath string) mutableNodeConfig {
 	cfg := defaultProductionConfig()
 
 	cfg.SetDuration(BENCHMARK_CONSENSUS_RETRY_INTERVAL, 250*time.Millisecond)
+	cfg.SetDuration(LEAN_HELIX_CONSENSUS_ROUND_TIMEOUT_INTERVAL, 1*time.Second)
 	cfg.SetDuration(CONSENSUS_CONTEXT_MINIMAL_BLOCK_TIME, 100*time.Millisecond) // this is the time between empty blocks when no transactions, need to be large so we don't close infinite blocks on idle
 	cfg.SetDuration(PUBLIC_API_SEND_TRANSACTION_TIMEOUT, 10*time.Second)
 	cfg.SetUint32(CONSENSUS_CONTEXT_MINIMUM_TRANSACTIONS_IN_BLOCK, 1)
@@ -88,6 +114,7 @@ func ForAcceptanceTests(
 	cfg.OverrideNodeSpecificValues(federationNodes, gossipPeers, 0, nodePublicKey, nodePrivateKey, constantConsensusLeader, activeConsensusAlgo)
 
 	cfg.SetDuration(BENCHMARK_CONSENSUS_RETRY_INTERVAL, 1*time.Millisecond)
+	cfg.SetDuration(LEAN_HELIX_CONSENSUS_ROUND_TIMEOUT_INTERVAL, 200*time.Millisecond)
 	cfg.SetDuration(CONSENSUS_CONTEXT_MINIMAL_BLOCK_TIME, 10*time.Millisecond)
 	cfg.SetUint32(CONSENSUS_REQUIRED_QUORUM_PERCENTAGE, 100)
 	cfg.SetDuration(BLOCK_TRACKER_GRACE_TIMEOUT, 50*time.Millisecond)
@@ -0,0 +1,98 @@
+package digest
+
+import (
+	"bytes"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+// the receipts root hash of a results block is the root of a binary merkle tree over the receipt hashes in block order,
+// a node without a sibling moves up a level as is (it is not paired with itself)
+
+func CalcReceiptHash(receipt *protocol.TransactionReceipt) primitives.Sha256 {
+	return hash.CalcSha256(receipt.Raw())
+}
+
+func CalcReceiptsRootHash(receipts []*protocol.TransactionReceipt) primitives.MerkleSha256 {
+	if len(receipts) == 0 {
+		return primitives.MerkleSha256(hash.CalcSha256(nil))
+	}
+
+	level := receiptHashes(receipts)
+	for len(level) > 1 {
+		level = nextMerkleLevel(level)
+	}
+	return primitives.MerkleSha256(level[0])
+}
+
+// the sibling hashes from the leaf of the receipt at index up to the root
+func CalcReceiptProof(receipts []*protocol.TransactionReceipt, index int) ([]primitives.Sha256, error) {
+	if index < 0 || index >= len(receipts) {
+		return nil, errors.Errorf("receipt index %d is out of range of %d receipts", index, len(receipts))
+	}
+
+	var proof []primitives.Sha256
+	level := receiptHashes(receipts)
+	for len(level) > 1 {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		level = nextMerkleLevel(level)
+		index /= 2
+	}
+	return proof, nil
+}
+
+// the number of receipts fixes the shape of the tree, so a proof must hold exactly one sibling per level that has one
+func VerifyReceiptProof(rootHash primitives.MerkleSha256, receipt *protocol.TransactionReceipt, index uint32, numReceipts uint32, proof []primitives.Sha256) bool {
+	if index >= numReceipts {
+		return false
+	}
+
+	current := CalcReceiptHash(receipt)
+	i, size := int(index), int(numReceipts)
+	for ; size > 1; size = (size + 1) / 2 {
+		if sibling := i ^ 1; sibling < size {
+			if len(proof) == 0 {
+				return false
+			}
+			if i%2 == 0 {
+				current = calcMerkleNodeHash(current, proof[0])
+			} else {
+				current = calcMerkleNodeHash(proof[0], current)
+			}
+			proof = proof[1:]
+		}
+		i /= 2
+	}
+	return len(proof) == 0 && bytes.Equal(current, rootHash)
+}
+
+func receiptHashes(receipts []*protocol.TransactionReceipt) []primitives.Sha256 {
+	hashes := make([]primitives.Sha256, len(receipts))
+	for i, receipt := range receipts {
+		hashes[i] = CalcReceiptHash(receipt)
+	}
+	return hashes
+}
+
+func nextMerkleLevel(level []primitives.Sha256) []primitives.Sha256 {
+	next := make([]primitives.Sha256, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+		} else {
+			next = append(next, calcMerkleNodeHash(level[i], level[i+1]))
+		}
+	}
+	return next
+}
+
+func calcMerkleNodeHash(left primitives.Sha256, right primitives.Sha256) primitives.Sha256 {
+	pair := make([]byte, 0, len(left)+len(right))
+	pair = append(pair, left...)
+	pair = append(pair, right...)
+	return hash.CalcSha256(pair)
+}
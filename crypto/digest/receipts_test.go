@@ -0,0 +1,54 @@
+package digest_test
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func receiptsForTests(num int) []*protocol.TransactionReceipt {
+	receipts := make([]*protocol.TransactionReceipt, num)
+	for i := range receipts {
+		receipts[i] = builders.TransactionReceipt().WithRandomHash().Build()
+	}
+	return receipts
+}
+
+func TestReceiptProof_VerifiesEveryReceiptAgainstTheRootHash(t *testing.T) {
+	for num := 1; num <= 9; num++ {
+		receipts := receiptsForTests(num)
+		rootHash := digest.CalcReceiptsRootHash(receipts)
+
+		for i, receipt := range receipts {
+			proof, err := digest.CalcReceiptProof(receipts, i)
+			require.NoError(t, err)
+			require.True(t, digest.VerifyReceiptProof(rootHash, receipt, uint32(i), uint32(num), proof), "receipt %d of %d should be proven", i, num)
+		}
+	}
+}
+
+func TestReceiptProof_RejectsReceiptsThatAreNotInTheBlock(t *testing.T) {
+	receipts := receiptsForTests(5)
+	rootHash := digest.CalcReceiptsRootHash(receipts)
+	proof, err := digest.CalcReceiptProof(receipts, 2)
+	require.NoError(t, err)
+
+	require.False(t, digest.VerifyReceiptProof(rootHash, builders.TransactionReceipt().WithRandomHash().Build(), 2, 5, proof), "other receipt should not be proven")
+	require.False(t, digest.VerifyReceiptProof(rootHash, receipts[2], 3, 5, proof), "receipt should not be proven at another index")
+	require.False(t, digest.VerifyReceiptProof(rootHash, receipts[2], 2, 3, proof), "receipt should not be proven in a tree of another size")
+	require.False(t, digest.VerifyReceiptProof(rootHash, receipts[2], 2, 5, proof[1:]), "truncated proof should be rejected")
+	require.False(t, digest.VerifyReceiptProof(rootHash, receipts[2], 2, 5, append(proof, primitives.Sha256(rootHash))), "extended proof should be rejected")
+
+	_, err = digest.CalcReceiptProof(receipts, 5)
+	require.Error(t, err, "proof of a missing index should fail")
+}
+
+func TestReceiptsRootHash_DependsOnReceiptOrder(t *testing.T) {
+	receipts := receiptsForTests(2)
+	swapped := []*protocol.TransactionReceipt{receipts[1], receipts[0]}
+
+	require.NotEqual(t, digest.CalcReceiptsRootHash(receipts), digest.CalcReceiptsRootHash(swapped))
+}
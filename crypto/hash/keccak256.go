@@ -0,0 +1,16 @@
+package hash
+
+import (
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	KECCAK256_HASH_SIZE_BYTES = 32
+)
+
+// the original keccak padding used by ethereum, not the finalized sha3 standard
+func CalcKeccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
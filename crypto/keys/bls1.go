@@ -0,0 +1,64 @@
+package keys
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bn256"
+	"math/big"
+)
+
+const (
+	BLS1_PUBLIC_KEY_SIZE_BYTES  = 128
+	BLS1_PRIVATE_KEY_SIZE_BYTES = 32
+)
+
+type Bls1KeyPair struct {
+	publicKey  primitives.Bls1PublicKey
+	privateKey primitives.Bls1PrivateKey
+}
+
+func NewBls1KeyPair(publicKey primitives.Bls1PublicKey, privateKey primitives.Bls1PrivateKey) *Bls1KeyPair {
+	return &Bls1KeyPair{publicKey, privateKey}
+}
+
+func (k *Bls1KeyPair) PublicKey() primitives.Bls1PublicKey {
+	return k.publicKey
+}
+
+func (k *Bls1KeyPair) PrivateKey() primitives.Bls1PrivateKey {
+	return k.privateKey
+}
+
+func (k *Bls1KeyPair) PublicKeyHex() string {
+	return hex.EncodeToString(k.publicKey)
+}
+
+func (k *Bls1KeyPair) PrivateKeyHex() string {
+	return hex.EncodeToString(k.privateKey)
+}
+
+func GenerateBls1Key() (*Bls1KeyPair, error) {
+	if pri, pub, err := bn256.RandomG2(rand.Reader); err != nil {
+		return nil, errors.Wrapf(err, "cannot create new bls1 key from random scalar")
+	} else {
+		return NewBls1KeyPair(pub.Marshal(), bls1PrivateKeyFromScalar(pri)), nil
+	}
+}
+
+// the public key is derived from the private key, so configuration only needs to hold the private one
+func Bls1KeyPairFromPrivateKey(privateKey primitives.Bls1PrivateKey) (*Bls1KeyPair, error) {
+	k := new(big.Int).SetBytes(privateKey)
+	if len(privateKey) != BLS1_PRIVATE_KEY_SIZE_BYTES || k.Sign() == 0 || k.Cmp(bn256.Order) >= 0 {
+		return nil, errors.New("cannot derive bls1 public key, private key invalid")
+	}
+	return NewBls1KeyPair(new(bn256.G2).ScalarBaseMult(k).Marshal(), privateKey), nil
+}
+
+func bls1PrivateKeyFromScalar(k *big.Int) primitives.Bls1PrivateKey {
+	privateKey := make([]byte, BLS1_PRIVATE_KEY_SIZE_BYTES)
+	b := k.Bytes()
+	copy(privateKey[BLS1_PRIVATE_KEY_SIZE_BYTES-len(b):], b)
+	return privateKey
+}
@@ -1,19 +1,165 @@
 package signature
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bn256"
+	"math/big"
 )
 
-// TODO: insert real implementations
+// BLS signatures over the bn256 pairing curve: signatures are points on G1, public keys are points on G2
+// and a signature is valid when e(signature, g2) == e(H(data), publicKey)
 
-func SignBls1(privateKey primitives.Bls1PrivateKey, data []byte) primitives.Bls1Sig {
-	return []byte{0x88}
+const (
+	BLS1_SIGNATURE_SIZE_BYTES = 64
+)
+
+// the prime of the field over which G1 is defined, y^2 = x^3 + 3
+var bls1FieldPrime, _ = new(big.Int).SetString("65000549695646603732796438742359905742825358107623003571877145026864184071783", 10)
+
+func SignBls1(privateKey primitives.Bls1PrivateKey, data []byte) (primitives.Bls1Sig, error) {
+	k := new(big.Int).SetBytes(privateKey)
+	if len(privateKey) != keys.BLS1_PRIVATE_KEY_SIZE_BYTES || k.Sign() == 0 || k.Cmp(bn256.Order) >= 0 {
+		return nil, errors.New("cannot sign with bls1, private key invalid")
+	}
+	return new(bn256.G1).ScalarMult(hashToG1(data), k).Marshal(), nil
 }
 
 func VerifyBls1(publicKey primitives.Bls1PublicKey, data []byte, signature primitives.Bls1Sig) bool {
-	if signature.Equal([]byte{0x88}) {
-		return true
-	} else {
+	pk, ok := unmarshalBls1PublicKey(publicKey)
+	if !ok {
+		return false
+	}
+	sig, ok := unmarshalBls1Signature(signature)
+	if !ok {
+		return false
+	}
+
+	left := bn256.Pair(sig, new(bn256.G2).ScalarBaseMult(big.NewInt(1)))
+	right := bn256.Pair(hashToG1(data), pk)
+	return bytes.Equal(left.Marshal(), right.Marshal())
+}
+
+// combines signatures of several signers over the same data into a single signature of the same size
+func AggregateBls1Signatures(signatures []primitives.Bls1Sig) (primitives.Bls1Sig, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("cannot aggregate an empty list of bls1 signatures")
+	}
+
+	var aggregated *bn256.G1
+	for i, signature := range signatures {
+		sig, ok := unmarshalBls1Signature(signature)
+		if !ok {
+			return nil, errors.Errorf("cannot aggregate bls1 signatures, signature %d invalid", i)
+		}
+		if aggregated == nil {
+			aggregated = sig
+		} else {
+			aggregated = new(bn256.G1).Add(aggregated, sig)
+		}
+	}
+	return aggregated.Marshal(), nil
+}
+
+// combines the public keys of several signers into the key that verifies their aggregated signature,
+// the keys must be known to belong to their signers (eg. taken from the committee) to rule out rogue key attacks
+func AggregateBls1PublicKeys(publicKeys []primitives.Bls1PublicKey) (primitives.Bls1PublicKey, error) {
+	if len(publicKeys) == 0 {
+		return nil, errors.New("cannot aggregate an empty list of bls1 public keys")
+	}
+
+	var aggregated *bn256.G2
+	for i, publicKey := range publicKeys {
+		pk, ok := unmarshalBls1PublicKey(publicKey)
+		if !ok {
+			return nil, errors.Errorf("cannot aggregate bls1 public keys, public key %d invalid", i)
+		}
+		if aggregated == nil {
+			aggregated = pk
+		} else {
+			aggregated = new(bn256.G2).Add(aggregated, pk)
+		}
+	}
+	return aggregated.Marshal(), nil
+}
+
+func VerifyAggregatedBls1(publicKeys []primitives.Bls1PublicKey, data []byte, aggregatedSignature primitives.Bls1Sig) bool {
+	aggregatedPublicKey, err := AggregateBls1PublicKeys(publicKeys)
+	if err != nil {
 		return false
 	}
+	return VerifyBls1(aggregatedPublicKey, data, aggregatedSignature)
+}
+
+// maps data to a point on G1 by trying consecutive counters until the hash is the x coordinate of a point,
+// G1 has a cofactor of 1 so every point on the curve is in the group
+func hashToG1(data []byte) *bn256.G1 {
+	counter := make([]byte, 4)
+	for i := uint32(0); ; i++ {
+		binary.BigEndian.PutUint32(counter, i)
+		h := sha256.Sum256(append(counter, data...))
+
+		x := new(big.Int).SetBytes(h[:])
+		x.Mod(x, bls1FieldPrime)
+		ySquared := new(big.Int).Exp(x, big.NewInt(3), bls1FieldPrime)
+		ySquared.Add(ySquared, big.NewInt(3))
+		ySquared.Mod(ySquared, bls1FieldPrime)
+
+		y := new(big.Int).ModSqrt(ySquared, bls1FieldPrime)
+		if y == nil {
+			continue
+		}
+
+		point := make([]byte, BLS1_SIGNATURE_SIZE_BYTES)
+		xBytes, yBytes := x.Bytes(), y.Bytes()
+		copy(point[BLS1_SIGNATURE_SIZE_BYTES/2-len(xBytes):], xBytes)
+		copy(point[BLS1_SIGNATURE_SIZE_BYTES-len(yBytes):], yBytes)
+		if p, ok := new(bn256.G1).Unmarshal(point); ok {
+			return p
+		}
+	}
+}
+
+// rejects the point at infinity and non canonical encodings so that every signature and key has a single representation,
+// and points outside the prime order subgroup which unmarshal only checks to be on the curve
+func unmarshalBls1Signature(signature primitives.Bls1Sig) (*bn256.G1, bool) {
+	if len(signature) != BLS1_SIGNATURE_SIZE_BYTES || isAllZeros(signature) {
+		return nil, false
+	}
+	sig, ok := new(bn256.G1).Unmarshal(signature)
+	if !ok || !bytes.Equal(sig.Marshal(), signature) {
+		return nil, false
+	}
+	if !isAllZeros(new(bn256.G1).ScalarMult(sig, bn256.Order).Marshal()) {
+		return nil, false
+	}
+	return sig, true
+}
+
+func unmarshalBls1PublicKey(publicKey primitives.Bls1PublicKey) (*bn256.G2, bool) {
+	if len(publicKey) != keys.BLS1_PUBLIC_KEY_SIZE_BYTES || isAllZeros(publicKey) {
+		return nil, false
+	}
+	pk, ok := new(bn256.G2).Unmarshal(publicKey)
+	if !ok || !bytes.Equal(pk.Marshal(), publicKey) {
+		return nil, false
+	}
+	// the twist curve has a large cofactor, a key outside the subgroup could forge aggregated signatures
+	if !isAllZeros(new(bn256.G2).ScalarMult(pk, bn256.Order).Marshal()) {
+		return nil, false
+	}
+	return pk, true
+}
+
+func isAllZeros(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,213 @@
+package signature
+
+import (
+	cryptoKeys "github.com/orbs-network/orbs-network-go/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bn256"
+	"math/big"
+	"testing"
+)
+
+func TestSignAndVerifyBls1(t *testing.T) {
+	kp := keys.Bls1KeyPairForTests(1)
+
+	sig, err := SignBls1(kp.PrivateKey(), someDataToSign)
+	require.NoError(t, err)
+	require.Len(t, sig, BLS1_SIGNATURE_SIZE_BYTES)
+
+	require.True(t, VerifyBls1(kp.PublicKey(), someDataToSign, sig), "verification failed")
+	require.False(t, VerifyBls1(kp.PublicKey(), []byte("some other data"), sig), "verification succeeded for other data")
+	require.False(t, VerifyBls1(keys.Bls1KeyPairForTests(2).PublicKey(), someDataToSign, sig), "verification succeeded with another public key")
+}
+
+func TestSignBls1IsDeterministic(t *testing.T) {
+	kp := keys.Bls1KeyPairForTests(1)
+
+	sig1, err := SignBls1(kp.PrivateKey(), someDataToSign)
+	require.NoError(t, err)
+	sig2, err := SignBls1(kp.PrivateKey(), someDataToSign)
+	require.NoError(t, err)
+	require.Equal(t, sig1, sig2)
+}
+
+func TestSignBls1InvalidPrivateKey(t *testing.T) {
+	_, err := SignBls1([]byte{0}, someDataToSign)
+	require.Error(t, err, "sign succeeded with a short private key")
+
+	_, err = SignBls1(make([]byte, cryptoKeys.BLS1_PRIVATE_KEY_SIZE_BYTES), someDataToSign)
+	require.Error(t, err, "sign succeeded with a zero private key")
+}
+
+func TestVerifyBls1InvalidPublicKeyOrSignature(t *testing.T) {
+	kp := keys.Bls1KeyPairForTests(1)
+	sig, err := SignBls1(kp.PrivateKey(), someDataToSign)
+	require.NoError(t, err)
+
+	require.False(t, VerifyBls1([]byte{0}, someDataToSign, sig), "verification succeeded with a short public key")
+	require.False(t, VerifyBls1(make([]byte, cryptoKeys.BLS1_PUBLIC_KEY_SIZE_BYTES), someDataToSign, sig), "verification succeeded with the public key at infinity")
+	require.False(t, VerifyBls1(kp.PublicKey(), someDataToSign, []byte{0x88}), "verification succeeded with a short signature")
+	require.False(t, VerifyBls1(kp.PublicKey(), someDataToSign, make([]byte, BLS1_SIGNATURE_SIZE_BYTES)), "verification succeeded with the signature at infinity")
+
+	notOnCurve := append([]byte{}, sig...)
+	notOnCurve[BLS1_SIGNATURE_SIZE_BYTES-1] ^= 1
+	require.False(t, VerifyBls1(kp.PublicKey(), someDataToSign, notOnCurve), "verification succeeded with a signature that is not on the curve")
+}
+
+func TestBls1RejectsPublicKeyOutsideSubgroup(t *testing.T) {
+	kp := keys.Bls1KeyPairForTests(1)
+	sig, err := SignBls1(kp.PrivateKey(), someDataToSign)
+	require.NoError(t, err)
+
+	outsideSubgroup := bls1TwistPointOutsideSubgroupForTests()
+	_, onCurve := new(bn256.G2).Unmarshal(outsideSubgroup)
+	require.True(t, onCurve, "test point should be on the twist curve")
+
+	require.False(t, VerifyBls1(outsideSubgroup, someDataToSign, sig), "verification succeeded with a public key outside the subgroup")
+	_, err = AggregateBls1PublicKeys([]primitives.Bls1PublicKey{kp.PublicKey(), outsideSubgroup})
+	require.Error(t, err, "aggregated a public key outside the subgroup")
+}
+
+func TestAggregateBls1Signatures(t *testing.T) {
+	var publicKeys []primitives.Bls1PublicKey
+	var signatures []primitives.Bls1Sig
+	for i := 0; i < 4; i++ {
+		kp := keys.Bls1KeyPairForTests(i)
+		sig, err := SignBls1(kp.PrivateKey(), someDataToSign)
+		require.NoError(t, err)
+		publicKeys = append(publicKeys, kp.PublicKey())
+		signatures = append(signatures, sig)
+	}
+
+	aggregated, err := AggregateBls1Signatures(signatures)
+	require.NoError(t, err)
+	require.Len(t, aggregated, BLS1_SIGNATURE_SIZE_BYTES, "aggregated signature should be as compact as a single one")
+
+	require.True(t, VerifyAggregatedBls1(publicKeys, someDataToSign, aggregated), "verification of aggregated signature failed")
+	require.False(t, VerifyAggregatedBls1(publicKeys[:3], someDataToSign, aggregated), "verification succeeded with a missing signer")
+	require.False(t, VerifyAggregatedBls1(publicKeys, []byte("some other data"), aggregated), "verification succeeded for other data")
+
+	aggregatedPublicKey, err := AggregateBls1PublicKeys(publicKeys)
+	require.NoError(t, err)
+	require.True(t, VerifyBls1(aggregatedPublicKey, someDataToSign, aggregated), "verification with aggregated public key failed")
+}
+
+func TestAggregateBls1SignaturesFailsOnInvalidInput(t *testing.T) {
+	_, err := AggregateBls1Signatures(nil)
+	require.Error(t, err, "aggregated an empty list")
+
+	_, err = AggregateBls1Signatures([]primitives.Bls1Sig{{0x88}})
+	require.Error(t, err, "aggregated an invalid signature")
+
+	_, err = AggregateBls1PublicKeys([]primitives.Bls1PublicKey{{0x88}})
+	require.Error(t, err, "aggregated an invalid public key")
+}
+
+func TestBls1KeyPairFromPrivateKey(t *testing.T) {
+	kp := keys.Bls1KeyPairForTests(3)
+
+	derived, err := cryptoKeys.Bls1KeyPairFromPrivateKey(kp.PrivateKey())
+	require.NoError(t, err)
+	require.Equal(t, kp.PublicKey(), derived.PublicKey(), "derived public key does not match")
+}
+
+func TestGenerateBls1Key(t *testing.T) {
+	kp, err := cryptoKeys.GenerateBls1Key()
+	require.NoError(t, err)
+
+	sig, err := SignBls1(kp.PrivateKey(), someDataToSign)
+	require.NoError(t, err)
+	require.True(t, VerifyBls1(kp.PublicKey(), someDataToSign, sig), "verification with generated key failed")
+}
+
+func BenchmarkSignBls1(b *testing.B) {
+	kp := keys.Bls1KeyPairForTests(1)
+	for i := 0; i < b.N; i++ {
+		if _, err := SignBls1(kp.PrivateKey(), someDataToSign); err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func BenchmarkVerifyBls1(b *testing.B) {
+	b.StopTimer()
+	kp := keys.Bls1KeyPairForTests(1)
+
+	if sig, err := SignBls1(kp.PrivateKey(), someDataToSign); err != nil {
+		b.Error(err)
+	} else {
+		b.StartTimer()
+		for i := 0; i < b.N; i++ {
+			if !VerifyBls1(kp.PublicKey(), someDataToSign, sig) {
+				b.Error("verification failed")
+			}
+		}
+	}
+}
+
+// elements of GF(p^2) are x*i + y with i^2 = -1, as bn256 encodes them
+type bls1Fp2 struct {
+	x, y *big.Int
+}
+
+func (a bls1Fp2) mul(b bls1Fp2) bls1Fp2 {
+	x := new(big.Int).Add(new(big.Int).Mul(a.x, b.y), new(big.Int).Mul(a.y, b.x))
+	y := new(big.Int).Sub(new(big.Int).Mul(a.y, b.y), new(big.Int).Mul(a.x, b.x))
+	return bls1Fp2{x.Mod(x, bls1FieldPrime), y.Mod(y, bls1FieldPrime)}
+}
+
+func (a bls1Fp2) add(b bls1Fp2) bls1Fp2 {
+	x := new(big.Int).Add(a.x, b.x)
+	y := new(big.Int).Add(a.y, b.y)
+	return bls1Fp2{x.Mod(x, bls1FieldPrime), y.Mod(y, bls1FieldPrime)}
+}
+
+// square root through the norm, y + x*i = (c + d*i)^2 where c^2 = (y + sqrt(x^2 + y^2)) / 2 and d = x / 2c
+func (a bls1Fp2) sqrt() (bls1Fp2, bool) {
+	norm := new(big.Int).Add(new(big.Int).Mul(a.x, a.x), new(big.Int).Mul(a.y, a.y))
+	n := new(big.Int).ModSqrt(norm.Mod(norm, bls1FieldPrime), bls1FieldPrime)
+	if n == nil {
+		return bls1Fp2{}, false
+	}
+	half := new(big.Int).ModInverse(big.NewInt(2), bls1FieldPrime)
+	for _, candidate := range []*big.Int{new(big.Int).Add(a.y, n), new(big.Int).Sub(a.y, n)} {
+		cSquared := candidate.Mul(candidate, half)
+		c := new(big.Int).ModSqrt(cSquared.Mod(cSquared, bls1FieldPrime), bls1FieldPrime)
+		if c == nil || c.Sign() == 0 {
+			continue
+		}
+		d := new(big.Int).Mul(a.x, new(big.Int).ModInverse(new(big.Int).Lsh(c, 1), bls1FieldPrime))
+		return bls1Fp2{d.Mod(d, bls1FieldPrime), c}, true
+	}
+	return bls1Fp2{}, false
+}
+
+// a point on the twist curve y^2 = x^3 + 3/(i+9), almost every such point is outside the prime order subgroup
+func bls1TwistPointOutsideSubgroupForTests() primitives.Bls1PublicKey {
+	twistB := bls1Fp2{
+		x: bigFromDecimal("6500054969564660373279643874235990574282535810762300357187714502686418407178"),
+		y: bigFromDecimal("45500384786952622612957507119651934019977750675336102500314001518804928850249"),
+	}
+	for k := int64(1); ; k++ {
+		x := bls1Fp2{big.NewInt(0), big.NewInt(k)}
+		y, ok := x.mul(x).mul(x).add(twistB).sqrt()
+		if !ok {
+			continue
+		}
+		encoded := make([]byte, cryptoKeys.BLS1_PUBLIC_KEY_SIZE_BYTES)
+		for i, coordinate := range []*big.Int{x.x, x.y, y.x, y.y} {
+			b := coordinate.Bytes()
+			copy(encoded[(i+1)*32-len(b):], b)
+		}
+		point, onCurve := new(bn256.G2).Unmarshal(encoded)
+		if onCurve && !isAllZeros(new(bn256.G2).ScalarMult(point, bn256.Order).Marshal()) {
+			return encoded
+		}
+	}
+}
+
+func bigFromDecimal(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return n
+}
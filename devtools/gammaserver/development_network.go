@@ -5,6 +5,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/bootstrap/inmemory"
 	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	ethereumAdapter "github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/adapter"
 	gossipAdapter "github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	nativeProcessorAdapter "github.com/orbs-network/orbs-network-go/services/processor/native/adapter"
 	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
@@ -28,9 +29,13 @@ func NewDevelopmentNetwork(ctx context.Context, logger log.BasicLogger) inmemory
 
 	sharedTransport := gossipAdapter.NewMemoryTransport(ctx, logger, federationNodes)
 
+	// gamma reads from a local ethereum node (such as ganache) listening on the default endpoint
+	ethereumConnection := ethereumAdapter.NewEthereumRpcConnection(config.ForProduction(""), logger)
+
 	network := &inmemory.Network{
-		Logger:    logger,
-		Transport: sharedTransport,
+		Logger:             logger,
+		Transport:          sharedTransport,
+		EthereumConnection: ethereumConnection,
 	}
 
 	for i := 0; i < numNodes; i++ {
@@ -35,7 +35,7 @@ func StartGammaServer(serverAddress string, blocking bool) *GammaServer {
 
 	metricRegistry := metric.NewRegistry()
 
-	httpServer := httpserver.NewHttpServer(serverAddress, testLogger, network.PublicApi(0), metricRegistry)
+	httpServer := httpserver.NewHttpServer(serverAddress, testLogger, network.PublicApi(0), network.BlockStorage(0), network.GetBlockPersistence(0), metricRegistry)
 
 	s := &GammaServer{
 		ctxCancel:    cancel,
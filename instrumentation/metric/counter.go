@@ -0,0 +1,55 @@
+package metric
+
+import (
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"sync/atomic"
+)
+
+// a value that only grows, eg. number of messages sent, unlike a Gauge which also goes down
+type Counter struct {
+	namedMetric
+	value uint64
+}
+
+type counterExport struct {
+	Name   string
+	Labels map[string]string `json:",omitempty"`
+	Value  uint64
+}
+
+func newCounter(name string, labels []label) *Counter {
+	return &Counter{namedMetric: namedMetric{name: name, labels: labels}}
+}
+
+func (c *Counter) Export() exportedMetric {
+	return counterExport{
+		c.name,
+		c.labelsMap(),
+		c.Value(),
+	}
+}
+
+func (c *Counter) String() string {
+	return fmt.Sprintf("metric %s: %d\n", c.fullName(), c.Value())
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) Add(i uint64) {
+	atomic.AddUint64(&c.value, i)
+}
+
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+func (c counterExport) LogRow() []*log.Field {
+	return withLabelsLogField([]*log.Field{
+		log.String("metric", c.Name),
+		log.String("metric-type", "counter"),
+		log.Uint64("counter", c.Value),
+	}, c.Labels)
+}
@@ -0,0 +1,22 @@
+package metric
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCounter_Inc(t *testing.T) {
+	c := Counter{}
+	c.Inc()
+	c.Inc()
+
+	require.EqualValues(t, 2, c.Value(), "counter value differed from expected")
+}
+
+func TestCounter_Add(t *testing.T) {
+	c := Counter{}
+	c.Add(10)
+	c.Add(5)
+
+	require.EqualValues(t, 15, c.Value(), "counter value differed from expected")
+}
@@ -12,19 +12,25 @@ type Gauge struct {
 }
 
 type gaugeExport struct {
-	Name  string
-	Value int64
+	Name   string
+	Labels map[string]string `json:",omitempty"`
+	Value  int64
+}
+
+func newGauge(name string, labels []label) *Gauge {
+	return &Gauge{namedMetric: namedMetric{name: name, labels: labels}}
 }
 
 func (g *Gauge) Export() exportedMetric {
 	return gaugeExport{
 		g.name,
+		g.labelsMap(),
 		g.value,
 	}
 }
 
 func (g *Gauge) String() string {
-	return fmt.Sprintf("metric %s: %d\n", g.name, g.value)
+	return fmt.Sprintf("metric %s: %d\n", g.fullName(), g.value)
 }
 
 func (g *Gauge) Inc() {
@@ -60,9 +66,9 @@ func (g *Gauge) Value() int64 {
 }
 
 func (g gaugeExport) LogRow() []*log.Field {
-	return []*log.Field{
+	return withLabelsLogField([]*log.Field{
 		log.String("metric", g.Name),
 		log.String("metric-type", "gauge"),
 		log.Int64("gauge", g.Value),
-	}
+	}, g.Labels)
 }
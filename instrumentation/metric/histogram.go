@@ -12,10 +12,16 @@ type Histogram struct {
 	namedMetric
 	histo         *hdrhistogram.WindowedHistogram
 	overflowCount int64
+
+	// prometheus treats histogram series as counters, so these accumulate across window rotations
+	bucketCounts []int64 // per prometheus latency bucket, not cumulative
+	count        int64
+	sum          int64
 }
 
 type histogramExport struct {
 	Name    string
+	Labels  map[string]string `json:",omitempty"`
 	Min     float64
 	P50     float64
 	P95     float64
@@ -33,20 +39,47 @@ func floatToMillis(nanoseconds float64) float64 {
 	return nanoseconds / 1e+6
 }
 
-func newHistogram(name string, max int64) *Histogram {
+func newHistogram(name string, labels []label, max int64) *Histogram {
 	return &Histogram{
-		namedMetric: namedMetric{name: name},
-		histo:       hdrhistogram.NewWindowed(5, 0, max, 1),
+		namedMetric:  namedMetric{name: name, labels: labels},
+		histo:        hdrhistogram.NewWindowed(5, 0, max, 1),
+		bucketCounts: make([]int64, len(prometheusLatencyBuckets)),
 	}
 }
 
 func (h *Histogram) RecordSince(t time.Time) {
 	d := time.Since(t).Nanoseconds()
+	h.recordCumulative(d)
 	if err := h.histo.Current.RecordValue(int64(d)); err != nil {
 		atomic.AddInt64(&h.overflowCount, 1)
 	}
 }
 
+func (h *Histogram) recordCumulative(nanoseconds int64) {
+	atomic.AddInt64(&h.count, 1)
+	atomic.AddInt64(&h.sum, nanoseconds)
+	for i, upperBound := range prometheusLatencyBuckets {
+		if toMillis(nanoseconds) <= upperBound {
+			atomic.AddInt64(&h.bucketCounts[i], 1)
+			return
+		}
+	}
+}
+
+// cumulative counts of the prometheus latency buckets since the histogram was created, with the total count and sum in
+// milliseconds; the count is read last so it is never below the buckets of a concurrent record
+func (h *Histogram) cumulativeBuckets() (buckets []int64, count int64, sum float64) {
+	buckets = make([]int64, len(h.bucketCounts))
+	var cumulative int64
+	for i := range h.bucketCounts {
+		cumulative += atomic.LoadInt64(&h.bucketCounts[i])
+		buckets[i] = cumulative
+	}
+	sum = toMillis(atomic.LoadInt64(&h.sum))
+	count = atomic.LoadInt64(&h.count)
+	return
+}
+
 func (h *Histogram) String() string {
 	var errorRate float64
 	histo := h.histo.Current
@@ -59,7 +92,7 @@ func (h *Histogram) String() string {
 
 	return fmt.Sprintf(
 		"metric %s: [min=%f, p50=%f, p95=%f, p99=%f, max=%f, avg=%f, samples=%d, error rate=%f]\n",
-		h.name,
+		h.fullName(),
 		toMillis(histo.Min()),
 		toMillis(histo.ValueAtQuantile(50)),
 		toMillis(histo.ValueAtQuantile(95)),
@@ -75,6 +108,7 @@ func (h *Histogram) Export() exportedMetric {
 
 	return &histogramExport{
 		h.name,
+		h.labelsMap(),
 		toMillis(histo.Min()),
 		toMillis(histo.ValueAtQuantile(50)),
 		toMillis(histo.ValueAtQuantile(95)),
@@ -94,7 +128,7 @@ func (h *histogramExport) LogRow() []*log.Field {
 		return nil
 	}
 
-	return []*log.Field{
+	return withLabelsLogField([]*log.Field{
 		log.String("metric", h.Name),
 		log.String("metric-type", "histogram"),
 		log.Float64("min", h.Min),
@@ -104,5 +138,5 @@ func (h *histogramExport) LogRow() []*log.Field {
 		log.Float64("max", h.Max),
 		log.Float64("avg", h.Avg),
 		log.Int64("samples", h.Samples),
-	}
+	}, h.Labels)
 }
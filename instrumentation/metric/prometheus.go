@@ -0,0 +1,150 @@
+package metric
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// histograms are exported in milliseconds like everywhere else, the buckets are upper bounds in milliseconds
+var prometheusLatencyBuckets = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000}
+
+var prometheusQuantiles = []float64{50, 95, 99}
+
+// renders all metrics in the prometheus text exposition format (version 0.0.4), every sample carries the registry labels
+func (r *inMemoryRegistry) ExportPrometheus() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := make([]metric, len(r.mu.metrics))
+	copy(metrics, r.mu.metrics)
+	sort.Slice(metrics, func(i, j int) bool {
+		if prometheusName(metrics[i].Name()) != prometheusName(metrics[j].Name()) {
+			return prometheusName(metrics[i].Name()) < prometheusName(metrics[j].Name())
+		}
+		return metrics[i].fullName() < metrics[j].fullName()
+	})
+
+	// the samples of a family (all children of a vec) must be written together under a single TYPE line
+	b := &strings.Builder{}
+	for first := 0; first < len(metrics); {
+		last := first + 1
+		for last < len(metrics) && prometheusName(metrics[last].Name()) == prometheusName(metrics[first].Name()) {
+			last++
+		}
+		writePrometheusFamily(b, prometheusName(metrics[first].Name()), r.mu.labels, metrics[first:last])
+		first = last
+	}
+	return b.String()
+}
+
+func writePrometheusFamily(b *strings.Builder, name string, registryLabels []label, family []metric) {
+	switch family[0].(type) {
+	case *Counter:
+		fmt.Fprintf(b, "# TYPE %s counter\n", name)
+	case *Gauge, *Rate:
+		fmt.Fprintf(b, "# TYPE %s gauge\n", name)
+	case *Histogram:
+		writePrometheusHistograms(b, name, registryLabels, family)
+		return
+	}
+
+	for _, m := range family {
+		labels := append(append([]label{}, registryLabels...), sanitizedLabels(m.metricLabels())...)
+		switch m := m.(type) {
+		case *Counter:
+			writePrometheusSample(b, name, labels, float64(m.Value()))
+		case *Gauge:
+			writePrometheusSample(b, name, labels, float64(m.Value()))
+		case *Rate:
+			writePrometheusSample(b, name, labels, m.Export().(rateExport).Rate)
+		}
+	}
+}
+
+// written twice: as a histogram with cumulative buckets and as a summary (suffixed _summary) with quantiles, the buckets,
+// sums and counts of both only ever grow while the quantiles are of the current window
+func writePrometheusHistograms(b *strings.Builder, name string, registryLabels []label, family []metric) {
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+	for _, m := range family {
+		if h, ok := m.(*Histogram); ok {
+			labels := append(append([]label{}, registryLabels...), sanitizedLabels(h.labels)...)
+			buckets, count, sum := h.cumulativeBuckets()
+			for i, upperBound := range prometheusLatencyBuckets {
+				writePrometheusSample(b, name+"_bucket", withLabel(labels, "le", prometheusFloat(upperBound)), float64(buckets[i]))
+			}
+			writePrometheusSample(b, name+"_bucket", withLabel(labels, "le", "+Inf"), float64(count))
+			writePrometheusSample(b, name+"_sum", labels, sum)
+			writePrometheusSample(b, name+"_count", labels, float64(count))
+		}
+	}
+
+	fmt.Fprintf(b, "# TYPE %s_summary summary\n", name)
+	for _, m := range family {
+		if h, ok := m.(*Histogram); ok {
+			labels := append(append([]label{}, registryLabels...), sanitizedLabels(h.labels)...)
+			histo := h.histo.Current
+			for _, quantile := range prometheusQuantiles {
+				writePrometheusSample(b, name+"_summary", withLabel(labels, "quantile", prometheusFloat(quantile/100)), toMillis(histo.ValueAtQuantile(quantile)))
+			}
+			_, count, sum := h.cumulativeBuckets()
+			writePrometheusSample(b, name+"_summary_sum", labels, sum)
+			writePrometheusSample(b, name+"_summary_count", labels, float64(count))
+		}
+	}
+}
+
+func writePrometheusSample(b *strings.Builder, name string, labels []label, value float64) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteString("{")
+		for i, l := range labels {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(b, "%s=\"%s\"", l.name, prometheusLabelValue(l.value))
+		}
+		b.WriteString("}")
+	}
+	fmt.Fprintf(b, " %s\n", prometheusFloat(value))
+}
+
+func withLabel(labels []label, name string, value string) []label {
+	res := make([]label, len(labels), len(labels)+1)
+	copy(res, labels)
+	return append(res, label{name, value})
+}
+
+// BlockStorage.BlockHeight becomes blockstorage_blockheight, prometheus names may only contain [a-zA-Z0-9_:] and not start with a digit
+func prometheusName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '_'
+		}
+	}, name)
+	if sanitized == "" || (sanitized[0] >= '0' && sanitized[0] <= '9') {
+		sanitized = "_" + sanitized
+	}
+	return sanitized
+}
+
+func sanitizedLabels(labels []label) []label {
+	res := make([]label, len(labels))
+	for i, l := range labels {
+		res[i] = label{prometheusName(l.name), l.value}
+	}
+	return res
+}
+
+func prometheusLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func prometheusFloat(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
@@ -0,0 +1,107 @@
+package metric
+
+import (
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusName(t *testing.T) {
+	require.Equal(t, "blockstorage_blockheight", prometheusName("BlockStorage.BlockHeight"))
+	require.Equal(t, "transactionpool_pending_pooltimespent", prometheusName("TransactionPool.Pending.PoolTimeSpent"))
+	require.Equal(t, "_1st_metric_name", prometheusName("1st metric-name"))
+}
+
+func TestInMemoryRegistry_ExportPrometheus_GaugeWithLabels(t *testing.T) {
+	registry := NewRegistry().WithVirtualChainId(42).WithNodePublicKey(primitives.Ed25519PublicKey{0xab, 0xcd})
+	registry.NewGauge("BlockStorage.BlockHeight").Update(17)
+
+	require.Equal(t,
+		"# TYPE blockstorage_blockheight gauge\n"+
+			"blockstorage_blockheight{vcid=\"42\",node=\"abcd\"} 17\n",
+		registry.ExportPrometheus())
+}
+
+func TestInMemoryRegistry_ExportPrometheus_SortsMetricsByName(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("Zebra")
+	registry.NewRate("Alpaca")
+
+	require.Equal(t,
+		"# TYPE alpaca gauge\n"+
+			"alpaca 0\n"+
+			"# TYPE zebra gauge\n"+
+			"zebra 0\n",
+		registry.ExportPrometheus())
+}
+
+func TestInMemoryRegistry_ExportPrometheus_Histogram(t *testing.T) {
+	registry := NewRegistry()
+	histogram := registry.NewLatency("Latency", 30*time.Second)
+	for i := 0; i < 10; i++ {
+		histogram.RecordSince(time.Now().Add(-3 * time.Millisecond))
+	}
+	histogram.RecordSince(time.Now().Add(-3 * time.Second))
+
+	exported := registry.ExportPrometheus()
+
+	require.Contains(t, exported, "# TYPE latency histogram\n")
+	require.Contains(t, exported, "latency_bucket{le=\"2\"} 0\n")
+	require.Contains(t, exported, "latency_bucket{le=\"5\"} 10\n")
+	require.Contains(t, exported, "latency_bucket{le=\"2000\"} 10\n")
+	require.Contains(t, exported, "latency_bucket{le=\"5000\"} 11\n")
+	require.Contains(t, exported, "latency_bucket{le=\"+Inf\"} 11\n")
+	require.Contains(t, exported, "latency_count 11\n")
+	require.Contains(t, exported, "# TYPE latency_summary summary\n")
+	require.Contains(t, exported, "latency_summary{quantile=\"0.5\"} 3")
+	require.Contains(t, exported, "latency_summary_count 11\n")
+}
+
+func TestInMemoryRegistry_ExportPrometheus_HistogramCountsSurviveWindowRotation(t *testing.T) {
+	registry := NewRegistry()
+	histogram := registry.NewLatency("Latency", 30*time.Second)
+	histogram.RecordSince(time.Now().Add(-3 * time.Millisecond))
+	histogram.RecordSince(time.Now().Add(-3 * time.Millisecond))
+	histogram.Rotate()
+	histogram.RecordSince(time.Now().Add(-30 * time.Millisecond))
+
+	exported := registry.ExportPrometheus()
+
+	require.Contains(t, exported, "latency_bucket{le=\"5\"} 2\n", "buckets should not drop when the window rotates")
+	require.Contains(t, exported, "latency_bucket{le=\"50\"} 3\n")
+	require.Contains(t, exported, "latency_count 3\n")
+	require.Contains(t, exported, "latency_summary_count 3\n")
+}
+
+func TestInMemoryRegistry_ExportPrometheus_VecChildrenShareFamily(t *testing.T) {
+	registry := NewRegistry().WithVirtualChainId(42)
+	sent := registry.NewCounterVec("Gossip.MessagesSent", "topic", "peer")
+	registry.NewGauge("Gossip.Peers").Update(2)
+	sent.WithLabelValues("TransactionRelay", "a1b2").Add(3)
+	sent.WithLabelValues("BlockSync", "a1b2").Inc()
+
+	require.Equal(t,
+		"# TYPE gossip_messagessent counter\n"+
+			"gossip_messagessent{vcid=\"42\",topic=\"BlockSync\",peer=\"a1b2\"} 1\n"+
+			"gossip_messagessent{vcid=\"42\",topic=\"TransactionRelay\",peer=\"a1b2\"} 3\n"+
+			"# TYPE gossip_peers gauge\n"+
+			"gossip_peers{vcid=\"42\"} 2\n",
+		registry.ExportPrometheus())
+}
+
+func TestInMemoryRegistry_ExportPrometheus_LatencyVec(t *testing.T) {
+	registry := NewRegistry()
+	latencies := registry.NewLatencyVec("Processor.Latency", 30*time.Second, "contract")
+	latencies.WithLabelValues("Tokens").RecordSince(time.Now().Add(-3 * time.Millisecond))
+	latencies.WithLabelValues("Voting").RecordSince(time.Now().Add(-30 * time.Millisecond))
+
+	exported := registry.ExportPrometheus()
+
+	require.Equal(t, 1, strings.Count(exported, "# TYPE processor_latency histogram\n"), "histogram family should have a single TYPE line")
+	require.Equal(t, 1, strings.Count(exported, "# TYPE processor_latency_summary summary\n"), "summary family should have a single TYPE line")
+	require.Contains(t, exported, "processor_latency_bucket{contract=\"Tokens\",le=\"5\"} 1\n")
+	require.Contains(t, exported, "processor_latency_bucket{contract=\"Voting\",le=\"5\"} 0\n")
+	require.True(t, strings.Index(exported, "processor_latency_count{contract=\"Voting\"}") < strings.Index(exported, "# TYPE processor_latency_summary summary"), "all histogram samples should precede the summary family")
+}
@@ -21,6 +21,7 @@ type Rate struct {
 
 type rateExport struct {
 	Name     string
+	Labels   map[string]string `json:",omitempty"`
 	Rate     float64
 	Interval float64
 }
@@ -36,13 +37,14 @@ func newRate(name string) *Rate {
 func (r *Rate) Export() exportedMetric {
 	return rateExport{
 		r.name,
+		r.labelsMap(),
 		r.movingAverage.Value(),
 		toMillis(tickInterval.Nanoseconds()),
 	}
 }
 
 func (r *Rate) String() string {
-	return fmt.Sprintf("metric %s: %f per %s\n", r.name, r.movingAverage.Value(), tickInterval)
+	return fmt.Sprintf("metric %s: %f per %s\n", r.fullName(), r.movingAverage.Value(), tickInterval)
 }
 
 func (r *Rate) Measure(eventCount int64) {
@@ -65,10 +67,10 @@ func (r *Rate) Reset() {
 }
 
 func (r rateExport) LogRow() []*log.Field {
-	return []*log.Field{
+	return withLabelsLogField([]*log.Field{
 		log.String("metric", r.Name),
 		log.String("metric-type", "rate"),
 		log.Float64("rate", r.Rate),
 		log.Float64("interval", r.Interval),
-	}
+	}, r.Labels)
 }
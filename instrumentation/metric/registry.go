@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/synchronization"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,12 +16,19 @@ type Factory interface {
 	NewLatency(name string, maxDuration time.Duration) *Histogram
 	NewGauge(name string) *Gauge
 	NewRate(name string) *Rate
+	NewCounter(name string) *Counter
+	NewLatencyVec(name string, maxDuration time.Duration, labelNames ...string) *LatencyVec
+	NewGaugeVec(name string, labelNames ...string) *GaugeVec
+	NewCounterVec(name string, labelNames ...string) *CounterVec
 }
 
 type Registry interface {
 	Factory
 	String() string
 	ExportAll() map[string]exportedMetric
+	ExportPrometheus() string
+	WithVirtualChainId(id primitives.VirtualChainId) Registry
+	WithNodePublicKey(nodePublicKey primitives.Ed25519PublicKey) Registry
 	ReportEvery(ctx context.Context, interval time.Duration, logger log.BasicLogger)
 }
 
@@ -30,16 +40,63 @@ type metric interface {
 	fmt.Stringer
 	Name() string
 	Export() exportedMetric
+	fullName() string
+	metricLabels() []label
+}
+
+type label struct {
+	name  string
+	value string
 }
 
 type namedMetric struct {
-	name string
+	name   string
+	labels []label // set on children of a vec, eg. per peer or per topic
 }
 
 func (m *namedMetric) Name() string {
 	return m.name
 }
 
+// unique in the registry, eg. Gossip.MessagesSent{topic="BlockSync",peer="a1b2"}
+func (m *namedMetric) fullName() string {
+	if len(m.labels) == 0 {
+		return m.name
+	}
+	pairs := make([]string, len(m.labels))
+	for i, l := range m.labels {
+		pairs[i] = fmt.Sprintf("%s=%q", l.name, l.value)
+	}
+	return fmt.Sprintf("%s{%s}", m.name, strings.Join(pairs, ","))
+}
+
+func (m *namedMetric) metricLabels() []label {
+	return m.labels
+}
+
+func (m *namedMetric) labelsMap() map[string]string {
+	if len(m.labels) == 0 {
+		return nil
+	}
+	res := make(map[string]string)
+	for _, l := range m.labels {
+		res[l.name] = l.value
+	}
+	return res
+}
+
+func withLabelsLogField(logRow []*log.Field, labels map[string]string) []*log.Field {
+	if len(labels) == 0 {
+		return logRow
+	}
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return append(logRow, log.String("metric-labels", strings.Join(pairs, ",")))
+}
+
 func NewRegistry() Registry {
 	return &inMemoryRegistry{}
 }
@@ -48,9 +105,26 @@ type inMemoryRegistry struct {
 	mu struct {
 		sync.Mutex
 		metrics []metric
+		labels  []label
 	}
 }
 
+func (r *inMemoryRegistry) WithVirtualChainId(id primitives.VirtualChainId) Registry {
+	r.addLabel("vcid", fmt.Sprintf("%d", id))
+	return r
+}
+
+func (r *inMemoryRegistry) WithNodePublicKey(nodePublicKey primitives.Ed25519PublicKey) Registry {
+	r.addLabel("node", nodePublicKey.String())
+	return r
+}
+
+func (r *inMemoryRegistry) addLabel(name string, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.labels = append(r.mu.labels, label{name, value})
+}
+
 func (r *inMemoryRegistry) register(m metric) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -64,17 +138,41 @@ func (r *inMemoryRegistry) NewRate(name string) *Rate {
 }
 
 func (r *inMemoryRegistry) NewGauge(name string) *Gauge {
-	g := &Gauge{namedMetric: namedMetric{name: name}}
+	g := newGauge(name, nil)
 	r.register(g)
 	return g
 }
 
 func (r *inMemoryRegistry) NewLatency(name string, maxDuration time.Duration) *Histogram {
-	h := newHistogram(name, maxDuration.Nanoseconds())
+	h := newHistogram(name, nil, maxDuration.Nanoseconds())
 	r.register(h)
 	return h
 }
 
+func (r *inMemoryRegistry) NewCounter(name string) *Counter {
+	c := newCounter(name, nil)
+	r.register(c)
+	return c
+}
+
+func (r *inMemoryRegistry) NewLatencyVec(name string, maxDuration time.Duration, labelNames ...string) *LatencyVec {
+	return &LatencyVec{newMetricVec(r, name, labelNames, func(labels []label) metric {
+		return newHistogram(name, labels, maxDuration.Nanoseconds())
+	})}
+}
+
+func (r *inMemoryRegistry) NewGaugeVec(name string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{newMetricVec(r, name, labelNames, func(labels []label) metric {
+		return newGauge(name, labels)
+	})}
+}
+
+func (r *inMemoryRegistry) NewCounterVec(name string, labelNames ...string) *CounterVec {
+	return &CounterVec{newMetricVec(r, name, labelNames, func(labels []label) metric {
+		return newCounter(name, labels)
+	})}
+}
+
 func (r *inMemoryRegistry) String() string {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -93,7 +191,7 @@ func (r *inMemoryRegistry) ExportAll() map[string]exportedMetric {
 
 	all := make(map[string]exportedMetric)
 	for _, m := range r.mu.metrics {
-		all[m.Name()] = m.Export()
+		all[m.fullName()] = m.Export()
 	}
 
 	return all
@@ -13,3 +13,28 @@ func TestInMemoryRegistry_ExportAll(t *testing.T) {
 	gaugeValue := registry.ExportAll()["hello"].(gaugeExport)
 	require.EqualValues(t, gaugeValue.Value, 1)
 }
+
+func TestInMemoryRegistry_VecCreatesChildrenLazily(t *testing.T) {
+	registry := NewRegistry()
+	sent := registry.NewCounterVec("Gossip.MessagesSent", "topic", "peer")
+	require.Empty(t, registry.ExportAll(), "vec should not register anything before it is used")
+
+	sent.WithLabelValues("BlockSync", "a1b2").Inc()
+	sent.WithLabelValues("BlockSync", "a1b2").Inc()
+	sent.WithLabelValues("TransactionRelay", "a1b2").Add(3)
+
+	exported := registry.ExportAll()
+	require.Len(t, exported, 2, "vec should register a child per label values")
+	require.EqualValues(t, 2, exported[`Gossip.MessagesSent{topic="BlockSync",peer="a1b2"}`].(counterExport).Value)
+	require.EqualValues(t, 3, exported[`Gossip.MessagesSent{topic="TransactionRelay",peer="a1b2"}`].(counterExport).Value)
+	require.Equal(t, map[string]string{"topic": "BlockSync", "peer": "a1b2"}, exported[`Gossip.MessagesSent{topic="BlockSync",peer="a1b2"}`].(counterExport).Labels)
+}
+
+func TestInMemoryRegistry_VecPanicsOnWrongNumberOfLabelValues(t *testing.T) {
+	registry := NewRegistry()
+	gauges := registry.NewGaugeVec("Gossip.PendingMessages", "peer")
+
+	require.Panics(t, func() {
+		gauges.WithLabelValues("a1b2", "extra")
+	})
+}
@@ -0,0 +1,77 @@
+package metric
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// a family of metrics sharing a name and told apart by label values, eg. one counter per gossip topic and peer,
+// children are created and registered on first use
+type metricVec struct {
+	registry   *inMemoryRegistry
+	name       string
+	labelNames []string
+	newChild   func(labels []label) metric
+
+	mutex    sync.Mutex
+	children map[string]metric
+}
+
+func newMetricVec(registry *inMemoryRegistry, name string, labelNames []string, newChild func(labels []label) metric) *metricVec {
+	return &metricVec{
+		registry:   registry,
+		name:       name,
+		labelNames: labelNames,
+		newChild:   newChild,
+		children:   make(map[string]metric),
+	}
+}
+
+// label values are given in the order of the label names the vec was created with
+func (v *metricVec) child(labelValues []string) metric {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metric %s has %d labels but got %d label values", v.name, len(v.labelNames), len(labelValues)))
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	if m, found := v.children[key]; found {
+		return m
+	}
+
+	labels := make([]label, len(v.labelNames))
+	for i, labelName := range v.labelNames {
+		labels[i] = label{labelName, labelValues[i]}
+	}
+	m := v.newChild(labels)
+	v.registry.register(m)
+	v.children[key] = m
+	return m
+}
+
+type CounterVec struct {
+	vec *metricVec
+}
+
+func (v *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	return v.vec.child(labelValues).(*Counter)
+}
+
+type GaugeVec struct {
+	vec *metricVec
+}
+
+func (v *GaugeVec) WithLabelValues(labelValues ...string) *Gauge {
+	return v.vec.child(labelValues).(*Gauge)
+}
+
+type LatencyVec struct {
+	vec *metricVec
+}
+
+func (v *LatencyVec) WithLabelValues(labelValues ...string) *Histogram {
+	return v.vec.child(labelValues).(*Histogram)
+}
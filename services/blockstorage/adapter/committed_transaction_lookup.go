@@ -0,0 +1,35 @@
+package adapter
+
+import (
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+)
+
+// searches persisted blocks directly, for services that need to know about committed transactions on startup,
+// before the block storage service exists
+type BlockPersistenceTransactionLookup struct {
+	persistence BlockPersistence
+	searchRules BlockSearchRules
+}
+
+func NewBlockPersistenceTransactionLookup(persistence BlockPersistence, config config.BlockStorageConfig) *BlockPersistenceTransactionLookup {
+	return &BlockPersistenceTransactionLookup{
+		persistence: persistence,
+		searchRules: BlockSearchRules{
+			EndGraceNano:          config.BlockTransactionReceiptQueryGraceEnd().Nanoseconds(),
+			StartGraceNano:        config.BlockTransactionReceiptQueryGraceStart().Nanoseconds(),
+			TransactionExpireNano: config.BlockTransactionReceiptQueryExpirationWindow().Nanoseconds(),
+		},
+	}
+}
+
+func (l *BlockPersistenceTransactionLookup) IsTransactionCommitted(txHash primitives.Sha256, txTimestamp primitives.TimestampNano) bool {
+	for _, b := range l.persistence.GetBlocksRelevantToTxTimestamp(txTimestamp, l.searchRules) {
+		for _, txr := range b.ResultsBlock.TransactionReceipts {
+			if txr.Txhash().Equal(txHash) {
+				return true
+			}
+		}
+	}
+	return false
+}
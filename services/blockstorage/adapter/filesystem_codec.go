@@ -0,0 +1,215 @@
+package adapter
+
+import (
+	"encoding/binary"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"hash/crc32"
+	"io"
+)
+
+// every record in the block log is laid out as [magic][payload size][payload checksum][payload]
+// the payload is a list of length-prefixed membuffers chunks of a single block pair
+const (
+	blockRecordMagic      = uint32(0x0B5B10C1)
+	blockRecordHeaderSize = 12
+	blockPairFixedChunks  = 5 // txHeader, txMetadata, txProof, rxHeader, rxProof
+	maxBlockRecordSize    = 512 * 1024 * 1024
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+type blockRecordHeader struct {
+	payloadSize uint32
+	checksum    uint32
+}
+
+func encodeBlockRecord(blockPair *protocol.BlockPairContainer) ([]byte, error) {
+	chunks, err := blockPairToChunks(blockPair)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadSize := 4
+	for _, chunk := range chunks {
+		payloadSize += 4 + len(chunk)
+	}
+
+	record := make([]byte, blockRecordHeaderSize+payloadSize)
+	payload := record[blockRecordHeaderSize:]
+
+	binary.LittleEndian.PutUint32(payload, uint32(len(chunks)))
+	offset := 4
+	for _, chunk := range chunks {
+		binary.LittleEndian.PutUint32(payload[offset:], uint32(len(chunk)))
+		offset += 4
+		offset += copy(payload[offset:], chunk)
+	}
+
+	binary.LittleEndian.PutUint32(record[0:], blockRecordMagic)
+	binary.LittleEndian.PutUint32(record[4:], uint32(payloadSize))
+	binary.LittleEndian.PutUint32(record[8:], crc32.Checksum(payload, crcTable))
+
+	return record, nil
+}
+
+func readBlockRecordHeader(r io.ReaderAt, offset int64) (*blockRecordHeader, error) {
+	buf := make([]byte, blockRecordHeaderSize)
+	if _, err := r.ReadAt(buf, offset); err != nil {
+		return nil, err
+	}
+
+	if magic := binary.LittleEndian.Uint32(buf[0:]); magic != blockRecordMagic {
+		return nil, errors.Errorf("invalid block record magic %x at offset %d", magic, offset)
+	}
+
+	header := &blockRecordHeader{
+		payloadSize: binary.LittleEndian.Uint32(buf[4:]),
+		checksum:    binary.LittleEndian.Uint32(buf[8:]),
+	}
+	if header.payloadSize > maxBlockRecordSize {
+		return nil, errors.Errorf("block record at offset %d is too large (%d bytes)", offset, header.payloadSize)
+	}
+
+	return header, nil
+}
+
+// returns the decoded block pair and the total size of the record on disk
+func readBlockRecord(r io.ReaderAt, offset int64) (*protocol.BlockPairContainer, int64, error) {
+	header, err := readBlockRecordHeader(r, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	payload := make([]byte, header.payloadSize)
+	if _, err := r.ReadAt(payload, offset+blockRecordHeaderSize); err != nil {
+		return nil, 0, err
+	}
+
+	if checksum := crc32.Checksum(payload, crcTable); checksum != header.checksum {
+		return nil, 0, errors.Errorf("block record checksum mismatch at offset %d, expected %x got %x", offset, header.checksum, checksum)
+	}
+
+	chunks, err := payloadToChunks(payload)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "corrupt block record at offset %d", offset)
+	}
+
+	blockPair, err := chunksToBlockPair(chunks)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "corrupt block record at offset %d", offset)
+	}
+
+	return blockPair, blockRecordHeaderSize + int64(header.payloadSize), nil
+}
+
+func payloadToChunks(payload []byte) ([][]byte, error) {
+	if len(payload) < 4 {
+		return nil, errors.New("payload too short")
+	}
+
+	numChunks := binary.LittleEndian.Uint32(payload)
+	chunks := make([][]byte, 0, numChunks)
+	offset := uint32(4)
+	for i := uint32(0); i < numChunks; i++ {
+		if uint32(len(payload)) < offset+4 {
+			return nil, errors.Errorf("missing size of chunk %d", i)
+		}
+		size := binary.LittleEndian.Uint32(payload[offset:])
+		offset += 4
+		if uint32(len(payload)) < offset+size {
+			return nil, errors.Errorf("chunk %d is truncated", i)
+		}
+		chunks = append(chunks, payload[offset:offset+size])
+		offset += size
+	}
+
+	return chunks, nil
+}
+
+func blockPairToChunks(blockPair *protocol.BlockPairContainer) ([][]byte, error) {
+	if blockPair == nil || blockPair.TransactionsBlock == nil || blockPair.ResultsBlock == nil ||
+		blockPair.TransactionsBlock.Header == nil ||
+		blockPair.TransactionsBlock.Metadata == nil ||
+		blockPair.TransactionsBlock.BlockProof == nil ||
+		blockPair.ResultsBlock.Header == nil ||
+		blockPair.ResultsBlock.BlockProof == nil {
+		return nil, errors.New("failed to encode block pair due to missing fields")
+	}
+
+	chunks := make([][]byte, 0, blockPairFixedChunks+
+		len(blockPair.TransactionsBlock.SignedTransactions)+
+		len(blockPair.ResultsBlock.TransactionReceipts)+
+		len(blockPair.ResultsBlock.ContractStateDiffs))
+
+	chunks = append(chunks, blockPair.TransactionsBlock.Header.Raw())
+	chunks = append(chunks, blockPair.TransactionsBlock.Metadata.Raw())
+	chunks = append(chunks, blockPair.TransactionsBlock.BlockProof.Raw())
+	chunks = append(chunks, blockPair.ResultsBlock.Header.Raw())
+	chunks = append(chunks, blockPair.ResultsBlock.BlockProof.Raw())
+
+	for _, tx := range blockPair.TransactionsBlock.SignedTransactions {
+		chunks = append(chunks, tx.Raw())
+	}
+	for _, receipt := range blockPair.ResultsBlock.TransactionReceipts {
+		chunks = append(chunks, receipt.Raw())
+	}
+	for _, sdiff := range blockPair.ResultsBlock.ContractStateDiffs {
+		chunks = append(chunks, sdiff.Raw())
+	}
+
+	return chunks, nil
+}
+
+func chunksToBlockPair(chunks [][]byte) (*protocol.BlockPairContainer, error) {
+	if len(chunks) < blockPairFixedChunks {
+		return nil, errors.Errorf("expected at least %d chunks, got %d", blockPairFixedChunks, len(chunks))
+	}
+
+	txBlockHeader := protocol.TransactionsBlockHeaderReader(chunks[0])
+	txBlockMetadata := protocol.TransactionsBlockMetadataReader(chunks[1])
+	txBlockProof := protocol.TransactionsBlockProofReader(chunks[2])
+	rxBlockHeader := protocol.ResultsBlockHeaderReader(chunks[3])
+	rxBlockProof := protocol.ResultsBlockProofReader(chunks[4])
+
+	numTxs := txBlockHeader.NumSignedTransactions()
+	numReceipts := rxBlockHeader.NumTransactionReceipts()
+	numDiffs := rxBlockHeader.NumContractStateDiffs()
+	if uint32(len(chunks)) != blockPairFixedChunks+numTxs+numReceipts+numDiffs {
+		return nil, errors.Errorf("expected %d chunks, got %d", blockPairFixedChunks+numTxs+numReceipts+numDiffs, len(chunks))
+	}
+
+	index := uint32(blockPairFixedChunks)
+
+	txs := make([]*protocol.SignedTransaction, 0, numTxs)
+	for i := uint32(0); i < numTxs; i++ {
+		txs = append(txs, protocol.SignedTransactionReader(chunks[index+i]))
+	}
+	index += numTxs
+
+	receipts := make([]*protocol.TransactionReceipt, 0, numReceipts)
+	for i := uint32(0); i < numReceipts; i++ {
+		receipts = append(receipts, protocol.TransactionReceiptReader(chunks[index+i]))
+	}
+	index += numReceipts
+
+	sdiffs := make([]*protocol.ContractStateDiff, 0, numDiffs)
+	for i := uint32(0); i < numDiffs; i++ {
+		sdiffs = append(sdiffs, protocol.ContractStateDiffReader(chunks[index+i]))
+	}
+
+	return &protocol.BlockPairContainer{
+		TransactionsBlock: &protocol.TransactionsBlockContainer{
+			Header:             txBlockHeader,
+			Metadata:           txBlockMetadata,
+			SignedTransactions: txs,
+			BlockProof:         txBlockProof,
+		},
+		ResultsBlock: &protocol.ResultsBlockContainer{
+			Header:              rxBlockHeader,
+			TransactionReceipts: receipts,
+			ContractStateDiffs:  sdiffs,
+			BlockProof:          rxBlockProof,
+		},
+	}, nil
+}
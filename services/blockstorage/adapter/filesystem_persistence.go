@@ -0,0 +1,292 @@
+package adapter
+
+import (
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/synchronization"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const blocksFilename = "blocks.log"
+
+var LogTag = log.String("adapter", "block-storage")
+
+type heightIndex struct {
+	offsets    []int64 // offsets[h-1] is where the record of block h starts
+	timestamps []primitives.TimestampNano
+}
+
+func (i *heightIndex) numBlocks() primitives.BlockHeight {
+	return primitives.BlockHeight(len(i.offsets))
+}
+
+func (i *heightIndex) append(offset int64, ts primitives.TimestampNano) {
+	i.offsets = append(i.offsets, offset)
+	i.timestamps = append(i.timestamps, ts)
+}
+
+// Appends block pairs to a single log file on disk; only a small height index and the last block are kept in memory
+type filesystemBlockPersistence struct {
+	logger  log.BasicLogger
+	tracker *synchronization.BlockTracker
+
+	mutex       sync.RWMutex
+	file        *os.File
+	writeOffset int64
+	index       heightIndex
+	lastBlock   *protocol.BlockPairContainer
+}
+
+func NewFilesystemBlockPersistence(conf config.FilesystemBlockPersistenceConfig, parentLogger log.BasicLogger) (BlockPersistence, error) {
+	logger := parentLogger.WithTags(LogTag)
+
+	dir := conf.BlockStorageDataDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create block storage data dir %s", dir)
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, blocksFilename), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open block log in %s", dir)
+	}
+
+	p := &filesystemBlockPersistence{
+		logger: logger,
+		file:   file,
+	}
+
+	if err := p.recover(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	p.tracker = synchronization.NewBlockTracker(uint64(p.index.numBlocks()), 5)
+
+	logger.Info("loaded blocks from disk", log.BlockHeight(p.index.numBlocks()), log.String("path", file.Name()))
+
+	return p, nil
+}
+
+// scans the whole log to rebuild the height index, truncating a torn last record left by a crash; a bad record
+// followed by more data is corruption rather than a crash and fails the startup instead of discarding later blocks
+func (p *filesystemBlockPersistence) recover() error {
+	info, err := p.file.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat block log")
+	}
+	fileSize := info.Size()
+
+	offset := int64(0)
+	for offset < fileSize {
+		blockPair, size, err := readBlockRecord(p.file, offset)
+		if err != nil {
+			if !recordRunsToEndOfFile(p.file, offset, fileSize) {
+				return errors.Wrapf(err, "block log is corrupt at offset %d before its last record", offset)
+			}
+			p.logger.Error("block log has a torn tail, truncating", log.Error(err), log.Int64("offset", offset), log.Int64("discarded-bytes", fileSize-offset))
+			if err := p.file.Truncate(offset); err != nil {
+				return errors.Wrapf(err, "failed to truncate block log to %d bytes", offset)
+			}
+			if err := p.file.Sync(); err != nil {
+				return errors.Wrap(err, "failed to sync block log after truncation")
+			}
+			break
+		}
+
+		expectedHeight := p.index.numBlocks() + 1
+		if height := blockPair.TransactionsBlock.Header.BlockHeight(); height != expectedHeight {
+			return errors.Errorf("block log is corrupt, found block with height %d at offset %d when expecting %d", height, offset, expectedHeight)
+		}
+
+		p.index.append(offset, blockPair.TransactionsBlock.Header.Timestamp())
+		p.lastBlock = blockPair
+		offset += size
+	}
+
+	p.writeOffset = offset
+	return nil
+}
+
+// a torn write can only leave a partial record at the end of the log, so the record must end at or beyond the end of
+// the file according to its own header (or not even have a complete header)
+func recordRunsToEndOfFile(r io.ReaderAt, offset int64, fileSize int64) bool {
+	if offset+blockRecordHeaderSize >= fileSize {
+		return true
+	}
+	header, err := readBlockRecordHeader(r, offset)
+	if err != nil {
+		return false
+	}
+	return offset+blockRecordHeaderSize+int64(header.payloadSize) >= fileSize
+}
+
+func (p *filesystemBlockPersistence) GetBlockTracker() *synchronization.BlockTracker {
+	return p.tracker
+}
+
+func (p *filesystemBlockPersistence) WriteNextBlock(blockPair *protocol.BlockPairContainer) error {
+	record, err := encodeBlockRecord(blockPair)
+	if err != nil {
+		return err
+	}
+
+	if err := p.appendRecord(blockPair, record); err != nil {
+		return err
+	}
+
+	p.tracker.IncrementHeight()
+
+	return nil
+}
+
+func (p *filesystemBlockPersistence) appendRecord(blockPair *protocol.BlockPairContainer, record []byte) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.index.numBlocks()+1 != blockPair.TransactionsBlock.Header.BlockHeight() {
+		return errors.Errorf("block persistence tried to write next block with height %d when %d exist", blockPair.TransactionsBlock.Header.BlockHeight(), p.index.numBlocks())
+	}
+
+	if _, err := p.file.WriteAt(record, p.writeOffset); err != nil {
+		// whatever was partially written will be overwritten by the next append or truncated on recovery
+		return errors.Wrapf(err, "failed to write block %d", blockPair.TransactionsBlock.Header.BlockHeight())
+	}
+
+	if err := p.file.Sync(); err != nil {
+		return errors.Wrapf(err, "failed to sync block %d", blockPair.TransactionsBlock.Header.BlockHeight())
+	}
+
+	p.index.append(p.writeOffset, blockPair.TransactionsBlock.Header.Timestamp())
+	p.writeOffset += int64(len(record))
+	p.lastBlock = blockPair
+
+	return nil
+}
+
+func (p *filesystemBlockPersistence) GetLastBlock() (*protocol.BlockPairContainer, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.lastBlock, nil
+}
+
+func (p *filesystemBlockPersistence) GetNumBlocks() (primitives.BlockHeight, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.index.numBlocks(), nil
+}
+
+func (p *filesystemBlockPersistence) offsetOf(height primitives.BlockHeight) (int64, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if height == 0 || height > p.index.numBlocks() {
+		return 0, errors.Errorf("block with height %d not found in block persistence", height)
+	}
+
+	return p.index.offsets[height-1], nil
+}
+
+func (p *filesystemBlockPersistence) getBlockPairAtHeight(height primitives.BlockHeight) (*protocol.BlockPairContainer, error) {
+	offset, err := p.offsetOf(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockPair, _, err := readBlockRecord(p.file, offset)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read block %d from disk", height)
+	}
+
+	return blockPair, nil
+}
+
+func (p *filesystemBlockPersistence) GetTransactionsBlock(height primitives.BlockHeight) (*protocol.TransactionsBlockContainer, error) {
+	blockPair, err := p.getBlockPairAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return blockPair.TransactionsBlock, nil
+}
+
+func (p *filesystemBlockPersistence) GetResultsBlock(height primitives.BlockHeight) (*protocol.ResultsBlockContainer, error) {
+	blockPair, err := p.getBlockPairAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return blockPair.ResultsBlock, nil
+}
+
+func (p *filesystemBlockPersistence) GetBlocks(first primitives.BlockHeight, last primitives.BlockHeight) (blocks []*protocol.BlockPairContainer, firstReturnedBlockHeight primitives.BlockHeight, lastReturnedBlockHeight primitives.BlockHeight, err error) {
+	numBlocks, _ := p.GetNumBlocks()
+
+	if first == 0 || first > numBlocks {
+		return nil, 0, 0, nil
+	}
+	firstReturnedBlockHeight = first
+
+	lastReturnedBlockHeight = last
+	if last > numBlocks {
+		lastReturnedBlockHeight = numBlocks
+	}
+
+	for height := first; height <= lastReturnedBlockHeight; height++ {
+		blockPair, err := p.getBlockPairAtHeight(height)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		blocks = append(blocks, blockPair)
+	}
+
+	return blocks, firstReturnedBlockHeight, lastReturnedBlockHeight, nil
+}
+
+func (p *filesystemBlockPersistence) GetBlocksRelevantToTxTimestamp(txTimeStamp primitives.TimestampNano, rules BlockSearchRules) []*protocol.BlockPairContainer {
+	start := txTimeStamp - primitives.TimestampNano(rules.StartGraceNano)
+	end := txTimeStamp + primitives.TimestampNano(rules.EndGraceNano+rules.TransactionExpireNano)
+
+	if end < start {
+		return nil
+	}
+	interval := end - start
+	// same sanity check as the in-memory adapter, we never want to scan the whole chain for a single receipt
+	if interval > primitives.TimestampNano(time.Hour.Nanoseconds()) {
+		return nil
+	}
+
+	var relevantHeights []primitives.BlockHeight
+	p.mutex.RLock()
+	for i, ts := range p.index.timestamps {
+		delta := end - ts
+		if delta > 0 && interval > delta {
+			relevantHeights = append(relevantHeights, primitives.BlockHeight(i+1))
+		}
+	}
+	p.mutex.RUnlock()
+
+	var relevantBlocks []*protocol.BlockPairContainer
+	for _, height := range relevantHeights {
+		blockPair, err := p.getBlockPairAtHeight(height)
+		if err != nil {
+			p.logger.Error("failed to read block while searching for transaction receipt", log.Error(err), log.BlockHeight(height))
+			continue
+		}
+		relevantBlocks = append(relevantBlocks, blockPair)
+	}
+	return relevantBlocks
+}
+
+func (p *filesystemBlockPersistence) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.file.Close()
+}
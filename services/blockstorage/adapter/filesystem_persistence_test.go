@@ -0,0 +1,203 @@
+package adapter
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newFilesystemPersistenceForTests(t *testing.T, dir string) *filesystemBlockPersistence {
+	p, err := NewFilesystemBlockPersistence(config.ForFilesystemBlockPersistenceTests(dir), log.GetLogger())
+	require.NoError(t, err, "failed to open block persistence")
+	return p.(*filesystemBlockPersistence)
+}
+
+func writeBlocks(t *testing.T, p BlockPersistence, count int) []*protocol.BlockPairContainer {
+	var prev *protocol.BlockPairContainer
+	var blocks []*protocol.BlockPairContainer
+	for i := 1; i <= count; i++ {
+		block := builders.BlockPair().
+			WithHeight(primitives.BlockHeight(i)).
+			WithTransactions(uint32(i % 3)).
+			WithReceipts(uint32(i % 3)).
+			WithStateDiffs(1).
+			WithPrevBlockHash(prev).
+			Build()
+		require.NoError(t, p.WriteNextBlock(block), "failed to write block %d", i)
+		blocks = append(blocks, block)
+		prev = block
+	}
+	return blocks
+}
+
+func requireSameBlockPair(t *testing.T, expected *protocol.BlockPairContainer, actual *protocol.BlockPairContainer) {
+	require.Equal(t, expected.TransactionsBlock.Header.Raw(), actual.TransactionsBlock.Header.Raw(), "transactions block header mismatch")
+	require.Equal(t, expected.ResultsBlock.Header.Raw(), actual.ResultsBlock.Header.Raw(), "results block header mismatch")
+	require.Equal(t, expected.ResultsBlock.BlockProof.Raw(), actual.ResultsBlock.BlockProof.Raw(), "results block proof mismatch")
+	require.Len(t, actual.TransactionsBlock.SignedTransactions, len(expected.TransactionsBlock.SignedTransactions), "transaction count mismatch")
+	for i, tx := range expected.TransactionsBlock.SignedTransactions {
+		require.Equal(t, tx.Raw(), actual.TransactionsBlock.SignedTransactions[i].Raw(), "transaction %d mismatch", i)
+	}
+	require.Len(t, actual.ResultsBlock.ContractStateDiffs, len(expected.ResultsBlock.ContractStateDiffs), "state diff count mismatch")
+}
+
+func TestFilesystemBlockPersistence_ReloadsBlocksAfterRestart(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	p := newFilesystemPersistenceForTests(t, dir)
+	blocks := writeBlocks(t, p, 10)
+	require.NoError(t, p.Close())
+
+	reopened := newFilesystemPersistenceForTests(t, dir)
+	defer reopened.Close()
+
+	numBlocks, err := reopened.GetNumBlocks()
+	require.NoError(t, err)
+	require.EqualValues(t, 10, numBlocks, "all blocks should be loaded from disk")
+
+	lastBlock, err := reopened.GetLastBlock()
+	require.NoError(t, err)
+	requireSameBlockPair(t, blocks[9], lastBlock)
+
+	txBlock, err := reopened.GetTransactionsBlock(4)
+	require.NoError(t, err)
+	require.Equal(t, blocks[3].TransactionsBlock.Header.Raw(), txBlock.Header.Raw())
+
+	rxBlock, err := reopened.GetResultsBlock(7)
+	require.NoError(t, err)
+	require.Equal(t, blocks[6].ResultsBlock.Header.Raw(), rxBlock.Header.Raw())
+
+	test.WithContextWithTimeout(time.Second, func(ctx context.Context) {
+		require.NoError(t, reopened.GetBlockTracker().WaitForBlock(ctx, 10), "block tracker should start from the last persisted height")
+	})
+}
+
+func TestFilesystemBlockPersistence_GetBlocks(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	p := newFilesystemPersistenceForTests(t, dir)
+	defer p.Close()
+	blocks := writeBlocks(t, p, 5)
+
+	fetched, first, last, err := p.GetBlocks(2, 8)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, first)
+	require.EqualValues(t, 5, last, "last returned height should be capped by the number of blocks")
+	require.Len(t, fetched, 4)
+	for i, block := range fetched {
+		requireSameBlockPair(t, blocks[i+1], block)
+	}
+
+	fetched, _, _, err = p.GetBlocks(6, 8)
+	require.NoError(t, err)
+	require.Empty(t, fetched, "no blocks should be returned beyond the top of the chain")
+
+	_, err = p.GetTransactionsBlock(6)
+	require.Error(t, err, "reading a block that was not written should fail")
+}
+
+func TestFilesystemBlockPersistence_RejectsBlocksOutOfOrder(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	p := newFilesystemPersistenceForTests(t, dir)
+	defer p.Close()
+	writeBlocks(t, p, 2)
+
+	err := p.WriteNextBlock(builders.BlockPair().WithHeight(4).Build())
+	require.Error(t, err, "a block with a gap in height should be rejected")
+
+	numBlocks, _ := p.GetNumBlocks()
+	require.EqualValues(t, 2, numBlocks)
+}
+
+func TestFilesystemBlockPersistence_TruncatesTornTailOnRecovery(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	p := newFilesystemPersistenceForTests(t, dir)
+	blocks := writeBlocks(t, p, 3)
+	sizeWithThreeBlocks := p.writeOffset
+	require.NoError(t, p.WriteNextBlock(builders.BlockPair().WithHeight(4).WithPrevBlockHash(blocks[2]).Build()))
+	require.NoError(t, p.Close())
+
+	// simulate a crash in the middle of writing the fourth block
+	path := filepath.Join(dir, blocksFilename)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-7))
+
+	reopened := newFilesystemPersistenceForTests(t, dir)
+	numBlocks, _ := reopened.GetNumBlocks()
+	require.EqualValues(t, 3, numBlocks, "torn block should be discarded")
+	require.EqualValues(t, sizeWithThreeBlocks, reopened.writeOffset, "log should be truncated to the last complete record")
+
+	lastBlock, _ := reopened.GetLastBlock()
+	requireSameBlockPair(t, blocks[2], lastBlock)
+
+	newBlock := builders.BlockPair().WithHeight(4).WithPrevBlockHash(blocks[2]).Build()
+	require.NoError(t, reopened.WriteNextBlock(newBlock), "should be able to append after recovery")
+	require.NoError(t, reopened.Close())
+
+	again := newFilesystemPersistenceForTests(t, dir)
+	defer again.Close()
+	numBlocks, _ = again.GetNumBlocks()
+	require.EqualValues(t, 4, numBlocks)
+}
+
+func corruptBlockRecordPayload(t *testing.T, dir string, recordOffset int64) {
+	file, err := os.OpenFile(filepath.Join(dir, blocksFilename), os.O_RDWR, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteAt([]byte{0xff}, recordOffset+blockRecordHeaderSize+10)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+func TestFilesystemBlockPersistence_TruncatesCorruptLastRecordOnRecovery(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	p := newFilesystemPersistenceForTests(t, dir)
+	writeBlocks(t, p, 3)
+	lastBlockOffset := p.index.offsets[2]
+	require.NoError(t, p.Close())
+
+	corruptBlockRecordPayload(t, dir, lastBlockOffset)
+
+	reopened := newFilesystemPersistenceForTests(t, dir)
+	defer reopened.Close()
+	numBlocks, _ := reopened.GetNumBlocks()
+	require.EqualValues(t, 2, numBlocks, "corrupt last record should be discarded as a torn write")
+	require.EqualValues(t, lastBlockOffset, reopened.writeOffset, "log should be truncated to the last complete record")
+}
+
+func TestFilesystemBlockPersistence_FailsOnCorruptRecordBeforeTheLast(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	p := newFilesystemPersistenceForTests(t, dir)
+	writeBlocks(t, p, 3)
+	secondBlockOffset := p.index.offsets[1]
+	sizeWithThreeBlocks := p.writeOffset
+	require.NoError(t, p.Close())
+
+	corruptBlockRecordPayload(t, dir, secondBlockOffset)
+
+	_, err := NewFilesystemBlockPersistence(config.ForFilesystemBlockPersistenceTests(dir), log.GetLogger())
+	require.Error(t, err, "corruption followed by more records should fail the startup")
+
+	info, err := os.Stat(filepath.Join(dir, blocksFilename))
+	require.NoError(t, err)
+	require.EqualValues(t, sizeWithThreeBlocks, info.Size(), "later blocks should not be truncated")
+}
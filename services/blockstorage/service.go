@@ -25,6 +25,11 @@ const (
 
 var LogTag = log.Service("block-storage")
 
+// lets a consensus algo that committed a block it could not store catch up from the other nodes right away
+type BlockSyncStarter interface {
+	StartBlockSync()
+}
+
 type service struct {
 	persistence  adapter.BlockPersistence
 	stateStorage services.StateStorage
@@ -67,12 +72,34 @@ func NewBlockStorage(ctx context.Context, config config.BlockStorageConfig, pers
 		metrics:      newMetrics(metricFactory),
 	}
 
+	if err := s.syncStateStorageOnInit(ctx); err != nil {
+		logger.Error("failed to sync state storage with persisted blocks", log.Error(err))
+	}
+
 	gossip.RegisterBlockSyncHandler(s)
 	s.blockSync = blockSync.NewBlockSync(ctx, config, gossip, s, logger, metricFactory)
 
 	return s
 }
 
+func (s *service) StartBlockSync() {
+	s.blockSync.StartSyncNow()
+}
+
+func (s *service) syncStateStorageOnInit(ctx context.Context) error {
+	lastCommittedBlock, err := s.persistence.GetLastBlock()
+	if err != nil || lastCommittedBlock == nil {
+		return err
+	}
+
+	out, err := s.stateStorage.GetStateStorageBlockHeight(ctx, &services.GetStateStorageBlockHeightInput{})
+	if err != nil {
+		return err
+	}
+
+	return s.syncPersistedBlocksToStateStorage(ctx, out.LastCommittedBlockHeight+1, getBlockHeight(lastCommittedBlock))
+}
+
 func (s *service) GetLastCommittedBlockHeight(ctx context.Context, input *services.GetLastCommittedBlockHeightInput) (*services.GetLastCommittedBlockHeightOutput, error) {
 	b, err := s.persistence.GetLastBlock()
 	if err != nil {
@@ -402,11 +429,48 @@ func (s *service) validateProtocolVersion(blockPair *protocol.BlockPairContainer
 
 // TODO: this should not be called directly from CommitBlock, it should be called from a long living goroutine that continuously syncs the state storage
 func (s *service) syncBlockToStateStorage(ctx context.Context, committedBlockPair *protocol.BlockPairContainer) error {
-	_, err := s.stateStorage.CommitStateDiff(ctx, &services.CommitStateDiffInput{
+	out, err := s.stateStorage.CommitStateDiff(ctx, &services.CommitStateDiffInput{
 		ResultsBlockHeader: committedBlockPair.ResultsBlock.Header,
 		ContractStateDiffs: committedBlockPair.ResultsBlock.ContractStateDiffs,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	committedHeight := committedBlockPair.ResultsBlock.Header.BlockHeight()
+	if out != nil && out.NextDesiredBlockHeight != 0 && out.NextDesiredBlockHeight <= committedHeight {
+		return s.syncPersistedBlocksToStateStorage(ctx, out.NextDesiredBlockHeight, committedHeight)
+	}
+	return nil
+}
+
+// state storage only persists revisions once they are old enough, so after a restart it may lag behind the blocks we have on disk
+func (s *service) syncPersistedBlocksToStateStorage(ctx context.Context, from primitives.BlockHeight, to primitives.BlockHeight) error {
+	if from > to {
+		return nil
+	}
+
+	s.logger.Info("syncing persisted blocks to state storage", log.Stringable("from-block-height", from), log.Stringable("to-block-height", to))
+
+	for height := from; height <= to; height++ {
+		resultsBlock, err := s.persistence.GetResultsBlock(height)
+		if err != nil {
+			return err
+		}
+
+		out, err := s.stateStorage.CommitStateDiff(ctx, &services.CommitStateDiffInput{
+			ResultsBlockHeader: resultsBlock.Header,
+			ContractStateDiffs: resultsBlock.ContractStateDiffs,
+		})
+		if err != nil {
+			return err
+		}
+
+		if out != nil && out.NextDesiredBlockHeight != 0 && out.NextDesiredBlockHeight != height+1 {
+			return errors.Errorf("state storage desires block height %d after being synced with %d", out.NextDesiredBlockHeight, height)
+		}
+	}
+	return nil
 }
 
 // TODO: this should not be called directly from CommitBlock, it should be called from a long living goroutine that continuously syncs the state storage
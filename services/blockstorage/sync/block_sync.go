@@ -45,6 +45,7 @@ type BlockSyncStorage interface {
 // the data that the states receive, regardless of their instance, is waiting at these channels
 type blockSyncConduit struct {
 	idleReset chan struct{}
+	syncNow   chan struct{}
 	responses chan *gossipmessages.BlockAvailabilityResponseMessage
 	blocks    chan *gossipmessages.BlockSyncResponseMessage
 }
@@ -102,6 +103,7 @@ func NewBlockSync(ctx context.Context, config blockSyncConfig, gossip gossiptopi
 
 	conduit := &blockSyncConduit{
 		idleReset: make(chan struct{}),
+		syncNow:   make(chan struct{}, 1),
 		responses: make(chan *gossipmessages.BlockAvailabilityResponseMessage),
 		blocks:    make(chan *gossipmessages.BlockSyncResponseMessage),
 	}
@@ -136,6 +138,15 @@ func (bs *BlockSync) HandleBlockCommitted(ctx context.Context) {
 	}
 }
 
+// starts syncing right away instead of when the no-commit timer expires, a request made while syncing is served by
+// the next idle state
+func (bs *BlockSync) StartSyncNow() {
+	select {
+	case bs.conduit.syncNow <- struct{}{}:
+	default: // a request is already pending
+	}
+}
+
 func (bs *BlockSync) HandleBlockAvailabilityResponse(ctx context.Context, input *gossiptopics.BlockAvailabilityResponseInput) (*gossiptopics.EmptyOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, bs.config.BlockSyncCollectResponseTimeout()/2)
 	defer cancel()
@@ -77,6 +77,7 @@ func newBlockSyncHarnessWithTimers(
 	logger := log.GetLogger()
 	conduit := &blockSyncConduit{
 		idleReset: make(chan struct{}),
+		syncNow:   make(chan struct{}, 1),
 		responses: make(chan *gossipmessages.BlockAvailabilityResponseMessage),
 		blocks:    make(chan *gossipmessages.BlockSyncResponseMessage),
 	}
@@ -39,6 +39,9 @@ func (s *idleState) processState(ctx context.Context) syncState {
 	case <-s.conduit.idleReset:
 		s.metrics.timesReset.Inc()
 		return s.factory.CreateIdleState()
+	case <-s.conduit.syncNow:
+		logger.Info("starting sync on request")
+		return s.factory.CreateCollectingAvailabilityResponseState()
 	case <-ctx.Done():
 		return nil
 	}
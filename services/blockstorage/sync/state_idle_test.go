@@ -37,6 +37,21 @@ func TestStateIdle_MovesToCollectingAvailabilityResponsesOnNoCommitTimeout(t *te
 	})
 }
 
+func TestStateIdle_MovesToCollectingAvailabilityResponsesWhenSyncIsRequested(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		manualNoCommitTimer := synchronization.NewTimerWithManualTick()
+		h := newBlockSyncHarnessWithManualNoCommitTimeoutTimer(func() *synchronization.Timer {
+			return manualNoCommitTimer
+		})
+
+		state := h.factory.CreateIdleState()
+		h.factory.conduit.syncNow <- struct{}{}
+		nextState := state.processState(ctx)
+
+		require.IsType(t, &collectingAvailabilityResponsesState{}, nextState, "processState state should be collecting availability responses without waiting for the no-commit timer")
+	})
+}
+
 func TestStateIdle_TerminatesOnContextTermination(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	h := newBlockSyncHarness()
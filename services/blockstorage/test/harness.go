@@ -192,6 +192,9 @@ func (d *harness) setupCustomBlocksForInit() time.Time {
 
 	d.consensus.When("HandleBlockConsensus", mock.Any, mock.Any).Return(out, nil).Times(1)
 
+	// state storage is already in sync with the persisted blocks
+	d.stateStorage.When("GetStateStorageBlockHeight", mock.Any, mock.Any).Return(&services.GetStateStorageBlockHeightOutput{LastCommittedBlockHeight: 10}, nil).Times(1)
+
 	return now
 }
 
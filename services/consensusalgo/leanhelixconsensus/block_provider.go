@@ -6,8 +6,9 @@ import (
 	lhprimitives "github.com/orbs-network/lean-helix-go/primitives"
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/crypto/logic"
-	"github.com/orbs-network/orbs-network-go/crypto/signature"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/services/blockstorage"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
@@ -35,30 +36,13 @@ func (s *service) HandleBlockConsensus(ctx context.Context, input *handlers.Hand
 	}
 
 	// update lastCommitted to reflect this if newer
-	// TODO: Gad - how to update internal block height and continue from there
 	if mode == handlers.HANDLE_BLOCK_CONSENSUS_MODE_VERIFY_AND_UPDATE || mode == handlers.HANDLE_BLOCK_CONSENSUS_MODE_UPDATE_ONLY {
-		//lastCommittedBlockHeight, lastCommittedBlock := s.getLastCommittedBlock()
-		//
-		//
-		//if blockPair.TransactionsBlock.Header.BlockHeight() > lastCommittedBlockHeight {
-		//
-		//	// TODO Set last committed block here?
-		//	//err := s.setLastCommittedBlock(blockPair, lastCommittedBlock)
-		//	//if err != nil {
-		//	//	return err
-		//	//}
-		//	// don't forget to update internal vars too since they may be used later on in the function
-		//	lastCommittedBlock = blockPair
-		//	lastCommittedBlockHeight = lastCommittedBlock.TransactionsBlock.Header.BlockHeight()
-		//}
+		s.setLastCommittedBlockIfNewer(blockPair)
 	}
 
 	return nil, nil
 }
 
-func (s *service) updateLastCommit(mode handlers.HandleBlockConsensusMode, blockPair *protocol.BlockPairContainer) {
-}
-
 func (s *service) validateBlockConsensus(blockPair *protocol.BlockPairContainer, prevCommittedBlockPair *protocol.BlockPairContainer) error {
 	// correct block type
 	if !blockPair.TransactionsBlock.BlockProof.IsTypeLeanHelix() {
@@ -68,9 +52,6 @@ func (s *service) validateBlockConsensus(blockPair *protocol.BlockPairContainer,
 		return errors.Errorf("incorrect block proof type: %s", blockPair.ResultsBlock.BlockProof.Type())
 	}
 
-	panic("should not have reached here - not supposed to have generated any Lean Helix blocks!!")
-
-	// TODO Verify with Gad - do this here?
 	// prev block hash ptr (if given)
 	if prevCommittedBlockPair != nil {
 		prevTxHash := digest.CalcTransactionsBlockHash(prevCommittedBlockPair.TransactionsBlock)
@@ -83,17 +64,7 @@ func (s *service) validateBlockConsensus(blockPair *protocol.BlockPairContainer,
 		}
 	}
 
-	// TODO block proof
-	//blockProof := blockPair.ResultsBlock.BlockProof.LeanHelix()
-	//if !blockProof.Sender().SenderPublicKey().Equal(s.config.ConstantConsensusLeader()) {
-	//	return errors.Errorf("block proof not from leader: %s", blockProof.Sender().SenderPublicKey())
-	//}
-	//signedData := s.signedDataForBlockProof(blockPair)
-	//if !signature.VerifyEd25519(blockProof.Sender().SenderPublicKey(), signedData, blockProof.Sender().Signature()) {
-	//	return errors.Errorf("block proof signature is invalid: %s", blockProof.Sender().Signature())
-	//}
-
-	return nil
+	return s.verifyBlockProof(blockPair)
 }
 
 func (s *service) getLastCommittedBlock() (primitives.BlockHeight, *protocol.BlockPairContainer) {
@@ -106,94 +77,157 @@ func (s *service) getLastCommittedBlock() (primitives.BlockHeight, *protocol.Blo
 	return s.lastCommittedBlock.block.TransactionsBlock.Header.BlockHeight(), s.lastCommittedBlock.block
 }
 
-func (s *service) RequestNewBlock(parentCtx context.Context, blockHeight lhprimitives.BlockHeight) leanhelix.Block {
+func (s *service) setLastCommittedBlockIfNewer(blockPair *protocol.BlockPairContainer) {
+	s.lastCommittedBlock.Lock()
+	defer s.lastCommittedBlock.Unlock()
 
-	// TODO Is this the right timeout here - probably should be a little smaller
-	ctxWithTimeout, cancel := context.WithTimeout(parentCtx, s.config.LeanHelixConsensusRoundTimeoutInterval())
-	defer cancel()
+	if s.lastCommittedBlock.block == nil || blockPair.TransactionsBlock.Header.BlockHeight() > s.lastCommittedBlock.block.TransactionsBlock.Header.BlockHeight() {
+		s.lastCommittedBlock.block = blockPair
+	}
+}
 
+// the block before the first one, all nodes build on top of the same empty pair
+func genesisBlock() *protocol.BlockPairContainer {
+	return &protocol.BlockPairContainer{
+		TransactionsBlock: &protocol.TransactionsBlockContainer{
+			Header:             (&protocol.TransactionsBlockHeaderBuilder{BlockHeight: 0}).Build(),
+			Metadata:           (&protocol.TransactionsBlockMetadataBuilder{}).Build(),
+			SignedTransactions: []*protocol.SignedTransaction{},
+		},
+		ResultsBlock: &protocol.ResultsBlockContainer{
+			Header:              (&protocol.ResultsBlockHeaderBuilder{BlockHeight: 0}).Build(),
+			TransactionReceipts: []*protocol.TransactionReceipt{},
+			ContractStateDiffs:  []*protocol.ContractStateDiff{},
+		},
+	}
+}
+
+func (s *service) lastCommittedBlockOrGenesis() (primitives.BlockHeight, *protocol.BlockPairContainer) {
 	lastCommittedBlockHeight, lastCommittedBlock := s.getLastCommittedBlock()
-	s.logger.Info("generating new proposed block", log.BlockHeight(lastCommittedBlockHeight+1))
+	if lastCommittedBlock == nil {
+		return 0, genesisBlock()
+	}
+	return lastCommittedBlockHeight, lastCommittedBlock
+}
+
+func (s *service) RequestNewBlock(parentCtx context.Context, blockHeight lhprimitives.BlockHeight) leanhelix.Block {
+	ctx, cancel := context.WithTimeout(parentCtx, s.config.LeanHelixConsensusRoundTimeoutInterval())
+	defer cancel()
+
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
+
+	lastCommittedBlockHeight, lastCommittedBlock := s.lastCommittedBlockOrGenesis()
+	logger.Info("generating new proposed block", log.BlockHeight(lastCommittedBlockHeight+1))
 
 	// get tx
-	txOutput, err := s.consensusContext.RequestNewTransactionsBlock(ctxWithTimeout, &services.RequestNewTransactionsBlockInput{
+	txOutput, err := s.consensusContext.RequestNewTransactionsBlock(ctx, &services.RequestNewTransactionsBlockInput{
 		BlockHeight:   lastCommittedBlockHeight + 1,
 		PrevBlockHash: digest.CalcTransactionsBlockHash(lastCommittedBlock.TransactionsBlock),
 	})
 	if err != nil {
+		logger.Info("failed to generate transactions block", log.Error(err))
 		return nil
 	}
 
 	// get rx
-	rxOutput, err := s.consensusContext.RequestNewResultsBlock(ctxWithTimeout, &services.RequestNewResultsBlockInput{
+	rxOutput, err := s.consensusContext.RequestNewResultsBlock(ctx, &services.RequestNewResultsBlockInput{
 		BlockHeight:       lastCommittedBlockHeight + 1,
 		PrevBlockHash:     digest.CalcResultsBlockHash(lastCommittedBlock.ResultsBlock),
 		TransactionsBlock: txOutput.TransactionsBlock,
 	})
 	if err != nil {
+		logger.Info("failed to generate results block", log.Error(err))
 		return nil
 	}
 
-	// generate signed block
-	// TODO what to do in case of error - similar to handling timeout
-	pair, _ := s.signBlockProposal(txOutput.TransactionsBlock, rxOutput.ResultsBlock)
-	blockPairWrapper := NewBlockPairWrapper(&protocol.BlockPairContainer{
-		TransactionsBlock: pair.TransactionsBlock,
-		ResultsBlock:      pair.ResultsBlock,
-	})
+	return NewBlockPairWrapper(s.blockPairWithProof(txOutput.TransactionsBlock, rxOutput.ResultsBlock))
+}
 
-	return blockPairWrapper
+// proposals carry an empty proof, the commit signatures are only known once the block is committed
+func (s *service) blockPairWithProof(transactionsBlock *protocol.TransactionsBlockContainer, resultsBlock *protocol.ResultsBlockContainer) *protocol.BlockPairContainer {
+	transactionsBlock.BlockProof = (&protocol.TransactionsBlockProofBuilder{
+		Type:      protocol.TRANSACTIONS_BLOCK_PROOF_TYPE_LEAN_HELIX,
+		LeanHelix: &consensus.LeanHelixBlockProofBuilder{},
+	}).Build()
 
-}
+	resultsBlock.BlockProof = (&protocol.ResultsBlockProofBuilder{
+		Type:      protocol.RESULTS_BLOCK_PROOF_TYPE_LEAN_HELIX,
+		LeanHelix: &consensus.LeanHelixBlockProofBuilder{},
+	}).Build()
 
-func (s *service) signBlockProposal(transactionsBlock *protocol.TransactionsBlockContainer, resultsBlock *protocol.ResultsBlockContainer) (*protocol.BlockPairContainer, error) {
-	blockPair := &protocol.BlockPairContainer{
+	return &protocol.BlockPairContainer{
 		TransactionsBlock: transactionsBlock,
 		ResultsBlock:      resultsBlock,
 	}
+}
 
-	// prepare signature over the block headers
-	signedData := s.signedDataForBlockProof(blockPair)
-	sig, err := signature.SignEd25519(s.config.NodePrivateKey(), signedData)
-	if err != nil {
-		return nil, err
+func (s *service) onCommit(ctx context.Context, block leanhelix.Block) {
+	blockPairWrapper, ok := block.(*BlockPairWrapper)
+	if !ok || blockPairWrapper.blockPair == nil {
+		s.logger.Error("lean helix committed a block that is not a block pair")
+		return
 	}
+	blockHeight := blockPairWrapper.blockPair.TransactionsBlock.Header.BlockHeight()
+
+	// a block without a quorum proof would be rejected by every node syncing it, so it is not stored
+	blockProof := s.commitSignatures.takeBlockProof(blockHeight, calcBlockPairHash(blockPairWrapper.blockPair))
+	if blockProof == nil || len(blockProof.Nodes) < requiredCommitQuorumSize(len(s.config.FederationNodes(uint64(blockHeight)))) {
+		s.logger.Error("lean helix committed a block without a quorum of commit signatures", log.BlockHeight(blockHeight))
+		s.followCommittedBlockMissingFromStorage(blockPairWrapper.blockPair)
+		return
+	}
+	blockPair := blockPairWithCommitProof(blockPairWrapper.blockPair, blockProof)
 
-	// generate tx block proof
-	blockPair.TransactionsBlock.BlockProof = (&protocol.TransactionsBlockProofBuilder{
-		Type:               protocol.TRANSACTIONS_BLOCK_PROOF_TYPE_BENCHMARK_CONSENSUS,
-		BenchmarkConsensus: &consensus.BenchmarkConsensusBlockProofBuilder{},
-	}).Build()
+	s.logger.Info("saving block to storage", log.BlockHeight(blockHeight))
+	_, err := s.blockStorage.CommitBlock(ctx, &services.CommitBlockInput{
+		BlockPair: blockPair,
+	})
+	if err != nil {
+		s.logger.Error("failed to save committed block to storage", log.BlockHeight(blockHeight), log.Error(err))
+		s.followCommittedBlockMissingFromStorage(blockPair)
+		return
+	}
 
-	// generate rx block proof
-	blockPair.ResultsBlock.BlockProof = (&protocol.ResultsBlockProofBuilder{
-		Type: protocol.RESULTS_BLOCK_PROOF_TYPE_BENCHMARK_CONSENSUS,
-		BenchmarkConsensus: &consensus.BenchmarkConsensusBlockProofBuilder{
-			Sender: &consensus.BenchmarkConsensusSenderSignatureBuilder{
-				SenderPublicKey: s.config.NodePublicKey(),
-				Signature:       sig,
-			},
-		},
-	}).Build()
-	return blockPair, nil
+	s.setLastCommittedBlockIfNewer(blockPair)
 }
 
-func (s *service) hash(txBlock *protocol.TransactionsBlockContainer, rxBlock *protocol.ResultsBlockContainer) []byte {
-	txHash := digest.CalcTransactionsBlockHash(txBlock)
-	rxHash := digest.CalcResultsBlockHash(rxBlock)
-	xorHash := logic.CalcXor(txHash, rxHash)
-	return xorHash
+// the lib has moved on to the next height either way, so the next proposal and validation build on this block while
+// block storage syncs it (with a proof) from the other nodes instead of waiting for its no-commit timer
+func (s *service) followCommittedBlockMissingFromStorage(blockPair *protocol.BlockPairContainer) {
+	s.setLastCommittedBlockIfNewer(blockPair)
+
+	if syncStarter, ok := s.blockStorage.(blockstorage.BlockSyncStarter); ok {
+		s.logger.Info("starting block sync for a committed block missing from storage", log.BlockHeight(blockPair.TransactionsBlock.Header.BlockHeight()))
+		syncStarter.StartBlockSync()
+	}
 }
 
-func (s *service) signedDataForBlockProof(blockPair *protocol.BlockPairContainer) []byte {
-	return s.hash(blockPair.TransactionsBlock, blockPair.ResultsBlock)
+// the proposal is shared with the lib so the committed block pair gets containers of its own
+func blockPairWithCommitProof(blockPair *protocol.BlockPairContainer, blockProof *consensus.LeanHelixBlockProofBuilder) *protocol.BlockPairContainer {
+	transactionsBlock := *blockPair.TransactionsBlock
+	transactionsBlock.BlockProof = (&protocol.TransactionsBlockProofBuilder{
+		Type:      protocol.TRANSACTIONS_BLOCK_PROOF_TYPE_LEAN_HELIX,
+		LeanHelix: blockProof,
+	}).Build()
+
+	resultsBlock := *blockPair.ResultsBlock
+	resultsBlock.BlockProof = (&protocol.ResultsBlockProofBuilder{
+		Type:      protocol.RESULTS_BLOCK_PROOF_TYPE_LEAN_HELIX,
+		LeanHelix: blockProof,
+	}).Build()
+
+	return &protocol.BlockPairContainer{
+		TransactionsBlock: &transactionsBlock,
+		ResultsBlock:      &resultsBlock,
+	}
 }
 
-func (s *service) signedDataForBlockProofWrapper(blockPairWrapper *BlockPairWrapper) []byte {
-	return s.hash(blockPairWrapper.blockPair.TransactionsBlock, blockPairWrapper.blockPair.ResultsBlock)
+func calcBlockPairHash(blockPair *protocol.BlockPairContainer) []byte {
+	txHash := digest.CalcTransactionsBlockHash(blockPair.TransactionsBlock)
+	rxHash := digest.CalcResultsBlockHash(blockPair.ResultsBlock)
+	return logic.CalcXor(txHash, rxHash)
 }
 
 func (s *service) CalculateBlockHash(block leanhelix.Block) lhprimitives.Uint256 {
-
-	return s.signedDataForBlockProofWrapper(block.(*BlockPairWrapper))
+	return lhprimitives.Uint256(calcBlockPairHash(block.(*BlockPairWrapper).blockPair))
 }
@@ -0,0 +1,134 @@
+package leanhelixconsensus
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+	"time"
+)
+
+type blockStorageWithSync struct {
+	*services.MockBlockStorage
+}
+
+func (b *blockStorageWithSync) StartBlockSync() {
+	b.Called()
+}
+
+func newServiceWithFederationOfFour(ctx context.Context) *service {
+	blockStorage := &services.MockBlockStorage{}
+	blockStorage.When("RegisterConsensusBlocksHandler", mock.Any).Return()
+	return newServiceWithFederationOfFourAndBlockStorage(ctx, blockStorage)
+}
+
+func newServiceWithFederationOfFourAndBlockStorage(ctx context.Context, blockStorage services.BlockStorage) *service {
+	federationNodes := make(map[string]config.FederationNode)
+	for i := 0; i < 4; i++ {
+		publicKey := testKeys.Ed25519KeyPairForTests(i).PublicKey()
+		federationNodes[publicKey.KeyForMap()] = config.NewHardCodedFederationNode(publicKey)
+	}
+	res := NewLeanHelixConsensusAlgo(
+		ctx,
+		&testGossip{},
+		blockStorage,
+		nil,
+		log.GetLogger().WithOutput(log.NewFormattingOutput(os.Stdout, log.NewHumanReadableFormatter())),
+		&testConfig{timeout: 1 * time.Millisecond, federationNodes: federationNodes},
+		metric.NewRegistry(),
+	)
+	return res.(*service)
+}
+
+func TestValidateBlockConsensus_AcceptsQuorumOfCommitSignatures(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newServiceWithFederationOfFour(ctx)
+
+	blockPair := builders.LeanHelixBlockPair().Build()
+
+	require.NoError(t, s.validateBlockConsensus(blockPair, nil))
+}
+
+func TestValidateBlockConsensus_RejectsProofBelowQuorum(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newServiceWithFederationOfFour(ctx)
+
+	blockPair := builders.BlockPair().WithLeanHelixBlockProofSigners(
+		testKeys.Ed25519KeyPairForTests(0),
+		testKeys.Ed25519KeyPairForTests(1),
+	).Build()
+
+	require.Error(t, s.validateBlockConsensus(blockPair, nil))
+}
+
+func TestValidateBlockConsensus_RejectsProofSignedOutsideFederation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newServiceWithFederationOfFour(ctx)
+
+	blockPair := builders.BlockPair().WithLeanHelixBlockProofSigners(
+		testKeys.Ed25519KeyPairForTests(0),
+		testKeys.Ed25519KeyPairForTests(1),
+		testKeys.Ed25519KeyPairForTests(5),
+	).Build()
+
+	require.Error(t, s.validateBlockConsensus(blockPair, nil))
+}
+
+func TestValidateBlockConsensus_RejectsProofOfAnotherBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newServiceWithFederationOfFour(ctx)
+
+	blockPair := builders.LeanHelixBlockPair().WithHeight(2).Build()
+	otherBlockPair := builders.LeanHelixBlockPair().WithHeight(2).WithTransactions(2).Build()
+	blockPair.TransactionsBlock.BlockProof = otherBlockPair.TransactionsBlock.BlockProof
+	blockPair.ResultsBlock.BlockProof = otherBlockPair.ResultsBlock.BlockProof
+
+	require.Error(t, s.validateBlockConsensus(blockPair, nil))
+}
+
+func TestCommitSignatures_BuildsBlockProofFromRecordedCommits(t *testing.T) {
+	blockPair := builders.LeanHelixBlockPair().WithHeight(3).Build()
+	blockProof := blockPair.ResultsBlock.BlockProof.LeanHelix()
+
+	c := newCommitSignatures()
+	for i := blockProof.NodesIterator(); i.HasNext(); {
+		sender := i.NextNodes()
+		c.record(blockProof.BlockRef().Raw(), sender.SenderPublicKey(), sender.Signature())
+		c.record(blockProof.BlockRef().Raw(), sender.SenderPublicKey(), sender.Signature())
+	}
+	c.record([]byte{0x01, 0x02, 0x03}, testKeys.Ed25519KeyPairForTests(0).PublicKey(), nil)
+
+	built := c.takeBlockProof(3, calcBlockPairHash(blockPair))
+	require.NotNil(t, built, "block proof should be built from the recorded commits")
+	require.Len(t, built.Nodes, 3, "duplicate commits should be counted once")
+	require.Nil(t, c.takeBlockProof(3, calcBlockPairHash(blockPair)), "commits should be dropped once taken")
+}
+
+func TestOnCommit_WithoutQuorumOfCommitSignaturesFollowsTheLibAndStartsBlockSync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blockStorage := &blockStorageWithSync{&services.MockBlockStorage{}}
+	blockStorage.When("RegisterConsensusBlocksHandler", mock.Any).Return()
+	blockStorage.When("CommitBlock", mock.Any, mock.Any).Return(nil, nil).Times(0)
+	blockStorage.When("StartBlockSync").Return().Times(1)
+	s := newServiceWithFederationOfFourAndBlockStorage(ctx, blockStorage)
+
+	blockPair := builders.BlockPair().WithHeight(1).Build()
+	s.onCommit(ctx, NewBlockPairWrapper(blockPair))
+
+	lastCommittedBlockHeight, _ := s.getLastCommittedBlock()
+	require.EqualValues(t, 1, lastCommittedBlockHeight, "the next proposal should build on the block the lib committed")
+	_, err := blockStorage.Verify()
+	require.NoError(t, err)
+}
@@ -1,10 +1,48 @@
 package leanhelixconsensus
 
 import (
+	"context"
 	"github.com/orbs-network/lean-helix-go"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-spec/types/go/services"
 )
 
 func (s *service) ValidateBlock(block leanhelix.Block) bool {
-	// TODO implement me
+	blockPairWrapper, ok := block.(*BlockPairWrapper)
+	if !ok || blockPairWrapper.blockPair == nil {
+		s.logger.Info("rejected a proposal that is not a block pair")
+		return false
+	}
+	blockPair := blockPairWrapper.blockPair
+
+	// the library does not pass a context here, so the validation is bounded by a single round
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.LeanHelixConsensusRoundTimeoutInterval())
+	defer cancel()
+
+	lastCommittedBlockHeight, lastCommittedBlock := s.lastCommittedBlockOrGenesis()
+	blockHeight := lastCommittedBlockHeight + 1
+
+	_, err := s.consensusContext.ValidateTransactionsBlock(ctx, &services.ValidateTransactionsBlockInput{
+		BlockHeight:       blockHeight,
+		TransactionsBlock: blockPair.TransactionsBlock,
+		PrevBlockHash:     digest.CalcTransactionsBlockHash(lastCommittedBlock.TransactionsBlock),
+	})
+	if err != nil {
+		s.logger.Info("rejected proposed transactions block", log.BlockHeight(blockHeight), log.Error(err))
+		return false
+	}
+
+	_, err = s.consensusContext.ValidateResultsBlock(ctx, &services.ValidateResultsBlockInput{
+		BlockHeight:       blockHeight,
+		ResultsBlock:      blockPair.ResultsBlock,
+		PrevBlockHash:     digest.CalcResultsBlockHash(lastCommittedBlock.ResultsBlock),
+		TransactionsBlock: blockPair.TransactionsBlock,
+	})
+	if err != nil {
+		s.logger.Info("rejected proposed results block", log.BlockHeight(blockHeight), log.Error(err))
+		return false
+	}
+
 	return true
 }
@@ -0,0 +1,140 @@
+package leanhelixconsensus
+
+import (
+	"bytes"
+	"github.com/orbs-network/orbs-network-go/crypto/signature"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// the lib verifies the commit messages through our key manager but does not hand them over when it commits, so the
+// signatures over commit block refs are collected as they are signed and verified and the block proof is built from them
+type commitSignatures struct {
+	sync.Mutex
+	byBlockRef map[string]*commitSignaturesOfBlockRef
+}
+
+type commitSignaturesOfBlockRef struct {
+	blockRef *consensus.LeanHelixBlockRefBuilder
+	senders  map[string]*consensus.LeanHelixSenderSignatureBuilder
+}
+
+func newCommitSignatures() *commitSignatures {
+	return &commitSignatures{
+		byBlockRef: make(map[string]*commitSignaturesOfBlockRef),
+	}
+}
+
+// anything signed or verified which is not exactly an encoded commit block ref is ignored
+func (c *commitSignatures) record(signedData []byte, senderPublicKey primitives.Ed25519PublicKey, sig primitives.Ed25519Sig) {
+	blockRef := consensus.LeanHelixBlockRefReader(signedData)
+	if blockRef.MessageType() != consensus.LEAN_HELIX_COMMIT {
+		return
+	}
+	blockRefBuilder := &consensus.LeanHelixBlockRefBuilder{
+		MessageType: blockRef.MessageType(),
+		BlockHeight: blockRef.BlockHeight(),
+		View:        blockRef.View(),
+		BlockHash:   blockRef.BlockHash(),
+	}
+	if !bytes.Equal(blockRefBuilder.Build().Raw(), signedData) {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	key := string(signedData)
+	ofBlockRef, found := c.byBlockRef[key]
+	if !found {
+		ofBlockRef = &commitSignaturesOfBlockRef{
+			blockRef: blockRefBuilder,
+			senders:  make(map[string]*consensus.LeanHelixSenderSignatureBuilder),
+		}
+		c.byBlockRef[key] = ofBlockRef
+	}
+	ofBlockRef.senders[senderPublicKey.KeyForMap()] = &consensus.LeanHelixSenderSignatureBuilder{
+		SenderPublicKey: senderPublicKey,
+		Signature:       sig,
+	}
+}
+
+// the commits of the block ref with the most signers, signatures of this and earlier heights are dropped
+func (c *commitSignatures) takeBlockProof(blockHeight primitives.BlockHeight, blockHash []byte) *consensus.LeanHelixBlockProofBuilder {
+	c.Lock()
+	defer c.Unlock()
+
+	var best *commitSignaturesOfBlockRef
+	for key, ofBlockRef := range c.byBlockRef {
+		if ofBlockRef.blockRef.BlockHeight > blockHeight {
+			continue
+		}
+		if ofBlockRef.blockRef.BlockHeight == blockHeight && bytes.Equal(ofBlockRef.blockRef.BlockHash, blockHash) {
+			if best == nil || len(ofBlockRef.senders) > len(best.senders) {
+				best = ofBlockRef
+			}
+		}
+		delete(c.byBlockRef, key)
+	}
+	if best == nil {
+		return nil
+	}
+
+	nodes := make([]*consensus.LeanHelixSenderSignatureBuilder, 0, len(best.senders))
+	for _, sender := range best.senders {
+		nodes = append(nodes, sender)
+	}
+	return &consensus.LeanHelixBlockProofBuilder{
+		BlockRef: best.blockRef,
+		Nodes:    nodes,
+	}
+}
+
+// lean helix tolerates f faulty nodes out of 3f+1 and commits on 2f+1
+func requiredCommitQuorumSize(federationSize int) int {
+	return federationSize - (federationSize-1)/3
+}
+
+func (s *service) verifyBlockProof(blockPair *protocol.BlockPairContainer) error {
+	blockProof := blockPair.ResultsBlock.BlockProof.LeanHelix()
+	if !bytes.Equal(blockPair.TransactionsBlock.BlockProof.LeanHelix().Raw(), blockProof.Raw()) {
+		return errors.New("transactions and results block proofs differ")
+	}
+
+	blockHeight := blockPair.TransactionsBlock.Header.BlockHeight()
+	blockRef := blockProof.BlockRef()
+	if blockRef.MessageType() != consensus.LEAN_HELIX_COMMIT {
+		return errors.Errorf("block proof is not over a commit: %s", blockRef.MessageType())
+	}
+	if blockRef.BlockHeight() != blockHeight {
+		return errors.Errorf("block proof is for height %d instead of %d", blockRef.BlockHeight(), blockHeight)
+	}
+	if !bytes.Equal(blockRef.BlockHash(), calcBlockPairHash(blockPair)) {
+		return errors.Errorf("block proof is for another block: %s", blockRef.BlockHash())
+	}
+
+	federationNodes := s.config.FederationNodes(uint64(blockHeight))
+	signers := make(map[string]bool)
+	for i := blockProof.NodesIterator(); i.HasNext(); {
+		sender := i.NextNodes()
+		publicKey := sender.SenderPublicKey()
+		if _, found := federationNodes[publicKey.KeyForMap()]; !found {
+			return errors.Errorf("block proof signed by a node outside the federation: %s", publicKey)
+		}
+		if signers[publicKey.KeyForMap()] {
+			return errors.Errorf("block proof signed twice by %s", publicKey)
+		}
+		if !signature.VerifyEd25519(publicKey, blockRef.Raw(), sender.Signature()) {
+			return errors.Errorf("block proof signature of %s is invalid", publicKey)
+		}
+		signers[publicKey.KeyForMap()] = true
+	}
+
+	if required := requiredCommitQuorumSize(len(federationNodes)); len(signers) < required {
+		return errors.Errorf("block proof has %d commit signatures when %d are required", len(signers), required)
+	}
+	return nil
+}
@@ -8,13 +8,21 @@ import (
 )
 
 func (s *service) Sign(content []byte) []byte {
-	sig, _ := signature.SignEd25519(s.config.NodePrivateKey(), content)
+	sig, err := signature.SignEd25519(s.config.NodePrivateKey(), content)
+	if err == nil {
+		s.commitSignatures.record(content, s.config.NodePublicKey(), sig)
+	}
 	return sig
 }
 
 func (s *service) Verify(content []byte, sender *leanhelix.SenderSignature) bool {
-
-	return signature.VerifyEd25519(primitives.Ed25519PublicKey(sender.SenderPublicKey()), content, primitives.Ed25519Sig(sender.Signature()))
+	publicKey := primitives.Ed25519PublicKey(sender.SenderPublicKey())
+	sig := primitives.Ed25519Sig(sender.Signature())
+	if !signature.VerifyEd25519(publicKey, content, sig) {
+		return false
+	}
+	s.commitSignatures.record(content, publicKey, sig)
+	return true
 }
 
 func (s *service) MyPublicKey() lhprimitives.Ed25519PublicKey {
@@ -4,20 +4,27 @@ import (
 	"context"
 	"github.com/orbs-network/lean-helix-go"
 	lhprimitives "github.com/orbs-network/lean-helix-go/primitives"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
 )
 
 func (s *service) HandleLeanHelixMessage(ctx context.Context, input *gossiptopics.LeanHelixInput) (*gossiptopics.EmptyOutput, error) {
 
+	// only messages which carry a proposal have a block
+	var block leanhelix.Block
+	if input.Message.BlockPair != nil {
+		block = NewBlockPairWrapper(input.Message.BlockPair)
+	}
+
 	message := leanhelix.CreateConsensusRawMessage(
 		leanhelix.MessageType(input.Message.MessageType),
 		input.Message.Content,
-		&BlockPairWrapper{
-			blockPair: input.Message.BlockPair,
-		},
+		block,
 	)
 
 	for _, messageReceiver := range s.messageReceivers {
@@ -26,12 +33,35 @@ func (s *service) HandleLeanHelixMessage(ctx context.Context, input *gossiptopic
 	return nil, nil
 }
 
+// all nodes derive the same committee from the same seed, its first member is the leader of the view
 func (s *service) RequestOrderedCommittee(seed uint64) []lhprimitives.Ed25519PublicKey {
-	panic("implement me")
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.LeanHelixConsensusRoundTimeoutInterval())
+	defer cancel()
+
+	lastCommittedBlockHeight, _ := s.getLastCommittedBlock()
+	blockHeight := lastCommittedBlockHeight + 1
+
+	output, err := s.consensusContext.RequestOrderingCommittee(ctx, &services.RequestCommitteeInput{
+		BlockHeight:      blockHeight,
+		RandomSeed:       seed,
+		MaxCommitteeSize: uint32(len(s.config.FederationNodes(uint64(blockHeight)))),
+	})
+	if err != nil {
+		s.logger.Error("failed to get ordered committee", log.BlockHeight(blockHeight), log.Error(err))
+		return nil
+	}
+
+	committee := make([]lhprimitives.Ed25519PublicKey, 0, len(output.NodePublicKeys))
+	for _, publicKey := range output.NodePublicKeys {
+		committee = append(committee, lhprimitives.Ed25519PublicKey(publicKey))
+	}
+	return committee
 }
 
 func (s *service) IsMember(pk lhprimitives.Ed25519PublicKey) bool {
-	panic("implement me")
+	lastCommittedBlockHeight, _ := s.getLastCommittedBlock()
+	_, found := s.config.FederationNodes(uint64(lastCommittedBlockHeight + 1))[primitives.Ed25519PublicKey(pk).KeyForMap()]
+	return found
 }
 
 // Lib calls this method to register itself for incoming messages, and supplies the callback
@@ -54,11 +84,14 @@ func (s *service) CountRegisteredOnMessage() int {
 func (s *service) SendMessage(ctx context.Context, lhtargets []lhprimitives.Ed25519PublicKey, consensusRawMessage leanhelix.ConsensusRawMessage) {
 
 	targets := make([]primitives.Ed25519PublicKey, 0, len(lhtargets))
-	for i, lhtarget := range lhtargets {
-		targets[i] = primitives.Ed25519PublicKey(lhtarget)
+	for _, lhtarget := range lhtargets {
+		targets = append(targets, primitives.Ed25519PublicKey(lhtarget))
 	}
 
-	blockPairWrapper := consensusRawMessage.Block().(*BlockPairWrapper)
+	var blockPair *protocol.BlockPairContainer
+	if blockPairWrapper, ok := consensusRawMessage.Block().(*BlockPairWrapper); ok && blockPairWrapper != nil {
+		blockPair = blockPairWrapper.blockPair
+	}
 
 	message := &gossiptopics.LeanHelixInput{
 		RecipientsList: &gossiptopics.RecipientsList{
@@ -68,8 +101,10 @@ func (s *service) SendMessage(ctx context.Context, lhtargets []lhprimitives.Ed25
 		Message: &gossipmessages.LeanHelixMessage{
 			MessageType: consensus.LeanHelixMessageType(consensusRawMessage.MessageType()),
 			Content:     consensusRawMessage.Content(),
-			BlockPair:   blockPairWrapper.blockPair,
+			BlockPair:   blockPair,
 		},
 	}
-	s.gossip.SendLeanHelixMessage(ctx, message)
+	if _, err := s.gossip.SendLeanHelixMessage(ctx, message); err != nil {
+		s.logger.Info("failed to send lean helix message", log.Error(err))
+	}
 }
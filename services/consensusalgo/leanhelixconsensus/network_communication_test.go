@@ -2,11 +2,14 @@ package leanhelixconsensus
 
 import (
 	"context"
+	"github.com/orbs-network/go-mock"
 	"github.com/orbs-network/lean-helix-go"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
+	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
 	"github.com/stretchr/testify/require"
 	"os"
@@ -15,7 +18,8 @@ import (
 )
 
 type testConfig struct {
-	timeout time.Duration
+	timeout         time.Duration
+	federationNodes map[string]config.FederationNode
 }
 
 func (c *testConfig) NodePublicKey() primitives.Ed25519PublicKey {
@@ -26,12 +30,17 @@ func (c *testConfig) NodePrivateKey() primitives.Ed25519PrivateKey {
 	panic("implement me")
 }
 
+func (c *testConfig) FederationNodes(asOfBlock uint64) map[string]config.FederationNode {
+	return c.federationNodes
+}
+
 func (c *testConfig) LeanHelixConsensusRoundTimeoutInterval() time.Duration {
 	return c.timeout
 }
 
+// lean helix is not the active algo so that it does not start running consensus rounds in the background
 func (c *testConfig) ActiveConsensusAlgo() consensus.ConsensusAlgoType {
-	return consensus.CONSENSUS_ALGO_TYPE_LEAN_HELIX
+	return consensus.CONSENSUS_ALGO_TYPE_BENCHMARK_CONSENSUS
 }
 
 type testGossip struct{}
@@ -51,10 +60,12 @@ func TestMessageRegistration(t *testing.T) {
 	metricFactory := metric.NewRegistry()
 	ctx, ctxCancel := context.WithCancel(context.Background())
 	defer ctxCancel()
+	blockStorage := &services.MockBlockStorage{}
+	blockStorage.When("RegisterConsensusBlocksHandler", mock.Any).Return()
 	res := NewLeanHelixConsensusAlgo(
 		ctx,
 		&testGossip{},
-		nil,
+		blockStorage,
 		nil,
 		log,
 		&testConfig{timeout: timeout},
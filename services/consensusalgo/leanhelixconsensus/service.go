@@ -4,8 +4,10 @@ import (
 	"context"
 	"github.com/orbs-network/lean-helix-go"
 	lhprimitives "github.com/orbs-network/lean-helix-go/primitives"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/synchronization/supervised"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
@@ -31,6 +33,7 @@ type service struct {
 	metrics          *metrics
 	leanHelix        leanhelix.LeanHelix
 	*lastCommittedBlock
+	commitSignatures        *commitSignatures
 	messageReceivers        map[int]func(ctx context.Context, message leanhelix.ConsensusRawMessage)
 	messageReceiversCounter int
 }
@@ -45,6 +48,7 @@ type metrics struct {
 type Config interface {
 	NodePublicKey() primitives.Ed25519PublicKey
 	NodePrivateKey() primitives.Ed25519PrivateKey
+	FederationNodes(asOfBlock uint64) map[string]config.FederationNode
 
 	LeanHelixConsensusRoundTimeoutInterval() time.Duration
 	ActiveConsensusAlgo() consensus.ConsensusAlgoType
@@ -67,8 +71,7 @@ func (b *BlockPairWrapper) Height() lhprimitives.BlockHeight {
 }
 
 func (b *BlockPairWrapper) BlockHash() lhprimitives.Uint256 {
-	// TODO This is surely incorrect, fix to use the right hash
-	return lhprimitives.Uint256(b.blockPair.TransactionsBlock.Header.MetadataHash())
+	return lhprimitives.Uint256(calcBlockPairHash(b.blockPair))
 }
 
 func NewBlockPairWrapper(blockPair *protocol.BlockPairContainer) *BlockPairWrapper {
@@ -85,7 +88,6 @@ func NewLeanHelixConsensusAlgo(
 	logger log.BasicLogger,
 	config Config,
 	metricFactory metric.Factory,
-
 ) services.ConsensusAlgoLeanHelix {
 
 	electionTrigger := leanhelix.NewTimerBasedElectionTrigger(config.LeanHelixConsensusRoundTimeoutInterval())
@@ -98,6 +100,8 @@ func NewLeanHelixConsensusAlgo(
 		config:                  config,
 		metrics:                 newMetrics(metricFactory, config.LeanHelixConsensusRoundTimeoutInterval()),
 		leanHelix:               nil,
+		lastCommittedBlock:      &lastCommittedBlock{},
+		commitSignatures:        newCommitSignatures(),
 		messageReceivers:        make(map[int]func(ctx context.Context, message leanhelix.ConsensusRawMessage)),
 		messageReceiversCounter: 0,
 	}
@@ -109,17 +113,22 @@ func NewLeanHelixConsensusAlgo(
 		ElectionTrigger:      electionTrigger,
 	}
 
-	leanHelix := leanhelix.NewLeanHelix(leanHelixConfig)
-
-	s.leanHelix = leanHelix
+	s.leanHelix = leanhelix.NewLeanHelix(leanHelixConfig)
+	s.leanHelix.RegisterOnCommitted(func(block leanhelix.Block) {
+		s.onCommit(ctx, block)
+	})
 
 	gossip.RegisterLeanHelixHandler(s)
 
-	// TODO uncomment after BlockStorage mutex issues (s.lastBlockLock) are fixed
-	//blockStorage.RegisterConsensusBlocksHandler(s)
+	// block storage updates us synchronously about the last block it holds, so this must come before we start
+	blockStorage.RegisterConsensusBlocksHandler(s)
 
 	if config.ActiveConsensusAlgo() == consensus.CONSENSUS_ALGO_TYPE_LEAN_HELIX {
-		go s.leanHelix.Start(ctx, 1) // TODO Get the block height from someplace
+		lastCommittedBlockHeight, _ := s.getLastCommittedBlock()
+		s.logger.Info("starting lean helix consensus", log.BlockHeight(lastCommittedBlockHeight+1))
+		supervised.GoOnce(s.logger, func() {
+			s.leanHelix.Start(ctx, lhprimitives.BlockHeight(lastCommittedBlockHeight+1))
+		})
 	}
 
 	return s
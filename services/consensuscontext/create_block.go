@@ -6,6 +6,7 @@ import (
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
 	"time"
 )
 
@@ -21,10 +22,11 @@ func (s *service) createTransactionsBlock(ctx context.Context, blockHeight primi
 
 	txBlock := &protocol.TransactionsBlockContainer{
 		Header: (&protocol.TransactionsBlockHeaderBuilder{
-			ProtocolVersion: primitives.ProtocolVersion(1), // TODO: fix
-			BlockHeight:     blockHeight,
-			//Timestamp: 			   primitives.TimestampNano(time.Now().UnixNano()),
+			ProtocolVersion:       ProtocolVersion,
+			VirtualChainId:        s.config.VirtualChainId(),
+			BlockHeight:           blockHeight,
 			PrevBlockHashPtr:      prevBlockHash,
+			Timestamp:             primitives.TimestampNano(time.Now().UnixNano()),
 			NumSignedTransactions: uint32(txCount),
 		}).Build(),
 		Metadata:           (&protocol.TransactionsBlockMetadataBuilder{}).Build(),
@@ -40,20 +42,30 @@ func (s *service) createResultsBlock(ctx context.Context, blockHeight primitives
 
 	output, err := s.virtualMachine.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
 		BlockHeight:        blockHeight,
+		BlockTimestamp:     transactionsBlock.Header.Timestamp(),
 		SignedTransactions: transactionsBlock.SignedTransactions,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	preExecutionStateRootHash, err := s.preExecutionStateRootHash(ctx, blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
 	rxBlock := &protocol.ResultsBlockContainer{
 		Header: (&protocol.ResultsBlockHeaderBuilder{
-			ProtocolVersion:          primitives.ProtocolVersion(1), // TODO: fix
-			BlockHeight:              blockHeight,
-			PrevBlockHashPtr:         prevBlockHash,
-			TransactionsBlockHashPtr: digest.CalcTransactionsBlockHash(transactionsBlock),
-			NumTransactionReceipts:   uint32(len(output.TransactionReceipts)),
-			NumContractStateDiffs:    uint32(len(output.ContractStateDiffs)),
+			ProtocolVersion:           ProtocolVersion,
+			VirtualChainId:            s.config.VirtualChainId(),
+			BlockHeight:               blockHeight,
+			PrevBlockHashPtr:          prevBlockHash,
+			Timestamp:                 transactionsBlock.Header.Timestamp(),
+			ReceiptsRootHash:          digest.CalcReceiptsRootHash(output.TransactionReceipts),
+			TransactionsBlockHashPtr:  digest.CalcTransactionsBlockHash(transactionsBlock),
+			PreExecutionStateRootHash: preExecutionStateRootHash,
+			NumTransactionReceipts:    uint32(len(output.TransactionReceipts)),
+			NumContractStateDiffs:     uint32(len(output.ContractStateDiffs)),
 		}).Build(),
 		TransactionReceipts: output.TransactionReceipts,
 		ContractStateDiffs:  output.ContractStateDiffs,
@@ -61,3 +73,14 @@ func (s *service) createResultsBlock(ctx context.Context, blockHeight primitives
 	}
 	return rxBlock, nil
 }
+
+// the state the block's transactions were executed against, which is the state after the previous block
+func (s *service) preExecutionStateRootHash(ctx context.Context, blockHeight primitives.BlockHeight) (primitives.MerkleSha256, error) {
+	output, err := s.stateStorage.GetStateHash(ctx, &services.GetStateHashInput{
+		BlockHeight: blockHeight - 1,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the state root hash of block height %d", blockHeight-1)
+	}
+	return output.StateRootHash, nil
+}
@@ -91,11 +91,3 @@ func (s *service) RequestNewResultsBlock(ctx context.Context, input *services.Re
 		ResultsBlock: rxBlock,
 	}, nil
 }
-
-func (s *service) ValidateTransactionsBlock(ctx context.Context, input *services.ValidateTransactionsBlockInput) (*services.ValidateTransactionsBlockOutput, error) {
-	panic("Not implemented")
-}
-
-func (s *service) ValidateResultsBlock(ctx context.Context, input *services.ValidateResultsBlockInput) (*services.ValidateResultsBlockOutput, error) {
-	panic("Not implemented")
-}
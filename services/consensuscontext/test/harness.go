@@ -32,6 +32,8 @@ var federationNodePublicKeysForTest = []primitives.Ed25519PublicKey{
 
 type harness struct {
 	transactionPool *services.MockTransactionPool
+	virtualMachine  *services.MockVirtualMachine
+	stateStorage    *services.MockStateStorage
 	reporting       log.BasicLogger
 	service         services.ConsensusContext
 	config          config.ConsensusContextConfig
@@ -50,6 +52,55 @@ func (h *harness) requestTransactionsBlock(ctx context.Context) (*protocol.Trans
 	return output.TransactionsBlock, nil
 }
 
+func (h *harness) requestResultsBlock(ctx context.Context, txBlock *protocol.TransactionsBlockContainer) (*protocol.ResultsBlockContainer, error) {
+	output, err := h.service.RequestNewResultsBlock(ctx, &services.RequestNewResultsBlockInput{
+		BlockHeight:       1,
+		PrevBlockHash:     hash.CalcSha256([]byte{2}),
+		TransactionsBlock: txBlock,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.ResultsBlock, nil
+}
+
+func (h *harness) validateTransactionsBlock(ctx context.Context, txBlock *protocol.TransactionsBlockContainer) error {
+	_, err := h.service.ValidateTransactionsBlock(ctx, &services.ValidateTransactionsBlockInput{
+		BlockHeight:       1,
+		TransactionsBlock: txBlock,
+		PrevBlockHash:     hash.CalcSha256([]byte{1}),
+	})
+	return err
+}
+
+func (h *harness) validateResultsBlock(ctx context.Context, rxBlock *protocol.ResultsBlockContainer, txBlock *protocol.TransactionsBlockContainer) error {
+	_, err := h.service.ValidateResultsBlock(ctx, &services.ValidateResultsBlockInput{
+		BlockHeight:       1,
+		ResultsBlock:      rxBlock,
+		PrevBlockHash:     hash.CalcSha256([]byte{2}),
+		TransactionsBlock: txBlock,
+	})
+	return err
+}
+
+func (h *harness) expectTransactionsValidatedByTransactionPool(err error) {
+	h.transactionPool.When("ValidateTransactionsForOrdering", mock.Any, mock.Any).Return(&services.ValidateTransactionsForOrderingOutput{}, err).Times(1)
+}
+
+func (h *harness) expectStateRootHashOfPreviousBlock(stateRootHash primitives.MerkleSha256) {
+	h.stateStorage.When("GetStateHash", mock.Any, mock.Any).Return(&services.GetStateHashOutput{StateRootHash: stateRootHash}, nil).AtLeast(1)
+}
+
+func (h *harness) expectTransactionsExecuted(txBlock *protocol.TransactionsBlockContainer) {
+	output := &services.ProcessTransactionSetOutput{
+		ContractStateDiffs: []*protocol.ContractStateDiff{builders.ContractStateDiff().WithContractName("BenchmarkToken").WithStringRecord("balance", "10").Build()},
+	}
+	for _, tx := range txBlock.SignedTransactions {
+		output.TransactionReceipts = append(output.TransactionReceipts, builders.TransactionReceipt().WithTransaction(tx.Transaction()).Build())
+	}
+	h.virtualMachine.When("ProcessTransactionSet", mock.Any, mock.Any).Return(output, nil).AtLeast(1)
+}
+
 func (h *harness) expectTransactionsRequestedFromTransactionPool(numTransactionsToReturn uint32) {
 
 	output := &services.GetTransactionsForOrderingOutput{
@@ -79,6 +130,8 @@ func newHarness() *harness {
 	log := log.GetLogger().WithOutput(log.NewFormattingOutput(os.Stdout, log.NewHumanReadableFormatter()))
 
 	transactionPool := &services.MockTransactionPool{}
+	virtualMachine := &services.MockVirtualMachine{}
+	stateStorage := &services.MockStateStorage{}
 	federationNodes := make(map[string]config.FederationNode)
 	for _, pk := range federationNodePublicKeysForTest {
 		federationNodes[pk.KeyForMap()] = config.NewHardCodedFederationNode(pk)
@@ -88,11 +141,13 @@ func newHarness() *harness {
 
 	metricFactory := metric.NewRegistry()
 
-	service := consensuscontext.NewConsensusContext(transactionPool, nil, nil,
+	service := consensuscontext.NewConsensusContext(transactionPool, virtualMachine, stateStorage,
 		cfg, log, metricFactory)
 
 	return &harness{
 		transactionPool: transactionPool,
+		virtualMachine:  virtualMachine,
+		stateStorage:    stateStorage,
 		reporting:       log,
 		service:         service,
 		config:          cfg,
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func txBlockWithHeader(txBlock *protocol.TransactionsBlockContainer, modify func(header *protocol.TransactionsBlockHeaderBuilder)) *protocol.TransactionsBlockContainer {
+	header := &protocol.TransactionsBlockHeaderBuilder{
+		ProtocolVersion:       txBlock.Header.ProtocolVersion(),
+		VirtualChainId:        txBlock.Header.VirtualChainId(),
+		BlockHeight:           txBlock.Header.BlockHeight(),
+		PrevBlockHashPtr:      txBlock.Header.PrevBlockHashPtr(),
+		Timestamp:             txBlock.Header.Timestamp(),
+		NumSignedTransactions: txBlock.Header.NumSignedTransactions(),
+	}
+	modify(header)
+	return &protocol.TransactionsBlockContainer{
+		Header:             header.Build(),
+		Metadata:           txBlock.Metadata,
+		SignedTransactions: txBlock.SignedTransactions,
+		BlockProof:         txBlock.BlockProof,
+	}
+}
+
+func rxBlockWithHeader(rxBlock *protocol.ResultsBlockContainer, modify func(header *protocol.ResultsBlockHeaderBuilder)) *protocol.ResultsBlockContainer {
+	header := &protocol.ResultsBlockHeaderBuilder{
+		ProtocolVersion:           rxBlock.Header.ProtocolVersion(),
+		VirtualChainId:            rxBlock.Header.VirtualChainId(),
+		BlockHeight:               rxBlock.Header.BlockHeight(),
+		PrevBlockHashPtr:          rxBlock.Header.PrevBlockHashPtr(),
+		Timestamp:                 rxBlock.Header.Timestamp(),
+		ReceiptsRootHash:          rxBlock.Header.ReceiptsRootHash(),
+		TransactionsBlockHashPtr:  rxBlock.Header.TransactionsBlockHashPtr(),
+		PreExecutionStateRootHash: rxBlock.Header.PreExecutionStateRootHash(),
+		NumTransactionReceipts:    rxBlock.Header.NumTransactionReceipts(),
+		NumContractStateDiffs:     rxBlock.Header.NumContractStateDiffs(),
+	}
+	modify(header)
+	return &protocol.ResultsBlockContainer{
+		Header:              header.Build(),
+		TransactionReceipts: rxBlock.TransactionReceipts,
+		ContractStateDiffs:  rxBlock.ContractStateDiffs,
+		BlockProof:          rxBlock.BlockProof,
+	}
+}
+
+func TestValidateTransactionsBlock(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectTransactionsRequestedFromTransactionPool(h.config.ConsensusContextMinimumTransactionsInBlock())
+		txBlock, err := h.requestTransactionsBlock(ctx)
+		require.NoError(t, err, "request transactions block failed")
+
+		t.Run("accepts a block proposed by a node with the same config", func(t *testing.T) {
+			h.expectTransactionsValidatedByTransactionPool(nil)
+			require.NoError(t, h.validateTransactionsBlock(ctx, txBlock))
+		})
+
+		t.Run("rejects a block whose transactions are rejected by the transaction pool", func(t *testing.T) {
+			h.expectTransactionsValidatedByTransactionPool(errors.New("transaction already committed"))
+			require.Error(t, h.validateTransactionsBlock(ctx, txBlock))
+		})
+
+		invalidHeaders := map[string]func(header *protocol.TransactionsBlockHeaderBuilder){
+			"protocol version": func(header *protocol.TransactionsBlockHeaderBuilder) { header.ProtocolVersion = 999 },
+			"virtual chain":    func(header *protocol.TransactionsBlockHeaderBuilder) { header.VirtualChainId++ },
+			"block height":     func(header *protocol.TransactionsBlockHeaderBuilder) { header.BlockHeight++ },
+			"prev block hash pointer": func(header *protocol.TransactionsBlockHeaderBuilder) {
+				header.PrevBlockHashPtr = hash.CalcSha256([]byte{9})
+			},
+			"timestamp in the future": func(header *protocol.TransactionsBlockHeaderBuilder) {
+				header.Timestamp += primitives.TimestampNano(time.Hour)
+			},
+			"timestamp in the past": func(header *protocol.TransactionsBlockHeaderBuilder) {
+				header.Timestamp -= primitives.TimestampNano(time.Hour)
+			},
+			"number of transactions": func(header *protocol.TransactionsBlockHeaderBuilder) { header.NumSignedTransactions++ },
+		}
+		for name, modify := range invalidHeaders {
+			t.Run("rejects a block with a mismatching "+name, func(t *testing.T) {
+				require.Error(t, h.validateTransactionsBlock(ctx, txBlockWithHeader(txBlock, modify)))
+			})
+		}
+	})
+}
+
+func TestValidateResultsBlock(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectTransactionsRequestedFromTransactionPool(h.config.ConsensusContextMinimumTransactionsInBlock())
+		txBlock, err := h.requestTransactionsBlock(ctx)
+		require.NoError(t, err, "request transactions block failed")
+
+		h.expectStateRootHashOfPreviousBlock(primitives.MerkleSha256(hash.CalcSha256([]byte{3})))
+		h.expectTransactionsExecuted(txBlock)
+		rxBlock, err := h.requestResultsBlock(ctx, txBlock)
+		require.NoError(t, err, "request results block failed")
+
+		t.Run("accepts a block proposed by a node with the same state", func(t *testing.T) {
+			require.NoError(t, h.validateResultsBlock(ctx, rxBlock, txBlock))
+		})
+
+		t.Run("rejects a block paired with a different transactions block", func(t *testing.T) {
+			otherTxBlock := txBlockWithHeader(txBlock, func(header *protocol.TransactionsBlockHeaderBuilder) { header.Timestamp++ })
+			require.Error(t, h.validateResultsBlock(ctx, rxBlock, otherTxBlock))
+		})
+
+		t.Run("rejects a block with receipts that do not match local execution", func(t *testing.T) {
+			tampered := rxBlockWithHeader(rxBlock, func(header *protocol.ResultsBlockHeaderBuilder) {})
+			tampered.TransactionReceipts = []*protocol.TransactionReceipt{builders.TransactionReceipt().WithRandomHash().Build()}
+			tampered.TransactionReceipts = append(tampered.TransactionReceipts, rxBlock.TransactionReceipts[1:]...)
+			require.Error(t, h.validateResultsBlock(ctx, tampered, txBlock))
+		})
+
+		invalidHeaders := map[string]func(header *protocol.ResultsBlockHeaderBuilder){
+			"protocol version":        func(header *protocol.ResultsBlockHeaderBuilder) { header.ProtocolVersion = 999 },
+			"virtual chain":           func(header *protocol.ResultsBlockHeaderBuilder) { header.VirtualChainId++ },
+			"block height":            func(header *protocol.ResultsBlockHeaderBuilder) { header.BlockHeight++ },
+			"prev block hash pointer": func(header *protocol.ResultsBlockHeaderBuilder) { header.PrevBlockHashPtr = hash.CalcSha256([]byte{9}) },
+			"transactions block pointer": func(header *protocol.ResultsBlockHeaderBuilder) {
+				header.TransactionsBlockHashPtr = hash.CalcSha256([]byte{9})
+			},
+			"timestamp": func(header *protocol.ResultsBlockHeaderBuilder) { header.Timestamp++ },
+			"receipts root hash": func(header *protocol.ResultsBlockHeaderBuilder) {
+				header.ReceiptsRootHash = primitives.MerkleSha256(hash.CalcSha256([]byte{9}))
+			},
+			"pre-execution state root hash": func(header *protocol.ResultsBlockHeaderBuilder) {
+				header.PreExecutionStateRootHash = primitives.MerkleSha256(hash.CalcSha256([]byte{9}))
+			},
+			"number of receipts":    func(header *protocol.ResultsBlockHeaderBuilder) { header.NumTransactionReceipts++ },
+			"number of state diffs": func(header *protocol.ResultsBlockHeaderBuilder) { header.NumContractStateDiffs++ },
+		}
+		for name, modify := range invalidHeaders {
+			t.Run("rejects a block with a mismatching "+name, func(t *testing.T) {
+				require.Error(t, h.validateResultsBlock(ctx, rxBlockWithHeader(rxBlock, modify), txBlock))
+			})
+		}
+	})
+}
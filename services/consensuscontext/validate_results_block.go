@@ -0,0 +1,169 @@
+package consensuscontext
+
+import (
+	"bytes"
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+)
+
+type rxBlockValidator func(block *protocol.ResultsBlockContainer, vctx *rxBlockValidationContext) error
+
+type rxBlockValidationContext struct {
+	protocolVersion       primitives.ProtocolVersion
+	virtualChainId        primitives.VirtualChainId
+	expectedBlockHeight   primitives.BlockHeight
+	expectedPrevBlockHash primitives.Sha256
+	transactionsBlock     *protocol.TransactionsBlockContainer
+}
+
+func (s *service) ValidateResultsBlock(ctx context.Context, input *services.ValidateResultsBlockInput) (*services.ValidateResultsBlockOutput, error) {
+	block := input.ResultsBlock
+	if block == nil || block.Header == nil {
+		return nil, errors.New("results block is missing")
+	}
+	if input.TransactionsBlock == nil || input.TransactionsBlock.Header == nil {
+		return nil, errors.New("transactions block of the results block is missing")
+	}
+
+	vctx := &rxBlockValidationContext{
+		protocolVersion:       ProtocolVersion,
+		virtualChainId:        s.config.VirtualChainId(),
+		expectedBlockHeight:   input.BlockHeight,
+		expectedPrevBlockHash: input.PrevBlockHash,
+		transactionsBlock:     input.TransactionsBlock,
+	}
+
+	validators := []rxBlockValidator{
+		validateRxProtocolVersion,
+		validateRxVirtualChainId,
+		validateRxBlockHeight,
+		validateRxPrevBlockHashPtr,
+		validateRxTransactionsBlockHashPtr,
+		validateRxTimestamp,
+		validateRxReceiptsRootHash,
+	}
+
+	for _, validate := range validators {
+		if err := validate(block, vctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.validatePreExecutionStateRootHash(ctx, block); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateExecutionResults(ctx, block, input.TransactionsBlock); err != nil {
+		return nil, err
+	}
+
+	return &services.ValidateResultsBlockOutput{}, nil
+}
+
+func validateRxProtocolVersion(block *protocol.ResultsBlockContainer, vctx *rxBlockValidationContext) error {
+	if actual := block.Header.ProtocolVersion(); actual != vctx.protocolVersion {
+		return errors.Errorf("results block has protocol version %d, expected %d", actual, vctx.protocolVersion)
+	}
+	return nil
+}
+
+func validateRxVirtualChainId(block *protocol.ResultsBlockContainer, vctx *rxBlockValidationContext) error {
+	if actual := block.Header.VirtualChainId(); actual != vctx.virtualChainId {
+		return errors.Errorf("results block belongs to virtual chain %d, expected %d", actual, vctx.virtualChainId)
+	}
+	return nil
+}
+
+func validateRxBlockHeight(block *protocol.ResultsBlockContainer, vctx *rxBlockValidationContext) error {
+	if actual := block.Header.BlockHeight(); actual != vctx.expectedBlockHeight {
+		return errors.Errorf("results block has height %d, expected %d", actual, vctx.expectedBlockHeight)
+	}
+	if txHeight := vctx.transactionsBlock.Header.BlockHeight(); txHeight != vctx.expectedBlockHeight {
+		return errors.Errorf("results block is paired with a transactions block of height %d, expected %d", txHeight, vctx.expectedBlockHeight)
+	}
+	return nil
+}
+
+func validateRxPrevBlockHashPtr(block *protocol.ResultsBlockContainer, vctx *rxBlockValidationContext) error {
+	if actual := block.Header.PrevBlockHashPtr(); !bytes.Equal(actual, vctx.expectedPrevBlockHash) {
+		return errors.Errorf("results block points to previous block %s, expected %s", actual, vctx.expectedPrevBlockHash)
+	}
+	return nil
+}
+
+func validateRxTransactionsBlockHashPtr(block *protocol.ResultsBlockContainer, vctx *rxBlockValidationContext) error {
+	expected := digest.CalcTransactionsBlockHash(vctx.transactionsBlock)
+	if actual := block.Header.TransactionsBlockHashPtr(); !bytes.Equal(actual, expected) {
+		return errors.Errorf("results block points to transactions block %s, expected %s", actual, expected)
+	}
+	return nil
+}
+
+func validateRxTimestamp(block *protocol.ResultsBlockContainer, vctx *rxBlockValidationContext) error {
+	if actual, expected := block.Header.Timestamp(), vctx.transactionsBlock.Header.Timestamp(); actual != expected {
+		return errors.Errorf("results block timestamp %d does not match transactions block timestamp %d", actual, expected)
+	}
+	return nil
+}
+
+// the receipts themselves are checked against local execution, so the root hash only needs to match the receipts in the block
+func validateRxReceiptsRootHash(block *protocol.ResultsBlockContainer, vctx *rxBlockValidationContext) error {
+	expected := digest.CalcReceiptsRootHash(block.TransactionReceipts)
+	if actual := block.Header.ReceiptsRootHash(); !bytes.Equal(actual, expected) {
+		return errors.Errorf("results block receipts root hash %s does not match its receipts %s", actual, expected)
+	}
+	return nil
+}
+
+func (s *service) validatePreExecutionStateRootHash(ctx context.Context, block *protocol.ResultsBlockContainer) error {
+	expected, err := s.preExecutionStateRootHash(ctx, block.Header.BlockHeight())
+	if err != nil {
+		return err
+	}
+	if actual := block.Header.PreExecutionStateRootHash(); !bytes.Equal(actual, expected) {
+		return errors.Errorf("results block pre-execution state root hash %s does not match local state root hash %s", actual, expected)
+	}
+	return nil
+}
+
+// the transactions are executed again locally and the results must match the proposal exactly
+func (s *service) validateExecutionResults(ctx context.Context, block *protocol.ResultsBlockContainer, transactionsBlock *protocol.TransactionsBlockContainer) error {
+	output, err := s.virtualMachine.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
+		BlockHeight:        block.Header.BlockHeight(),
+		BlockTimestamp:     transactionsBlock.Header.Timestamp(),
+		SignedTransactions: transactionsBlock.SignedTransactions,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to execute the transactions of the results block")
+	}
+
+	if actual := block.Header.NumTransactionReceipts(); actual != uint32(len(block.TransactionReceipts)) {
+		return errors.Errorf("results block header declares %d receipts but contains %d", actual, len(block.TransactionReceipts))
+	}
+	if len(block.TransactionReceipts) != len(output.TransactionReceipts) {
+		return errors.Errorf("results block has %d receipts but execution produced %d", len(block.TransactionReceipts), len(output.TransactionReceipts))
+	}
+	for i, receipt := range output.TransactionReceipts {
+		if !bytes.Equal(block.TransactionReceipts[i].Raw(), receipt.Raw()) {
+			return errors.Errorf("results block receipt %d for transaction %s does not match execution", i, receipt.Txhash())
+		}
+	}
+
+	if actual := block.Header.NumContractStateDiffs(); actual != uint32(len(block.ContractStateDiffs)) {
+		return errors.Errorf("results block header declares %d state diffs but contains %d", actual, len(block.ContractStateDiffs))
+	}
+	if len(block.ContractStateDiffs) != len(output.ContractStateDiffs) {
+		return errors.Errorf("results block has %d state diffs but execution produced %d", len(block.ContractStateDiffs), len(output.ContractStateDiffs))
+	}
+	for i, stateDiff := range output.ContractStateDiffs {
+		if !bytes.Equal(block.ContractStateDiffs[i].Raw(), stateDiff.Raw()) {
+			return errors.Errorf("results block state diff %d of contract %s does not match execution", i, stateDiff.ContractName())
+		}
+	}
+
+	return nil
+}
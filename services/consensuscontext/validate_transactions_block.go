@@ -0,0 +1,109 @@
+package consensuscontext
+
+import (
+	"bytes"
+	"context"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const ProtocolVersion = primitives.ProtocolVersion(1)
+
+type txBlockValidator func(block *protocol.TransactionsBlockContainer, vctx *txBlockValidationContext) error
+
+type txBlockValidationContext struct {
+	protocolVersion        primitives.ProtocolVersion
+	virtualChainId         primitives.VirtualChainId
+	expectedBlockHeight    primitives.BlockHeight
+	expectedPrevBlockHash  primitives.Sha256
+	nowNano                primitives.TimestampNano
+	allowedTimestampJitter time.Duration
+}
+
+func (s *service) ValidateTransactionsBlock(ctx context.Context, input *services.ValidateTransactionsBlockInput) (*services.ValidateTransactionsBlockOutput, error) {
+	block := input.TransactionsBlock
+	if block == nil || block.Header == nil {
+		return nil, errors.New("transactions block is missing")
+	}
+
+	vctx := &txBlockValidationContext{
+		protocolVersion:        ProtocolVersion,
+		virtualChainId:         s.config.VirtualChainId(),
+		expectedBlockHeight:    input.BlockHeight,
+		expectedPrevBlockHash:  input.PrevBlockHash,
+		nowNano:                primitives.TimestampNano(time.Now().UnixNano()),
+		allowedTimestampJitter: s.config.ConsensusContextSystemTimestampAllowedJitter(),
+	}
+
+	validators := []txBlockValidator{
+		validateTxProtocolVersion,
+		validateTxVirtualChainId,
+		validateTxBlockHeight,
+		validateTxPrevBlockHashPtr,
+		validateTxTimestamp,
+		validateTxTransactionCount,
+	}
+
+	for _, validate := range validators {
+		if err := validate(block, vctx); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := s.transactionPool.ValidateTransactionsForOrdering(ctx, &services.ValidateTransactionsForOrderingInput{
+		BlockHeight:        input.BlockHeight,
+		SignedTransactions: block.SignedTransactions,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "transactions block contains transactions that cannot be ordered")
+	}
+
+	return &services.ValidateTransactionsBlockOutput{}, nil
+}
+
+func validateTxProtocolVersion(block *protocol.TransactionsBlockContainer, vctx *txBlockValidationContext) error {
+	if actual := block.Header.ProtocolVersion(); actual != vctx.protocolVersion {
+		return errors.Errorf("transactions block has protocol version %d, expected %d", actual, vctx.protocolVersion)
+	}
+	return nil
+}
+
+func validateTxVirtualChainId(block *protocol.TransactionsBlockContainer, vctx *txBlockValidationContext) error {
+	if actual := block.Header.VirtualChainId(); actual != vctx.virtualChainId {
+		return errors.Errorf("transactions block belongs to virtual chain %d, expected %d", actual, vctx.virtualChainId)
+	}
+	return nil
+}
+
+func validateTxBlockHeight(block *protocol.TransactionsBlockContainer, vctx *txBlockValidationContext) error {
+	if actual := block.Header.BlockHeight(); actual != vctx.expectedBlockHeight {
+		return errors.Errorf("transactions block has height %d, expected %d", actual, vctx.expectedBlockHeight)
+	}
+	return nil
+}
+
+func validateTxPrevBlockHashPtr(block *protocol.TransactionsBlockContainer, vctx *txBlockValidationContext) error {
+	if actual := block.Header.PrevBlockHashPtr(); !bytes.Equal(actual, vctx.expectedPrevBlockHash) {
+		return errors.Errorf("transactions block points to previous block %s, expected %s", actual, vctx.expectedPrevBlockHash)
+	}
+	return nil
+}
+
+func validateTxTimestamp(block *protocol.TransactionsBlockContainer, vctx *txBlockValidationContext) error {
+	jitter := primitives.TimestampNano(vctx.allowedTimestampJitter.Nanoseconds())
+	timestamp := block.Header.Timestamp()
+	if timestamp > vctx.nowNano+jitter || timestamp+jitter < vctx.nowNano {
+		return errors.Errorf("transactions block timestamp %d is more than %s away from the local time %d", timestamp, vctx.allowedTimestampJitter, vctx.nowNano)
+	}
+	return nil
+}
+
+func validateTxTransactionCount(block *protocol.TransactionsBlockContainer, vctx *txBlockValidationContext) error {
+	if expected, actual := block.Header.NumSignedTransactions(), uint32(len(block.SignedTransactions)); expected != actual {
+		return errors.Errorf("transactions block header declares %d transactions but contains %d", expected, actual)
+	}
+	return nil
+}
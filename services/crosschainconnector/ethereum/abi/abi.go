@@ -0,0 +1,91 @@
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+const SELECTOR_SIZE_BYTES = 4
+
+type Argument struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type Method struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Constant bool       `json:"constant"`
+	Inputs   []Argument `json:"inputs"`
+	Outputs  []Argument `json:"outputs"`
+}
+
+type ABI struct {
+	methods map[string]*Method
+}
+
+func Parse(jsonAbi string) (*ABI, error) {
+	var entries []*Method
+	if err := json.Unmarshal([]byte(jsonAbi), &entries); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ethereum json abi")
+	}
+
+	methods := make(map[string]*Method)
+	for _, entry := range entries {
+		// entries without a type are functions according to the abi spec
+		if entry.Type != "" && entry.Type != "function" {
+			continue
+		}
+		methods[entry.Name] = entry
+	}
+	return &ABI{methods: methods}, nil
+}
+
+func (a *ABI) Method(name string) (*Method, error) {
+	method, found := a.methods[name]
+	if !found {
+		return nil, errors.Errorf("method %s not found in ethereum abi", name)
+	}
+	return method, nil
+}
+
+func (m *Method) Signature() string {
+	types := make([]string, len(m.Inputs))
+	for i, input := range m.Inputs {
+		types[i] = input.Type
+	}
+	return fmt.Sprintf("%s(%s)", m.Name, strings.Join(types, ","))
+}
+
+func (m *Method) Selector() []byte {
+	return hash.CalcKeccak256([]byte(m.Signature()))[:SELECTOR_SIZE_BYTES]
+}
+
+// returns the call data of the method, the selector followed by the encoded arguments
+func (m *Method) Pack(args ...interface{}) ([]byte, error) {
+	packedArgs, err := PackArguments(m.Inputs, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to pack arguments of %s", m.Signature())
+	}
+	return append(m.Selector(), packedArgs...), nil
+}
+
+func (m *Method) UnpackOutputs(data []byte) ([]interface{}, error) {
+	outputs, err := UnpackArguments(m.Outputs, data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unpack outputs of %s", m.Signature())
+	}
+	return outputs, nil
+}
+
+func HexToAddress(address string) ([]byte, error) {
+	decoded, err := hex.DecodeString(strings.TrimPrefix(address, "0x"))
+	if err != nil || len(decoded) != ADDRESS_SIZE_BYTES {
+		return nil, errors.Errorf("invalid ethereum address %s", address)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,144 @@
+package abi
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const erc20Abi = `[
+	{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"}],"name":"Transfer","type":"event"}
+]`
+
+func words(hexWords ...string) []byte {
+	b, err := hex.DecodeString(strings.Join(hexWords, ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestMethodSelector(t *testing.T) {
+	parsed, err := Parse(erc20Abi)
+	require.NoError(t, err)
+
+	method, err := parsed.Method("transfer")
+	require.NoError(t, err)
+	require.Equal(t, "transfer(address,uint256)", method.Signature())
+	require.Equal(t, "a9059cbb", hex.EncodeToString(method.Selector()))
+
+	_, err = parsed.Method("Transfer")
+	require.Error(t, err, "events are not callable methods")
+}
+
+func TestPackStaticArguments(t *testing.T) {
+	parsed, err := Parse(erc20Abi)
+	require.NoError(t, err)
+	method, err := parsed.Method("transfer")
+	require.NoError(t, err)
+
+	address := words("00112233445566778899aabbccddeeff00112233")
+	packed, err := method.Pack(address, uint64(1000))
+	require.NoError(t, err)
+	require.Equal(t, words(
+		"a9059cbb",
+		"00000000000000000000000000112233445566778899aabbccddeeff00112233",
+		"00000000000000000000000000000000000000000000000000000000000003e8",
+	), packed)
+
+	_, err = method.Pack(address)
+	require.Error(t, err, "missing argument")
+	_, err = method.Pack(address[1:], uint64(1))
+	require.Error(t, err, "short address")
+}
+
+func TestPackDynamicArguments(t *testing.T) {
+	arguments := []Argument{{Type: "string"}, {Type: "uint32"}, {Type: "bytes"}}
+	packed, err := PackArguments(arguments, "hello", uint32(7), []byte{0xab})
+	require.NoError(t, err)
+	require.Equal(t, words(
+		"0000000000000000000000000000000000000000000000000000000000000060",
+		"0000000000000000000000000000000000000000000000000000000000000007",
+		"00000000000000000000000000000000000000000000000000000000000000a0",
+		"0000000000000000000000000000000000000000000000000000000000000005",
+		"68656c6c6f000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"ab00000000000000000000000000000000000000000000000000000000000000",
+	), packed)
+
+	unpacked, err := UnpackArguments(arguments, packed)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"hello", uint32(7), []byte{0xab}}, unpacked)
+}
+
+func TestPackAndUnpackSignedIntegers(t *testing.T) {
+	arguments := []Argument{{Type: "int256"}, {Type: "int8"}}
+	packed, err := PackArguments(arguments, big.NewInt(-1), big.NewInt(127))
+	require.NoError(t, err)
+	require.Equal(t, words(
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"000000000000000000000000000000000000000000000000000000000000007f",
+	), packed)
+
+	unpacked, err := UnpackArguments(arguments, packed)
+	require.NoError(t, err)
+	require.Equal(t, 0, big.NewInt(-1).Cmp(unpacked[0].(*big.Int)))
+	require.Equal(t, 0, big.NewInt(127).Cmp(unpacked[1].(*big.Int)))
+
+	_, err = PackArguments(arguments, big.NewInt(0), big.NewInt(128))
+	require.Error(t, err, "value does not fit in int8")
+}
+
+func TestUnpackOutputs(t *testing.T) {
+	parsed, err := Parse(erc20Abi)
+	require.NoError(t, err)
+
+	balanceOf, err := parsed.Method("balanceOf")
+	require.NoError(t, err)
+	outputs, err := balanceOf.UnpackOutputs(words("00000000000000000000000000000000000000000000000000000000000003e8"))
+	require.NoError(t, err)
+	require.Equal(t, 0, big.NewInt(1000).Cmp(outputs[0].(*big.Int)))
+
+	transfer, err := parsed.Method("transfer")
+	require.NoError(t, err)
+	outputs, err = transfer.UnpackOutputs(words("0000000000000000000000000000000000000000000000000000000000000001"))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{true}, outputs)
+
+	_, err = transfer.UnpackOutputs(words("0000000000000000000000000000000000000000000000000000000000000002"))
+	require.Error(t, err, "invalid bool")
+	_, err = transfer.UnpackOutputs(nil)
+	require.Error(t, err, "empty output")
+
+	name, err := parsed.Method("name")
+	require.NoError(t, err)
+	_, err = name.UnpackOutputs(words(
+		"0000000000000000000000000000000000000000000000000000000000000020",
+		"0000000000000000000000000000000000000000000000000000000000000040",
+		"68656c6c6f000000000000000000000000000000000000000000000000000000",
+	))
+	require.Error(t, err, "string length exceeds output")
+}
+
+func TestUnsupportedTypes(t *testing.T) {
+	for _, typeName := range []string{"uint256[]", "tuple", "uint7", "bytes33", "fixed128x18"} {
+		_, err := PackArguments([]Argument{{Type: typeName}}, uint32(0))
+		require.Error(t, err, "type %s should not be supported", typeName)
+	}
+}
+
+func TestHexToAddress(t *testing.T) {
+	address, err := HexToAddress("0x00112233445566778899aabbccddeeff00112233")
+	require.NoError(t, err)
+	require.Equal(t, words("00112233445566778899aabbccddeeff00112233"), address)
+
+	_, err = HexToAddress("0x0011")
+	require.Error(t, err, "short address")
+	_, err = HexToAddress("not an address")
+	require.Error(t, err, "invalid hex")
+}
@@ -0,0 +1,349 @@
+package abi
+
+import (
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	WORD_SIZE_BYTES    = 32
+	ADDRESS_SIZE_BYTES = 20
+)
+
+type typeKind int
+
+const (
+	KIND_UINT typeKind = iota
+	KIND_INT
+	KIND_BOOL
+	KIND_ADDRESS
+	KIND_FIXED_BYTES
+	KIND_BYTES
+	KIND_STRING
+)
+
+type abiType struct {
+	kind typeKind
+	size int // bits for integers, bytes for fixed bytes
+}
+
+func (t abiType) isDynamic() bool {
+	return t.kind == KIND_BYTES || t.kind == KIND_STRING
+}
+
+// arrays and tuples are not supported yet
+func parseType(name string) (abiType, error) {
+	switch {
+	case name == "bool":
+		return abiType{kind: KIND_BOOL}, nil
+	case name == "address":
+		return abiType{kind: KIND_ADDRESS}, nil
+	case name == "string":
+		return abiType{kind: KIND_STRING}, nil
+	case name == "bytes":
+		return abiType{kind: KIND_BYTES}, nil
+	case strings.HasPrefix(name, "uint"):
+		size, err := parseSize(name[len("uint"):], 256, 8, 256)
+		return abiType{kind: KIND_UINT, size: size}, errors.Wrapf(err, "unsupported abi type %s", name)
+	case strings.HasPrefix(name, "int"):
+		size, err := parseSize(name[len("int"):], 256, 8, 256)
+		return abiType{kind: KIND_INT, size: size}, errors.Wrapf(err, "unsupported abi type %s", name)
+	case strings.HasPrefix(name, "bytes"):
+		size, err := parseSize(name[len("bytes"):], 0, 1, WORD_SIZE_BYTES)
+		return abiType{kind: KIND_FIXED_BYTES, size: size}, errors.Wrapf(err, "unsupported abi type %s", name)
+	}
+	return abiType{}, errors.Errorf("unsupported abi type %s", name)
+}
+
+func parseSize(suffix string, defaultSize int, multipleOf int, max int) (int, error) {
+	if suffix == "" && defaultSize > 0 {
+		return defaultSize, nil
+	}
+	size, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 0, err
+	}
+	if size <= 0 || size > max || size%multipleOf != 0 {
+		return 0, errors.Errorf("invalid size %d", size)
+	}
+	return size, nil
+}
+
+// encodes the arguments with the head and tail layout of the ethereum abi
+func PackArguments(arguments []Argument, values ...interface{}) ([]byte, error) {
+	if len(arguments) != len(values) {
+		return nil, errors.Errorf("expected %d arguments but got %d", len(arguments), len(values))
+	}
+
+	head := make([]byte, 0, len(arguments)*WORD_SIZE_BYTES)
+	var tail []byte
+	for i, argument := range arguments {
+		t, err := parseType(argument.Type)
+		if err != nil {
+			return nil, err
+		}
+		packed, err := packValue(t, values[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d of type %s", i, argument.Type)
+		}
+		if t.isDynamic() {
+			offset := len(arguments)*WORD_SIZE_BYTES + len(tail)
+			head = append(head, packUint(big.NewInt(int64(offset)))...)
+			tail = append(tail, packed...)
+		} else {
+			head = append(head, packed...)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+func packValue(t abiType, value interface{}) ([]byte, error) {
+	switch t.kind {
+	case KIND_UINT, KIND_INT:
+		n, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIntRange(t, n); err != nil {
+			return nil, err
+		}
+		return packUint(toTwosComplement(n)), nil
+	case KIND_BOOL:
+		b, err := toBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return packUint(big.NewInt(1)), nil
+		}
+		return packUint(big.NewInt(0)), nil
+	case KIND_ADDRESS:
+		address, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(address) != ADDRESS_SIZE_BYTES {
+			return nil, errors.Errorf("address must be %d bytes but is %d", ADDRESS_SIZE_BYTES, len(address))
+		}
+		return leftPad(address), nil
+	case KIND_FIXED_BYTES:
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > t.size {
+			return nil, errors.Errorf("value of %d bytes does not fit in bytes%d", len(b), t.size)
+		}
+		return rightPad(b), nil
+	case KIND_BYTES, KIND_STRING:
+		b, err := toBytes(value)
+		if s, ok := value.(string); ok && t.kind == KIND_STRING {
+			b, err = []byte(s), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return append(packUint(big.NewInt(int64(len(b)))), rightPad(b)...), nil
+	}
+	return nil, errors.Errorf("unsupported abi type kind %d", t.kind)
+}
+
+func UnpackArguments(arguments []Argument, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(arguments))
+	for i, argument := range arguments {
+		t, err := parseType(argument.Type)
+		if err != nil {
+			return nil, err
+		}
+		word, err := wordAt(data, i*WORD_SIZE_BYTES)
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d of type %s", i, argument.Type)
+		}
+		values[i], err = unpackValue(t, word, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d of type %s", i, argument.Type)
+		}
+	}
+	return values, nil
+}
+
+// unsigned integers that fit are returned as uint32 or uint64, anything wider or signed as *big.Int
+func unpackValue(t abiType, word []byte, data []byte) (interface{}, error) {
+	switch t.kind {
+	case KIND_UINT:
+		n := new(big.Int).SetBytes(word)
+		if err := checkIntRange(t, n); err != nil {
+			return nil, err
+		}
+		switch {
+		case t.size <= 32:
+			return uint32(n.Uint64()), nil
+		case t.size <= 64:
+			return n.Uint64(), nil
+		}
+		return n, nil
+	case KIND_INT:
+		n := fromTwosComplement(new(big.Int).SetBytes(word))
+		if err := checkIntRange(t, n); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case KIND_BOOL:
+		n := new(big.Int).SetBytes(word)
+		if !n.IsUint64() || n.Uint64() > 1 {
+			return nil, errors.Errorf("invalid bool value %s", n)
+		}
+		return n.Uint64() == 1, nil
+	case KIND_ADDRESS:
+		return word[WORD_SIZE_BYTES-ADDRESS_SIZE_BYTES:], nil
+	case KIND_FIXED_BYTES:
+		return word[:t.size], nil
+	case KIND_BYTES, KIND_STRING:
+		b, err := unpackDynamic(word, data)
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == KIND_STRING {
+			return string(b), nil
+		}
+		return b, nil
+	}
+	return nil, errors.Errorf("unsupported abi type kind %d", t.kind)
+}
+
+func unpackDynamic(offsetWord []byte, data []byte) ([]byte, error) {
+	offset, err := wordToInt(offsetWord)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid offset")
+	}
+	lengthWord, err := wordAt(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	length, err := wordToInt(lengthWord)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid length")
+	}
+	start := offset + WORD_SIZE_BYTES
+	if length > len(data)-start {
+		return nil, errors.Errorf("value of %d bytes at offset %d exceeds data of %d bytes", length, offset, len(data))
+	}
+	return data[start : start+length], nil
+}
+
+func wordAt(data []byte, offset int) ([]byte, error) {
+	if offset < 0 || offset+WORD_SIZE_BYTES > len(data) {
+		return nil, errors.Errorf("data of %d bytes is too short to read a word at offset %d", len(data), offset)
+	}
+	return data[offset : offset+WORD_SIZE_BYTES], nil
+}
+
+func wordToInt(word []byte) (int, error) {
+	n := new(big.Int).SetBytes(word)
+	if !n.IsInt64() || n.Int64() > int64(^uint32(0)) {
+		return 0, errors.Errorf("value %s is too large", n)
+	}
+	return int(n.Int64()), nil
+}
+
+func checkIntRange(t abiType, n *big.Int) error {
+	if t.kind == KIND_UINT {
+		if n.Sign() < 0 || n.BitLen() > t.size {
+			return errors.Errorf("value %s does not fit in uint%d", n, t.size)
+		}
+		return nil
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.size-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return errors.Errorf("value %s does not fit in int%d", n, t.size)
+	}
+	return nil
+}
+
+var twoTo256 = new(big.Int).Lsh(big.NewInt(1), WORD_SIZE_BYTES*8)
+
+func toTwosComplement(n *big.Int) *big.Int {
+	if n.Sign() >= 0 {
+		return n
+	}
+	return new(big.Int).Add(n, twoTo256)
+}
+
+func fromTwosComplement(n *big.Int) *big.Int {
+	if n.Bit(WORD_SIZE_BYTES*8-1) == 0 {
+		return n
+	}
+	return new(big.Int).Sub(n, twoTo256)
+}
+
+func packUint(n *big.Int) []byte {
+	return leftPad(n.Bytes())
+}
+
+func leftPad(b []byte) []byte {
+	padded := make([]byte, WORD_SIZE_BYTES)
+	copy(padded[WORD_SIZE_BYTES-len(b):], b)
+	return padded
+}
+
+func rightPad(b []byte) []byte {
+	size := (len(b) + WORD_SIZE_BYTES - 1) / WORD_SIZE_BYTES * WORD_SIZE_BYTES
+	padded := make([]byte, size)
+	copy(padded, b)
+	return padded
+}
+
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case int64:
+		return big.NewInt(v), nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case []byte:
+		if len(v) > WORD_SIZE_BYTES {
+			return nil, errors.Errorf("integer of %d bytes is too large", len(v))
+		}
+		return new(big.Int).SetBytes(v), nil
+	}
+	return nil, errors.Errorf("cannot convert %T to an integer", value)
+}
+
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case uint32:
+		if v <= 1 {
+			return v == 1, nil
+		}
+	case uint64:
+		if v <= 1 {
+			return v == 1, nil
+		}
+	}
+	return false, errors.Errorf("cannot convert %v of type %T to a bool", value, value)
+}
+
+// strings passed for binary types are treated as hex when they start with 0x
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		if strings.HasPrefix(v, "0x") {
+			b, err := hex.DecodeString(v[2:])
+			return b, errors.Wrapf(err, "invalid hex string %s", v)
+		}
+		return []byte(v), nil
+	}
+	return nil, errors.Errorf("cannot convert %T to bytes", value)
+}
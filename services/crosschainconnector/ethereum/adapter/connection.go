@@ -0,0 +1,12 @@
+package adapter
+
+import (
+	"context"
+	"math/big"
+)
+
+type EthereumConnection interface {
+	CallContract(ctx context.Context, contractAddress []byte, packedInput []byte, blockNumber *big.Int) ([]byte, error)
+	BlockNumber(ctx context.Context) (*big.Int, error)
+	BlockTimestamp(ctx context.Context, blockNumber *big.Int) (uint64, error) // in seconds since the epoch
+}
@@ -0,0 +1,186 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+var LogTag = log.String("adapter", "ethereum")
+
+type Config interface {
+	EthereumEndpoint() string
+}
+
+type rpcRequest struct {
+	JsonRpc string        `json:"jsonrpc"`
+	Id      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	Id     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type callArgs struct {
+	To   string `json:"to"`
+	Data string `json:"data"`
+}
+
+type blockHeader struct {
+	Timestamp string `json:"timestamp"`
+}
+
+type rpcConnection struct {
+	config Config
+	logger log.BasicLogger
+	client *http.Client
+	nextId uint64
+}
+
+func NewEthereumRpcConnection(config Config, logger log.BasicLogger) EthereumConnection {
+	return &rpcConnection{
+		config: config,
+		logger: logger.WithTags(LogTag),
+		client: &http.Client{},
+	}
+}
+
+func (c *rpcConnection) CallContract(ctx context.Context, contractAddress []byte, packedInput []byte, blockNumber *big.Int) ([]byte, error) {
+	args := &callArgs{
+		To:   encodeHex(contractAddress),
+		Data: encodeHex(packedInput),
+	}
+
+	var result string
+	if err := c.call(ctx, &result, "eth_call", args, encodeQuantity(blockNumber)); err != nil {
+		return nil, err
+	}
+	return decodeHex(result)
+}
+
+func (c *rpcConnection) BlockNumber(ctx context.Context) (*big.Int, error) {
+	var result string
+	if err := c.call(ctx, &result, "eth_blockNumber"); err != nil {
+		return nil, err
+	}
+	return decodeQuantity(result)
+}
+
+func (c *rpcConnection) BlockTimestamp(ctx context.Context, blockNumber *big.Int) (uint64, error) {
+	var result *blockHeader
+	if err := c.call(ctx, &result, "eth_getBlockByNumber", encodeQuantity(blockNumber), false); err != nil {
+		return 0, err
+	}
+	if result == nil {
+		return 0, errors.Errorf("ethereum block %s was not found", blockNumber)
+	}
+	timestamp, err := decodeQuantity(result.Timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return timestamp.Uint64(), nil
+}
+
+func (c *rpcConnection) call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	err := c.doCall(ctx, result, method, params)
+	if err != nil {
+		c.logger.Info("ethereum rpc call failed", log.String("method", method), log.String("endpoint", c.config.EthereumEndpoint()), log.Error(err))
+	}
+	return err
+}
+
+func (c *rpcConnection) doCall(ctx context.Context, result interface{}, method string, params []interface{}) error {
+	request := &rpcRequest{
+		JsonRpc: "2.0",
+		Id:      atomic.AddUint64(&c.nextId, 1),
+		Method:  method,
+		Params:  params,
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %s request", method)
+	}
+
+	httpRequest, err := http.NewRequest("POST", c.config.EthereumEndpoint(), bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s request", method)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := c.client.Do(httpRequest.WithContext(ctx))
+	if err != nil {
+		return errors.Wrapf(err, "%s request to ethereum node failed", method)
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(httpResponse.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s response", method)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return errors.Errorf("%s request to ethereum node returned http status %d", method, httpResponse.StatusCode)
+	}
+
+	response := &rpcResponse{}
+	if err := json.Unmarshal(responseBody, response); err != nil {
+		return errors.Wrapf(err, "failed to decode %s response", method)
+	}
+	if response.Error != nil {
+		return errors.Errorf("%s failed on ethereum node with code %d: %s", method, response.Error.Code, response.Error.Message)
+	}
+	if response.Id != request.Id {
+		return errors.Errorf("%s response id %d does not match request id %d", method, response.Id, request.Id)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return errors.Wrapf(err, "failed to decode %s result", method)
+	}
+	return nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func decodeHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, errors.Errorf("hex value %s is missing the 0x prefix", s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid hex value %s", s)
+	}
+	return b, nil
+}
+
+func encodeQuantity(n *big.Int) string {
+	if n == nil {
+		return "latest"
+	}
+	return fmt.Sprintf("0x%x", n)
+}
+
+func decodeQuantity(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !strings.HasPrefix(s, "0x") || !ok {
+		return nil, errors.Errorf("invalid quantity %s", s)
+	}
+	return n, nil
+}
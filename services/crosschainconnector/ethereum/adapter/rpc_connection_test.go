@@ -0,0 +1,138 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type stubRpcServer struct {
+	*httptest.Server
+	requests []*rpcRequest
+	reply    func(request *rpcRequest) (result interface{}, rpcErr *rpcError)
+}
+
+func newStubRpcServer(reply func(request *rpcRequest) (interface{}, *rpcError)) *stubRpcServer {
+	s := &stubRpcServer{reply: reply}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &rpcRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.requests = append(s.requests, request)
+
+		result, rpcErr := s.reply(request)
+		encodedResult, _ := json.Marshal(result)
+		json.NewEncoder(w).Encode(&rpcResponse{Id: request.Id, Result: encodedResult, Error: rpcErr})
+	}))
+	return s
+}
+
+func newRpcConnection(endpoint string) EthereumConnection {
+	return NewEthereumRpcConnection(config.ForEthereumCrosschainConnectorTests(endpoint, 0, 0), log.GetLogger())
+}
+
+func TestRpcConnection_CallContractSendsEthCallAtPinnedBlock(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		server := newStubRpcServer(func(request *rpcRequest) (interface{}, *rpcError) {
+			return "0x00ff", nil
+		})
+		defer server.Close()
+
+		output, err := newRpcConnection(server.URL).CallContract(ctx, []byte{0x12, 0x34}, []byte{0xab, 0xcd}, big.NewInt(300))
+		require.NoError(t, err, "call contract should succeed")
+		require.Equal(t, []byte{0x00, 0xff}, output, "output should be decoded from the hex result")
+
+		require.Len(t, server.requests, 1, "exactly one request should be sent")
+		request := server.requests[0]
+		require.Equal(t, "eth_call", request.Method)
+		require.Equal(t, map[string]interface{}{"to": "0x1234", "data": "0xabcd"}, request.Params[0], "call args should be hex encoded")
+		require.Equal(t, "0x12c", request.Params[1], "block number should be encoded as a quantity")
+	})
+}
+
+func TestRpcConnection_BlockNumber(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		server := newStubRpcServer(func(request *rpcRequest) (interface{}, *rpcError) {
+			return "0x4b7", nil
+		})
+		defer server.Close()
+
+		blockNumber, err := newRpcConnection(server.URL).BlockNumber(ctx)
+		require.NoError(t, err, "block number should succeed")
+		require.Equal(t, int64(1207), blockNumber.Int64())
+		require.Equal(t, "eth_blockNumber", server.requests[0].Method)
+	})
+}
+
+func TestRpcConnection_ReturnsErrorOnRpcError(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		server := newStubRpcServer(func(request *rpcRequest) (interface{}, *rpcError) {
+			return nil, &rpcError{Code: -32000, Message: "execution reverted"}
+		})
+		defer server.Close()
+
+		_, err := newRpcConnection(server.URL).CallContract(ctx, []byte{0x12}, []byte{0xab}, big.NewInt(1))
+		require.Error(t, err, "rpc error should fail the call")
+		require.Contains(t, err.Error(), "execution reverted")
+	})
+}
+
+func TestRpcConnection_ReturnsErrorOnMalformedResult(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		server := newStubRpcServer(func(request *rpcRequest) (interface{}, *rpcError) {
+			return "not-hex", nil
+		})
+		defer server.Close()
+
+		_, err := newRpcConnection(server.URL).CallContract(ctx, []byte{0x12}, []byte{0xab}, big.NewInt(1))
+		require.Error(t, err, "malformed result should fail the call")
+	})
+}
+
+func TestRpcConnection_ReturnsErrorWhenEndpointIsDown(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		server := newStubRpcServer(nil)
+		server.Close()
+
+		_, err := newRpcConnection(server.URL).BlockNumber(ctx)
+		require.Error(t, err, "unreachable endpoint should fail the call")
+	})
+}
+
+func TestRpcConnection_BlockTimestampReadsBlockHeader(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		server := newStubRpcServer(func(request *rpcRequest) (interface{}, *rpcError) {
+			return map[string]interface{}{"number": "0x12c", "timestamp": "0x5bc8a0f0"}, nil
+		})
+		defer server.Close()
+
+		timestamp, err := newRpcConnection(server.URL).BlockTimestamp(ctx, big.NewInt(300))
+		require.NoError(t, err, "block timestamp should succeed")
+		require.EqualValues(t, 0x5bc8a0f0, timestamp, "timestamp should be decoded from the block header")
+
+		request := server.requests[0]
+		require.Equal(t, "eth_getBlockByNumber", request.Method)
+		require.Equal(t, []interface{}{"0x12c", false}, request.Params, "block should be requested without its transactions")
+	})
+}
+
+func TestRpcConnection_BlockTimestampFailsOnMissingBlock(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		server := newStubRpcServer(func(request *rpcRequest) (interface{}, *rpcError) {
+			return nil, nil
+		})
+		defer server.Close()
+
+		_, err := newRpcConnection(server.URL).BlockTimestamp(ctx, big.NewInt(300))
+		require.Error(t, err, "missing block should fail")
+	})
+}
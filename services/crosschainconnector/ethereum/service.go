@@ -2,16 +2,175 @@ package ethereum
 
 import (
 	"context"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/abi"
+	"github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"math/big"
+	"sync"
+	"time"
 )
 
+var LogTag = log.Service("crosschain-connector-ethereum")
+
+const MINED_BY_CACHE_SIZE = 64
+
+type Config interface {
+	EthereumFinalityBlocksComponent() uint32
+	EthereumFinalityTimeComponent() time.Duration
+}
+
 type service struct {
+	connection adapter.EthereumConnection
+	config     Config
+	logger     log.BasicLogger
+	minedBy    *minedByCache
 }
 
-func NewEthereumCrosschainConnector() services.CrosschainConnector {
-	return &service{}
+// all the calls of a block (and of its validation) share its reference timestamp, so the block it resolves to is only
+// searched for once; a result is only cached once a later block exists, before that more blocks may still be mined by it
+type minedByCache struct {
+	sync.Mutex
+	blockNumbers map[uint64]uint64
+	timestamps   []uint64 // in insertion order for eviction
+}
+
+func newMinedByCache() *minedByCache {
+	return &minedByCache{
+		blockNumbers: make(map[uint64]uint64),
+	}
+}
+
+func (c *minedByCache) get(timestamp uint64) (uint64, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	blockNumber, found := c.blockNumbers[timestamp]
+	return blockNumber, found
+}
+
+func (c *minedByCache) put(timestamp uint64, blockNumber uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, found := c.blockNumbers[timestamp]; found {
+		return
+	}
+	if len(c.timestamps) == MINED_BY_CACHE_SIZE {
+		delete(c.blockNumbers, c.timestamps[0])
+		c.timestamps = c.timestamps[1:]
+	}
+	c.blockNumbers[timestamp] = blockNumber
+	c.timestamps = append(c.timestamps, timestamp)
+}
+
+func NewEthereumCrosschainConnector(connection adapter.EthereumConnection, config Config, logger log.BasicLogger) services.CrosschainConnector {
+	return &service{
+		connection: connection,
+		config:     config,
+		logger:     logger.WithTags(LogTag),
+		minedBy:    newMinedByCache(),
+	}
 }
 
 func (s *service) EthereumCallContract(ctx context.Context, input *services.EthereumCallContractInput) (*services.EthereumCallContractOutput, error) {
-	panic("Not implemented")
+	parsedAbi, err := abi.Parse(input.EthereumJsonAbi)
+	if err != nil {
+		return nil, err
+	}
+	method, err := parsedAbi.Method(input.EthereumFunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	contractAddress, err := abi.HexToAddress(input.EthereumContractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	blockNumber, err := s.finalBlockNumber(ctx, input.ReferenceTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	packedInput := append(method.Selector(), input.EthereumAbiPackedInputArguments...)
+	output, err := s.connection.CallContract(ctx, contractAddress, packedInput, blockNumber)
+	if err != nil {
+		return nil, errors.Wrapf(err, "ethereum call to %s on contract %s failed", method.Signature(), input.EthereumContractAddress)
+	}
+
+	// a contract that does not exist at the pinned block or a mismatching abi returns output we cannot decode
+	if _, err := method.UnpackOutputs(output); err != nil {
+		return nil, errors.Wrapf(err, "unexpected output from contract %s at block %s", input.EthereumContractAddress, blockNumber)
+	}
+
+	s.logger.Info("called ethereum contract", log.String("contract", input.EthereumContractAddress), log.String("method", method.Signature()), log.String("ethereum-block", blockNumber.String()))
+	return &services.EthereumCallContractOutput{
+		EthereumAbiPackedOutput: output,
+	}, nil
+}
+
+// calls are pinned by the orbs block timestamp which every node agrees on, and not by each node's own ethereum tip:
+// the last ethereum block mined by the finality time before the reference, minus the finality blocks
+func (s *service) finalBlockNumber(ctx context.Context, referenceTimestamp primitives.TimestampNano) (*big.Int, error) {
+	finalityTime := s.config.EthereumFinalityTimeComponent()
+	if time.Duration(referenceTimestamp) < finalityTime {
+		return nil, errors.Errorf("reference timestamp %d is before the finality time component of %s", referenceTimestamp, finalityTime)
+	}
+	cutoff := uint64((time.Duration(referenceTimestamp) - finalityTime) / time.Second)
+
+	blockNumber, err := s.lastBlockMinedBy(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	finality := new(big.Int).SetUint64(uint64(s.config.EthereumFinalityBlocksComponent()))
+	if blockNumber.Cmp(finality) < 0 {
+		return nil, errors.Errorf("ethereum block %s is not yet past the finality component of %s blocks", blockNumber, finality)
+	}
+	return new(big.Int).Sub(blockNumber, finality), nil
+}
+
+// binary search since ethereum block timestamps never decrease
+func (s *service) lastBlockMinedBy(ctx context.Context, timestamp uint64) (*big.Int, error) {
+	if blockNumber, found := s.minedBy.get(timestamp); found {
+		return new(big.Int).SetUint64(blockNumber), nil
+	}
+
+	latest, err := s.connection.BlockNumber(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest ethereum block number")
+	}
+
+	minedBy := func(blockNumber uint64) (bool, error) {
+		blockTimestamp, err := s.connection.BlockTimestamp(ctx, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get timestamp of ethereum block %d", blockNumber)
+		}
+		return blockTimestamp <= timestamp, nil
+	}
+
+	low, high := uint64(0), latest.Uint64()
+	if ok, err := minedBy(low); err != nil || !ok {
+		return nil, errors.Errorf("no ethereum block was mined by %d", timestamp)
+	}
+	for low < high {
+		mid := low + (high-low+1)/2
+		ok, err := minedBy(mid)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	if low < latest.Uint64() {
+		s.minedBy.put(timestamp, low)
+	}
+	return new(big.Int).SetUint64(low), nil
 }
@@ -0,0 +1,195 @@
+package ethereum
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/abi"
+	"github.com/orbs-network/orbs-network-go/test"
+	harnessAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/crosschainconnector/ethereum/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+const (
+	contractAddress = "0x00112233445566778899aabbccddeeff00112233"
+	finalityBlocks  = 10
+	finalityTime    = 1 * time.Minute
+	storageAbi      = `[{"constant":true,"inputs":[{"name":"key","type":"uint64"}],"name":"get","outputs":[{"name":"","type":"string"}],"type":"function"}]`
+)
+
+// the simulated chain mined blocks 1 to 100 at chainStart
+var chainStart = time.Unix(1500000000, 0)
+
+type harness struct {
+	connection         harnessAdapter.SimulatedEthereumConnection
+	connector          services.CrosschainConnector
+	method             *abi.Method
+	referenceTimestamp primitives.TimestampNano
+}
+
+func newHarness(t *testing.T) *harness {
+	parsed, err := abi.Parse(storageAbi)
+	require.NoError(t, err)
+	method, err := parsed.Method("get")
+	require.NoError(t, err)
+
+	connection := harnessAdapter.NewSimulatedEthereumConnection()
+	connection.SetBlockNumberAt(100, chainStart)
+	return &harness{
+		connection:         connection,
+		connector:          NewEthereumCrosschainConnector(connection, config.ForEthereumCrosschainConnectorTests("", finalityBlocks, finalityTime), log.GetLogger()),
+		method:             method,
+		referenceTimestamp: primitives.TimestampNano(chainStart.Add(finalityTime).UnixNano()),
+	}
+}
+
+func (h *harness) provideStorageContract(t *testing.T, value string) {
+	address, err := abi.HexToAddress(contractAddress)
+	require.NoError(t, err)
+
+	h.connection.ProvideContract(address, func(packedInput []byte, blockNumber *big.Int) ([]byte, error) {
+		require.Equal(t, h.method.Selector(), packedInput[:abi.SELECTOR_SIZE_BYTES], "call should start with the method selector")
+		require.EqualValues(t, 100-finalityBlocks, blockNumber.Int64(), "call should be pinned behind the block mined by the reference timestamp")
+		return abi.PackArguments(h.method.Outputs, value)
+	})
+}
+
+func (h *harness) callGet(ctx context.Context, t *testing.T, address string, key uint64) (*services.EthereumCallContractOutput, error) {
+	packedInput, err := abi.PackArguments(h.method.Inputs, key)
+	require.NoError(t, err)
+
+	return h.connector.EthereumCallContract(ctx, &services.EthereumCallContractInput{
+		ReferenceTimestamp:              h.referenceTimestamp,
+		EthereumContractAddress:         address,
+		EthereumFunctionName:            "get",
+		EthereumJsonAbi:                 storageAbi,
+		EthereumAbiPackedInputArguments: packedInput,
+	})
+}
+
+func TestEthereumCallContract_ReturnsPackedOutput(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+		h.provideStorageContract(t, "hello")
+
+		output, err := h.callGet(ctx, t, contractAddress, 17)
+		require.NoError(t, err, "call should succeed")
+
+		values, err := h.method.UnpackOutputs(output.EthereumAbiPackedOutput)
+		require.NoError(t, err, "output should be packed by the abi")
+		require.Equal(t, []interface{}{"hello"}, values)
+	})
+}
+
+func TestEthereumCallContract_FailsOnUnknownMethod(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+
+		_, err := h.connector.EthereumCallContract(ctx, &services.EthereumCallContractInput{
+			ReferenceTimestamp:      h.referenceTimestamp,
+			EthereumContractAddress: contractAddress,
+			EthereumFunctionName:    "set",
+			EthereumJsonAbi:         storageAbi,
+		})
+		require.Error(t, err, "method missing from the abi should fail")
+	})
+}
+
+func TestEthereumCallContract_FailsOnInvalidContractAddress(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+
+		_, err := h.callGet(ctx, t, "0x0011", 17)
+		require.Error(t, err, "invalid address should fail")
+	})
+}
+
+func TestEthereumCallContract_FailsWhenContractDoesNotExist(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+
+		_, err := h.callGet(ctx, t, contractAddress, 17)
+		require.Error(t, err, "empty output of a missing contract should not decode")
+	})
+}
+
+func TestEthereumCallContract_FailsWhenContractReverts(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+		address, _ := abi.HexToAddress(contractAddress)
+		h.connection.ProvideContract(address, func(packedInput []byte, blockNumber *big.Int) ([]byte, error) {
+			return nil, errors.New("execution reverted")
+		})
+
+		_, err := h.callGet(ctx, t, contractAddress, 17)
+		require.Error(t, err, "revert should fail the call")
+	})
+}
+
+func TestEthereumCallContract_FailsBeforeChainPassesFinality(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+		h.provideStorageContract(t, "hello")
+		h.connection.SetBlockNumber(finalityBlocks - 1)
+
+		_, err := h.callGet(ctx, t, contractAddress, 17)
+		require.Error(t, err, "no block is final yet")
+	})
+}
+
+func TestEthereumCallContract_PinnedByReferenceTimestampAndNotByTip(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+		h.provideStorageContract(t, "hello")
+		h.connection.SetBlockNumberAt(200, chainStart.Add(10*time.Minute))
+
+		_, err := h.callGet(ctx, t, contractAddress, 17)
+		require.NoError(t, err, "blocks mined after the reference timestamp should not move the pinned block")
+	})
+}
+
+func TestEthereumCallContract_FailsWhenNoBlockWasMinedByReferenceTimestamp(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+		h.provideStorageContract(t, "hello")
+		h.referenceTimestamp = 0
+
+		_, err := h.callGet(ctx, t, contractAddress, 17)
+		require.Error(t, err, "reference timestamp before the finality time component should fail")
+	})
+}
+
+type countingConnection struct {
+	harnessAdapter.SimulatedEthereumConnection
+	blockTimestampReads int
+}
+
+func (c *countingConnection) BlockTimestamp(ctx context.Context, blockNumber *big.Int) (uint64, error) {
+	c.blockTimestampReads++
+	return c.SimulatedEthereumConnection.BlockTimestamp(ctx, blockNumber)
+}
+
+func TestEthereumCallContract_SearchesForThePinnedBlockOncePerReferenceTimestamp(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness(t)
+		h.provideStorageContract(t, "hello")
+		h.connection.SetBlockNumberAt(200, chainStart.Add(10*time.Minute))
+		connection := &countingConnection{SimulatedEthereumConnection: h.connection}
+		h.connector = NewEthereumCrosschainConnector(connection, config.ForEthereumCrosschainConnectorTests("", finalityBlocks, finalityTime), log.GetLogger())
+
+		_, err := h.callGet(ctx, t, contractAddress, 17)
+		require.NoError(t, err, "first call should succeed")
+		require.NotZero(t, connection.blockTimestampReads, "first call should search for the pinned block")
+
+		connection.blockTimestampReads = 0
+		_, err = h.callGet(ctx, t, contractAddress, 18)
+		require.NoError(t, err, "second call should succeed")
+		require.Zero(t, connection.blockTimestampReads, "second call with the same reference timestamp should not search again")
+	})
+}
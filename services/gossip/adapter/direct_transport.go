@@ -20,33 +20,51 @@ const MAX_PAYLOAD_SIZE_BYTES = 10 * 1024 * 1024
 
 var LogTag = log.String("adapter", "gossip")
 
-type directTransport struct {
-	config config.GossipTransportConfig
-	logger log.BasicLogger
+// handed to the client goroutine of a peer, which reports back whether the data was written to the peer
+type outgoingMessage struct {
+	data   *TransportData
+	result chan error
+}
 
-	peerQueues map[string]chan *TransportData // does not require mutex to read
+// an outgoing connection to a peer, owned by a client goroutine which lives until the peer is removed
+type outgoingPeer struct {
+	publicKey primitives.Ed25519PublicKey
+	address   string
+	queue     chan *outgoingMessage
+	removed   <-chan struct{}
+	remove    context.CancelFunc
+
+	stateUnderMutex PeerConnectionState
+}
+
+type directTransport struct {
+	config   config.GossipTransportConfig
+	logger   log.BasicLogger
+	security TransportSecurity
+	lifetime context.Context // of the transport, client goroutines of peers never outlive it
 
 	mutex                       *sync.RWMutex
+	peersUnderMutex             map[string]*outgoingPeer
 	transportListenerUnderMutex TransportListener
 	serverListeningUnderMutex   bool
 	serverPort                  int
 }
 
-func NewDirectTransport(ctx context.Context, config config.GossipTransportConfig, logger log.BasicLogger) Transport {
-	t := &directTransport{
-		config: config,
-		logger: logger.WithTags(LogTag),
-
-		peerQueues: make(map[string]chan *TransportData),
-
-		mutex: &sync.RWMutex{},
+func NewDirectTransport(ctx context.Context, config config.GossipTransportConfig, logger log.BasicLogger) DynamicPeersTransport {
+	// a misspelled setting must not turn encryption off
+	security, err := parseTransportSecurity(config.GossipTransportSecurity())
+	if err != nil {
+		logger.Error("falling back to authenticated encryption", log.Error(err))
 	}
 
-	// client channels (not under mutex, before all goroutines)
-	for peerNodeKey := range t.config.GossipPeers(0) {
-		if peerNodeKey != t.config.NodePublicKey().KeyForMap() {
-			t.peerQueues[peerNodeKey] = make(chan *TransportData)
-		}
+	t := &directTransport{
+		config:   config,
+		logger:   logger.WithTags(LogTag),
+		security: security,
+		lifetime: ctx,
+
+		mutex:           &sync.RWMutex{},
+		peersUnderMutex: make(map[string]*outgoingPeer),
 	}
 
 	// server goroutine
@@ -55,15 +73,78 @@ func NewDirectTransport(ctx context.Context, config config.GossipTransportConfig
 	})
 
 	// client goroutines
-	for peerNodeKey, peer := range t.config.GossipPeers(0) {
-		if peerNodeKey != t.config.NodePublicKey().KeyForMap() {
-			peerAddress := fmt.Sprintf("%s:%d", peer.GossipEndpoint(), peer.GossipPort())
-			//TODO supervise
-			go t.clientMainLoop(ctx, peerAddress, t.peerQueues[peerNodeKey])
+	t.UpdatePeers(t.config.GossipPeers(0))
+
+	return t
+}
+
+// reconciles the outgoing connections with peers, which is the whole federation (the node itself is skipped),
+// client goroutines of new peers live as long as the transport, removed peers finish the data they are sending,
+// fail the data still waiting for them and disconnect
+func (t *directTransport) UpdatePeers(peers map[string]config.GossipPeer) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for peerKey, peer := range t.peersUnderMutex {
+		if updatedPeer, found := peers[peerKey]; !found || addressOf(updatedPeer) != peer.address {
+			t.logger.Info("removing gossip peer", log.Stringable("peer-public-key", peer.publicKey), log.String("peer", peer.address))
+			peer.remove()
+			delete(t.peersUnderMutex, peerKey)
 		}
 	}
 
-	return t
+	for peerKey, gossipPeer := range peers {
+		if _, found := t.peersUnderMutex[peerKey]; found || peerKey == t.config.NodePublicKey().KeyForMap() {
+			continue
+		}
+
+		peerCtx, remove := context.WithCancel(t.lifetime)
+		peer := &outgoingPeer{
+			publicKey:       primitives.Ed25519PublicKey(peerKey),
+			address:         addressOf(gossipPeer),
+			queue:           make(chan *outgoingMessage),
+			removed:         peerCtx.Done(),
+			remove:          remove,
+			stateUnderMutex: PEER_CONNECTION_STATE_CONNECTING,
+		}
+		t.peersUnderMutex[peerKey] = peer
+		t.logger.Info("adding gossip peer", log.Stringable("peer-public-key", peer.publicKey), log.String("peer", peer.address))
+		supervised.GoOnce(t.logger, func() {
+			t.clientMainLoop(peerCtx, peer)
+			t.failQueuedMessages(peer)
+		})
+	}
+}
+
+// the connection state of the outgoing connection to every current peer
+func (t *directTransport) PeerConnectionStates() map[string]PeerConnectionState {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	states := make(map[string]PeerConnectionState)
+	for peerKey, peer := range t.peersUnderMutex {
+		states[peerKey] = peer.stateUnderMutex
+	}
+	return states
+}
+
+func (t *directTransport) setPeerState(peer *outgoingPeer, state PeerConnectionState) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	peer.stateUnderMutex = state
+}
+
+func (t *directTransport) getPeer(peerKey string) (*outgoingPeer, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	peer, found := t.peersUnderMutex[peerKey]
+	return peer, found
+}
+
+func addressOf(peer config.GossipPeer) string {
+	return fmt.Sprintf("%s:%d", peer.GossipEndpoint(), peer.GossipPort())
 }
 
 func (t *directTransport) RegisterListener(listener TransportListener, listenerPublicKey primitives.Ed25519PublicKey) {
@@ -73,29 +154,88 @@ func (t *directTransport) RegisterListener(listener TransportListener, listenerP
 	t.transportListenerUnderMutex = listener
 }
 
-// TODO: we are not currently respecting any intents given in ctx (added in context refactor)
+// returns once every recipient either received the data or failed to, blocks while a recipient is disconnected unless ctx is done
 func (t *directTransport) Send(ctx context.Context, data *TransportData) error {
+	peerKeys, err := t.recipientPeerKeys(data)
+	if err != nil {
+		return err
+	}
+
+	peerErrors := make([]error, len(peerKeys))
+	wg := sync.WaitGroup{}
+	for i, peerKey := range peerKeys {
+		peer, found := t.getPeer(peerKey)
+		if !found {
+			peerErrors[i] = errors.New("unknown recipient public key")
+			continue
+		}
+		wg.Add(1)
+		go func(i int, peer *outgoingPeer) {
+			defer wg.Done()
+			peerErrors[i] = sendToPeer(ctx, peer, data)
+		}(i, peer)
+	}
+	wg.Wait()
+
+	failed := make(map[string]error)
+	for i, err := range peerErrors {
+		if err != nil {
+			failed[peerKeys[i]] = err
+		}
+	}
+	return sendFailedOrNil(failed)
+}
+
+func (t *directTransport) recipientPeerKeys(data *TransportData) ([]string, error) {
 	switch data.RecipientMode {
 	case gossipmessages.RECIPIENT_LIST_MODE_BROADCAST:
-		for _, peerQueue := range t.peerQueues {
-			peerQueue <- data
-		}
-		// TODO: how can we tell if was actually sent without error?
-		return nil
+		return t.allPeerKeysExcept(nil), nil
 	case gossipmessages.RECIPIENT_LIST_MODE_LIST:
-		for _, recipientPublicKey := range data.RecipientPublicKeys {
-			if peerQueue, found := t.peerQueues[recipientPublicKey.KeyForMap()]; found {
-				peerQueue <- data
-			} else {
-				return errors.Errorf("unknown recipient public key: %s", recipientPublicKey.KeyForMap())
-			}
+		peerKeys := make([]string, len(data.RecipientPublicKeys))
+		for i, recipientPublicKey := range data.RecipientPublicKeys {
+			peerKeys[i] = recipientPublicKey.KeyForMap()
 		}
-		// TODO: how can we tell if was actually sent without error?
-		return nil
+		return peerKeys, nil
 	case gossipmessages.RECIPIENT_LIST_MODE_ALL_BUT_LIST:
-		panic("Not implemented")
+		return t.allPeerKeysExcept(data.RecipientPublicKeys), nil
+	}
+	return nil, errors.Errorf("unknown recipient mode: %s", data.RecipientMode.String())
+}
+
+func (t *directTransport) allPeerKeysExcept(excludedPublicKeys []primitives.Ed25519PublicKey) []string {
+	excluded := make(map[string]bool)
+	for _, excludedPublicKey := range excludedPublicKeys {
+		excluded[excludedPublicKey.KeyForMap()] = true
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var peerKeys []string
+	for peerKey := range t.peersUnderMutex {
+		if !excluded[peerKey] {
+			peerKeys = append(peerKeys, peerKey)
+		}
+	}
+	return peerKeys
+}
+
+func sendToPeer(ctx context.Context, peer *outgoingPeer, data *TransportData) error {
+	msg := &outgoingMessage{data: data, result: make(chan error, 1)}
+	select {
+	case peer.queue <- msg:
+	case <-peer.removed:
+		return errors.New("peer was removed")
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "peer did not pick up the data")
+	}
+
+	select {
+	case err := <-msg.result:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "peer did not finish sending the data")
 	}
-	return errors.Errorf("unknown recipient mode: %s", data.RecipientMode.String())
 }
 
 func (t *directTransport) serverListenForIncomingConnections(ctx context.Context, listenPort uint16) (net.Listener, error) {
@@ -159,8 +299,20 @@ func (t *directTransport) serverMainLoop(ctx context.Context, listenPort uint16)
 
 func (t *directTransport) serverHandleIncomingConnection(ctx context.Context, conn net.Conn) {
 	t.logger.Info("successful incoming gossip transport connection", log.String("peer", conn.RemoteAddr().String()))
-	// TODO: add a white list for IPs we're willing to accept connections from
-	// TODO: make sure each IP from the white list connects only once
+	// TODO: make sure each peer connects only once
+
+	var peerKey primitives.Ed25519PublicKey
+	if t.security == TRANSPORT_SECURITY_AUTHENTICATED_ENCRYPTION {
+		secured, peerPublicKey, err := secureIncomingConnection(ctx, conn, t.handshakeIdentity(), t.isKnownPeer, t.config.GossipNetworkTimeout())
+		if err != nil {
+			t.logger.Info("incoming gossip transport handshake failed, disconnecting", log.Error(err), log.String("peer", conn.RemoteAddr().String()))
+			conn.Close()
+			return
+		}
+		t.logger.Info("incoming gossip transport connection authenticated", log.Stringable("peer-public-key", peerPublicKey), log.String("peer", conn.RemoteAddr().String()))
+		conn = secured
+		peerKey = peerPublicKey
+	}
 
 	for {
 		payloads, err := t.receiveTransportData(ctx, conn)
@@ -170,6 +322,13 @@ func (t *directTransport) serverHandleIncomingConnection(ctx context.Context, co
 			return
 		}
 
+		// an authenticated peer is not heard anymore once it was removed
+		if peerKey != nil && !t.isKnownPeer(peerKey) {
+			t.logger.Info("gossip peer was removed, disconnecting", log.Stringable("peer-public-key", peerKey), log.String("peer", conn.RemoteAddr().String()))
+			conn.Close()
+			return
+		}
+
 		// notify if not keepalive
 		if len(payloads) > 0 {
 			t.notifyListener(ctx, payloads)
@@ -242,31 +401,74 @@ func (t *directTransport) getListener() TransportListener {
 	return t.transportListenerUnderMutex
 }
 
-func (t *directTransport) clientMainLoop(ctx context.Context, address string, msgs chan *TransportData) {
+// ctx is done when the peer is removed or the transport is shutting down
+func (t *directTransport) clientMainLoop(ctx context.Context, peer *outgoingPeer) {
 	for {
-		t.logger.Info("attempting outgoing transport connection", log.String("server", address))
-		conn, err := net.Dial("tcp", address)
+		t.logger.Info("attempting outgoing transport connection", log.String("server", peer.address))
+		conn, err := net.Dial("tcp", peer.address)
 
 		if err != nil {
-			t.logger.Info("cannot connect to gossip peer endpoint", log.String("peer", address), log.Error(err))
-			time.Sleep(t.config.GossipConnectionKeepAliveInterval())
+			t.logger.Info("cannot connect to gossip peer endpoint", log.String("peer", peer.address), log.Error(err))
+			if !t.waitBeforeReconnect(ctx, peer) {
+				return
+			}
 			continue
 		}
 
-		if !t.clientHandleOutgoingConnection(ctx, conn, msgs) {
+		if t.security == TRANSPORT_SECURITY_AUTHENTICATED_ENCRYPTION {
+			secured, err := secureOutgoingConnection(ctx, conn, t.handshakeIdentity(), peer.publicKey, t.config.GossipNetworkTimeout())
+			if err != nil {
+				t.logger.Info("outgoing gossip transport handshake failed", log.String("peer", peer.address), log.Error(err))
+				conn.Close()
+				if !t.waitBeforeReconnect(ctx, peer) {
+					return
+				}
+				continue
+			}
+			conn = secured
+		}
+
+		t.setPeerState(peer, PEER_CONNECTION_STATE_CONNECTED)
+		shouldReconnect := t.clientHandleOutgoingConnection(ctx, conn, peer.queue)
+		t.setPeerState(peer, PEER_CONNECTION_STATE_CONNECTING)
+		if !shouldReconnect {
+			return
+		}
+	}
+}
+
+// senders racing the removal may still be handing data to the queue after the client loop stopped reading it
+func (t *directTransport) failQueuedMessages(peer *outgoingPeer) {
+	for {
+		select {
+		case msg := <-peer.queue:
+			msg.result <- errors.New("peer was removed")
+		default:
 			return
 		}
 	}
 }
 
+// returns false if the peer was removed or the transport is shutting down while waiting
+func (t *directTransport) waitBeforeReconnect(ctx context.Context, peer *outgoingPeer) bool {
+	select {
+	case <-time.After(t.config.GossipConnectionKeepAliveInterval()):
+		return true
+	case <-ctx.Done():
+		t.logger.Info("client loop stopped since peer was removed or server is shutting down", log.String("peer", peer.address))
+		return false
+	}
+}
+
 // returns true if should attempt reconnect on error
-func (t *directTransport) clientHandleOutgoingConnection(ctx context.Context, conn net.Conn, msgs chan *TransportData) bool {
+func (t *directTransport) clientHandleOutgoingConnection(ctx context.Context, conn net.Conn, msgs chan *outgoingMessage) bool {
 	t.logger.Info("successful outgoing gossip transport connection", log.String("peer", conn.RemoteAddr().String()))
 
 	for {
 		select {
-		case data := <-msgs:
-			err := t.sendTransportData(ctx, conn, data)
+		case msg := <-msgs:
+			err := t.sendTransportData(ctx, conn, msg.data)
+			msg.result <- err
 			if err != nil {
 				t.logger.Info("failed sending transport data, reconnecting", log.Error(err), log.String("peer", conn.RemoteAddr().String()))
 				conn.Close()
@@ -280,13 +482,22 @@ func (t *directTransport) clientHandleOutgoingConnection(ctx context.Context, co
 				return true
 			}
 		case <-ctx.Done():
-			t.logger.Info("client loop stopped since server is shutting down")
+			t.logger.Info("client loop stopped since peer was removed or server is shutting down", log.String("peer", conn.RemoteAddr().String()))
 			conn.Close()
 			return false
 		}
 	}
 }
 
+func (t *directTransport) handshakeIdentity() *handshakeIdentity {
+	return &handshakeIdentity{publicKey: t.config.NodePublicKey(), privateKey: t.config.NodePrivateKey()}
+}
+
+func (t *directTransport) isKnownPeer(publicKey primitives.Ed25519PublicKey) bool {
+	_, found := t.getPeer(publicKey.KeyForMap())
+	return found
+}
+
 func (t *directTransport) sendTransportData(ctx context.Context, conn net.Conn, data *TransportData) error {
 	t.logger.Info("sending transport data", log.Int("payloads", len(data.Payloads)), log.String("peer", conn.RemoteAddr().String()))
 
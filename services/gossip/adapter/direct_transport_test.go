@@ -3,12 +3,15 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
 	"github.com/stretchr/testify/require"
 	"net"
 	"testing"
+	"time"
 )
 
 func TestDirectIncoming_ConnectionsAreListenedToWhileContextIsLive(t *testing.T) {
@@ -276,6 +279,89 @@ func TestDirectIncoming_TimeoutDuringReceiveCausesDisconnect(t *testing.T) {
 	})
 }
 
+func TestDirectOutgoing_ReportsPeerConnectionStates(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+
+		h := newDirectHarnessWithConnectedPeers(t, ctx)
+		defer h.cleanupConnectedPeers()
+
+		require.True(t, test.Eventually(test.EVENTUALLY_ADAPTER_TIMEOUT, func() bool {
+			return h.peerConnectionState(0) == PEER_CONNECTION_STATE_CONNECTED && h.peerConnectionState(1) == PEER_CONNECTION_STATE_CONNECTED
+		}), "all peers should be reported connected")
+
+		h.peersListenersConnections[0].Close()
+		h.peersListeners[0].Close() // peer is down, keepalives fail and reconnecting fails
+
+		require.True(t, test.Eventually(test.EVENTUALLY_ADAPTER_TIMEOUT, func() bool {
+			return h.peerConnectionState(0) == PEER_CONNECTION_STATE_CONNECTING
+		}), "peer that is down should be reported connecting")
+		require.Equal(t, PEER_CONNECTION_STATE_CONNECTED, h.peerConnectionState(1), "other peer should still be reported connected")
+	})
+}
+
+func TestDirectOutgoing_AddedPeerIsConnectedAndSentTo(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+
+		h := newDirectHarnessWithConnectedPeers(t, ctx)
+		defer h.cleanupConnectedPeers()
+
+		addedPublicKey := keys.Ed25519KeyPairForTests(NETWORK_SIZE).PublicKey()
+		addedListener, err := net.Listen("tcp", ":0")
+		require.NoError(t, err, "test peer server could not listen")
+		defer addedListener.Close()
+
+		peers := h.copyOfGossipPeers()
+		peers[addedPublicKey.KeyForMap()] = config.NewHardCodedGossipPeer(uint16(addedListener.Addr().(*net.TCPAddr).Port), "127.0.0.1")
+		h.transport.UpdatePeers(peers)
+
+		addedConnection, err := addedListener.Accept()
+		require.NoError(t, err, "added test peer server could not accept connection from local transport")
+		defer addedConnection.Close()
+
+		err = h.transport.Send(ctx, &TransportData{
+			SenderPublicKey:     h.config.NodePublicKey(),
+			RecipientMode:       gossipmessages.RECIPIENT_LIST_MODE_LIST,
+			RecipientPublicKeys: []primitives.Ed25519PublicKey{addedPublicKey},
+			Payloads:            [][]byte{{0x11}, {0x22, 0x33}},
+		})
+		require.NoError(t, err, "adapter Send to an added peer should not fail")
+
+		data, err := readTotal(ctx, addedConnection, len(exampleWireProtocolEncoding_Payloads_0x11_0x2233()), 1*time.Second)
+		require.NoError(t, err, "added test peer server could not read from local transport")
+		require.Equal(t, exampleWireProtocolEncoding_Payloads_0x11_0x2233(), data)
+		require.Equal(t, PEER_CONNECTION_STATE_CONNECTED, h.transport.PeerConnectionStates()[addedPublicKey.KeyForMap()])
+	})
+}
+
+func TestDirectOutgoing_RemovedPeerIsDisconnectedAndNotSentTo(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+
+		h := newDirectHarnessWithConnectedPeers(t, ctx)
+		defer h.cleanupConnectedPeers()
+
+		peers := h.copyOfGossipPeers()
+		delete(peers, h.publicKeyForPeer(1).KeyForMap())
+		h.transport.UpdatePeers(peers)
+
+		buffer := []byte{0}
+		read, err := h.peersListenersConnections[1].Read(buffer)
+		require.Equal(t, 0, read, "local transport should disconnect from removed test peer without sending anything")
+		require.Error(t, err, "local transport should disconnect from removed test peer")
+
+		err = h.transport.Send(ctx, &TransportData{
+			SenderPublicKey:     h.config.NodePublicKey(),
+			RecipientMode:       gossipmessages.RECIPIENT_LIST_MODE_LIST,
+			RecipientPublicKeys: []primitives.Ed25519PublicKey{h.publicKeyForPeer(1)},
+			Payloads:            [][]byte{{0x11}, {0x22, 0x33}},
+		})
+		require.IsType(t, &ErrSendFailed{}, err, "adapter Send to a removed peer should fail")
+
+		_, found := h.transport.PeerConnectionStates()[h.publicKeyForPeer(1).KeyForMap()]
+		require.False(t, found, "removed peer should not be reported")
+		require.Len(t, h.transport.PeerConnectionStates(), NETWORK_SIZE-2)
+	})
+}
+
 func concatSlices(slices ...[]byte) []byte {
 	var tmp []byte
 	for _, s := range slices {
@@ -30,9 +30,9 @@ type directHarness struct {
 func newDirectHarnessWithConnectedPeers(t *testing.T, ctx context.Context) *directHarness {
 
 	// order matters here
-	gossipPeers, peersListeners := makePeers(t)        // step 1: create the peer server listeners to reserve random TCP ports
-	cfg := config.ForDirectTransportTests(gossipPeers) // step 2: create the config given the peer pk/port pairs
-	transport := makeTransport(ctx, cfg)               // step 3: create the transport; it will attempt to establish connections with the peer servers repeatedly until they start accepting connections
+	gossipPeers, peersListeners := makePeers(t)                // step 1: create the peer server listeners to reserve random TCP ports
+	cfg := config.ForDirectTransportTests(gossipPeers, "none") // step 2: create the config given the peer pk/port pairs
+	transport := makeTransport(ctx, cfg)                       // step 3: create the transport; it will attempt to establish connections with the peer servers repeatedly until they start accepting connections
 	// end of section where order matters
 
 	peerTalkerConnection := establishPeerClient(t, transport.serverPort)           // establish connection from test to server port ( test harness ==> SUT )
@@ -133,6 +133,19 @@ func (h *directHarness) portForPeer(index int) uint16 {
 	return h.config.GossipPeers(0)[peerPublicKey.KeyForMap()].GossipPort()
 }
 
+// the config returns its own map so tests changing the peers should change a copy
+func (h *directHarness) copyOfGossipPeers() map[string]config.GossipPeer {
+	peers := make(map[string]config.GossipPeer)
+	for peerKey, peer := range h.config.GossipPeers(0) {
+		peers[peerKey] = peer
+	}
+	return peers
+}
+
+func (h *directHarness) peerConnectionState(index int) PeerConnectionState {
+	return h.transport.PeerConnectionStates()[h.publicKeyForPeer(index).KeyForMap()]
+}
+
 func (h *directHarness) expectTransportListenerCalled(payloads [][]byte) {
 	h.listenerMock.When("OnTransportMessageReceived", mock.Any, payloads).Return().Times(1)
 }
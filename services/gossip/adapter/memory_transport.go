@@ -8,6 +8,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/synchronization/supervised"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"github.com/pkg/errors"
 	"sync"
 )
 
@@ -50,25 +51,56 @@ func (p *memoryTransport) RegisterListener(listener TransportListener, key primi
 }
 
 func (p *memoryTransport) Send(ctx context.Context, data *TransportData) error {
+	peerKeys, err := p.recipientPeerKeys(data)
+	if err != nil {
+		return err
+	}
+
+	peerErrors := make(map[string]error)
+	for _, peerKey := range peerKeys {
+		if peer, found := p.peers[peerKey]; !found {
+			peerErrors[peerKey] = errors.New("unknown recipient public key")
+		} else if err := peer.send(ctx, data); err != nil {
+			peerErrors[peerKey] = err
+		}
+	}
+
+	return sendFailedOrNil(peerErrors)
+}
+
+func (p *memoryTransport) recipientPeerKeys(data *TransportData) ([]string, error) {
 	switch data.RecipientMode {
 
 	case gossipmessages.RECIPIENT_LIST_MODE_BROADCAST:
-		for key, peer := range p.peers {
-			if key != data.SenderPublicKey.KeyForMap() {
-				peer.send(ctx, data)
-			}
-		}
+		return p.allPeerKeysExcept(data.SenderPublicKey), nil
 
 	case gossipmessages.RECIPIENT_LIST_MODE_LIST:
-		for _, k := range data.RecipientPublicKeys {
-			p.peers[k.KeyForMap()].send(ctx, data)
+		peerKeys := make([]string, len(data.RecipientPublicKeys))
+		for i, k := range data.RecipientPublicKeys {
+			peerKeys[i] = k.KeyForMap()
 		}
+		return peerKeys, nil
 
 	case gossipmessages.RECIPIENT_LIST_MODE_ALL_BUT_LIST:
-		panic("Not implemented")
+		return p.allPeerKeysExcept(append([]primitives.Ed25519PublicKey{data.SenderPublicKey}, data.RecipientPublicKeys...)...), nil
 	}
 
-	return nil
+	return nil, errors.Errorf("unknown recipient mode: %s", data.RecipientMode.String())
+}
+
+func (p *memoryTransport) allPeerKeysExcept(excludedPublicKeys ...primitives.Ed25519PublicKey) []string {
+	excluded := make(map[string]bool)
+	for _, k := range excludedPublicKeys {
+		excluded[k.KeyForMap()] = true
+	}
+
+	var peerKeys []string
+	for key := range p.peers {
+		if !excluded[key] {
+			peerKeys = append(peerKeys, key)
+		}
+	}
+	return peerKeys
 }
 
 func newPeer(bgCtx context.Context, logger log.BasicLogger) *peer {
@@ -91,12 +123,13 @@ func (p *peer) attach(listener TransportListener) {
 	p.listener <- listener
 }
 
-func (p *peer) send(ctx context.Context, data *TransportData) {
+func (p *peer) send(ctx context.Context, data *TransportData) error {
 	tracingContext, _ := trace.FromContext(ctx)
 	select {
 	case p.socket <- message{payloads: data.Payloads, traceContext: tracingContext}:
+		return nil
 	case <-ctx.Done():
-		return
+		return errors.Wrap(ctx.Err(), "peer did not receive the data")
 	}
 }
 
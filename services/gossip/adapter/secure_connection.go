@@ -0,0 +1,266 @@
+package adapter
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"github.com/orbs-network/membuffers/go"
+	"github.com/orbs-network/orbs-network-go/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/crypto/signature"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"net"
+	"time"
+)
+
+type TransportSecurity int
+
+const (
+	TRANSPORT_SECURITY_NONE TransportSecurity = iota
+	TRANSPORT_SECURITY_AUTHENTICATED_ENCRYPTION
+)
+
+const (
+	TRANSPORT_SECURITY_NAME_NONE                     = "none"
+	TRANSPORT_SECURITY_NAME_AUTHENTICATED_ENCRYPTION = "authenticated-encryption"
+)
+
+func parseTransportSecurity(name string) (TransportSecurity, error) {
+	switch name {
+	case TRANSPORT_SECURITY_NAME_AUTHENTICATED_ENCRYPTION, "":
+		return TRANSPORT_SECURITY_AUTHENTICATED_ENCRYPTION, nil
+	case TRANSPORT_SECURITY_NAME_NONE:
+		return TRANSPORT_SECURITY_NONE, nil
+	default:
+		return TRANSPORT_SECURITY_AUTHENTICATED_ENCRYPTION, errors.Errorf("unknown gossip transport security %s", name)
+	}
+}
+
+const (
+	EPHEMERAL_KEY_SIZE_BYTES = 32
+	SESSION_KEY_SIZE_BYTES   = 32
+	MAX_RECORD_SIZE_BYTES    = MAX_PAYLOAD_SIZE_BYTES + 1024
+)
+
+// the handshake is initiated by the side that dialed and has three messages:
+// (1) client -> server: client node public key, client ephemeral x25519 key
+// (2) server -> client: server node public key, server ephemeral x25519 key, server signature over the transcript
+// (3) client -> server: client signature over the transcript
+// each side proves ownership of its node key by signing both ephemeral keys, the session keys are derived from
+// their diffie-hellman secret so a recorded session can not be decrypted even if node keys leak later
+var (
+	handshakeServerLabel  = []byte("orbs gossip handshake server")
+	handshakeClientLabel  = []byte("orbs gossip handshake client")
+	sessionKeyDerivedInfo = []byte("orbs gossip session keys")
+)
+
+// every Write is sealed as a single record: 4 byte little endian length followed by the aes-gcm sealed data,
+// nonces are per-direction counters so records can not be replayed, reordered or dropped without failing Read
+type secureConnection struct {
+	net.Conn
+	sendCipher    cipher.AEAD
+	receiveCipher cipher.AEAD
+	sendNonce     uint64
+	receiveNonce  uint64
+	unread        []byte
+}
+
+func (c *secureConnection) Write(buffer []byte) (int, error) {
+	sealed := c.sendCipher.Seal(nil, nonceFor(c.sendNonce, c.sendCipher.NonceSize()), buffer, nil)
+	c.sendNonce++
+
+	record := make([]byte, 4+len(sealed))
+	membuffers.WriteUint32(record, uint32(len(sealed)))
+	copy(record[4:], sealed)
+	if _, err := c.Conn.Write(record); err != nil {
+		return 0, err
+	}
+	return len(buffer), nil
+}
+
+func (c *secureConnection) Read(buffer []byte) (int, error) {
+	if len(c.unread) == 0 {
+		sizeBuffer := make([]byte, 4)
+		if _, err := io.ReadFull(c.Conn, sizeBuffer); err != nil {
+			return 0, err
+		}
+		recordSize := membuffers.GetUint32(sizeBuffer)
+		if recordSize > MAX_RECORD_SIZE_BYTES {
+			return 0, errors.Errorf("received encrypted record too big: %d bytes", recordSize)
+		}
+
+		sealed := make([]byte, recordSize)
+		if _, err := io.ReadFull(c.Conn, sealed); err != nil {
+			return 0, err
+		}
+		opened, err := c.receiveCipher.Open(sealed[:0], nonceFor(c.receiveNonce, c.receiveCipher.NonceSize()), sealed, nil)
+		if err != nil {
+			return 0, errors.Wrap(err, "received encrypted record failed authentication")
+		}
+		c.receiveNonce++
+		c.unread = opened
+	}
+
+	read := copy(buffer, c.unread)
+	c.unread = c.unread[read:]
+	return read, nil
+}
+
+func nonceFor(counter uint64, size int) []byte {
+	nonce := make([]byte, size)
+	binary.BigEndian.PutUint64(nonce[size-8:], counter)
+	return nonce
+}
+
+type handshakeIdentity struct {
+	publicKey  primitives.Ed25519PublicKey
+	privateKey primitives.Ed25519PrivateKey
+}
+
+// performed by the side that dialed, fails unless the other side proves it owns the expected peer key
+func secureOutgoingConnection(ctx context.Context, conn net.Conn, identity *handshakeIdentity, expectedPeerPublicKey primitives.Ed25519PublicKey, timeout time.Duration) (net.Conn, error) {
+	ephemeralPrivateKey, ephemeralPublicKey, err := generateEphemeralKey()
+	if err != nil {
+		return nil, err
+	}
+
+	clientHello := concatBytes(identity.publicKey, ephemeralPublicKey)
+	if err := write(ctx, conn, clientHello, timeout); err != nil {
+		return nil, err
+	}
+
+	serverHello, err := readTotal(ctx, conn, keys.ED25519_PUBLIC_KEY_SIZE_BYTES+EPHEMERAL_KEY_SIZE_BYTES+signature.ED25519_SIGNATURE_SIZE_BYTES, timeout)
+	if err != nil {
+		return nil, err
+	}
+	serverPublicKey := primitives.Ed25519PublicKey(serverHello[:keys.ED25519_PUBLIC_KEY_SIZE_BYTES])
+	serverEphemeralPublicKey := serverHello[keys.ED25519_PUBLIC_KEY_SIZE_BYTES : keys.ED25519_PUBLIC_KEY_SIZE_BYTES+EPHEMERAL_KEY_SIZE_BYTES]
+	serverSignature := primitives.Ed25519Sig(serverHello[keys.ED25519_PUBLIC_KEY_SIZE_BYTES+EPHEMERAL_KEY_SIZE_BYTES:])
+	if !serverPublicKey.Equal(expectedPeerPublicKey) {
+		return nil, errors.Errorf("gossip peer identified as %s instead of %s", serverPublicKey, expectedPeerPublicKey)
+	}
+
+	transcript := concatBytes(clientHello, serverPublicKey, serverEphemeralPublicKey)
+	if !signature.VerifyEd25519(serverPublicKey, concatBytes(handshakeServerLabel, transcript), serverSignature) {
+		return nil, errors.Errorf("gossip peer %s failed to prove ownership of its key", serverPublicKey)
+	}
+
+	clientSignature, err := signature.SignEd25519(identity.privateKey, concatBytes(handshakeClientLabel, transcript))
+	if err != nil {
+		return nil, err
+	}
+	if err := write(ctx, conn, clientSignature, timeout); err != nil {
+		return nil, err
+	}
+
+	clientToServer, serverToClient, err := deriveSessionKeys(ephemeralPrivateKey, serverEphemeralPublicKey, transcript)
+	if err != nil {
+		return nil, err
+	}
+	return newSecureConnection(conn, clientToServer, serverToClient)
+}
+
+// performed by the side that accepted, fails unless the other side proves it owns a key found in isKnownPeer
+func secureIncomingConnection(ctx context.Context, conn net.Conn, identity *handshakeIdentity, isKnownPeer func(primitives.Ed25519PublicKey) bool, timeout time.Duration) (net.Conn, primitives.Ed25519PublicKey, error) {
+	clientHello, err := readTotal(ctx, conn, keys.ED25519_PUBLIC_KEY_SIZE_BYTES+EPHEMERAL_KEY_SIZE_BYTES, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientPublicKey := primitives.Ed25519PublicKey(clientHello[:keys.ED25519_PUBLIC_KEY_SIZE_BYTES])
+	clientEphemeralPublicKey := clientHello[keys.ED25519_PUBLIC_KEY_SIZE_BYTES:]
+	if !isKnownPeer(clientPublicKey) {
+		return nil, clientPublicKey, errors.Errorf("gossip peer %s is not a known peer", clientPublicKey)
+	}
+
+	ephemeralPrivateKey, ephemeralPublicKey, err := generateEphemeralKey()
+	if err != nil {
+		return nil, clientPublicKey, err
+	}
+
+	transcript := concatBytes(clientHello, identity.publicKey, ephemeralPublicKey)
+	serverSignature, err := signature.SignEd25519(identity.privateKey, concatBytes(handshakeServerLabel, transcript))
+	if err != nil {
+		return nil, clientPublicKey, err
+	}
+	if err := write(ctx, conn, concatBytes(identity.publicKey, ephemeralPublicKey, serverSignature), timeout); err != nil {
+		return nil, clientPublicKey, err
+	}
+
+	clientSignature, err := readTotal(ctx, conn, signature.ED25519_SIGNATURE_SIZE_BYTES, timeout)
+	if err != nil {
+		return nil, clientPublicKey, err
+	}
+	if !signature.VerifyEd25519(clientPublicKey, concatBytes(handshakeClientLabel, transcript), clientSignature) {
+		return nil, clientPublicKey, errors.Errorf("gossip peer %s failed to prove ownership of its key", clientPublicKey)
+	}
+
+	clientToServer, serverToClient, err := deriveSessionKeys(ephemeralPrivateKey, clientEphemeralPublicKey, transcript)
+	if err != nil {
+		return nil, clientPublicKey, err
+	}
+	secured, err := newSecureConnection(conn, serverToClient, clientToServer)
+	if err != nil {
+		return nil, clientPublicKey, err
+	}
+	return secured, clientPublicKey, nil
+}
+
+func newSecureConnection(conn net.Conn, sendKey []byte, receiveKey []byte) (net.Conn, error) {
+	sendCipher, err := newAesGcm(sendKey)
+	if err != nil {
+		return nil, err
+	}
+	receiveCipher, err := newAesGcm(receiveKey)
+	if err != nil {
+		return nil, err
+	}
+	return &secureConnection{Conn: conn, sendCipher: sendCipher, receiveCipher: receiveCipher}, nil
+}
+
+func newAesGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func generateEphemeralKey() (privateKey *[EPHEMERAL_KEY_SIZE_BYTES]byte, publicKey []byte, err error) {
+	privateKey = new([EPHEMERAL_KEY_SIZE_BYTES]byte)
+	if _, err := io.ReadFull(rand.Reader, privateKey[:]); err != nil {
+		return nil, nil, errors.Wrap(err, "cannot create ephemeral key from random bytes")
+	}
+	var pub [EPHEMERAL_KEY_SIZE_BYTES]byte
+	curve25519.ScalarBaseMult(&pub, privateKey)
+	return privateKey, pub[:], nil
+}
+
+func deriveSessionKeys(ephemeralPrivateKey *[EPHEMERAL_KEY_SIZE_BYTES]byte, peerEphemeralPublicKey []byte, transcript []byte) (clientToServer []byte, serverToClient []byte, err error) {
+	var peerPublicKey, sharedSecret [EPHEMERAL_KEY_SIZE_BYTES]byte
+	copy(peerPublicKey[:], peerEphemeralPublicKey)
+	curve25519.ScalarMult(&sharedSecret, ephemeralPrivateKey, &peerPublicKey)
+	if sharedSecret == [EPHEMERAL_KEY_SIZE_BYTES]byte{} {
+		return nil, nil, errors.New("gossip peer sent a low order ephemeral key")
+	}
+
+	transcriptHash := sha256.Sum256(transcript)
+	sessionKeys := make([]byte, 2*SESSION_KEY_SIZE_BYTES)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, sharedSecret[:], transcriptHash[:], sessionKeyDerivedInfo), sessionKeys); err != nil {
+		return nil, nil, err
+	}
+	return sessionKeys[:SESSION_KEY_SIZE_BYTES], sessionKeys[SESSION_KEY_SIZE_BYTES:], nil
+}
+
+func concatBytes(slices ...[]byte) []byte {
+	var res []byte
+	for _, s := range slices {
+		res = append(res, s...)
+	}
+	return res
+}
@@ -0,0 +1,186 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/stretchr/testify/require"
+	"net"
+	"testing"
+	"time"
+)
+
+const handshakeTimeout = 1 * time.Second
+
+func identityForTests(setIndex int) *handshakeIdentity {
+	keyPair := keys.Ed25519KeyPairForTests(setIndex)
+	return &handshakeIdentity{publicKey: keyPair.PublicKey(), privateKey: keyPair.PrivateKey()}
+}
+
+func onlyPeer(peerPublicKey primitives.Ed25519PublicKey) func(primitives.Ed25519PublicKey) bool {
+	return func(publicKey primitives.Ed25519PublicKey) bool {
+		return publicKey.Equal(peerPublicKey)
+	}
+}
+
+type handshakeResult struct {
+	conn net.Conn
+	err  error
+}
+
+// runs both sides of the handshake over an in-memory pipe
+func handshake(ctx context.Context, client *handshakeIdentity, expectedServerPublicKey primitives.Ed25519PublicKey, server *handshakeIdentity, isKnownPeer func(primitives.Ed25519PublicKey) bool) (clientResult handshakeResult, serverResult handshakeResult) {
+	clientConn, serverConn := net.Pipe()
+
+	serverDone := make(chan handshakeResult)
+	go func() {
+		conn, _, err := secureIncomingConnection(ctx, serverConn, server, isKnownPeer, handshakeTimeout)
+		if err != nil {
+			serverConn.Close()
+		}
+		serverDone <- handshakeResult{conn, err}
+	}()
+
+	conn, err := secureOutgoingConnection(ctx, clientConn, client, expectedServerPublicKey, handshakeTimeout)
+	if err != nil {
+		clientConn.Close()
+	}
+	return handshakeResult{conn, err}, <-serverDone
+}
+
+func TestParseTransportSecurity(t *testing.T) {
+	security, err := parseTransportSecurity("none")
+	require.NoError(t, err)
+	require.Equal(t, TRANSPORT_SECURITY_NONE, security)
+
+	security, err = parseTransportSecurity("")
+	require.NoError(t, err)
+	require.Equal(t, TRANSPORT_SECURITY_AUTHENTICATED_ENCRYPTION, security, "encryption should be the default")
+
+	security, err = parseTransportSecurity("nonee")
+	require.Error(t, err)
+	require.Equal(t, TRANSPORT_SECURITY_AUTHENTICATED_ENCRYPTION, security, "unknown settings should keep encryption on")
+}
+
+func TestSecureConnection_HandshakeAndExchangeData(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		client, server := identityForTests(1), identityForTests(2)
+
+		clientResult, serverResult := handshake(ctx, client, server.publicKey, server, onlyPeer(client.publicKey))
+		require.NoError(t, clientResult.err, "client handshake should succeed")
+		require.NoError(t, serverResult.err, "server handshake should succeed")
+		defer clientResult.conn.Close()
+
+		go write(ctx, clientResult.conn, []byte{0x11, 0x22, 0x33}, handshakeTimeout)
+		received, err := readTotal(ctx, serverResult.conn, 3, handshakeTimeout)
+		require.NoError(t, err, "server should read what the client wrote")
+		require.Equal(t, []byte{0x11, 0x22, 0x33}, received)
+
+		go write(ctx, serverResult.conn, []byte{0x44}, handshakeTimeout)
+		received, err = readTotal(ctx, clientResult.conn, 1, handshakeTimeout)
+		require.NoError(t, err, "client should read what the server wrote")
+		require.Equal(t, []byte{0x44}, received)
+	})
+}
+
+func TestSecureConnection_ServerRejectsUnknownPeer(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		client, server := identityForTests(1), identityForTests(2)
+
+		clientResult, serverResult := handshake(ctx, client, server.publicKey, server, onlyPeer(identityForTests(3).publicKey))
+		require.Error(t, serverResult.err, "server should reject a client which is not a known peer")
+		require.Error(t, clientResult.err, "client should fail when the server disconnects")
+	})
+}
+
+func TestSecureConnection_ClientRejectsUnexpectedServer(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		client, server := identityForTests(1), identityForTests(2)
+
+		clientResult, serverResult := handshake(ctx, client, identityForTests(3).publicKey, server, onlyPeer(client.publicKey))
+		require.Error(t, clientResult.err, "client should reject a server with an unexpected key")
+		require.Error(t, serverResult.err, "server should fail when the client disconnects")
+	})
+}
+
+func TestSecureConnection_ServerRejectsClientImpersonatingPeer(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		impersonator := &handshakeIdentity{publicKey: identityForTests(1).publicKey, privateKey: identityForTests(3).privateKey}
+		server := identityForTests(2)
+
+		_, serverResult := handshake(ctx, impersonator, server.publicKey, server, onlyPeer(impersonator.publicKey))
+		require.Error(t, serverResult.err, "server should reject a client which does not own the key it presents")
+	})
+}
+
+func TestSecureConnection_TamperedRecordFailsAuthentication(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		sendKey, receiveKey := make([]byte, SESSION_KEY_SIZE_BYTES), make([]byte, SESSION_KEY_SIZE_BYTES)
+		receiveKey[0] = 0x01
+
+		senderRaw, tampererIn := net.Pipe()
+		tampererOut, receiverRaw := net.Pipe()
+		defer senderRaw.Close()
+		defer receiverRaw.Close()
+		sender, err := newSecureConnection(senderRaw, sendKey, receiveKey)
+		require.NoError(t, err)
+		receiver, err := newSecureConnection(receiverRaw, receiveKey, sendKey)
+		require.NoError(t, err)
+
+		go write(ctx, sender, []byte{0x11}, handshakeTimeout)
+		go func() {
+			record, err := readTotal(ctx, tampererIn, 4+1+16, handshakeTimeout) // size, data and gcm tag
+			if err != nil {
+				return
+			}
+			record[4] ^= 0xff
+			write(ctx, tampererOut, record, handshakeTimeout)
+		}()
+
+		_, err = readTotal(ctx, receiver, 1, handshakeTimeout)
+		require.Error(t, err, "tampered record should not be accepted")
+	})
+}
+
+func TestEncryptedDirectTransport_AcceptsOnlyAuthenticatedKnownPeers(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		gossipPeers, peersListeners := makePeers(t)
+		defer func() {
+			for _, listener := range peersListeners {
+				listener.Close()
+			}
+		}()
+		cfg := config.ForDirectTransportTests(gossipPeers, "authenticated-encryption")
+		transport := NewDirectTransport(ctx, cfg, log.GetLogger()).(*directTransport)
+		require.True(t, test.Eventually(test.EVENTUALLY_ADAPTER_TIMEOUT, transport.isServerListening), "server should listen")
+
+		listenerMock := &MockTransportListener{}
+		transport.RegisterListener(listenerMock, nil)
+		listenerMock.ExpectReceive([][]byte{{0x11}, {0x22, 0x33}})
+
+		unknownConn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", transport.serverPort))
+		require.NoError(t, err)
+		defer unknownConn.Close()
+		_, err = secureOutgoingConnection(ctx, unknownConn, identityForTests(9), cfg.NodePublicKey(), handshakeTimeout)
+		require.Error(t, err, "transport should reject a peer missing from the gossip peers")
+
+		plaintextConn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", transport.serverPort))
+		require.NoError(t, err)
+		defer plaintextConn.Close()
+		_, err = plaintextConn.Write(exampleWireProtocolEncoding_Payloads_0x11_0x2233())
+		require.NoError(t, err)
+
+		peerConn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", transport.serverPort))
+		require.NoError(t, err)
+		defer peerConn.Close()
+		secured, err := secureOutgoingConnection(ctx, peerConn, identityForTests(1), cfg.NodePublicKey(), handshakeTimeout)
+		require.NoError(t, err, "transport should accept a known peer")
+		require.NoError(t, write(ctx, secured, exampleWireProtocolEncoding_Payloads_0x11_0x2233(), handshakeTimeout))
+
+		require.NoError(t, test.EventuallyVerify(test.EVENTUALLY_ADAPTER_TIMEOUT, listenerMock), "only the authenticated peer should be heard")
+	})
+}
@@ -2,9 +2,13 @@ package adapter
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"sort"
+	"strings"
 )
 
 type TransportData struct {
@@ -19,6 +23,30 @@ type Transport interface {
 	Send(ctx context.Context, data *TransportData) error
 }
 
+// a transport whose peers can change while it runs, eg. when the federation changes at some block height
+type DynamicPeersTransport interface {
+	Transport
+	UpdatePeers(peers map[string]config.GossipPeer)
+	PeerConnectionStates() map[string]PeerConnectionState // by primitives.Ed25519PublicKey.KeyForMap() of the peer
+}
+
+type PeerConnectionState int
+
+const (
+	PEER_CONNECTION_STATE_CONNECTING PeerConnectionState = iota
+	PEER_CONNECTION_STATE_CONNECTED
+)
+
+func (s PeerConnectionState) String() string {
+	switch s {
+	case PEER_CONNECTION_STATE_CONNECTING:
+		return "CONNECTING"
+	case PEER_CONNECTION_STATE_CONNECTED:
+		return "CONNECTED"
+	}
+	return fmt.Sprintf("PeerConnectionState(%d)", int(s))
+}
+
 type TransportListener interface {
 	OnTransportMessageReceived(ctx context.Context, payloads [][]byte)
 }
@@ -37,3 +65,29 @@ type ErrTransportFailed struct {
 func (e *ErrTransportFailed) Error() string {
 	return fmt.Sprintf("transport failed to send: %v", e.Data)
 }
+
+// returned by Send when some recipients did not receive the data, all other recipients did
+type ErrSendFailed struct {
+	PeerErrors map[string]error // by primitives.Ed25519PublicKey.KeyForMap() of the recipient
+}
+
+func (e *ErrSendFailed) Error() string {
+	peerKeys := make([]string, 0, len(e.PeerErrors))
+	for peerKey := range e.PeerErrors {
+		peerKeys = append(peerKeys, peerKey)
+	}
+	sort.Strings(peerKeys)
+
+	failures := make([]string, len(peerKeys))
+	for i, peerKey := range peerKeys {
+		failures[i] = fmt.Sprintf("%s: %s", hex.EncodeToString([]byte(peerKey)), e.PeerErrors[peerKey])
+	}
+	return fmt.Sprintf("transport failed to send to %d peers: %s", len(peerKeys), strings.Join(failures, ", "))
+}
+
+func sendFailedOrNil(peerErrors map[string]error) error {
+	if len(peerErrors) == 0 {
+		return nil
+	}
+	return &ErrSendFailed{PeerErrors: peerErrors}
+}
@@ -17,15 +17,26 @@ import (
 
 func TestContract_SendBroadcast(t *testing.T) {
 	t.Run("DirectTransport", broadcastTest(aDirectTransport))
+	t.Run("EncryptedDirectTransport", broadcastTest(anEncryptedDirectTransport))
 	t.Run("ChannelTransport", broadcastTest(aChannelTransport))
 }
 
 func TestContract_SendToList(t *testing.T) {
-	t.Skipf("TODO implement")
+	t.Run("DirectTransport", sendToListTest(aDirectTransport))
+	t.Run("EncryptedDirectTransport", sendToListTest(anEncryptedDirectTransport))
+	t.Run("ChannelTransport", sendToListTest(aChannelTransport))
 }
 
 func TestContract_SendToAllButList(t *testing.T) {
-	t.Skipf("TODO implement")
+	t.Run("DirectTransport", sendToAllButListTest(aDirectTransport))
+	t.Run("EncryptedDirectTransport", sendToAllButListTest(anEncryptedDirectTransport))
+	t.Run("ChannelTransport", sendToAllButListTest(aChannelTransport))
+}
+
+func TestContract_SendToUnknownRecipientReturnsPerPeerError(t *testing.T) {
+	t.Run("DirectTransport", sendToUnknownRecipientTest(aDirectTransport))
+	t.Run("EncryptedDirectTransport", sendToUnknownRecipientTest(anEncryptedDirectTransport))
+	t.Run("ChannelTransport", sendToUnknownRecipientTest(aChannelTransport))
 }
 
 func broadcastTest(makeContext func(ctx context.Context) *transportContractContext) func(*testing.T) {
@@ -44,7 +55,81 @@ func broadcastTest(makeContext func(ctx context.Context) *transportContractConte
 			c.listeners[2].ExpectReceive(data.Payloads)
 			c.listeners[3].ExpectNotReceive()
 
-			c.transports[3].Send(ctx, data)
+			require.NoError(t, c.transports[3].Send(ctx, data))
+			c.verify(t)
+		})
+	}
+}
+
+func sendToListTest(makeContext func(ctx context.Context) *transportContractContext) func(*testing.T) {
+	return func(t *testing.T) {
+		test.WithContext(func(ctx context.Context) {
+			c := makeContext(ctx)
+
+			data := &TransportData{
+				SenderPublicKey:     c.publicKeys[3],
+				RecipientMode:       gossipmessages.RECIPIENT_LIST_MODE_LIST,
+				RecipientPublicKeys: []primitives.Ed25519PublicKey{c.publicKeys[1], c.publicKeys[2]},
+				Payloads:            [][]byte{{0x81, 0x82, 0x83}},
+			}
+
+			c.listeners[0].ExpectNotReceive()
+			c.listeners[1].ExpectReceive(data.Payloads)
+			c.listeners[2].ExpectReceive(data.Payloads)
+			c.listeners[3].ExpectNotReceive()
+
+			require.NoError(t, c.transports[3].Send(ctx, data))
+			c.verify(t)
+		})
+	}
+}
+
+func sendToAllButListTest(makeContext func(ctx context.Context) *transportContractContext) func(*testing.T) {
+	return func(t *testing.T) {
+		test.WithContext(func(ctx context.Context) {
+			c := makeContext(ctx)
+
+			data := &TransportData{
+				SenderPublicKey:     c.publicKeys[3],
+				RecipientMode:       gossipmessages.RECIPIENT_LIST_MODE_ALL_BUT_LIST,
+				RecipientPublicKeys: []primitives.Ed25519PublicKey{c.publicKeys[1]},
+				Payloads:            [][]byte{{0x91, 0x92, 0x93}},
+			}
+
+			c.listeners[0].ExpectReceive(data.Payloads)
+			c.listeners[1].ExpectNotReceive()
+			c.listeners[2].ExpectReceive(data.Payloads)
+			c.listeners[3].ExpectNotReceive()
+
+			require.NoError(t, c.transports[3].Send(ctx, data))
+			c.verify(t)
+		})
+	}
+}
+
+func sendToUnknownRecipientTest(makeContext func(ctx context.Context) *transportContractContext) func(*testing.T) {
+	return func(t *testing.T) {
+		test.WithContext(func(ctx context.Context) {
+			c := makeContext(ctx)
+
+			unknownPublicKey := primitives.Ed25519PublicKey{0x66, 0x66}
+			data := &TransportData{
+				SenderPublicKey:     c.publicKeys[3],
+				RecipientMode:       gossipmessages.RECIPIENT_LIST_MODE_LIST,
+				RecipientPublicKeys: []primitives.Ed25519PublicKey{unknownPublicKey, c.publicKeys[0]},
+				Payloads:            [][]byte{{0xa1, 0xa2, 0xa3}},
+			}
+
+			c.listeners[0].ExpectReceive(data.Payloads)
+			c.listeners[1].ExpectNotReceive()
+			c.listeners[2].ExpectNotReceive()
+			c.listeners[3].ExpectNotReceive()
+
+			err := c.transports[3].Send(ctx, data)
+			require.IsType(t, &ErrSendFailed{}, err, "sending to an unknown recipient did not fail")
+			peerErrors := err.(*ErrSendFailed).PeerErrors
+			require.Len(t, peerErrors, 1, "only the unknown recipient should fail")
+			require.Contains(t, peerErrors, unknownPublicKey.KeyForMap())
 			c.verify(t)
 		})
 	}
@@ -80,6 +165,14 @@ func aChannelTransport(ctx context.Context) *transportContractContext {
 }
 
 func aDirectTransport(ctx context.Context) *transportContractContext {
+	return aDirectTransportWithSecurity(ctx, "none")
+}
+
+func anEncryptedDirectTransport(ctx context.Context) *transportContractContext {
+	return aDirectTransportWithSecurity(ctx, "authenticated-encryption")
+}
+
+func aDirectTransportWithSecurity(ctx context.Context, transportSecurity string) *transportContractContext {
 	res := &transportContractContext{}
 
 	// randomize listen port between tests to reduce flakiness and chances of listening clashes
@@ -94,10 +187,10 @@ func aDirectTransport(ctx context.Context) *transportContractContext {
 	}
 
 	configs := []config.GossipTransportConfig{
-		config.ForGossipAdapterTests(res.publicKeys[0], uint16(firstRandomPort+0), gossipPeers),
-		config.ForGossipAdapterTests(res.publicKeys[1], uint16(firstRandomPort+1), gossipPeers),
-		config.ForGossipAdapterTests(res.publicKeys[2], uint16(firstRandomPort+2), gossipPeers),
-		config.ForGossipAdapterTests(res.publicKeys[3], uint16(firstRandomPort+3), gossipPeers),
+		config.ForGossipAdapterTests(keys.Ed25519KeyPairForTests(0), uint16(firstRandomPort+0), gossipPeers, transportSecurity),
+		config.ForGossipAdapterTests(keys.Ed25519KeyPairForTests(1), uint16(firstRandomPort+1), gossipPeers, transportSecurity),
+		config.ForGossipAdapterTests(keys.Ed25519KeyPairForTests(2), uint16(firstRandomPort+2), gossipPeers, transportSecurity),
+		config.ForGossipAdapterTests(keys.Ed25519KeyPairForTests(3), uint16(firstRandomPort+3), gossipPeers, transportSecurity),
 	}
 
 	logger := log.GetLogger().WithOutput(log.NewFormattingOutput(os.Stdout, log.NewHumanReadableFormatter()))
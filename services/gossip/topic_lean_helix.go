@@ -3,7 +3,10 @@ package gossip
 import (
 	"context"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
 	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
 	"github.com/pkg/errors"
@@ -14,16 +17,24 @@ func (s *service) RegisterLeanHelixHandler(handler gossiptopics.LeanHelixHandler
 }
 
 func (s *service) receivedLeanHelixMessage(ctx context.Context, header *gossipmessages.Header, payloads [][]byte) {
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
 
-	if len(payloads) < 2 {
+	if len(payloads) < 1 {
 		return
 	}
 
 	messageType := header.LeanHelix()
-	content := payloads[1]
-	blockPair, err := decodeBlockPair(payloads[2:])
-	if err != nil {
-		return
+	content := payloads[0]
+
+	// only some of the message types carry a block pair
+	var blockPair *protocol.BlockPairContainer
+	if len(payloads) > 1 {
+		var err error
+		blockPair, err = decodeBlockPair(payloads[1:])
+		if err != nil {
+			logger.Info("HandleLeanHelixMessage failed to decode block pair", log.Error(err))
+			return
+		}
 	}
 
 	for _, l := range s.leanHelixHandlers {
@@ -35,30 +46,43 @@ func (s *service) receivedLeanHelixMessage(ctx context.Context, header *gossipme
 			},
 		})
 		if err != nil {
-			s.logger.Info("HandleLeanHelixMessage failed", log.Error(err))
+			logger.Info("HandleLeanHelixMessage failed", log.Error(err))
 		}
 	}
 }
 
 func (s *service) SendLeanHelixMessage(ctx context.Context, input *gossiptopics.LeanHelixInput) (*gossiptopics.EmptyOutput, error) {
+	recipientMode := gossipmessages.RECIPIENT_LIST_MODE_BROADCAST
+	var recipientPublicKeys []primitives.Ed25519PublicKey
+	if input.RecipientsList != nil {
+		recipientMode = input.RecipientsList.RecipientMode
+		recipientPublicKeys = input.RecipientsList.RecipientPublicKeys
+	}
+
 	header := (&gossipmessages.HeaderBuilder{
-		Topic:         gossipmessages.HEADER_TOPIC_LEAN_HELIX,
-		LeanHelix:     input.Message.MessageType,
-		RecipientMode: gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
+		Topic:               gossipmessages.HEADER_TOPIC_LEAN_HELIX,
+		LeanHelix:           input.Message.MessageType,
+		RecipientMode:       recipientMode,
+		RecipientPublicKeys: recipientPublicKeys,
 	}).Build()
 
-	blockPairPayloads, err := encodeBlockPair(input.Message.BlockPair)
-	if err != nil {
-		return nil, err
-	}
 	if input.Message.Content == nil {
 		return nil, errors.Errorf("cannot encode LeanHelixMessage: %s", input.Message.String())
 	}
-	payloads := append([][]byte{header.Raw(), input.Message.Content}, blockPairPayloads...)
+	payloads := [][]byte{header.Raw(), input.Message.Content}
+
+	if input.Message.BlockPair != nil {
+		blockPairPayloads, err := encodeBlockPair(input.Message.BlockPair)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, blockPairPayloads...)
+	}
 
 	return nil, s.transport.Send(ctx, &adapter.TransportData{
-		SenderPublicKey: s.config.NodePublicKey(),
-		RecipientMode:   gossipmessages.RECIPIENT_LIST_MODE_BROADCAST, // TODO: shouldn't be broadcast
-		Payloads:        payloads,
+		SenderPublicKey:     s.config.NodePublicKey(),
+		RecipientMode:       recipientMode,
+		RecipientPublicKeys: recipientPublicKeys,
+		Payloads:            payloads,
 	})
 }
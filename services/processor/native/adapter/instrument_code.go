@@ -0,0 +1,86 @@
+package adapter
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+const METERING_IMPORT_PATH = "github.com/orbs-network/orbs-network-go/services/processor/native/metering"
+
+// the sandbox audit reserves identifiers starting with __orbs, so the injected import cannot be shadowed or used by the contract
+const METERING_PACKAGE_NAME = "__orbs_metering"
+
+// injects metering calls into deployed source code that passed the sandbox audit: every function body and loop body
+// starts with an instruction count and every make is charged its capacity, or its length when it has none, before it
+// allocates (a length over the capacity panics without allocating)
+func instrumentSourceCode(code string) (string, error) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "", code, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncDecl:
+			prependInstruction(node.Body)
+		case *ast.FuncLit:
+			prependInstruction(node.Body)
+		case *ast.ForStmt:
+			prependInstruction(node.Body)
+		case *ast.RangeStmt:
+			prependInstruction(node.Body)
+		case *ast.CallExpr:
+			if isBuiltinMake(node) && len(node.Args) > 1 {
+				last := len(node.Args) - 1
+				node.Args[last] = meteredAllocation(node.Args[0], node.Args[last])
+			}
+		}
+		return true
+	})
+
+	file.Decls = append([]ast.Decl{&ast.GenDecl{
+		Tok: token.IMPORT,
+		Specs: []ast.Spec{&ast.ImportSpec{
+			Name: ast.NewIdent(METERING_PACKAGE_NAME),
+			Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(METERING_IMPORT_PATH)},
+		}},
+	}}, file.Decls...)
+
+	var instrumented bytes.Buffer
+	if err := format.Node(&instrumented, fileSet, file); err != nil {
+		return "", err
+	}
+	return instrumented.String(), nil
+}
+
+func prependInstruction(body *ast.BlockStmt) {
+	if body == nil {
+		return
+	}
+	instruction := &ast.ExprStmt{X: &ast.CallExpr{Fun: meteringFunc("Instruction")}}
+	body.List = append([]ast.Stmt{instruction}, body.List...)
+}
+
+// the made type is passed as its empty literal so the allocation is charged in bytes, the sandbox audit forbids channels
+// which are the only made type without one
+func meteredAllocation(madeType ast.Expr, size ast.Expr) ast.Expr {
+	return &ast.CallExpr{
+		Fun:  meteringFunc("Allocation"),
+		Args: []ast.Expr{&ast.CompositeLit{Type: madeType}, size},
+	}
+}
+
+func meteringFunc(name string) ast.Expr {
+	return &ast.SelectorExpr{X: ast.NewIdent(METERING_PACKAGE_NAME), Sel: ast.NewIdent(name)}
+}
+
+// make is only the builtin when nothing in the file declares it
+func isBuiltinMake(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == "make" && ident.Obj == nil
+}
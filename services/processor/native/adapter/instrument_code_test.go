@@ -0,0 +1,74 @@
+package adapter
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestInstrumentSourceCode_MetersFunctionsLoopsAndAllocations(t *testing.T) {
+	instrumented, err := instrumentSourceCode(`package main
+
+import "strings"
+
+func repeat(s string, times uint32) string {
+	parts := make([]string, 0, times)
+	for i := uint32(0); i < times; i++ {
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, "")
+}
+
+func sum(values map[string]int) (res int) {
+	each := func(value int) {
+		res += value
+	}
+	for _, value := range values {
+		each(value)
+	}
+	return
+}
+`)
+	require.NoError(t, err)
+	require.Equal(t, `package main
+
+import __orbs_metering "github.com/orbs-network/orbs-network-go/services/processor/native/metering"
+
+import "strings"
+
+func repeat(s string, times uint32) string {
+	__orbs_metering.Instruction()
+	parts := make([]string, 0, __orbs_metering.Allocation([]string{}, times))
+	for i := uint32(0); i < times; i++ {
+		__orbs_metering.Instruction()
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, "")
+}
+
+func sum(values map[string]int) (res int) {
+	__orbs_metering.Instruction()
+	each := func(value int) {
+		__orbs_metering.Instruction()
+		res += value
+	}
+	for _, value := range values {
+		__orbs_metering.Instruction()
+		each(value)
+	}
+	return
+}
+`, instrumented)
+}
+
+func TestInstrumentSourceCode_DoesNotMeterMakeThatIsNotTheBuiltin(t *testing.T) {
+	instrumented, err := instrumentSourceCode(`package main
+
+func make(length int) []byte {
+	return nil
+}
+
+var _ = make(10)
+`)
+	require.NoError(t, err)
+	require.NotContains(t, instrumented, "Allocation", "a function declared as make should be called as is")
+}
@@ -59,7 +59,12 @@ func (c *nativeCompiler) Compile(ctx context.Context, code string) (*sdk.Contrac
 	artifactsPath := c.config.ProcessorArtifactPath()
 	hashOfCode := getHashOfCode(code)
 
-	sourceCodeFilePath, err := writeSourceCodeToDisk(hashOfCode, code, artifactsPath)
+	instrumentedCode, err := instrumentSourceCode(code)
+	if err != nil {
+		return nil, errors.Wrap(err, "error instrumenting go source")
+	}
+
+	sourceCodeFilePath, err := writeSourceCodeToDisk(hashOfCode, instrumentedCode, artifactsPath)
 	defer os.Remove(sourceCodeFilePath)
 	if err != nil {
 		return nil, err
@@ -30,6 +30,17 @@ func initializeContractInstance(contractInfo *sdk.ContractInfo, sdkHandler handl
 	))
 }
 
+// the constructor of a deployed contract is metered like the rest of its code, so it panics once the execution is over budget
+func initializeDeployedContractInstance(contractInfo *sdk.ContractInfo, sdkHandler handlers.ContractSdkCallHandler) (contractInstance sdk.ContractInstance, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("contract panic: %s", r)
+		}
+	}()
+
+	return initializeContractInstance(contractInfo, sdkHandler), nil
+}
+
 func (s *service) retrieveContractAndMethodInfoFromRepository(ctx context.Context, executionContextId sdk.Context, contractName string, methodName string) (*sdk.ContractInfo, *sdk.MethodInfo, error) {
 	contract, err := s.retrieveContractInfoFromRepository(ctx, executionContextId, contractName)
 	if err != nil {
@@ -88,7 +99,10 @@ func (s *service) retrieveDeployableContractInfoFromState(ctx context.Context, e
 	if sdkHandler == nil {
 		return nil, errors.New("ContractSdkCallHandler has not registered yet")
 	}
-	contractInstance := initializeContractInstance(newContractInfo, sdkHandler)
+	contractInstance, err := initializeDeployedContractInstance(newContractInfo, sdkHandler)
+	if err != nil {
+		return nil, errors.Wrapf(err, "initialization of deployable contract '%s' failed", contractName)
+	}
 
 	s.addContractInstanceToRepository(contractName, contractInstance)
 	s.addDeployableContractInfoToRepository(contractName, newContractInfo) // must add after instance to avoid race (when somebody RunsMethod at same time)
@@ -0,0 +1,93 @@
+// Package metering is linked into deployed contracts by the native compiler, which calls Instruction at the entry of
+// every function and of every loop iteration and passes the size of every make through Allocation. The contract code
+// is charged to the execution running it, so its cost is counted the same way on every node even if it never calls the
+// sdk. It must not import anything outside the standard library since deployed contracts are built against it.
+package metering
+
+import (
+	"math"
+	"reflect"
+	"sync"
+)
+
+// instructions are charged in chunks since charging one is a call into the virtual machine
+const INSTRUCTIONS_PER_CHARGE = 1000
+
+// a meter stops the contract by panicking once its execution is over budget
+type Meter interface {
+	ChargeInstructions(count uint32)
+	ChargeAllocation(bytes int64)
+}
+
+// deployed contract code runs one execution at a time, so the instructions counted are always those of the execution
+// holding the lock; contracts cannot start goroutines, so only the goroutine holding it runs metered code
+var (
+	running      sync.Mutex
+	current      Meter
+	instructions uint32
+)
+
+// runs f, which may run deployed contract code, charging that code to meter; code nested in f is already charged to it
+// and must not call Run again
+func Run(meter Meter, f func()) {
+	running.Lock()
+	defer running.Unlock()
+
+	current, instructions = meter, 0
+	defer func() {
+		current = nil
+	}()
+
+	f()
+}
+
+func Instruction() {
+	instructions++
+	if instructions == INSTRUCTIONS_PER_CHARGE {
+		instructions = 0
+		current.ChargeInstructions(INSTRUCTIONS_PER_CHARGE)
+	}
+}
+
+// charges make(T, size) before it allocates, zero is T{} so the element size is known and size is the last argument
+// of make which may be any integer type
+func Allocation(zero interface{}, size interface{}) int {
+	length := toLength(reflect.ValueOf(size))
+
+	var elementSize uintptr
+	switch t := reflect.TypeOf(zero); t.Kind() {
+	case reflect.Slice:
+		elementSize = t.Elem().Size()
+	case reflect.Map:
+		elementSize = t.Key().Size() + t.Elem().Size()
+	}
+
+	bytes := int64(math.MaxInt64)
+	if elementSize == 0 || length <= math.MaxInt64/int64(elementSize) {
+		bytes = length * int64(elementSize)
+	}
+	if length >= 0 {
+		current.ChargeAllocation(bytes)
+	}
+	return int(length)
+}
+
+// a negative length is returned as is for make to panic on, a length that int cannot hold panics here
+func toLength(size reflect.Value) int64 {
+	var length int64
+	switch size.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		length = size.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if size.Uint() > math.MaxInt64 {
+			panic("makeslice: len out of range")
+		}
+		length = int64(size.Uint())
+	case reflect.Float32, reflect.Float64:
+		length = int64(size.Float())
+	}
+	if int64(int(length)) != length {
+		panic("makeslice: len out of range")
+	}
+	return length
+}
@@ -105,7 +105,7 @@ func (c *contract) deployService(ctx sdk.Context, serviceName string, processorT
 
 	_, err = c.Service.CallMethod(ctx, serviceName, "_init")
 	if err != nil {
-		return errors.New("failed to initialize contract")
+		return fmt.Errorf("failed to initialize contract: %s", err.Error())
 	}
 
 	return nil
@@ -1,6 +1,294 @@
 package native
 
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+const SDK_IMPORT_PATH = "github.com/orbs-network/orbs-contract-sdk/go/sdk"
+
+// the native compiler injects metering code under identifiers with this prefix
+const RESERVED_IDENTIFIER_PREFIX = "__orbs"
+
+// deployed contracts run on every validator, so besides the sdk they may only import packages without access to the
+// outside world and without global state
+var allowedImportsInDeployedSourceCode = map[string]bool{
+	SDK_IMPORT_PATH:   true,
+	"bytes":           true,
+	"encoding/base64": true,
+	"encoding/binary": true,
+	"encoding/hex":    true,
+	"errors":          true,
+	"fmt":             true,
+	"math":            true,
+	"math/big":        true,
+	"math/bits":       true,
+	"sort":            true,
+	"strconv":         true,
+	"strings":         true,
+	"unicode":         true,
+	"unicode/utf16":   true,
+	"unicode/utf8":    true,
+}
+
+// the only package-level variables a contract needs are its sdk declarations
+var allowedPackageLevelVariableTypes = map[string]bool{
+	"ContractInfo": true,
+	"MethodInfo":   true,
+}
+
+type SandboxViolation struct {
+	Position token.Position
+	Message  string
+}
+
+func (v *SandboxViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Position, v.Message)
+}
+
+type ErrSandboxViolations struct {
+	Violations []*SandboxViolation
+}
+
+func (e *ErrSandboxViolations) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+	return strings.Join(messages, "; ")
+}
+
+type sourceCodeAuditor struct {
+	fileSet      *token.FileSet
+	packageScope *ast.Scope
+	sdkName      string
+	violations   []*SandboxViolation
+}
+
+// audits the source code against the sandbox rules, the error lists every violation positioned as line:column
 func sanitizeDeployedSourceCode(code string) (string, error) {
-	// TODO: finish implementing
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "", code, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	a := &sourceCodeAuditor{fileSet: fileSet, packageScope: file.Scope}
+	a.auditImports(file)
+	a.auditDirectives(file)
+	a.auditDeclarations(file)
+	a.auditStatements(file)
+
+	if len(a.violations) > 0 {
+		return "", &ErrSandboxViolations{a.violations}
+	}
 	return code, nil
 }
+
+func (a *sourceCodeAuditor) violation(pos token.Pos, format string, args ...interface{}) {
+	a.violations = append(a.violations, &SandboxViolation{
+		Position: a.fileSet.Position(pos),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (a *sourceCodeAuditor) auditImports(file *ast.File) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			a.violation(spec.Pos(), "import path %s is invalid", spec.Path.Value)
+			continue
+		}
+
+		switch {
+		case path == "C":
+			a.violation(spec.Pos(), "cgo is forbidden")
+		case path == "unsafe":
+			a.violation(spec.Pos(), "import of unsafe is forbidden")
+		case !allowedImportsInDeployedSourceCode[path]:
+			a.violation(spec.Pos(), "import of %s is not allowed", path)
+		case path == SDK_IMPORT_PATH:
+			a.sdkName = "sdk"
+			if spec.Name != nil {
+				a.sdkName = spec.Name.Name
+			}
+		}
+	}
+}
+
+// directives such as go:linkname reach into packages that cannot be imported
+func (a *sourceCodeAuditor) auditDirectives(file *ast.File) {
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, "//go:") {
+				a.violation(comment.Pos(), "compiler directive %s is forbidden", strings.Fields(comment.Text)[0])
+			}
+		}
+	}
+}
+
+func (a *sourceCodeAuditor) auditDeclarations(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil && decl.Name.Name == "init" {
+				a.violation(decl.Pos(), "init functions are forbidden")
+			}
+		case *ast.GenDecl:
+			if decl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				if !a.isSdkDeclaration(valueSpec) {
+					for _, name := range valueSpec.Names {
+						a.violation(name.Pos(), "package-level variable %s is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared", name.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (a *sourceCodeAuditor) isSdkDeclaration(spec *ast.ValueSpec) bool {
+	if len(spec.Values) != len(spec.Names) {
+		return false
+	}
+	for _, value := range spec.Values {
+		literal, ok := value.(*ast.CompositeLit)
+		if !ok || !a.isSdkType(literal.Type) || (spec.Type != nil && !a.isSdkType(spec.Type)) {
+			return false
+		}
+		if !isConstantLiteral(literal) {
+			return false
+		}
+	}
+	return true
+}
+
+// sdk declarations are evaluated when the contract is loaded, so their elements may not run any code: calls and function
+// literals are rejected anywhere in the literal, including in nested literals
+func isConstantLiteral(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit, *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		return isSelectorOperand(e.X)
+	case *ast.KeyValueExpr:
+		return isConstantLiteral(e.Key) && isConstantLiteral(e.Value)
+	case *ast.CompositeLit:
+		for _, element := range e.Elts {
+			if !isConstantLiteral(element) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// a selector is a qualified identifier (sdk.ACCESS_SCOPE_READ_ONLY), a field (METHOD_GET.Name) or a method expression
+// ((*contract).get)
+func isSelectorOperand(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		return isSelectorOperand(e.X)
+	case *ast.ParenExpr:
+		star, ok := e.X.(*ast.StarExpr)
+		if !ok {
+			return false
+		}
+		_, ok = star.X.(*ast.Ident)
+		return ok
+	default:
+		return false
+	}
+}
+
+func (a *sourceCodeAuditor) isSdkType(expr ast.Expr) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && a.sdkName != "" && pkg.Name == a.sdkName && allowedPackageLevelVariableTypes[selector.Sel.Name]
+}
+
+func (a *sourceCodeAuditor) auditStatements(file *ast.File) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.GoStmt:
+			a.violation(node.Pos(), "goroutines are forbidden")
+		case *ast.Ident:
+			if strings.HasPrefix(node.Name, RESERVED_IDENTIFIER_PREFIX) {
+				a.violation(node.Pos(), "identifier %s is reserved", node.Name)
+			}
+		case *ast.ChanType:
+			// without goroutines a channel can only block forever, and the metering cannot size what it allocates
+			a.violation(node.Pos(), "channels are forbidden")
+		case *ast.SelectStmt:
+			a.violation(node.Pos(), "select statements are forbidden")
+		case *ast.BranchStmt:
+			// a loop made of goto skips the metering the compiler injects into loop bodies
+			if node.Tok == token.GOTO {
+				a.violation(node.Pos(), "goto is forbidden")
+			}
+		case *ast.CallExpr:
+			// the metering stops a contract over its budget by panicking, which the contract may not recover from
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "recover" && ident.Obj == nil {
+				a.violation(node.Pos(), "recover is forbidden")
+			}
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE {
+				a.auditNotPackageLevel("modified", node.Lhs...)
+			}
+		case *ast.IncDecStmt:
+			a.auditNotPackageLevel("modified", node.X)
+		case *ast.RangeStmt:
+			if node.Tok == token.ASSIGN {
+				a.auditNotPackageLevel("modified", node.Key, node.Value)
+			}
+		case *ast.UnaryExpr:
+			if node.Op == token.AND {
+				a.auditNotPackageLevel("referenced by pointer", node.X)
+			}
+		}
+		return true
+	})
+}
+
+// package-level declarations are shared by all calls, so they may not be modified or referenced by pointer
+func (a *sourceCodeAuditor) auditNotPackageLevel(use string, exprs ...ast.Expr) {
+	for _, expr := range exprs {
+		ident := rootIdent(expr)
+		if ident != nil && ident.Obj != nil && ident.Obj.Kind == ast.Var && a.packageScope.Lookup(ident.Name) == ident.Obj {
+			a.violation(expr.Pos(), "package-level variable %s may not be %s", ident.Name, use)
+		}
+	}
+}
+
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package native
+
+import (
+	"github.com/orbs-network/orbs-network-go/test/contracts"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const SANDBOX_TEST_CONTRACT_HEADER = `package main
+
+import (
+	"errors"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk"
+)
+
+var CONTRACT = sdk.ContractInfo{Name: "Sandboxed", InitSingleton: newContract}
+
+var METHOD_GET = sdk.MethodInfo{Name: "get", Implementation: (*contract).get}
+
+func newContract(base *sdk.BaseContract) sdk.ContractInstance {
+	return &contract{base}
+}
+
+type contract struct{ *sdk.BaseContract }
+
+`
+
+func requireSandboxViolations(t *testing.T, code string, expected ...string) {
+	_, err := sanitizeDeployedSourceCode(code)
+	require.Error(t, err, "source code should fail the sandbox audit")
+	violations, ok := err.(*ErrSandboxViolations)
+	require.True(t, ok, "expected sandbox violations, got %s", err)
+
+	var messages []string
+	for _, violation := range violations.Violations {
+		messages = append(messages, violation.String())
+	}
+	require.Equal(t, expected, messages)
+}
+
+func TestSanitizeDeployedSourceCode_AcceptsContracts(t *testing.T) {
+	for _, code := range []string{
+		string(contracts.NativeSourceCodeForCounter(contracts.MOCK_COUNTER_CONTRACT_START_FROM)),
+		string(contracts.SourceCodeForNop()),
+		SANDBOX_TEST_CONTRACT_HEADER + `
+func (c *contract) get(ctx sdk.Context) (string, error) {
+	values := map[string]uint64{}
+	values["a"]++
+	if len(values) == 0 {
+		return "", errors.New("empty")
+	}
+	return METHOD_GET.Name, nil
+}
+`,
+	} {
+		sanitized, err := sanitizeDeployedSourceCode(code)
+		require.NoError(t, err, "source code should pass the sandbox audit")
+		require.Equal(t, code, sanitized, "source code should not be modified")
+	}
+}
+
+func TestSanitizeDeployedSourceCode_AllowsOnlyWhitelistedImports(t *testing.T) {
+	requireSandboxViolations(t, `package main
+
+import (
+	"os"
+	"strings"
+	"unsafe"
+	net "net/http"
+)
+
+// #include <stdio.h>
+import "C"
+`,
+		`4:2: import of os is not allowed`,
+		`6:2: import of unsafe is forbidden`,
+		`7:2: import of net/http is not allowed`,
+		`11:8: cgo is forbidden`,
+	)
+}
+
+func TestSanitizeDeployedSourceCode_ForbidsGoroutinesInitAndDirectives(t *testing.T) {
+	requireSandboxViolations(t, SANDBOX_TEST_CONTRACT_HEADER+`func init() {
+}
+
+//go:noinline
+func (c *contract) get(ctx sdk.Context) error {
+	go func() {}()
+	return nil
+}
+`,
+		`21:1: compiler directive //go:noinline is forbidden`,
+		`18:1: init functions are forbidden`,
+		`23:2: goroutines are forbidden`,
+	)
+}
+
+func TestSanitizeDeployedSourceCode_ForbidsPackageLevelMutableState(t *testing.T) {
+	requireSandboxViolations(t, SANDBOX_TEST_CONTRACT_HEADER+`var counter uint64
+
+var names, values = []string{}, sdk.MethodInfo{}
+
+func (c *contract) get(ctx sdk.Context) error {
+	CONTRACT.Name = "Renamed"
+	METHOD_GET.Implementation = nil
+	pointer := &CONTRACT
+	pointer.Name = "Renamed"
+	return nil
+}
+`,
+		`18:5: package-level variable counter is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared`,
+		`20:5: package-level variable names is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared`,
+		`20:12: package-level variable values is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared`,
+		`23:2: package-level variable CONTRACT may not be modified`,
+		`24:2: package-level variable METHOD_GET may not be modified`,
+		`25:14: package-level variable CONTRACT may not be referenced by pointer`,
+	)
+}
+
+func TestSanitizeDeployedSourceCode_ForbidsCallsInSdkDeclarations(t *testing.T) {
+	requireSandboxViolations(t, SANDBOX_TEST_CONTRACT_HEADER+`var METHOD_CALL = sdk.MethodInfo{Name: newName(), Implementation: (*contract).get}
+
+var METHOD_NESTED_CALL = sdk.ContractInfo{Name: "Nested", Methods: map[string]sdk.MethodInfo{"get": {Name: string([]byte{65})}}}
+
+var METHOD_METHOD_CALL = sdk.MethodInfo{Name: "get", Implementation: newContract(nil).get}
+
+func newName() string {
+	return "name"
+}
+`,
+		`18:5: package-level variable METHOD_CALL is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared`,
+		`20:5: package-level variable METHOD_NESTED_CALL is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared`,
+		`22:5: package-level variable METHOD_METHOD_CALL is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared`,
+	)
+}
+
+func TestSanitizeDeployedSourceCode_ForbidsFunctionLiteralsInSdkDeclarations(t *testing.T) {
+	requireSandboxViolations(t, SANDBOX_TEST_CONTRACT_HEADER+`var METHOD_FUNC = sdk.MethodInfo{Name: "func", Implementation: func(c *contract) {}}
+
+var METHOD_NESTED_FUNC = sdk.ContractInfo{Name: "Nested", Methods: map[string]sdk.MethodInfo{"get": {Name: "get", Implementation: func() {}}}}
+`,
+		`18:5: package-level variable METHOD_FUNC is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared`,
+		`20:5: package-level variable METHOD_NESTED_FUNC is forbidden, only sdk.ContractInfo and sdk.MethodInfo literals may be declared`,
+	)
+}
+
+func TestSanitizeDeployedSourceCode_ForbidsEscapingTheMetering(t *testing.T) {
+	requireSandboxViolations(t, SANDBOX_TEST_CONTRACT_HEADER+`func (c *contract) get(ctx sdk.Context) error {
+	defer func() {
+		recover()
+	}()
+	__orbs_metering := 0
+loop:
+	__orbs_metering++
+	goto loop
+}
+`,
+		`20:3: recover is forbidden`,
+		`22:2: identifier __orbs_metering is reserved`,
+		`24:2: identifier __orbs_metering is reserved`,
+		`25:2: goto is forbidden`,
+	)
+}
+
+func TestSanitizeDeployedSourceCode_ForbidsChannelsAndSelect(t *testing.T) {
+	requireSandboxViolations(t, SANDBOX_TEST_CONTRACT_HEADER+`type signal chan struct{}
+
+func (c *contract) get(ctx sdk.Context) error {
+	done := make(chan bool, 1)
+	done <- true
+	select {}
+}
+`,
+		`18:13: channels are forbidden`,
+		`21:15: channels are forbidden`,
+		`23:2: select statements are forbidden`,
+	)
+}
+
+func TestSanitizeDeployedSourceCode_ReportsSyntaxErrors(t *testing.T) {
+	_, err := sanitizeDeployedSourceCode("package main\n\nfunc {")
+	require.Error(t, err, "source code that does not parse should fail the sandbox audit")
+	require.Contains(t, err.Error(), "3:6", "syntax error should be positioned")
+}
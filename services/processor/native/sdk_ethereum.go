@@ -0,0 +1,57 @@
+package native
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/pkg/errors"
+)
+
+// not passed to sdk.NewBaseContract yet since the contract sdk has no ethereum handler slot
+type ethereumSdk struct {
+	handler         handlers.ContractSdkCallHandler
+	permissionScope protocol.ExecutionPermissionScope
+}
+
+const SDK_OPERATION_NAME_ETHEREUM = "Sdk.Ethereum"
+
+func (s *ethereumSdk) CallMethod(executionContextId sdk.Context, contractAddress string, jsonAbi string, methodName string, args ...interface{}) ([]interface{}, error) {
+	output, err := s.handler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+		ContextId:     primitives.ExecutionContextId(executionContextId),
+		OperationName: SDK_OPERATION_NAME_ETHEREUM,
+		MethodName:    "callMethod",
+		InputArguments: []*protocol.MethodArgument{
+			(&protocol.MethodArgumentBuilder{
+				Name:        "contractAddress",
+				Type:        protocol.METHOD_ARGUMENT_TYPE_STRING_VALUE,
+				StringValue: contractAddress,
+			}).Build(),
+			(&protocol.MethodArgumentBuilder{
+				Name:        "jsonAbi",
+				Type:        protocol.METHOD_ARGUMENT_TYPE_STRING_VALUE,
+				StringValue: jsonAbi,
+			}).Build(),
+			(&protocol.MethodArgumentBuilder{
+				Name:        "methodName",
+				Type:        protocol.METHOD_ARGUMENT_TYPE_STRING_VALUE,
+				StringValue: methodName,
+			}).Build(),
+			(&protocol.MethodArgumentBuilder{
+				Name:       "inputArgs",
+				Type:       protocol.METHOD_ARGUMENT_TYPE_BYTES_VALUE,
+				BytesValue: argsToMethodArgumentArray(args...).Raw(),
+			}).Build(),
+		},
+		PermissionScope: s.permissionScope,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.OutputArguments) != 1 || !output.OutputArguments[0].IsTypeBytesValue() {
+		return nil, errors.Errorf("callMethod Sdk.Ethereum returned corrupt output value")
+	}
+	methodArgumentArray := protocol.MethodArgumentArrayReader(output.OutputArguments[0].BytesValue())
+	return methodArgumentArrayToArgs(methodArgumentArray), nil
+}
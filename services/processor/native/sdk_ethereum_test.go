@@ -0,0 +1,47 @@
+package native
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const EXAMPLE_ETHEREUM_CONTRACT_ADDRESS = "0x00112233445566778899aabbccddeeff00112233"
+
+func TestEthereumCallMethod(t *testing.T) {
+	s := createEthereumSdk()
+
+	res, err := s.CallMethod(EXAMPLE_CONTEXT, EXAMPLE_ETHEREUM_CONTRACT_ADDRESS, "[]", "someMethod", uint64(17), "hello")
+	require.NoError(t, err, "callMethod should succeed")
+	require.Equal(t, []interface{}{uint64(17), "hello"}, res, "callMethod result should match expected")
+}
+
+func createEthereumSdk() *ethereumSdk {
+	return &ethereumSdk{
+		handler:         &contractSdkEthereumCallHandlerStub{},
+		permissionScope: protocol.PERMISSION_SCOPE_SERVICE,
+	}
+}
+
+type contractSdkEthereumCallHandlerStub struct {
+}
+
+func (c *contractSdkEthereumCallHandlerStub) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCallInput) (*handlers.HandleSdkCallOutput, error) {
+	if input.PermissionScope != protocol.PERMISSION_SCOPE_SERVICE {
+		panic("permissions passed to SDK are incorrect")
+	}
+	if input.OperationName != SDK_OPERATION_NAME_ETHEREUM || input.InputArguments[0].StringValue() != EXAMPLE_ETHEREUM_CONTRACT_ADDRESS {
+		return nil, errors.New("unexpected operation or contract address")
+	}
+	switch input.MethodName {
+	case "callMethod":
+		return &handlers.HandleSdkCallOutput{
+			OutputArguments: []*protocol.MethodArgument{input.InputArguments[3]},
+		}, nil
+	default:
+		return nil, errors.New("unknown method")
+	}
+}
@@ -0,0 +1,39 @@
+package native
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+)
+
+// not passed to sdk.NewBaseContract yet since the contract sdk has no events handler slot
+type eventsSdk struct {
+	handler         handlers.ContractSdkCallHandler
+	permissionScope protocol.ExecutionPermissionScope
+}
+
+const SDK_OPERATION_NAME_EVENTS = "Sdk.Events"
+
+func (s *eventsSdk) EmitEvent(executionContextId sdk.Context, eventName string, args ...interface{}) error {
+	_, err := s.handler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+		ContextId:     primitives.ExecutionContextId(executionContextId),
+		OperationName: SDK_OPERATION_NAME_EVENTS,
+		MethodName:    "emitEvent",
+		InputArguments: []*protocol.MethodArgument{
+			(&protocol.MethodArgumentBuilder{
+				Name:        "eventName",
+				Type:        protocol.METHOD_ARGUMENT_TYPE_STRING_VALUE,
+				StringValue: eventName,
+			}).Build(),
+			(&protocol.MethodArgumentBuilder{
+				Name:       "inputArgs",
+				Type:       protocol.METHOD_ARGUMENT_TYPE_BYTES_VALUE,
+				BytesValue: argsToMethodArgumentArray(args...).Raw(),
+			}).Build(),
+		},
+		PermissionScope: s.permissionScope,
+	})
+	return err
+}
@@ -0,0 +1,60 @@
+package native
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEventsEmitEvent(t *testing.T) {
+	handler := &contractSdkEventsCallHandlerStub{}
+	s := &eventsSdk{
+		handler:         handler,
+		permissionScope: protocol.PERMISSION_SCOPE_SERVICE,
+	}
+
+	err := s.EmitEvent(EXAMPLE_CONTEXT, "Transfer", uint64(17), "hello")
+	require.NoError(t, err, "emitEvent should succeed")
+	require.Equal(t, "Transfer", handler.eventName, "emitted event name should match")
+	require.Equal(t, []interface{}{uint64(17), "hello"}, handler.eventArgs, "emitted event arguments should match")
+}
+
+func TestEventsEmitEvent_Fails(t *testing.T) {
+	s := &eventsSdk{
+		handler:         &contractSdkEventsCallHandlerStub{},
+		permissionScope: protocol.PERMISSION_SCOPE_SERVICE,
+	}
+
+	err := s.EmitEvent(EXAMPLE_CONTEXT, "")
+	require.Error(t, err, "emitEvent without a name should fail")
+}
+
+type contractSdkEventsCallHandlerStub struct {
+	eventName string
+	eventArgs []interface{}
+}
+
+func (c *contractSdkEventsCallHandlerStub) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCallInput) (*handlers.HandleSdkCallOutput, error) {
+	if input.PermissionScope != protocol.PERMISSION_SCOPE_SERVICE {
+		panic("permissions passed to SDK are incorrect")
+	}
+	if input.OperationName != SDK_OPERATION_NAME_EVENTS {
+		return nil, errors.New("unexpected operation")
+	}
+	switch input.MethodName {
+	case "emitEvent":
+		if input.InputArguments[0].StringValue() == "" {
+			return nil, errors.New("missing event name")
+		}
+		c.eventName = input.InputArguments[0].StringValue()
+		c.eventArgs = methodArgumentArrayToArgs(protocol.MethodArgumentArrayReader(input.InputArguments[1].BytesValue()))
+		return &handlers.HandleSdkCallOutput{
+			OutputArguments: []*protocol.MethodArgument{},
+		}, nil
+	default:
+		return nil, errors.New("unknown method")
+	}
+}
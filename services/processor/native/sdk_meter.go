@@ -0,0 +1,50 @@
+package native
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/metering"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+)
+
+// charges the instructions and allocations of deployed contract code to its execution context, the contract is stopped
+// by a panic once the virtual machine refuses the charge
+type meterSdk struct {
+	handler            handlers.ContractSdkCallHandler
+	executionContextId sdk.Context
+}
+
+const SDK_OPERATION_NAME_METER = "Sdk.Meter"
+
+var _ metering.Meter = (*meterSdk)(nil)
+
+func (s *meterSdk) ChargeInstructions(count uint32) {
+	s.charge("useInstructions", (&protocol.MethodArgumentBuilder{
+		Name:        "count",
+		Type:        protocol.METHOD_ARGUMENT_TYPE_UINT_32_VALUE,
+		Uint32Value: count,
+	}).Build())
+}
+
+func (s *meterSdk) ChargeAllocation(bytes int64) {
+	s.charge("allocate", (&protocol.MethodArgumentBuilder{
+		Name:        "bytes",
+		Type:        protocol.METHOD_ARGUMENT_TYPE_UINT_64_VALUE,
+		Uint64Value: uint64(bytes),
+	}).Build())
+}
+
+func (s *meterSdk) charge(methodName string, arg *protocol.MethodArgument) {
+	_, err := s.handler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+		ContextId:       primitives.ExecutionContextId(s.executionContextId),
+		OperationName:   SDK_OPERATION_NAME_METER,
+		MethodName:      methodName,
+		InputArguments:  []*protocol.MethodArgument{arg},
+		PermissionScope: protocol.PERMISSION_SCOPE_SERVICE,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+}
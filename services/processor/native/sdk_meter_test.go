@@ -0,0 +1,61 @@
+package native
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMeterChargeInstructionsAndAllocation(t *testing.T) {
+	handler := &contractSdkMeterCallHandlerStub{budget: 1000}
+	s := &meterSdk{
+		handler:            handler,
+		executionContextId: EXAMPLE_CONTEXT,
+	}
+
+	s.ChargeInstructions(600)
+	s.ChargeAllocation(400)
+	require.EqualValues(t, 0, handler.budget, "charges should use up the budget")
+}
+
+func TestMeterChargeInstructionsAndAllocation_PanicsOverBudget(t *testing.T) {
+	s := &meterSdk{
+		handler:            &contractSdkMeterCallHandlerStub{budget: 1000},
+		executionContextId: EXAMPLE_CONTEXT,
+	}
+
+	require.Panics(t, func() { s.ChargeInstructions(1001) }, "instructions over the budget should stop the contract")
+	require.Panics(t, func() { s.ChargeAllocation(1001) }, "allocation over the budget should stop the contract")
+}
+
+type contractSdkMeterCallHandlerStub struct {
+	budget uint64
+}
+
+func (c *contractSdkMeterCallHandlerStub) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCallInput) (*handlers.HandleSdkCallOutput, error) {
+	if input.PermissionScope != protocol.PERMISSION_SCOPE_SERVICE {
+		panic("permissions passed to SDK are incorrect")
+	}
+	if input.OperationName != SDK_OPERATION_NAME_METER || len(input.InputArguments) != 1 {
+		return nil, errors.New("unexpected operation")
+	}
+	var charge uint64
+	switch input.MethodName {
+	case "useInstructions":
+		charge = uint64(input.InputArguments[0].Uint32Value())
+	case "allocate":
+		charge = input.InputArguments[0].Uint64Value()
+	default:
+		return nil, errors.New("unknown method")
+	}
+	if charge > c.budget {
+		return nil, errors.New("budget used up")
+	}
+	c.budget -= charge
+	return &handlers.HandleSdkCallOutput{
+		OutputArguments: []*protocol.MethodArgument{},
+	}, nil
+}
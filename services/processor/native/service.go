@@ -7,6 +7,8 @@ import (
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/processor/native/adapter"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/metering"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
@@ -24,6 +26,7 @@ type service struct {
 	contractSdkHandlerUnderMutex  handlers.ContractSdkCallHandler
 	contractInstancesUnderMutex   map[string]sdk.ContractInstance
 	deployableContractsUnderMutex map[string]*sdk.ContractInfo
+	meteredContextsUnderMutex     map[sdk.Context]bool
 
 	metrics *metrics
 }
@@ -48,10 +51,11 @@ func NewNativeProcessor(
 	metricFactory metric.Factory,
 ) services.Processor {
 	return &service{
-		compiler: compiler,
-		logger:   logger.WithTags(LogTag),
-		mutex:    &sync.RWMutex{},
-		metrics:  getMetrics(metricFactory),
+		compiler:                  compiler,
+		logger:                    logger.WithTags(LogTag),
+		mutex:                     &sync.RWMutex{},
+		meteredContextsUnderMutex: make(map[sdk.Context]bool),
+		metrics:                   getMetrics(metricFactory),
 	}
 }
 
@@ -68,7 +72,14 @@ func (s *service) RegisterContractSdkCallHandler(handler handlers.ContractSdkCal
 	}
 }
 
-func (s *service) ProcessCall(ctx context.Context, input *services.ProcessCallInput) (*services.ProcessCallOutput, error) {
+func (s *service) ProcessCall(ctx context.Context, input *services.ProcessCallInput) (output *services.ProcessCallOutput, err error) {
+	s.runMetered(sdk.Context(input.ContextId), string(input.ContractName), func() {
+		output, err = s.processCall(ctx, input)
+	})
+	return
+}
+
+func (s *service) processCall(ctx context.Context, input *services.ProcessCallInput) (*services.ProcessCallOutput, error) {
 	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
 	// retrieve code
 	executionContextId := sdk.Context(input.ContextId)
@@ -124,7 +135,14 @@ func (s *service) ProcessCall(ctx context.Context, input *services.ProcessCallIn
 	}, contractErr
 }
 
-func (s *service) GetContractInfo(ctx context.Context, input *services.GetContractInfoInput) (*services.GetContractInfoOutput, error) {
+func (s *service) GetContractInfo(ctx context.Context, input *services.GetContractInfoInput) (output *services.GetContractInfoOutput, err error) {
+	s.runMetered(sdk.Context(input.ContextId), string(input.ContractName), func() {
+		output, err = s.getContractInfo(ctx, input)
+	})
+	return
+}
+
+func (s *service) getContractInfo(ctx context.Context, input *services.GetContractInfoInput) (*services.GetContractInfoOutput, error) {
 	// retrieve code
 	executionContextId := sdk.Context(input.ContextId)
 	contractInfo, err := s.retrieveContractInfoFromRepository(ctx, executionContextId, string(input.ContractName))
@@ -138,6 +156,36 @@ func (s *service) GetContractInfo(ctx context.Context, input *services.GetContra
 	}, nil
 }
 
+// deployed contract code is charged to the execution context that first runs it, code it reaches through service calls
+// runs in the same context and is already charged to it; contracts are only metered one execution at a time
+func (s *service) runMetered(executionContextId sdk.Context, contractName string, f func()) {
+	if _, preBuilt := repository.PreBuiltContracts[contractName]; preBuilt || !s.startMeteringContext(executionContextId) {
+		f()
+		return
+	}
+	defer s.stopMeteringContext(executionContextId)
+
+	metering.Run(&meterSdk{s.getContractSdkHandler(), executionContextId}, f)
+}
+
+func (s *service) startMeteringContext(executionContextId sdk.Context) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.meteredContextsUnderMutex[executionContextId] {
+		return false
+	}
+	s.meteredContextsUnderMutex[executionContextId] = true
+	return true
+}
+
+func (s *service) stopMeteringContext(executionContextId sdk.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.meteredContextsUnderMutex, executionContextId)
+}
+
 func (s *service) getContractSdkHandler() handlers.ContractSdkCallHandler {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -8,6 +8,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/test/contracts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 )
 
@@ -37,6 +38,23 @@ func TestGetContractInfo_WithUnknownContractFails(t *testing.T) {
 	})
 }
 
+func TestProcessCall_WithDeployableContractThatFailsSandboxAudit(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		input := processCallInput().WithDeployableCounterContract(contracts.MOCK_COUNTER_CONTRACT_START_FROM).Build()
+		code := strings.Replace(string(contracts.NativeSourceCodeForCounter(contracts.MOCK_COUNTER_CONTRACT_START_FROM)), `"github.com/orbs-network/orbs-contract-sdk/go/sdk"`, `"github.com/orbs-network/orbs-contract-sdk/go/sdk"
+	"os"`, 1)
+		codeOutput := builders.MethodArgumentsArray([]byte(code))
+		h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_CODE.Name, builders.MethodArgumentsArray(string(input.ContractName)), codeOutput, nil)
+
+		_, err := h.service.ProcessCall(ctx, input)
+		require.Error(t, err, "call should fail")
+		require.Contains(t, err.Error(), "import of os is not allowed", "deployer should be told why the audit failed")
+
+		h.verifySdkCallMade(t)
+	})
+}
+
 func TestProcessCall_WithDeployableContractThatCompiles(t *testing.T) {
 	test.WithContext(func(ctx context.Context) {
 		h := newHarness()
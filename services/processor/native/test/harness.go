@@ -20,6 +20,7 @@ import (
 
 type harness struct {
 	sdkCallHandler *handlers.MockContractSdkCallHandler
+	compiler       adapter.FakeCompiler
 	service        services.Processor
 }
 
@@ -38,6 +39,7 @@ func newHarness() *harness {
 
 	return &harness{
 		sdkCallHandler: sdkCallHandler,
+		compiler:       compiler,
 		service:        service,
 	}
 }
@@ -110,6 +112,19 @@ func (h *harness) expectSdkCallMadeWithAddressGetCaller(returnAddress []byte) {
 	h.sdkCallHandler.When("HandleSdkCall", mock.Any, mock.AnyIf("Contract equals Sdk.Address, method equals getCallerAddress and 1 arg match", addressGetCallerCallMatcher)).Return(returnOutput, nil).Times(1)
 }
 
+func (h *harness) expectSdkCallMadeWithMeterUseInstructions(expectedCount uint32, returnError error, times int) {
+	meterUseInstructionsCallMatcher := func(i interface{}) bool {
+		input, ok := i.(*handlers.HandleSdkCallInput)
+		return ok &&
+			input.OperationName == native.SDK_OPERATION_NAME_METER &&
+			input.MethodName == "useInstructions" &&
+			len(input.InputArguments) == 1 &&
+			input.InputArguments[0].Uint32Value() == expectedCount
+	}
+
+	h.sdkCallHandler.When("HandleSdkCall", mock.Any, mock.AnyIf("Contract equals Sdk.Meter, method equals useInstructions and 1 arg matches", meterUseInstructionsCallMatcher)).Return(&handlers.HandleSdkCallOutput{}, returnError).Times(times)
+}
+
 func (h *harness) verifySdkCallMade(t *testing.T) {
 	_, err := h.sdkCallHandler.Verify()
 	require.NoError(t, err, "sdkCallHandler should be called as expected")
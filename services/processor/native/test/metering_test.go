@@ -0,0 +1,100 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/metering"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// the deployed source code of the contract below, which stands for what the native compiler builds after metering it
+const SPINNER_SOURCE_CODE = `package main
+
+import "github.com/orbs-network/orbs-contract-sdk/go/sdk"
+
+var CONTRACT = sdk.ContractInfo{Name: "Spinner", Permission: sdk.PERMISSION_SCOPE_SERVICE, Methods: map[string]sdk.MethodInfo{METHOD_SPIN.Name: METHOD_SPIN}, InitSingleton: newContract}
+
+var METHOD_SPIN = sdk.MethodInfo{Name: "spin", External: true, Access: sdk.ACCESS_SCOPE_READ_ONLY, Implementation: (*contract).spin}
+
+func newContract(base *sdk.BaseContract) sdk.ContractInstance {
+	return &contract{base}
+}
+
+type contract struct{ *sdk.BaseContract }
+
+func (c *contract) spin(ctx sdk.Context, times uint32) (uint32, error) {
+	for i := uint32(0); i < times; i++ {
+	}
+	return times, nil
+}
+`
+
+var SPINNER_METHOD_SPIN = sdk.MethodInfo{Name: "spin", External: true, Access: sdk.ACCESS_SCOPE_READ_ONLY, Implementation: (*spinner).spin}
+
+var SPINNER_CONTRACT = sdk.ContractInfo{
+	Name:       "Spinner",
+	Permission: sdk.PERMISSION_SCOPE_SERVICE,
+	Methods: map[string]sdk.MethodInfo{
+		SPINNER_METHOD_SPIN.Name: SPINNER_METHOD_SPIN,
+	},
+	InitSingleton: func(base *sdk.BaseContract) sdk.ContractInstance {
+		metering.Instruction()
+		return &spinner{base}
+	},
+}
+
+type spinner struct{ *sdk.BaseContract }
+
+func (c *spinner) spin(ctx sdk.Context, times uint32) (uint32, error) {
+	metering.Instruction()
+	for i := uint32(0); i < times; i++ {
+		metering.Instruction()
+	}
+	return times, nil
+}
+
+func (h *harness) expectSpinnerToBeDeployed() {
+	h.compiler.ProvideFakeContract(&SPINNER_CONTRACT, SPINNER_SOURCE_CODE)
+	codeOutput := builders.MethodArgumentsArray([]byte(SPINNER_SOURCE_CODE))
+	h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_CODE.Name, builders.MethodArgumentsArray(SPINNER_CONTRACT.Name), codeOutput, nil)
+}
+
+func TestProcessCall_DeployedContractWithoutSdkCallsIsCharged(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectSpinnerToBeDeployed()
+		input := processCallInput().WithMethod("Spinner", "spin").WithArgs(uint32(2997)).Build()
+
+		// the constructor, the method and its 2997 iterations are 2999 instructions, charged in two full chunks
+		h.expectSdkCallMadeWithMeterUseInstructions(metering.INSTRUCTIONS_PER_CHARGE, nil, 2)
+
+		output, err := h.service.ProcessCall(ctx, input)
+		require.NoError(t, err, "call should succeed")
+		require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, output.CallResult, "call result should be success")
+
+		h.verifySdkCallMade(t)
+	})
+}
+
+func TestProcessCall_DeployedContractIsStoppedWhenChargeIsRefused(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectSpinnerToBeDeployed()
+		input := processCallInput().WithMethod("Spinner", "spin").WithArgs(uint32(1000000)).Build()
+
+		h.expectSdkCallMadeWithMeterUseInstructions(metering.INSTRUCTIONS_PER_CHARGE, errors.New("instruction budget used up"), 1)
+
+		output, err := h.service.ProcessCall(ctx, input)
+		require.Error(t, err, "call should fail")
+		require.Contains(t, err.Error(), "instruction budget used up", "call should fail on the refused charge")
+		require.Equal(t, protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, output.CallResult, "call result should be smart contract error")
+
+		h.verifySdkCallMade(t)
+	})
+}
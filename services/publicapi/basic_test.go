@@ -51,6 +51,7 @@ func TestPublicApi_TranslateExecutionStatusToHttpCode(t *testing.T) {
 		{"EXECUTION_RESULT_RESERVED", protocol.REQUEST_STATUS_RESERVED, protocol.EXECUTION_RESULT_RESERVED},
 		{"EXECUTION_RESULT_SUCCESS", protocol.REQUEST_STATUS_COMPLETED, protocol.EXECUTION_RESULT_SUCCESS},
 		{"EXECUTION_RESULT_ERROR_SMART_CONTRACT", protocol.REQUEST_STATUS_COMPLETED, protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT},
+		{"EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED", protocol.REQUEST_STATUS_COMPLETED, protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED},
 		{"EXECUTION_RESULT_ERROR_INPUT", protocol.REQUEST_STATUS_REJECTED, protocol.EXECUTION_RESULT_ERROR_INPUT},
 		{"EXECUTION_RESULT_ERROR_UNEXPECTED", protocol.REQUEST_STATUS_SYSTEM_ERROR, protocol.EXECUTION_RESULT_ERROR_UNEXPECTED},
 	}
@@ -19,21 +19,24 @@ func (s *service) GetTransactionStatus(ctx context.Context, input *services.GetT
 	}
 
 	s.logger.Info("get transaction status request received", log.String("flow", "checkpoint"), log.Transaction(input.ClientRequest.Txhash()))
-	txHash := input.ClientRequest.Txhash()
-	timestamp := input.ClientRequest.TransactionTimestamp()
 
 	start := time.Now()
 	defer s.metrics.getTransactionStatusTime.RecordSince(start)
 
-	if txReceipt, err, ok := s.getFromTxPool(ctx, txHash, timestamp); ok {
-		return toGetTxOutput(txReceipt), err
+	response, err := s.getTransactionStatus(ctx, input.ClientRequest.Txhash(), input.ClientRequest.TransactionTimestamp())
+	if response == nil {
+		return nil, err
 	}
+	return toGetTxOutput(response), err
+}
 
-	blockReceipt, err := s.getFromBlockStorage(ctx, txHash, timestamp)
-	if err != nil {
-		return nil, err
+// looks in the transaction pool first and only then in block storage, the response is nil when block storage failed
+func (s *service) getTransactionStatus(ctx context.Context, txHash primitives.Sha256, timestamp primitives.TimestampNano) (*txResponse, error) {
+	if txReceipt, err, ok := s.getFromTxPool(ctx, txHash, timestamp); ok {
+		return txReceipt, err
 	}
-	return toGetTxOutput(blockReceipt), err
+
+	return s.getFromBlockStorage(ctx, txHash, timestamp)
 }
 
 func (s *service) getFromTxPool(ctx context.Context, txHash primitives.Sha256, timestamp primitives.TimestampNano) (*txResponse, error, bool) {
@@ -94,6 +97,7 @@ func toGetTxOutput(transactionOutput *txResponse) *services.GetTransactionStatus
 			Txhash:              receipt.Txhash(),
 			ExecutionResult:     receipt.ExecutionResult(),
 			OutputArgumentArray: receipt.OutputArgumentArray(),
+			OutputEventsArray:   receipt.OutputEventsArray(),
 		}
 	}
 
@@ -6,6 +6,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
 	"github.com/orbs-network/orbs-spec/types/go/services"
@@ -36,34 +37,47 @@ func (s *service) SendTransaction(parentCtx context.Context, input *services.Sen
 	start := time.Now()
 	defer s.metrics.sendTransactionTime.RecordSince(start)
 
+	waitResult, addResp, err := s.addNewTransaction(ctx, logger, tx, txHash, input.ReturnImmediately == 0)
+	if err != nil {
+		return toSendTxOutput(toTxResponse(addResp)), err
+	}
+	if waitResult == nil {
+		return toSendTxOutput(toTxResponse(addResp)), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.SendTransactionTimeout())
+	defer cancel()
+
+	obj, err := s.waiter.wait(ctx, waitResult)
+	if err != nil {
+		logger.Info("waiting for transaction to be processed failed")
+		return toSendTxOutput(toTxResponse(addResp)), err
+	}
+	return toSendTxOutput(obj.(*txResponse)), nil
+}
+
+// admits tx through the transaction pool, the returned waiter channel is nil when there is no result to wait for
+func (s *service) addNewTransaction(ctx context.Context, logger log.BasicLogger, tx *protocol.SignedTransaction, txHash primitives.Sha256, wait bool) (*waiterChannel, *services.AddNewTransactionOutput, error) {
 	waitResult := s.waiter.add(txHash.KeyForMap())
 
 	addResp, err := s.transactionPool.AddNewTransaction(ctx, &services.AddNewTransactionInput{SignedTransaction: tx})
 	if err != nil {
 		s.waiter.deleteByChannel(waitResult)
 		logger.Info("adding transaction to TransactionPool failed", log.Error(err))
-		return toSendTxOutput(toTxResponse(addResp)), errors.Wrap(err, fmt.Sprintf("error '%s' for transaction result", addResp))
+		return nil, addResp, errors.Wrap(err, fmt.Sprintf("error '%s' for transaction result", addResp))
 	}
 
 	if addResp.TransactionStatus == protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED {
 		s.waiter.deleteByChannel(waitResult)
-		return toSendTxOutput(toTxResponse(addResp)), nil
+		return nil, addResp, nil
 	}
 
-	if input.ReturnImmediately != 0 {
+	if !wait {
 		s.waiter.deleteByChannel(waitResult)
-		return toSendTxOutput(toTxResponse(addResp)), nil
+		return nil, addResp, nil
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, s.config.SendTransactionTimeout())
-	defer cancel()
-
-	obj, err := s.waiter.wait(ctx, waitResult)
-	if err != nil {
-		logger.Info("waiting for transaction to be processed failed")
-		return toSendTxOutput(toTxResponse(addResp)), err
-	}
-	return toSendTxOutput(obj.(*txResponse)), nil
+	return waitResult, addResp, nil
 }
 
 func toTxResponse(t *services.AddNewTransactionOutput) *txResponse {
@@ -83,6 +97,7 @@ func toSendTxOutput(transactionOutput *txResponse) *services.SendTransactionOutp
 			Txhash:              receipt.Txhash(),
 			ExecutionResult:     receipt.ExecutionResult(),
 			OutputArgumentArray: receipt.OutputArgumentArray(),
+			OutputEventsArray:   receipt.OutputEventsArray(),
 		}
 	}
 
@@ -0,0 +1,93 @@
+package publicapi
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// admits many transactions in one call, for clients whose throughput is bound by a request per transaction
+type TransactionBatchSender interface {
+	SendTransactionBatch(ctx context.Context, input *SendTransactionBatchInput) (*SendTransactionBatchOutput, error)
+}
+
+type SendTransactionBatchInput struct {
+	ClientRequests []*client.SendTransactionRequest
+	WaitForCommit  bool
+	WaitTimeout    time.Duration // a deadline shared by the whole batch, capped by (and defaulting to) the send transaction timeout
+}
+
+type SendTransactionBatchOutput struct {
+	ClientResponses []*client.SendTransactionResponse // in the order of the requests, a transaction still pending at the deadline is reported pending
+}
+
+func (s *service) SendTransactionBatch(parentCtx context.Context, input *SendTransactionBatchInput) (*SendTransactionBatchOutput, error) {
+	if input == nil {
+		err := errors.Errorf("error missing input (batch is nil)")
+		s.logger.Info("send transaction batch received missing input", log.Error(err))
+		return nil, err
+	}
+
+	ctx := trace.NewContext(parentCtx, "PublicApi.SendTransactionBatch")
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx), log.String("flow", "checkpoint"))
+	logger.Info("send transaction batch request received", log.Int("transactions", len(input.ClientRequests)))
+
+	start := time.Now()
+	defer s.metrics.sendTransactionBatchTime.RecordSince(start)
+
+	waitTimeout := s.config.SendTransactionTimeout()
+	if input.WaitTimeout > 0 && input.WaitTimeout < waitTimeout {
+		waitTimeout = input.WaitTimeout
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+
+	responses := make([]*txResponse, len(input.ClientRequests))
+	waitResults := make([]*waiterChannel, len(input.ClientRequests))
+	for i, clientRequest := range input.ClientRequests {
+		tx := clientRequest.SignedTransaction()
+		txHash := digest.CalcTxHash(tx.Transaction())
+		txLogger := logger.WithTags(log.Transaction(txHash))
+
+		if txStatus := isTransactionRequestValid(s.config, tx.Transaction()); txStatus != protocol.TRANSACTION_STATUS_RESERVED {
+			txLogger.Info("send transaction batch received input failed", log.Stringable("tx-status", txStatus))
+			responses[i] = &txResponse{txHash: txHash, transactionStatus: txStatus}
+			continue
+		}
+
+		waitResult, addResp, err := s.addNewTransaction(ctx, txLogger, tx, txHash, input.WaitForCommit)
+		if err != nil && addResp == nil { // rejections carry their status, other failures are reported without one
+			responses[i] = &txResponse{txHash: txHash, transactionStatus: protocol.TRANSACTION_STATUS_RESERVED}
+			continue
+		}
+		responses[i] = toTxResponse(addResp)
+		waitResults[i] = waitResult
+	}
+
+	pendingAtDeadline := 0
+	for i, waitResult := range waitResults {
+		if waitResult == nil {
+			continue
+		}
+		obj, err := s.waiter.wait(waitCtx, waitResult)
+		if err != nil {
+			pendingAtDeadline++
+			continue
+		}
+		responses[i] = obj.(*txResponse)
+	}
+	if pendingAtDeadline > 0 {
+		logger.Info("send transaction batch deadline passed before all transactions were processed", log.Int("pending", pendingAtDeadline))
+	}
+
+	output := &SendTransactionBatchOutput{ClientResponses: make([]*client.SendTransactionResponse, 0, len(responses))}
+	for _, response := range responses {
+		output.ClientResponses = append(output.ClientResponses, toSendTxOutput(response).ClientResponse)
+	}
+	return output, nil
+}
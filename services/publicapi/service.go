@@ -6,6 +6,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	blockStorageAdapter "github.com/orbs-network/orbs-network-go/services/blockstorage/adapter"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
@@ -16,6 +17,7 @@ import (
 var LogTag = log.Service("public-api")
 
 type txResponse struct {
+	txHash             primitives.Sha256
 	transactionStatus  protocol.TransactionStatus
 	transactionReceipt *protocol.TransactionReceipt
 	blockHeight        primitives.BlockHeight
@@ -23,11 +25,12 @@ type txResponse struct {
 }
 
 type service struct {
-	config          config.PublicApiConfig
-	transactionPool services.TransactionPool
-	virtualMachine  services.VirtualMachine
-	blockStorage    services.BlockStorage
-	logger          log.BasicLogger
+	config           config.PublicApiConfig
+	transactionPool  services.TransactionPool
+	virtualMachine   services.VirtualMachine
+	blockStorage     services.BlockStorage
+	blockPersistence blockStorageAdapter.BlockPersistence // followed by contract subscriptions
+	logger           log.BasicLogger
 
 	waiter *waiter
 
@@ -36,6 +39,7 @@ type service struct {
 
 type metrics struct {
 	sendTransactionTime      *metric.Histogram
+	sendTransactionBatchTime *metric.Histogram
 	getTransactionStatusTime *metric.Histogram
 	callMethodTime           *metric.Histogram
 }
@@ -43,6 +47,7 @@ type metrics struct {
 func newMetrics(factory metric.Factory, sendTransactionTimeout time.Duration, getTransactionStatusTimeout time.Duration, callMethodTimeout time.Duration) *metrics {
 	return &metrics{
 		sendTransactionTime:      factory.NewLatency("PublicApi.SendTransactionProcessingTime", sendTransactionTimeout),
+		sendTransactionBatchTime: factory.NewLatency("PublicApi.SendTransactionBatchProcessingTime", sendTransactionTimeout),
 		getTransactionStatusTime: factory.NewLatency("PublicApi.GetTransactionStatusProcessingTime", getTransactionStatusTimeout),
 		callMethodTime:           factory.NewLatency("PublicApi.CallMethodProcessingTime", callMethodTimeout),
 	}
@@ -53,15 +58,17 @@ func NewPublicApi(
 	transactionPool services.TransactionPool,
 	virtualMachine services.VirtualMachine,
 	blockStorage services.BlockStorage,
+	blockPersistence blockStorageAdapter.BlockPersistence,
 	logger log.BasicLogger,
 	metricFactory metric.Factory,
 ) services.PublicApi {
 	s := &service{
-		config:          config,
-		transactionPool: transactionPool,
-		virtualMachine:  virtualMachine,
-		blockStorage:    blockStorage,
-		logger:          logger.WithTags(LogTag),
+		config:           config,
+		transactionPool:  transactionPool,
+		virtualMachine:   virtualMachine,
+		blockStorage:     blockStorage,
+		blockPersistence: blockPersistence,
+		logger:           logger.WithTags(LogTag),
 
 		waiter:  newWaiter(),
 		metrics: newMetrics(metricFactory, config.SendTransactionTimeout(), 2*time.Second, 1*time.Second),
@@ -77,13 +84,14 @@ func (s *service) HandleTransactionResults(ctx context.Context, input *handlers.
 
 	for _, txReceipt := range input.TransactionReceipts {
 		logger.Info("transaction reported as committed", log.Transaction(txReceipt.Txhash()))
-		s.waiter.complete(txReceipt.Txhash().KeyForMap(),
-			&txResponse{
-				transactionStatus:  protocol.TRANSACTION_STATUS_COMMITTED,
-				transactionReceipt: txReceipt,
-				blockHeight:        input.BlockHeight,
-				blockTimestamp:     input.Timestamp,
-			})
+		response := &txResponse{
+			txHash:             txReceipt.Txhash(),
+			transactionStatus:  protocol.TRANSACTION_STATUS_COMMITTED,
+			transactionReceipt: txReceipt,
+			blockHeight:        input.BlockHeight,
+			blockTimestamp:     input.Timestamp,
+		}
+		s.waiter.complete(txReceipt.Txhash().KeyForMap(), response)
 	}
 	return &handlers.HandleTransactionResultsOutput{}, nil
 }
@@ -94,6 +102,7 @@ func (s *service) HandleTransactionError(ctx context.Context, input *handlers.Ha
 	logger.Info("transaction reported as errored", log.Transaction(input.Txhash), log.Stringable("tx-status", input.TransactionStatus))
 	s.waiter.complete(input.Txhash.KeyForMap(),
 		&txResponse{
+			txHash:             input.Txhash,
 			transactionStatus:  input.TransactionStatus,
 			transactionReceipt: nil,
 			blockHeight:        input.BlockHeight,
@@ -156,6 +165,8 @@ func translateExecutionStatusToResponseCode(executionResult protocol.ExecutionRe
 		return protocol.REQUEST_STATUS_COMPLETED
 	case protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT:
 		return protocol.REQUEST_STATUS_COMPLETED
+	case protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED:
+		return protocol.REQUEST_STATUS_COMPLETED
 	case protocol.EXECUTION_RESULT_ERROR_INPUT:
 		return protocol.REQUEST_STATUS_REJECTED
 	case protocol.EXECUTION_RESULT_ERROR_UNEXPECTED:
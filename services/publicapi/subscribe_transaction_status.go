@@ -0,0 +1,199 @@
+package publicapi
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+// pushes the status of transactions as they are committed or rejected,
+// so that clients need neither block in SendTransaction nor poll GetTransactionStatus
+type TransactionStatusSubscriber interface {
+	// blocks until all watched transactions are resolved, ctx is done or onStatus fails; a contract subscription only ends with ctx
+	SubscribeToTransactionStatus(ctx context.Context, filter *TransactionStatusFilter, onStatus func(update *TransactionStatusUpdate) error) error
+}
+
+type TransactionStatusFilter struct {
+	Txhashes              []primitives.Sha256
+	TransactionTimestamps []primitives.TimestampNano // optional, of the transaction at the same index, needed to find transactions committed before subscribing in block storage
+	ContractName          primitives.ContractName    // receipts of transactions calling this contract in blocks committed after subscribing, rejections are not reported
+}
+
+type TransactionStatusUpdate struct {
+	Txhash             primitives.Sha256
+	RequestStatus      protocol.RequestStatus
+	TransactionStatus  protocol.TransactionStatus
+	TransactionReceipt *protocol.TransactionReceipt
+	BlockHeight        primitives.BlockHeight
+	BlockTimestamp     primitives.TimestampNano
+}
+
+func (s *service) SubscribeToTransactionStatus(ctx context.Context, filter *TransactionStatusFilter, onStatus func(update *TransactionStatusUpdate) error) error {
+	if len(filter.Txhashes) == 0 && filter.ContractName == "" {
+		return errors.Errorf("subscription must watch at least one transaction hash or a contract")
+	}
+	if len(filter.TransactionTimestamps) > 0 && len(filter.TransactionTimestamps) != len(filter.Txhashes) {
+		return errors.Errorf("subscription has %d transaction timestamps for %d transaction hashes", len(filter.TransactionTimestamps), len(filter.Txhashes))
+	}
+
+	s.logger.Info("transaction status subscription started", log.Int("transactions", len(filter.Txhashes)), log.String("contract", string(filter.ContractName)))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var contractReceipts chan *txResponse
+	if filter.ContractName != "" {
+		if s.blockPersistence == nil {
+			return errors.Errorf("contract subscriptions are not supported without block persistence")
+		}
+		lastHeight, err := s.blockPersistence.GetNumBlocks()
+		if err != nil {
+			return errors.Wrap(err, "failed to read the last committed block height")
+		}
+		contractReceipts = s.followContractReceipts(ctx, filter.ContractName, lastHeight+1)
+	}
+
+	unresolved := make(map[string]bool)
+	var keys []string
+	for _, txHash := range filter.Txhashes {
+		keys = append(keys, txHash.KeyForMap())
+		unresolved[txHash.KeyForMap()] = true
+	}
+
+	var updates <-chan interface{}
+	if len(keys) > 0 {
+		sub := s.waiter.subscribe(keys...)
+		defer s.waiter.unsubscribe(sub)
+		updates = sub.c
+
+		// subscribing first so a transaction resolved meanwhile is reported at least once
+		for i, txHash := range filter.Txhashes {
+			response := s.resolvedTransactionStatus(ctx, txHash, timestampAt(filter.TransactionTimestamps, i))
+			if response == nil || !unresolved[txHash.KeyForMap()] {
+				continue
+			}
+			if err := onStatus(toTransactionStatusUpdate(response)); err != nil {
+				return err
+			}
+			delete(unresolved, txHash.KeyForMap())
+		}
+		if filter.ContractName == "" && len(unresolved) == 0 {
+			return nil
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case response := <-contractReceipts:
+			if err := onStatus(toTransactionStatusUpdate(response)); err != nil {
+				return err
+			}
+		case wo, open := <-updates:
+			if !open {
+				return errors.Errorf("subscription dropped after falling behind by %d updates", SUBSCRIPTION_BUFFER_SIZE)
+			}
+			response := wo.(*txResponse)
+			if !unresolved[response.txHash.KeyForMap()] {
+				continue
+			}
+			if err := onStatus(toTransactionStatusUpdate(response)); err != nil {
+				return err
+			}
+			delete(unresolved, response.txHash.KeyForMap())
+			if filter.ContractName == "" && len(unresolved) == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// nil unless the transaction is already committed or rejected
+func (s *service) resolvedTransactionStatus(ctx context.Context, txHash primitives.Sha256, timestamp primitives.TimestampNano) *txResponse {
+	response, err := s.getTransactionStatus(ctx, txHash, timestamp)
+	if err != nil || response == nil {
+		s.logger.Info("failed to look up the status of a subscribed transaction", log.Error(err), log.Transaction(txHash))
+		return nil
+	}
+
+	switch response.transactionStatus {
+	case protocol.TRANSACTION_STATUS_PENDING, protocol.TRANSACTION_STATUS_NO_RECORD_FOUND:
+		return nil
+	}
+	response.txHash = txHash
+	return response
+}
+
+// reads every block from the given height on until ctx is done and sends the receipts of the transactions calling the contract
+func (s *service) followContractReceipts(ctx context.Context, contractName primitives.ContractName, fromHeight primitives.BlockHeight) chan *txResponse {
+	receipts := make(chan *txResponse)
+
+	go func() {
+		for height := fromHeight; ; height++ {
+			if err := s.blockPersistence.GetBlockTracker().WaitForBlock(ctx, height); err != nil {
+				return
+			}
+
+			blocks, _, _, err := s.blockPersistence.GetBlocks(height, height)
+			if err != nil || len(blocks) == 0 {
+				s.logger.Info("failed to read committed block, contract subscription skips it", log.Error(err), log.BlockHeight(height), log.String("contract", string(contractName)))
+				continue
+			}
+
+			for _, response := range contractReceiptsOf(blocks[0], contractName) {
+				select {
+				case receipts <- response:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return receipts
+}
+
+func contractReceiptsOf(blockPair *protocol.BlockPairContainer, contractName primitives.ContractName) []*txResponse {
+	calling := make(map[string]bool)
+	for _, tx := range blockPair.TransactionsBlock.SignedTransactions {
+		if tx.Transaction().ContractName() == contractName {
+			calling[digest.CalcTxHash(tx.Transaction()).KeyForMap()] = true
+		}
+	}
+
+	var responses []*txResponse
+	for _, receipt := range blockPair.ResultsBlock.TransactionReceipts {
+		if calling[receipt.Txhash().KeyForMap()] {
+			responses = append(responses, &txResponse{
+				txHash:             receipt.Txhash(),
+				transactionStatus:  protocol.TRANSACTION_STATUS_COMMITTED,
+				transactionReceipt: receipt,
+				blockHeight:        blockPair.ResultsBlock.Header.BlockHeight(),
+				blockTimestamp:     blockPair.ResultsBlock.Header.Timestamp(),
+			})
+		}
+	}
+	return responses
+}
+
+func timestampAt(timestamps []primitives.TimestampNano, i int) primitives.TimestampNano {
+	if i < len(timestamps) {
+		return timestamps[i]
+	}
+	return 0
+}
+
+func toTransactionStatusUpdate(response *txResponse) *TransactionStatusUpdate {
+	return &TransactionStatusUpdate{
+		Txhash:             response.txHash,
+		RequestStatus:      translateTxStatusToResponseCode(response.transactionStatus),
+		TransactionStatus:  response.transactionStatus,
+		TransactionReceipt: response.transactionReceipt,
+		BlockHeight:        response.blockHeight,
+		BlockTimestamp:     response.blockTimestamp,
+	}
+}
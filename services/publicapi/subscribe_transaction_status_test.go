@@ -0,0 +1,154 @@
+package publicapi
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	blockStorageAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/blockstorage/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// transactions are pending unless told otherwise
+func newServiceForSubscriptionTests() (*service, blockStorageAdapter.InMemoryBlockPersistence) {
+	transactionPool := &services.MockTransactionPool{}
+	transactionPool.When("GetCommittedTransactionReceipt", mock.Any, mock.Any).Return(&services.GetCommittedTransactionReceiptOutput{
+		TransactionStatus: protocol.TRANSACTION_STATUS_PENDING,
+	}, nil)
+	blockPersistence := blockStorageAdapter.NewInMemoryBlockPersistence()
+
+	s := &service{
+		transactionPool:  transactionPool,
+		blockPersistence: blockPersistence,
+		logger:           log.GetLogger(),
+		waiter:           newWaiter(),
+	}
+	return s, blockPersistence
+}
+
+func subscribeInBackground(ctx context.Context, t *testing.T, s *service, filter *TransactionStatusFilter) (chan *TransactionStatusUpdate, chan error) {
+	updates := make(chan *TransactionStatusUpdate, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.SubscribeToTransactionStatus(ctx, filter, func(update *TransactionStatusUpdate) error {
+			updates <- update
+			return nil
+		})
+	}()
+
+	require.True(t, test.Eventually(1*time.Second, func() bool {
+		s.waiter.mutex.Lock()
+		defer s.waiter.mutex.Unlock()
+		return len(s.waiter.subscribers) > 0
+	}), "subscription did not start")
+	return updates, done
+}
+
+func TestPublicApiSubscribeToTransactionStatus_PushesStatusOfWatchedTransactionsUntilAllAreResolved(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		s, _ := newServiceForSubscriptionTests()
+		committedTx := builders.Transaction().Build()
+		rejectedTx := builders.Transaction().Build()
+		committedTxHash := digest.CalcTxHash(committedTx.Transaction())
+		rejectedTxHash := digest.CalcTxHash(rejectedTx.Transaction())
+
+		updates, done := subscribeInBackground(ctx, t, s, &TransactionStatusFilter{Txhashes: []primitives.Sha256{committedTxHash, rejectedTxHash}})
+
+		s.HandleTransactionResults(ctx, &handlers.HandleTransactionResultsInput{
+			BlockHeight:         3,
+			TransactionReceipts: []*protocol.TransactionReceipt{builders.TransactionReceipt().WithTransaction(committedTx.Transaction()).Build()},
+		})
+		s.HandleTransactionError(ctx, &handlers.HandleTransactionErrorInput{
+			Txhash:            rejectedTxHash,
+			TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED,
+		})
+
+		require.NoError(t, <-done, "subscription should end once all watched transactions are resolved")
+
+		committed := <-updates
+		require.Equal(t, committedTxHash, committed.Txhash)
+		require.Equal(t, protocol.TRANSACTION_STATUS_COMMITTED, committed.TransactionStatus)
+		require.Equal(t, protocol.REQUEST_STATUS_COMPLETED, committed.RequestStatus)
+		require.EqualValues(t, 3, committed.BlockHeight)
+		require.NotNil(t, committed.TransactionReceipt, "committed transaction should come with its receipt")
+
+		rejected := <-updates
+		require.Equal(t, rejectedTxHash, rejected.Txhash)
+		require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED, rejected.TransactionStatus)
+		require.Equal(t, protocol.REQUEST_STATUS_REJECTED, rejected.RequestStatus)
+	})
+}
+
+func TestPublicApiSubscribeToTransactionStatus_PushesStatusOfTransactionResolvedBeforeSubscribing(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		s, _ := newServiceForSubscriptionTests()
+		committedTx := builders.Transaction().Build()
+		receipt := builders.TransactionReceipt().WithTransaction(committedTx.Transaction()).Build()
+		transactionPool := &services.MockTransactionPool{}
+		s.transactionPool = transactionPool
+		transactionPool.When("GetCommittedTransactionReceipt", mock.Any, mock.Any).Return(&services.GetCommittedTransactionReceiptOutput{
+			TransactionStatus:  protocol.TRANSACTION_STATUS_COMMITTED,
+			TransactionReceipt: receipt,
+			BlockHeight:        3,
+		}, nil)
+
+		var updates []*TransactionStatusUpdate
+		err := s.SubscribeToTransactionStatus(ctx, &TransactionStatusFilter{Txhashes: []primitives.Sha256{receipt.Txhash()}}, func(update *TransactionStatusUpdate) error {
+			updates = append(updates, update)
+			return nil
+		})
+
+		require.NoError(t, err, "subscription should end once the watched transaction is resolved")
+		require.Len(t, updates, 1, "the status of the transaction should be pushed once")
+		require.Equal(t, receipt.Txhash(), updates[0].Txhash)
+		require.Equal(t, protocol.TRANSACTION_STATUS_COMMITTED, updates[0].TransactionStatus)
+		require.EqualValues(t, 3, updates[0].BlockHeight)
+	})
+}
+
+func TestPublicApiSubscribeToTransactionStatus_PushesReceiptsOfContractFromCommittedBlocks(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		s, blockPersistence := newServiceForSubscriptionTests()
+		contractTx := builders.Transaction().WithContract("Watched").Build()
+		otherContractTx := builders.Transaction().WithContract("Other").Build()
+		pendingTx := builders.Transaction().WithContract("Watched").Build()
+
+		// the pending transaction only tells when the subscription started
+		subscriptionCtx, cancel := context.WithCancel(ctx)
+		updates, done := subscribeInBackground(subscriptionCtx, t, s, &TransactionStatusFilter{
+			Txhashes:     []primitives.Sha256{digest.CalcTxHash(pendingTx.Transaction())},
+			ContractName: "Watched",
+		})
+
+		blockPair := builders.BlockPair().WithTransactions(0).WithTransaction(otherContractTx).WithTransaction(contractTx).WithReceiptsForTransactions().Build()
+		require.NoError(t, blockPersistence.WriteNextBlock(blockPair))
+
+		update := <-updates
+		require.Equal(t, digest.CalcTxHash(contractTx.Transaction()), update.Txhash, "got a receipt of another contract")
+		require.Equal(t, protocol.TRANSACTION_STATUS_COMMITTED, update.TransactionStatus)
+		require.EqualValues(t, 1, update.BlockHeight)
+
+		cancel()
+		require.NoError(t, <-done, "contract subscription should end quietly when its context is done")
+		require.Empty(t, updates, "got a receipt of another contract")
+	})
+}
+
+func TestPublicApiSubscribeToTransactionStatus_RejectsEmptyFilter(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		s, _ := newServiceForSubscriptionTests()
+
+		err := s.SubscribeToTransactionStatus(ctx, &TransactionStatusFilter{}, func(update *TransactionStatusUpdate) error {
+			return nil
+		})
+		require.Error(t, err, "subscription without transactions or contract should be rejected")
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/services/publicapi"
 	"github.com/orbs-network/orbs-network-go/test/builders"
+	blockStorageAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/blockstorage/adapter"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/stretchr/testify/require"
@@ -29,7 +30,7 @@ func newPublicApiHarness(ctx context.Context, txTimeout time.Duration) *harness
 	txpMock := makeTxMock()
 	vmMock := &services.MockVirtualMachine{}
 	bksMock := &services.MockBlockStorage{}
-	papi := publicapi.NewPublicApi(cfg, txpMock, vmMock, bksMock, logger, metric.NewRegistry())
+	papi := publicapi.NewPublicApi(cfg, txpMock, vmMock, bksMock, blockStorageAdapter.NewInMemoryBlockPersistence(), logger, metric.NewRegistry())
 	return &harness{
 		papi:    papi,
 		txpMock: txpMock,
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func sendTransactionRequest(txb *protocol.SignedTransactionBuilder) *client.SendTransactionRequest {
+	return (&client.SendTransactionRequestBuilder{SignedTransaction: txb}).Build()
+}
+
+func TestSendTransactionBatch_ReportsStatusPerTransaction(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		harness := newPublicApiHarness(ctx, 1*time.Second)
+		harness.txpMock.When("AddNewTransaction", mock.Any, mock.Any).Return(&services.AddNewTransactionOutput{
+			TransactionStatus: protocol.TRANSACTION_STATUS_PENDING,
+		}, nil).Times(2)
+
+		result, err := harness.papi.(publicapi.TransactionBatchSender).SendTransactionBatch(ctx, &publicapi.SendTransactionBatchInput{
+			ClientRequests: []*client.SendTransactionRequest{
+				sendTransactionRequest(builders.TransferTransaction().WithAmountAndTargetAddress(1, builders.AddressForEd25519SignerForTests(2)).Builder()),
+				sendTransactionRequest(builders.TransferTransaction().WithVirtualChainId(builders.DEFAULT_TEST_VIRTUAL_CHAIN_ID + 1).Builder()),
+				sendTransactionRequest(builders.TransferTransaction().WithAmountAndTargetAddress(2, builders.AddressForEd25519SignerForTests(2)).Builder()),
+			},
+		})
+
+		harness.verifyMocks(t) // contract test
+
+		require.NoError(t, err, "error happened when it should not")
+		require.Len(t, result.ClientResponses, 3, "expected a response per transaction")
+		require.Equal(t, protocol.TRANSACTION_STATUS_PENDING, result.ClientResponses[0].TransactionStatus())
+		require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH, result.ClientResponses[1].TransactionStatus(), "invalid transaction should not fail the batch")
+		require.Equal(t, protocol.TRANSACTION_STATUS_PENDING, result.ClientResponses[2].TransactionStatus())
+	})
+}
+
+func TestSendTransactionBatch_WaitsForCommitUntilSharedDeadline(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		harness := newPublicApiHarness(ctx, 1*time.Second)
+
+		committedTx := builders.TransferTransaction().WithAmountAndTargetAddress(1, builders.AddressForEd25519SignerForTests(2)).Builder()
+		committedTxHash := digest.CalcTxHash(committedTx.Build().Transaction())
+		pendingTx := builders.TransferTransaction().WithAmountAndTargetAddress(2, builders.AddressForEd25519SignerForTests(2)).Builder()
+
+		harness.txpMock.When("AddNewTransaction", mock.Any, mock.Any).Times(2).
+			Call(func(ctx context.Context, input *services.AddNewTransactionInput) (*services.AddNewTransactionOutput, error) {
+				if digest.CalcTxHash(input.SignedTransaction.Transaction()).Equal(committedTxHash) {
+					go func() {
+						time.Sleep(1 * time.Millisecond)
+						harness.papi.HandleTransactionResults(ctx, &handlers.HandleTransactionResultsInput{
+							TransactionReceipts: []*protocol.TransactionReceipt{builders.TransactionReceipt().WithTransaction(input.SignedTransaction.Transaction()).Build()},
+							BlockHeight:         3,
+						})
+					}()
+				}
+				return &services.AddNewTransactionOutput{TransactionStatus: protocol.TRANSACTION_STATUS_PENDING}, nil
+			})
+
+		start := time.Now()
+		result, err := harness.papi.(publicapi.TransactionBatchSender).SendTransactionBatch(ctx, &publicapi.SendTransactionBatchInput{
+			ClientRequests: []*client.SendTransactionRequest{sendTransactionRequest(pendingTx), sendTransactionRequest(committedTx)},
+			WaitForCommit:  true,
+			WaitTimeout:    50 * time.Millisecond,
+		})
+
+		harness.verifyMocks(t) // contract test
+
+		require.NoError(t, err, "error happened when it should not")
+		require.True(t, time.Since(start) < 1*time.Second, "batch should not wait beyond its own deadline")
+		require.Len(t, result.ClientResponses, 2, "expected a response per transaction")
+		require.Equal(t, protocol.TRANSACTION_STATUS_PENDING, result.ClientResponses[0].TransactionStatus(), "transaction not committed by the deadline should be reported pending")
+		require.Equal(t, protocol.TRANSACTION_STATUS_COMMITTED, result.ClientResponses[1].TransactionStatus(), "committed transaction should be reported even though it waited behind a pending one")
+		require.EqualValues(t, 3, result.ClientResponses[1].BlockHeight())
+	})
+}
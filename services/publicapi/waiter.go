@@ -14,15 +14,27 @@ type waiterChannel struct {
 
 type waiterChannels map[*waiterChannel]*waiterChannel
 
+const SUBSCRIPTION_BUFFER_SIZE = 100
+
+// unlike a waiterChannel, a subscription is not removed on completion and receives every completion of any of its keys
+type subscription struct {
+	c    chan interface{}
+	keys []string
+}
+
+type subscriptions map[*subscription]*subscription
+
 type waiter struct {
-	mutex sync.Mutex
-	m     map[string]waiterChannels
+	mutex       sync.Mutex
+	m           map[string]waiterChannels
+	subscribers map[string]subscriptions
 }
 
 func newWaiter() *waiter {
 	return &waiter{
-		mutex: sync.Mutex{},
-		m:     make(map[string]waiterChannels),
+		mutex:       sync.Mutex{},
+		m:           make(map[string]waiterChannels),
+		subscribers: make(map[string]subscriptions),
 	}
 }
 
@@ -69,6 +81,13 @@ func (w *waiter) deleteByChannel(wc *waiterChannel) {
 func (w *waiter) wait(ctx context.Context, wc *waiterChannel) (interface{}, error) {
 	select {
 	case <-ctx.Done():
+		select { // a response that arrived by the time ctx terminated is still returned
+		case response, open := <-wc.c:
+			if open {
+				return response, nil
+			}
+		default:
+		}
 		w.deleteByChannel(wc)
 		return nil, errors.Errorf("waiting aborted due to context termination for key %s", hex.EncodeToString([]byte(wc.k)))
 	case response, open := <-wc.c: // intentional not close channel here
@@ -84,4 +103,52 @@ func (w *waiter) complete(k string, wo interface{}) {
 		wc.c <- wo
 		close(wc.c)
 	}
+	w.notifySubscribers(k, wo)
+}
+
+func (w *waiter) subscribe(keys ...string) *subscription {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	sub := &subscription{make(chan interface{}, SUBSCRIPTION_BUFFER_SIZE), keys}
+	for _, k := range keys {
+		subs, exists := w.subscribers[k]
+		if !exists {
+			subs = make(subscriptions)
+			w.subscribers[k] = subs
+		}
+		subs[sub] = sub
+	}
+
+	return sub
+}
+
+func (w *waiter) unsubscribe(sub *subscription) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w._unsubscribe(sub)
+}
+
+func (w *waiter) _unsubscribe(sub *subscription) { // must be called under mutex
+	if _, subscribed := w.subscribers[sub.keys[0]][sub]; !subscribed {
+		return
+	}
+	for _, k := range sub.keys {
+		delete(w.subscribers[k], sub)
+		if len(w.subscribers[k]) == 0 {
+			delete(w.subscribers, k)
+		}
+	}
+	close(sub.c)
+}
+
+func (w *waiter) notifySubscribers(k string, wo interface{}) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for sub := range w.subscribers[k] {
+		select {
+		case sub.c <- wo:
+		default: // a subscriber that fell behind is dropped (its channel closed) rather than blocking the service completing k
+			w._unsubscribe(sub)
+		}
+	}
 }
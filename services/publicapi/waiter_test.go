@@ -103,6 +103,20 @@ func TestPublicApiWaiter_WaitFor(t *testing.T) {
 	})
 }
 
+func TestPublicApiWaiter_WaitReturnsResponseCompletedBeforeContextTerminated(t *testing.T) {
+	t.Parallel()
+	waiter := newWaiter()
+	wc := waiter.add("key")
+	waiter.complete("key", "response")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response, err := waiter.wait(ctx, wc)
+	require.NoError(t, err, "completed response should win over terminated context")
+	require.Equal(t, "response", response)
+}
+
 func TestPublicApiWaiter_CompleteAllChannels(t *testing.T) {
 	t.Parallel()
 	test.WithContext(func(ctx context.Context) {
@@ -222,3 +236,47 @@ func waitHarness(ctx context.Context, t *testing.T, name string, waiter *waiter,
 	}
 	done <- struct{}{}
 }
+
+func TestPublicApiWaiter_SubscriptionReceivesEveryCompletionOfItsKeys(t *testing.T) {
+	t.Parallel()
+	test.WithContext(func(ctx context.Context) {
+		waiter := newWaiter()
+		sub := waiter.subscribe("key1", "key2")
+
+		waiter.complete("key1", "hello")
+		waiter.complete("key2", "world")
+		waiter.complete("key1", "again")
+		waiter.complete("key3", "ignored")
+
+		require.Equal(t, "hello", <-sub.c)
+		require.Equal(t, "world", <-sub.c)
+		require.Equal(t, "again", <-sub.c)
+		require.Empty(t, sub.c, "subscription got a completion of a key it did not subscribe to")
+
+		waiter.unsubscribe(sub)
+		_, open := <-sub.c
+		require.False(t, open, "subscription channel should be closed")
+		require.Empty(t, waiter.subscribers, "subscription keys should be cleaned up")
+	})
+}
+
+func TestPublicApiWaiter_SubscriptionThatFellBehindIsDropped(t *testing.T) {
+	t.Parallel()
+	test.WithContext(func(ctx context.Context) {
+		waiter := newWaiter()
+		sub := waiter.subscribe("key")
+
+		for i := 0; i <= SUBSCRIPTION_BUFFER_SIZE; i++ {
+			waiter.complete("key", i)
+		}
+
+		for i := 0; i < SUBSCRIPTION_BUFFER_SIZE; i++ {
+			require.Equal(t, i, <-sub.c)
+		}
+		_, open := <-sub.c
+		require.False(t, open, "subscription channel should be closed after falling behind")
+		require.Empty(t, waiter.subscribers, "dropped subscription should be cleaned up")
+
+		waiter.unsubscribe(sub) // must not close the channel twice
+	})
+}
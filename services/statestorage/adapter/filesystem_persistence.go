@@ -0,0 +1,195 @@
+package adapter
+
+import (
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/statestorage/adapter/kvstore"
+	"github.com/orbs-network/orbs-network-go/services/statestorage/merkle"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+var LogTag = log.String("adapter", "state-storage")
+
+// key layout in the kv store
+var (
+	metadataKey       = []byte("m")
+	stateRecordPrefix = []byte("s")
+	merkleNodePrefix  = []byte("t")
+)
+
+type FilesystemStatePersistence struct {
+	db     *kvstore.Store
+	logger log.BasicLogger
+}
+
+func NewFilesystemStatePersistence(conf config.FilesystemStatePersistenceConfig, parentLogger log.BasicLogger) (*FilesystemStatePersistence, error) {
+	logger := parentLogger.WithTags(LogTag)
+
+	db, err := kvstore.Open(conf.StateStorageDataDir(), kvstore.DefaultCompactionThreshold)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open state db in %s", conf.StateStorageDataDir())
+	}
+
+	sp := &FilesystemStatePersistence{
+		db:     db,
+		logger: logger,
+	}
+
+	if !db.Has(metadataKey) {
+		// TODO - same hard coded Genesis block (height 0) as the in memory persistence
+		_, merkleRoot := merkle.NewForest()
+		if err := db.Write(kvstore.NewBatch().Put(metadataKey, encodeMetadata(0, 0, merkleRoot))); err != nil {
+			db.Close()
+			return nil, errors.Wrap(err, "failed to initialize state db")
+		}
+	}
+
+	height, _, _, err := sp.ReadMetadata()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	logger.Info("loaded state from disk", log.BlockHeight(height), log.String("path", conf.StateStorageDataDir()))
+
+	return sp, nil
+}
+
+func (sp *FilesystemStatePersistence) Write(height primitives.BlockHeight, ts primitives.TimestampNano, root primitives.MerkleSha256, diff ChainState) error {
+	batch := kvstore.NewBatch()
+	for contract, records := range diff {
+		for _, record := range records {
+			key := stateRecordKey(contract, string(record.Key()))
+			if isZeroValue(record.Value()) {
+				batch.Delete(key)
+			} else {
+				batch.Put(key, record.Raw())
+			}
+		}
+	}
+	batch.Put(metadataKey, encodeMetadata(height, ts, root))
+
+	if err := sp.write(batch); err != nil {
+		return errors.Wrapf(err, "failed to write state for block height %d", height)
+	}
+	return nil
+}
+
+func (sp *FilesystemStatePersistence) write(batch *kvstore.Batch) error {
+	if err := sp.db.Write(batch); err != nil {
+		return err
+	}
+
+	if sp.db.NeedsCompaction() {
+		if err := sp.db.Compact(); err != nil {
+			// the data itself is safely in the log, we'll try again on the next write
+			sp.logger.Error("failed to snapshot state db", log.Error(err))
+		}
+	}
+	return nil
+}
+
+func (sp *FilesystemStatePersistence) Read(contract primitives.ContractName, key string) (*protocol.StateRecord, bool, error) {
+	value, ok, err := sp.db.Get(stateRecordKey(contract, key))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return protocol.StateRecordReader(value), true, nil
+}
+
+func (sp *FilesystemStatePersistence) ReadMetadata() (primitives.BlockHeight, primitives.TimestampNano, primitives.MerkleSha256, error) {
+	value, ok, err := sp.db.Get(metadataKey)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if !ok {
+		return 0, 0, nil, errors.New("state db is missing its metadata")
+	}
+	return decodeMetadata(value)
+}
+
+func (sp *FilesystemStatePersistence) Each(callback func(contract primitives.ContractName, record *protocol.StateRecord) error) error {
+	return sp.db.Iterate(stateRecordPrefix, func(key []byte, value []byte) error {
+		contract, err := contractNameFromStateRecordKey(key)
+		if err != nil {
+			return err
+		}
+		return callback(contract, protocol.StateRecordReader(value))
+	})
+}
+
+// merkle nodes share the state db so that they survive restarts together with the state they describe
+func (sp *FilesystemStatePersistence) NodeStore() merkle.NodeStore {
+	return &filesystemNodeStore{sp}
+}
+
+func (sp *FilesystemStatePersistence) Close() error {
+	return sp.db.Close()
+}
+
+// [prefix][contract name size][contract name][record key]
+func stateRecordKey(contract primitives.ContractName, key string) []byte {
+	result := make([]byte, 0, len(stateRecordPrefix)+2+len(contract)+len(key))
+	result = append(result, stateRecordPrefix...)
+	result = append(result, byte(len(contract)>>8), byte(len(contract)))
+	result = append(result, contract...)
+	result = append(result, key...)
+	return result
+}
+
+func contractNameFromStateRecordKey(key []byte) (primitives.ContractName, error) {
+	offset := len(stateRecordPrefix)
+	if len(key) < offset+2 {
+		return "", errors.Errorf("state db key %x is too short", key)
+	}
+	size := int(key[offset])<<8 | int(key[offset+1])
+	offset += 2
+	if len(key) < offset+size {
+		return "", errors.Errorf("state db key %x has a truncated contract name", key)
+	}
+	return primitives.ContractName(key[offset : offset+size]), nil
+}
+
+func encodeMetadata(height primitives.BlockHeight, ts primitives.TimestampNano, root primitives.MerkleSha256) []byte {
+	result := make([]byte, 16+len(root))
+	binary.BigEndian.PutUint64(result, uint64(height))
+	binary.BigEndian.PutUint64(result[8:], uint64(ts))
+	copy(result[16:], root)
+	return result
+}
+
+func decodeMetadata(value []byte) (primitives.BlockHeight, primitives.TimestampNano, primitives.MerkleSha256, error) {
+	if len(value) < 16 {
+		return 0, 0, nil, errors.Errorf("state db metadata is corrupt, only %d bytes long", len(value))
+	}
+	height := primitives.BlockHeight(binary.BigEndian.Uint64(value))
+	ts := primitives.TimestampNano(binary.BigEndian.Uint64(value[8:]))
+	root := primitives.MerkleSha256(append([]byte{}, value[16:]...))
+	return height, ts, root, nil
+}
+
+type filesystemNodeStore struct {
+	sp *FilesystemStatePersistence
+}
+
+func (s *filesystemNodeStore) Get(key []byte) ([]byte, bool, error) {
+	return s.sp.db.Get(merkleNodeKey(key))
+}
+
+func (s *filesystemNodeStore) Write(changes map[string][]byte) error {
+	batch := kvstore.NewBatch()
+	for key, value := range changes {
+		if value == nil {
+			batch.Delete(merkleNodeKey([]byte(key)))
+		} else {
+			batch.Put(merkleNodeKey([]byte(key)), value)
+		}
+	}
+	return s.sp.write(batch)
+}
+
+func merkleNodeKey(key []byte) []byte {
+	return append(append(make([]byte, 0, len(merkleNodePrefix)+len(key)), merkleNodePrefix...), key...)
+}
@@ -0,0 +1,118 @@
+package adapter
+
+import (
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/statestorage/merkle"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func newFilesystemStatePersistenceForTests(t *testing.T, dir string) *FilesystemStatePersistence {
+	sp, err := NewFilesystemStatePersistence(config.ForFilesystemStatePersistenceTests(dir), log.GetLogger())
+	require.NoError(t, err, "failed to open state persistence")
+	return sp
+}
+
+func writeSingleValue(t *testing.T, sp StatePersistence, h primitives.BlockHeight, c, k, v string) {
+	record := (&protocol.StateRecordBuilder{Key: []byte(k), Value: []byte(v)}).Build()
+	diff := ChainState{primitives.ContractName(c): {k: record}}
+	require.NoError(t, sp.Write(h, primitives.TimestampNano(h*1000), []byte{byte(h)}, diff), "failed to write block %d", h)
+}
+
+func TestFilesystemStatePersistence_StartsFromGenesis(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	sp := newFilesystemStatePersistenceForTests(t, dir)
+	defer sp.Close()
+
+	height, ts, root, err := sp.ReadMetadata()
+	require.NoError(t, err)
+	require.EqualValues(t, 0, height)
+	require.EqualValues(t, 0, ts)
+	require.NotEmpty(t, root, "genesis merkle root should be set")
+}
+
+func TestFilesystemStatePersistence_ReloadsStateAfterRestart(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	sp := newFilesystemStatePersistenceForTests(t, dir)
+	writeSingleValue(t, sp, 1, "foo", "a", "1")
+	writeSingleValue(t, sp, 2, "foo", "b", "2")
+	writeSingleValue(t, sp, 3, "bar", "a", "3")
+	writeSingleValue(t, sp, 4, "foo", "a", "") // zero value removes the key
+	require.NoError(t, sp.Close())
+
+	reopened := newFilesystemStatePersistenceForTests(t, dir)
+	defer reopened.Close()
+
+	height, ts, root, err := reopened.ReadMetadata()
+	require.NoError(t, err)
+	require.EqualValues(t, 4, height)
+	require.EqualValues(t, 4000, ts)
+	require.EqualValues(t, []byte{4}, root)
+
+	_, ok, err := reopened.Read("foo", "a")
+	require.NoError(t, err)
+	require.False(t, ok, "deleted key should not be reloaded")
+
+	record, ok, err := reopened.Read("foo", "b")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, "2", record.Value())
+
+	record, ok, err = reopened.Read("bar", "a")
+	require.NoError(t, err)
+	require.True(t, ok, "same key in a different contract should be kept apart")
+	require.EqualValues(t, "3", record.Value())
+}
+
+func TestFilesystemStatePersistence_EachVisitsAllRecords(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	sp := newFilesystemStatePersistenceForTests(t, dir)
+	defer sp.Close()
+	writeSingleValue(t, sp, 1, "foo", "a", "1")
+	writeSingleValue(t, sp, 2, "foobar", "a", "2")
+	writeSingleValue(t, sp, 3, "foo", "b", "3")
+
+	visited := make(map[string]string)
+	require.NoError(t, sp.Each(func(contract primitives.ContractName, record *protocol.StateRecord) error {
+		visited[string(contract)+"/"+string(record.Key())] = string(record.Value())
+		return nil
+	}))
+	require.Equal(t, map[string]string{"foo/a": "1", "foo/b": "3", "foobar/a": "2"}, visited)
+}
+
+func TestFilesystemStatePersistence_MerkleNodesSurviveRestart(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	sp := newFilesystemStatePersistenceForTests(t, dir)
+	forest, emptyRoot, err := merkle.NewForestWithNodeStore(sp.NodeStore(), 0)
+	require.NoError(t, err)
+	root, err := forest.Update(emptyRoot, merkle.MerkleDiffs{{Key: []byte("foo"), Value: hash.CalcSha256([]byte("bar"))}})
+	require.NoError(t, err)
+	require.NoError(t, sp.Close())
+
+	reopened := newFilesystemStatePersistenceForTests(t, dir)
+	defer reopened.Close()
+
+	reloaded, _, err := merkle.NewForestWithNodeStore(reopened.NodeStore(), 0)
+	require.NoError(t, err)
+	require.True(t, reloaded.HasRoot(root), "merkle root should be loaded from disk")
+
+	proof, err := reloaded.GetProof(root, []byte("foo"))
+	require.NoError(t, err)
+	verified, err := reloaded.Verify(root, proof, []byte("foo"), hash.CalcSha256([]byte("bar")))
+	require.NoError(t, err)
+	require.True(t, verified, "proof read from disk should verify")
+}
@@ -0,0 +1,198 @@
+package kvstore
+
+import (
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// a record is [magic][payload size][payload checksum][payload] and holds one atomic batch of operations
+// the payload is [num ops] followed by [op type][key size][key][value size][value] for every operation
+const (
+	recordMagic      = uint32(0x5747A1E0)
+	recordHeaderSize = 12
+	maxRecordSize    = 1024 * 1024 * 1024
+
+	opTypePut    = byte(1)
+	opTypeDelete = byte(2)
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+type op struct {
+	key         []byte
+	value       []byte
+	delete      bool
+	valueOffset int64 // relative to the start of the record payload, set while encoding or decoding
+}
+
+type Batch struct {
+	ops []*op
+}
+
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+func (b *Batch) Put(key []byte, value []byte) *Batch {
+	b.ops = append(b.ops, &op{key: key, value: value})
+	return b
+}
+
+func (b *Batch) Delete(key []byte) *Batch {
+	b.ops = append(b.ops, &op{key: key, delete: true})
+	return b
+}
+
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+func encodeRecord(ops []*op) []byte {
+	payloadSize := 4
+	for _, o := range ops {
+		payloadSize += 1 + 4 + len(o.key) + 4 + len(o.value)
+	}
+
+	record := make([]byte, recordHeaderSize+payloadSize)
+	payload := record[recordHeaderSize:]
+
+	binary.LittleEndian.PutUint32(payload, uint32(len(ops)))
+	offset := 4
+	for _, o := range ops {
+		if o.delete {
+			payload[offset] = opTypeDelete
+		} else {
+			payload[offset] = opTypePut
+		}
+		offset++
+
+		binary.LittleEndian.PutUint32(payload[offset:], uint32(len(o.key)))
+		offset += 4
+		offset += copy(payload[offset:], o.key)
+
+		binary.LittleEndian.PutUint32(payload[offset:], uint32(len(o.value)))
+		offset += 4
+		o.valueOffset = int64(offset)
+		offset += copy(payload[offset:], o.value)
+	}
+
+	binary.LittleEndian.PutUint32(record[0:], recordMagic)
+	binary.LittleEndian.PutUint32(record[4:], uint32(payloadSize))
+	binary.LittleEndian.PutUint32(record[8:], crc32.Checksum(payload, crcTable))
+
+	return record
+}
+
+func decodePayload(payload []byte) ([]*op, error) {
+	if len(payload) < 4 {
+		return nil, errors.New("payload too short")
+	}
+
+	numOps := binary.LittleEndian.Uint32(payload)
+	ops := make([]*op, 0, numOps)
+	offset := uint32(4)
+	size := uint32(len(payload))
+
+	for i := uint32(0); i < numOps; i++ {
+		if size < offset+5 {
+			return nil, errors.Errorf("operation %d is truncated", i)
+		}
+		o := &op{delete: payload[offset] == opTypeDelete}
+		offset++
+
+		keySize := binary.LittleEndian.Uint32(payload[offset:])
+		offset += 4
+		if size < offset+keySize+4 {
+			return nil, errors.Errorf("key of operation %d is truncated", i)
+		}
+		o.key = payload[offset : offset+keySize]
+		offset += keySize
+
+		valueSize := binary.LittleEndian.Uint32(payload[offset:])
+		offset += 4
+		if size < offset+valueSize {
+			return nil, errors.Errorf("value of operation %d is truncated", i)
+		}
+		o.valueOffset = int64(offset)
+		o.value = payload[offset : offset+valueSize]
+		offset += valueSize
+
+		ops = append(ops, o)
+	}
+
+	return ops, nil
+}
+
+// reads the header of a single record, returning its payload size and checksum
+func readRecordHeader(r io.ReaderAt, offset int64) (uint32, uint32, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return 0, 0, err
+	}
+
+	if magic := binary.LittleEndian.Uint32(header[0:]); magic != recordMagic {
+		return 0, 0, errors.Errorf("invalid record magic %x at offset %d", magic, offset)
+	}
+
+	payloadSize := binary.LittleEndian.Uint32(header[4:])
+	if payloadSize > maxRecordSize {
+		return 0, 0, errors.Errorf("record at offset %d is too large (%d bytes)", offset, payloadSize)
+	}
+
+	return payloadSize, binary.LittleEndian.Uint32(header[8:]), nil
+}
+
+// reads a single record, returning its operations and total size on disk
+func readRecord(r io.ReaderAt, offset int64) ([]*op, int64, error) {
+	payloadSize, expectedChecksum, err := readRecordHeader(r, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	payload := make([]byte, payloadSize)
+	if _, err := r.ReadAt(payload, offset+recordHeaderSize); err != nil {
+		return nil, 0, err
+	}
+
+	if checksum := crc32.Checksum(payload, crcTable); checksum != expectedChecksum {
+		return nil, 0, errors.Errorf("record checksum mismatch at offset %d", offset)
+	}
+
+	ops, err := decodePayload(payload)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "corrupt record at offset %d", offset)
+	}
+
+	return ops, recordHeaderSize + int64(payloadSize), nil
+}
+
+// scans all records in the file, returning the size of the valid prefix and the error that stopped the scan (if any)
+func scanFile(file *os.File, fileSize int64, apply func(file *os.File, payloadOffset int64, ops []*op)) (int64, error) {
+	offset := int64(0)
+	for offset < fileSize {
+		ops, size, err := readRecord(file, offset)
+		if err != nil {
+			return offset, err
+		}
+		apply(file, offset+recordHeaderSize, ops)
+		offset += size
+	}
+
+	return offset, nil
+}
+
+// a torn write can only leave a partial record at the end of the log, so the record must end at or beyond the end of
+// the file according to its own header (or not even have a complete header)
+func recordRunsToEndOfFile(r io.ReaderAt, offset int64, fileSize int64) bool {
+	if offset+recordHeaderSize >= fileSize {
+		return true
+	}
+	payloadSize, _, err := readRecordHeader(r, offset)
+	if err != nil {
+		return false
+	}
+	return offset+recordHeaderSize+int64(payloadSize) >= fileSize
+}
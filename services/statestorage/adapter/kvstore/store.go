@@ -0,0 +1,327 @@
+// Package kvstore is a small embedded key-value store used to persist state on disk.
+//
+// It is log structured: every batch of writes is appended atomically to a write-ahead log, and an in-memory
+// index maps each live key to the location of its latest value on disk (values themselves are not kept in RAM).
+// When the log grows beyond a threshold, all live entries are compacted into a new snapshot file and the log
+// is reset, so restart time is bounded by the size of the snapshot index rather than the history of writes.
+package kvstore
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	snapshotFilename = "snapshot.db"
+	walFilename      = "wal.log"
+
+	DefaultCompactionThreshold = int64(64 * 1024 * 1024)
+
+	snapshotBatchSize = 4 * 1024 * 1024
+)
+
+type valuePointer struct {
+	file   *os.File
+	offset int64
+	size   uint32
+}
+
+type Store struct {
+	dir                 string
+	compactionThreshold int64
+
+	mutex     sync.RWMutex
+	snapshot  *os.File
+	wal       *os.File
+	walOffset int64
+	index     map[string]valuePointer
+}
+
+func Open(dir string, compactionThreshold int64) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create kv store dir %s", dir)
+	}
+
+	s := &Store{
+		dir:                 dir,
+		compactionThreshold: compactionThreshold,
+		index:               make(map[string]valuePointer),
+	}
+
+	// a leftover from a compaction that crashed before the rename is incomplete by definition
+	os.Remove(filepath.Join(dir, snapshotFilename+".tmp"))
+
+	snapshot, err := os.OpenFile(filepath.Join(dir, snapshotFilename), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open kv store snapshot")
+	}
+	s.snapshot = snapshot
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFilename), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		snapshot.Close()
+		return nil, errors.Wrap(err, "failed to open kv store write-ahead log")
+	}
+	s.wal = wal
+
+	if err := s.load(); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) load() error {
+	snapshotInfo, err := s.snapshot.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat kv store snapshot")
+	}
+	walInfo, err := s.wal.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat kv store write-ahead log")
+	}
+
+	// the snapshot is only ever replaced atomically, so any corruption in it is fatal
+	if _, err := scanFile(s.snapshot, snapshotInfo.Size(), s.applyBatch); err != nil {
+		return errors.Wrap(err, "kv store snapshot is corrupt")
+	}
+
+	// the log may have a torn tail if we crashed in the middle of a write, which is discarded; a bad record followed
+	// by more data is corruption rather than a crash and fails the open instead of discarding later batches
+	validSize, err := scanFile(s.wal, walInfo.Size(), s.applyBatch)
+	if err != nil {
+		if !recordRunsToEndOfFile(s.wal, validSize, walInfo.Size()) {
+			return errors.Wrapf(err, "kv store write-ahead log is corrupt at offset %d before its last record", validSize)
+		}
+		if err := s.wal.Truncate(validSize); err != nil {
+			return errors.Wrapf(err, "failed to truncate kv store write-ahead log to %d bytes", validSize)
+		}
+		if err := s.wal.Sync(); err != nil {
+			return errors.Wrap(err, "failed to sync kv store write-ahead log")
+		}
+	}
+	s.walOffset = validSize
+
+	return nil
+}
+
+func (s *Store) applyBatch(file *os.File, payloadOffset int64, ops []*op) {
+	for _, o := range ops {
+		if o.delete {
+			delete(s.index, string(o.key))
+		} else {
+			s.index[string(o.key)] = valuePointer{
+				file:   file,
+				offset: payloadOffset + o.valueOffset,
+				size:   uint32(len(o.value)),
+			}
+		}
+	}
+}
+
+func (s *Store) Get(key []byte) ([]byte, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.get(string(key))
+}
+
+func (s *Store) get(key string) ([]byte, bool, error) {
+	ptr, ok := s.index[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	value := make([]byte, ptr.size)
+	if _, err := ptr.file.ReadAt(value, ptr.offset); err != nil {
+		return nil, false, errors.Wrapf(err, "failed to read value of key %x", key)
+	}
+	return value, true, nil
+}
+
+func (s *Store) Has(key []byte) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, ok := s.index[string(key)]
+	return ok
+}
+
+// Write applies all the operations of the batch atomically, they are all durable once it returns
+func (s *Store) Write(batch *Batch) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	record := encodeRecord(batch.ops)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.wal.WriteAt(record, s.walOffset); err != nil {
+		return errors.Wrap(err, "failed to append to kv store write-ahead log")
+	}
+	if err := s.wal.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync kv store write-ahead log")
+	}
+
+	s.applyBatch(s.wal, s.walOffset+recordHeaderSize, batch.ops)
+	s.walOffset += int64(len(record))
+
+	return nil
+}
+
+// NeedsCompaction is true once the write-ahead log has grown beyond the compaction threshold
+func (s *Store) NeedsCompaction() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.compactionThreshold > 0 && s.walOffset > s.compactionThreshold
+}
+
+// Keys returns all keys with the given prefix in lexicographical order
+func (s *Store) Keys(prefix []byte) [][]byte {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.keys(prefix)
+}
+
+func (s *Store) keys(prefix []byte) [][]byte {
+	var keys [][]byte
+	for key := range s.index {
+		if bytes.HasPrefix([]byte(key), prefix) {
+			keys = append(keys, []byte(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	return keys
+}
+
+// Iterate calls fn for every key with the given prefix in lexicographical order, stopping on the first error
+func (s *Store) Iterate(prefix []byte, fn func(key []byte, value []byte) error) error {
+	for _, key := range s.Keys(prefix) {
+		value, ok, err := s.Get(key)
+		if err != nil {
+			return err
+		}
+		if !ok { // deleted while we were iterating
+			continue
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compact writes all live entries into a fresh snapshot and resets the write-ahead log
+func (s *Store) Compact() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.compact()
+}
+
+func (s *Store) compact() error {
+	tmpPath := filepath.Join(s.dir, snapshotFilename+".tmp")
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to create kv store snapshot")
+	}
+
+	newIndex := make(map[string]valuePointer, len(s.index))
+	offset := int64(0)
+	var batch []*op
+	batchSize := 0
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		record := encodeRecord(batch)
+		if _, err := tmp.WriteAt(record, offset); err != nil {
+			return err
+		}
+		for _, o := range batch {
+			newIndex[string(o.key)] = valuePointer{file: tmp, offset: offset + recordHeaderSize + o.valueOffset, size: uint32(len(o.value))}
+		}
+		offset += int64(len(record))
+		batch = nil
+		batchSize = 0
+		return nil
+	}
+
+	for _, key := range s.keys(nil) {
+		value, _, err := s.get(string(key))
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		batch = append(batch, &op{key: key, value: value})
+		batchSize += len(key) + len(value)
+		if batchSize >= snapshotBatchSize {
+			if err := flush(); err != nil {
+				tmp.Close()
+				return errors.Wrap(err, "failed to write kv store snapshot")
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to write kv store snapshot")
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to sync kv store snapshot")
+	}
+	if err := os.Rename(tmpPath, filepath.Join(s.dir, snapshotFilename)); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to replace kv store snapshot")
+	}
+	syncDir(s.dir)
+
+	// from here on the new snapshot contains everything, replaying the old log on top of it is harmless
+	if err := s.wal.Truncate(0); err != nil {
+		return errors.Wrap(err, "failed to reset kv store write-ahead log")
+	}
+	if err := s.wal.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync kv store write-ahead log")
+	}
+
+	s.snapshot.Close()
+	s.snapshot = tmp
+	s.index = newIndex
+	s.walOffset = 0
+
+	return nil
+}
+
+func (s *Store) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result error
+	if s.wal != nil {
+		result = s.wal.Close()
+	}
+	if s.snapshot != nil {
+		if err := s.snapshot.Close(); err != nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
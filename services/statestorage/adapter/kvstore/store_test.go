@@ -0,0 +1,148 @@
+package kvstore
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withStoreDir(t *testing.T, f func(dir string)) {
+	dir, err := ioutil.TempDir("", "kvstore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	f(dir)
+}
+
+func requireValue(t *testing.T, s *Store, key string, expected string) {
+	value, ok, err := s.Get([]byte(key))
+	require.NoError(t, err)
+	require.True(t, ok, "key %s should exist", key)
+	require.Equal(t, expected, string(value))
+}
+
+func requireMissing(t *testing.T, s *Store, key string) {
+	_, ok, err := s.Get([]byte(key))
+	require.NoError(t, err)
+	require.False(t, ok, "key %s should not exist", key)
+}
+
+func TestStore_WritesAreVisibleAfterReopen(t *testing.T) {
+	withStoreDir(t, func(dir string) {
+		s, err := Open(dir, DefaultCompactionThreshold)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Write(NewBatch().Put([]byte("a"), []byte("1")).Put([]byte("b"), []byte("2"))))
+		require.NoError(t, s.Write(NewBatch().Put([]byte("a"), []byte("3")).Delete([]byte("b"))))
+		requireValue(t, s, "a", "3")
+		requireMissing(t, s, "b")
+		require.NoError(t, s.Close())
+
+		reopened, err := Open(dir, DefaultCompactionThreshold)
+		require.NoError(t, err)
+		defer reopened.Close()
+		requireValue(t, reopened, "a", "3")
+		requireMissing(t, reopened, "b")
+	})
+}
+
+func TestStore_CompactionKeepsOnlyLiveEntries(t *testing.T) {
+	withStoreDir(t, func(dir string) {
+		s, err := Open(dir, 100)
+		require.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			require.NoError(t, s.Write(NewBatch().Put([]byte("counter"), []byte{byte(i)}).Put([]byte{'k', byte(i)}, []byte("v"))))
+		}
+		require.NoError(t, s.Write(NewBatch().Delete([]byte{'k', 0})))
+		require.True(t, s.NeedsCompaction(), "log should have grown beyond the threshold")
+
+		require.NoError(t, s.Compact())
+		require.False(t, s.NeedsCompaction(), "log should be reset after compaction")
+		requireValue(t, s, "counter", string([]byte{19}))
+		requireMissing(t, s, string([]byte{'k', 0}))
+
+		require.NoError(t, s.Write(NewBatch().Put([]byte("after"), []byte("compaction"))))
+		require.NoError(t, s.Close())
+
+		reopened, err := Open(dir, 100)
+		require.NoError(t, err)
+		defer reopened.Close()
+		requireValue(t, reopened, "counter", string([]byte{19}))
+		requireValue(t, reopened, "after", "compaction")
+		require.Len(t, reopened.Keys([]byte{'k'}), 19)
+	})
+}
+
+func TestStore_TornWriteIsDiscardedOnReopen(t *testing.T) {
+	withStoreDir(t, func(dir string) {
+		s, err := Open(dir, DefaultCompactionThreshold)
+		require.NoError(t, err)
+		require.NoError(t, s.Write(NewBatch().Put([]byte("a"), []byte("1"))))
+		require.NoError(t, s.Write(NewBatch().Put([]byte("b"), []byte("2")).Put([]byte("c"), []byte("3"))))
+		require.NoError(t, s.Close())
+
+		path := filepath.Join(dir, walFilename)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, os.Truncate(path, info.Size()-3))
+
+		reopened, err := Open(dir, DefaultCompactionThreshold)
+		require.NoError(t, err)
+		requireValue(t, reopened, "a", "1")
+		requireMissing(t, reopened, "b") // the whole batch is discarded, not just the torn operation
+		requireMissing(t, reopened, "c")
+
+		require.NoError(t, reopened.Write(NewBatch().Put([]byte("d"), []byte("4"))))
+		require.NoError(t, reopened.Close())
+
+		again, err := Open(dir, DefaultCompactionThreshold)
+		require.NoError(t, err)
+		defer again.Close()
+		requireValue(t, again, "d", "4")
+	})
+}
+
+func TestStore_CorruptRecordBeforeTheLastOneFailsOpen(t *testing.T) {
+	withStoreDir(t, func(dir string) {
+		s, err := Open(dir, DefaultCompactionThreshold)
+		require.NoError(t, err)
+		first := NewBatch().Put([]byte("a"), []byte("1"))
+		require.NoError(t, s.Write(first))
+		require.NoError(t, s.Write(NewBatch().Put([]byte("b"), []byte("2"))))
+		require.NoError(t, s.Write(NewBatch().Put([]byte("c"), []byte("3"))))
+		require.NoError(t, s.Close())
+
+		path := filepath.Join(dir, walFilename)
+		wal, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		secondPayload := len(encodeRecord(first.ops)) + recordHeaderSize
+		wal[secondPayload] ^= 0xff
+		require.NoError(t, ioutil.WriteFile(path, wal, 0644))
+
+		_, err = Open(dir, DefaultCompactionThreshold)
+		require.Error(t, err, "a corrupt record followed by valid ones should not be truncated away")
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.EqualValues(t, len(wal), info.Size(), "write-ahead log should be left untouched")
+	})
+}
+
+func TestStore_IterateInKeyOrder(t *testing.T) {
+	withStoreDir(t, func(dir string) {
+		s, err := Open(dir, DefaultCompactionThreshold)
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.NoError(t, s.Write(NewBatch().Put([]byte("p/b"), []byte("2")).Put([]byte("p/a"), []byte("1")).Put([]byte("q/c"), []byte("3"))))
+
+		var keys []string
+		require.NoError(t, s.Iterate([]byte("p/"), func(key []byte, value []byte) error {
+			keys = append(keys, string(key))
+			return nil
+		}))
+		require.Equal(t, []string{"p/a", "p/b"}, keys)
+	})
+}
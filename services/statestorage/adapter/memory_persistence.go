@@ -17,6 +17,7 @@ type InMemoryStatePersistence struct {
 	height     primitives.BlockHeight
 	ts         primitives.TimestampNano
 	merkleRoot primitives.MerkleSha256
+	nodeStore  *merkle.InMemoryNodeStore
 }
 
 func NewInMemoryStatePersistence() *InMemoryStatePersistence {
@@ -29,14 +30,20 @@ func NewInMemoryStatePersistence() *InMemoryStatePersistence {
 		height:     0,
 		ts:         0,
 		merkleRoot: merkleRoot,
+		nodeStore:  merkle.NewInMemoryNodeStore(),
 	}
 }
 
+func (sp *InMemoryStatePersistence) NodeStore() merkle.NodeStore {
+	return sp.nodeStore
+}
+
 func (sp *InMemoryStatePersistence) Write(height primitives.BlockHeight, ts primitives.TimestampNano, root primitives.MerkleSha256, diff ChainState) error {
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
 	sp.height = height
+	sp.ts = ts
 	sp.merkleRoot = root
 
 	for contract, records := range diff {
@@ -75,6 +82,20 @@ func (sp *InMemoryStatePersistence) ReadMetadata() (primitives.BlockHeight, prim
 	return sp.height, sp.ts, sp.merkleRoot, nil
 }
 
+func (sp *InMemoryStatePersistence) Each(callback func(contract primitives.ContractName, record *protocol.StateRecord) error) error {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+
+	for contract, records := range sp.fullState {
+		for _, record := range records {
+			if err := callback(contract, record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (sp *InMemoryStatePersistence) Dump() string {
 	output := strings.Builder{}
 	output.WriteString("{")
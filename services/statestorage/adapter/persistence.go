@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"github.com/orbs-network/orbs-network-go/services/statestorage/merkle"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 )
@@ -12,4 +13,6 @@ type StatePersistence interface {
 	Write(height primitives.BlockHeight, ts primitives.TimestampNano, root primitives.MerkleSha256, diff ChainState) error
 	Read(contract primitives.ContractName, key string) (*protocol.StateRecord, bool, error)
 	ReadMetadata() (primitives.BlockHeight, primitives.TimestampNano, primitives.MerkleSha256, error)
+	Each(callback func(contract primitives.ContractName, record *protocol.StateRecord) error) error
+	NodeStore() merkle.NodeStore
 }
@@ -11,6 +11,8 @@ import (
 
 const trieRadix = 16
 
+const DefaultNodeCacheSize = 10000
+
 func GetZeroValueHash() primitives.Sha256 {
 	return hash.CalcSha256([]byte{})
 }
@@ -32,11 +34,12 @@ func (pn *ProofNode) hash() primitives.MerkleSha256 {
 }
 
 type node struct {
-	path     []byte // TODO  parity bool
-	value    primitives.Sha256
-	hash     primitives.MerkleSha256
-	branches [trieRadix]*node
-	isLeaf   bool
+	path       []byte // TODO  parity bool
+	value      primitives.Sha256
+	hash       primitives.MerkleSha256
+	branches   [trieRadix]*node
+	isLeaf     bool
+	unresolved bool // only the hash is known, the node itself is in the node store
 }
 
 func createNode(path []byte, valueHash primitives.Sha256, isLeaf bool) *node {
@@ -49,6 +52,13 @@ func createNode(path []byte, valueHash primitives.Sha256, isLeaf bool) *node {
 	}
 }
 
+func createNodeReference(hash primitives.MerkleSha256) *node {
+	return &node{
+		hash:       hash,
+		unresolved: true,
+	}
+}
+
 func createEmptyNode() *node {
 	tmp := createNode([]byte{}, zeroValueHash, true)
 	tmp.hash = tmp.serialize().hash()
@@ -75,6 +85,20 @@ func (n *node) serialize() *ProofNode {
 	return sn
 }
 
+// a shallow copy that refers to its children by hash, so a root can be kept around without pinning its whole trie in memory
+func (n *node) detach() *node {
+	result := n.clone()
+	result.hash = n.hash
+	if !result.isLeaf {
+		for arc, child := range result.branches {
+			if child != nil {
+				result.branches[arc] = createNodeReference(child.hash)
+			}
+		}
+	}
+	return result
+}
+
 func (n *node) clone() *node {
 	newBranches := [trieRadix]*node{}
 	if !n.isLeaf {
@@ -109,13 +133,60 @@ type MerkleDiff struct {
 type MerkleDiffs []*MerkleDiff
 
 type Forest struct {
-	mutex sync.Mutex
-	roots []*node
+	mutex      sync.Mutex
+	roots      []*node
+	writeMutex sync.Mutex // serializes changes to the node store
+	store      NodeStore
+	cache      *nodeCache
 }
 
 func NewForest() (*Forest, primitives.MerkleSha256) {
-	var emptyNode = createEmptyNode()
-	return &Forest{sync.Mutex{}, []*node{emptyNode}}, emptyNode.hash
+	f, emptyRoot, err := NewForestWithNodeStore(NewInMemoryNodeStore(), DefaultNodeCacheSize)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create an in memory merkle forest: %s", err)) // the in memory store never fails
+	}
+	return f, emptyRoot
+}
+
+// NewForestWithNodeStore loads the roots kept in the store, a new store starts with a single empty root
+func NewForestWithNodeStore(store NodeStore, cacheSize int) (*Forest, primitives.MerkleSha256, error) {
+	emptyNode := createEmptyNode()
+	f := &Forest{
+		store: store,
+		cache: newNodeCache(cacheSize),
+	}
+
+	value, ok, err := store.Get(rootsKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read merkle roots")
+	}
+
+	if !ok {
+		batch := newNodeBatch(f)
+		if err := batch.retain(emptyNode); err != nil {
+			return nil, nil, err
+		}
+		batch.setRoots([]*node{emptyNode})
+		if err := batch.write(); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to initialize merkle node store")
+		}
+		f.roots = []*node{emptyNode}
+		return f, emptyNode.hash, nil
+	}
+
+	rootHashes, err := decodeRoots(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, rootHash := range rootHashes {
+		root, err := f.loadNode(rootHash)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to load merkle root")
+		}
+		f.roots = append(f.roots, root)
+	}
+
+	return f, emptyNode.hash, nil
 }
 
 func (f *Forest) findRoot(rootHash primitives.MerkleSha256) *node {
@@ -138,6 +209,38 @@ func (f *Forest) appendRoot(root *node) {
 	f.roots = append(f.roots, root)
 }
 
+func (f *Forest) HasRoot(rootHash primitives.MerkleSha256) bool {
+	return f.findRoot(rootHash) != nil
+}
+
+func (f *Forest) loadNode(hash primitives.MerkleSha256) (*node, error) {
+	if n, ok := f.cache.get(hash.KeyForMap()); ok {
+		return n, nil
+	}
+
+	value, ok, err := f.store.Get(nodeKey(hash))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read merkle node %s", hash)
+	}
+	if !ok {
+		return nil, errors.Errorf("missing merkle node %s", hash)
+	}
+
+	n, err := decodeNode(hash, value)
+	if err != nil {
+		return nil, err
+	}
+	f.cache.add(hash.KeyForMap(), n)
+	return n, nil
+}
+
+func (f *Forest) resolve(n *node) (*node, error) {
+	if n == nil || !n.unresolved {
+		return n, nil
+	}
+	return f.loadNode(n.hash)
+}
+
 func (f *Forest) GetProof(rootHash primitives.MerkleSha256, path []byte) (Proof, error) {
 	path = toHex(path)
 	current := f.findRoot(rootHash)
@@ -152,7 +255,11 @@ func (f *Forest) GetProof(rootHash primitives.MerkleSha256, path []byte) (Proof,
 		p = p[len(current.path):]
 
 		if len(p) != 0 {
-			if current = current.branches[p[0]]; current != nil {
+			var err error
+			if current, err = f.resolve(current.branches[p[0]]); err != nil {
+				return nil, err
+			}
+			if current != nil {
 				proof = append(proof, current.serialize())
 				p = p[1:]
 			} else {
@@ -195,13 +302,68 @@ func (f *Forest) Verify(rootHash primitives.MerkleSha256, proof Proof, path []by
 	return false, errors.Errorf("proof incomplete ")
 }
 
-func (f *Forest) Forget(rootHash primitives.MerkleSha256) {
+// Forget drops a single occurrence of the root, nodes that are no longer reachable from any root are deleted from the store
+func (f *Forest) Forget(rootHash primitives.MerkleSha256) error {
+	f.writeMutex.Lock()
+	defer f.writeMutex.Unlock()
+
+	roots, found := f.removeRoot(rootHash)
+	if !found {
+		return nil
+	}
+
+	batch := newNodeBatch(f)
+	if err := batch.release(rootHash); err != nil {
+		return errors.Wrapf(err, "failed to release nodes of merkle root %s", rootHash)
+	}
+	batch.setRoots(roots)
+	return batch.write()
+}
+
+// ForgetAllBut drops every root other than the given one, used on boot to discard revisions that were never committed
+func (f *Forest) ForgetAllBut(rootHash primitives.MerkleSha256) error {
+	if !f.HasRoot(rootHash) {
+		return errors.Errorf("unknown root %s", rootHash)
+	}
+
+	for {
+		var other primitives.MerkleSha256
+		f.mutex.Lock()
+		for _, root := range f.roots {
+			if !root.hash.Equal(rootHash) {
+				other = root.hash
+				break
+			}
+		}
+		remaining := len(f.roots)
+		f.mutex.Unlock()
+
+		if other == nil {
+			if remaining > 1 { // the same root may appear several times, only one occurrence is needed
+				if err := f.Forget(rootHash); err != nil {
+					return err
+				}
+				continue
+			}
+			return nil
+		}
+		if err := f.Forget(other); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *Forest) removeRoot(rootHash primitives.MerkleSha256) ([]*node, bool) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
+	if len(f.roots) == 0 {
+		return f.roots, false
+	}
+
 	if f.roots[0].hash.Equal(rootHash) { // optimization for most likely use
 		f.roots = f.roots[1:]
-		return
+		return f.roots, true
 	}
 
 	found := false
@@ -214,9 +376,13 @@ func (f *Forest) Forget(rootHash primitives.MerkleSha256) {
 		}
 	}
 	f.roots = newRoots
+	return f.roots, found
 }
 
 func (f *Forest) Update(rootMerkle primitives.MerkleSha256, diffs MerkleDiffs) (primitives.MerkleSha256, error) {
+	f.writeMutex.Lock()
+	defer f.writeMutex.Unlock()
+
 	root := f.findRoot(rootMerkle)
 	if root == nil {
 		return nil, errors.Errorf("must start with valid root")
@@ -224,25 +390,47 @@ func (f *Forest) Update(rootMerkle primitives.MerkleSha256, diffs MerkleDiffs) (
 
 	sandbox := make(dirtyNodes)
 
+	var err error
 	for _, diff := range diffs {
-		root = f.travelUpdateAndMark(nil, 0, root, toHex(diff.Key), diff.Value, sandbox)
+		if root, err = f.travelUpdateAndMark(nil, 0, root, toHex(diff.Key), diff.Value, sandbox); err != nil {
+			return nil, err
+		}
 	}
 
-	root = f.travelCollapseAndHash(root, sandbox)
+	if root, err = f.travelCollapseAndHash(root, sandbox); err != nil {
+		return nil, err
+	}
 	if root == nil { // special case we got back to empty merkle
 		root = createEmptyNode()
 	}
 
-	f.appendRoot(root)
+	f.mutex.Lock()
+	roots := append(append(make([]*node, 0, len(f.roots)+1), f.roots...), root)
+	f.mutex.Unlock()
+
+	batch := newNodeBatch(f)
+	if err := batch.retain(root); err != nil {
+		return nil, errors.Wrap(err, "failed to store merkle nodes")
+	}
+	batch.setRoots(roots)
+	if err := batch.write(); err != nil {
+		return nil, errors.Wrap(err, "failed to store merkle nodes")
+	}
+
+	f.appendRoot(root.detach())
 	return root.hash, nil
 }
 
-func (f *Forest) travelUpdateAndMark(parent *node, arc byte, current *node, path []byte, valueHash primitives.Sha256, sandbox dirtyNodes) *node {
+func (f *Forest) travelUpdateAndMark(parent *node, arc byte, current *node, path []byte, valueHash primitives.Sha256, sandbox dirtyNodes) (*node, error) {
+	current, err := f.resolve(current)
+	if err != nil {
+		return nil, err
+	}
 	current = f.getOrClone(current, parent, arc, sandbox)
 
 	if bytes.Equal(current.path, path) { // path reached exactly
 		current.value = valueHash
-		return current
+		return current, nil
 	}
 
 	if bytes.HasPrefix(path, current.path) { // current is next part of path
@@ -254,7 +442,9 @@ func (f *Forest) travelUpdateAndMark(parent *node, arc byte, current *node, path
 			//fmt.Printf("ch %d\n", childArc)
 			childPath := path[len(current.path)+1:]
 			if childNode := current.branches[childArc]; childNode != nil {
-				current.branches[childArc] = f.travelUpdateAndMark(current, childArc, childNode, childPath, valueHash, sandbox)
+				if current.branches[childArc], err = f.travelUpdateAndMark(current, childArc, childNode, childPath, valueHash, sandbox); err != nil {
+					return nil, err
+				}
 			} else if valueHash.Equal(zeroValueHash) {
 				// set to empty value cannot create new children, do nothing
 			} else {
@@ -264,7 +454,7 @@ func (f *Forest) travelUpdateAndMark(parent *node, arc byte, current *node, path
 				sandbox.set(current, childArc)
 			}
 		}
-		return current
+		return current, nil
 	}
 
 	if bytes.HasPrefix(current.path, path) { // "insert" a valued node along the path
@@ -275,7 +465,7 @@ func (f *Forest) travelUpdateAndMark(parent *node, arc byte, current *node, path
 		sandbox.set(newParent, childArc)
 
 		current.path = current.path[len(path)+1:]
-		return newParent
+		return newParent, nil
 	}
 
 	// new node is a brother of mine so i create a common parent too
@@ -296,7 +486,7 @@ func (f *Forest) travelUpdateAndMark(parent *node, arc byte, current *node, path
 	newParent.branches[newChildArc] = newChild
 	sandbox.set(newParent, newChildArc)
 
-	return newParent
+	return newParent, nil
 }
 
 func (f *Forest) getOrClone(current *node, parent *node, arc byte, sandbox dirtyNodes) *node {
@@ -311,13 +501,17 @@ func (f *Forest) getOrClone(current *node, parent *node, arc byte, sandbox dirty
 	return actual
 }
 
-func (f *Forest) travelCollapseAndHash(current *node, sandbox dirtyNodes) *node {
+func (f *Forest) travelCollapseAndHash(current *node, sandbox dirtyNodes) (*node, error) {
 	nChildren := 0
 	aChild := 0
 
 	if !current.isLeaf {
 		for arc := range sandbox[current] {
-			current.branches[arc] = f.travelCollapseAndHash(current.branches[arc], sandbox)
+			child, err := f.travelCollapseAndHash(current.branches[arc], sandbox)
+			if err != nil {
+				return nil, err
+			}
+			current.branches[arc] = child
 		}
 
 		// check if i have any children left: count+save last one
@@ -333,9 +527,12 @@ func (f *Forest) travelCollapseAndHash(current *node, sandbox dirtyNodes) *node
 	// if i have no value ...
 	if !current.hasValue() {
 		if current.isLeaf { // prune empty leaf node
-			return nil
+			return nil, nil
 		} else if nChildren == 1 { // fold up only child
-			child := current.branches[aChild]
+			child, err := f.resolve(current.branches[aChild])
+			if err != nil {
+				return nil, err
+			}
 			combinedPath := append(current.path, byte(aChild))
 			combinedPath = append(combinedPath, child.path...)
 			current = child.clone()
@@ -344,7 +541,7 @@ func (f *Forest) travelCollapseAndHash(current *node, sandbox dirtyNodes) *node
 	}
 
 	current.hash = current.serialize().hash()
-	return current
+	return current, nil
 }
 
 func toHex(s []byte) []byte {
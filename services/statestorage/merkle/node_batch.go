@@ -0,0 +1,110 @@
+package merkle
+
+import (
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/pkg/errors"
+)
+
+// collects the changes of a single forest operation so they can be written to the node store atomically
+// a node is reference counted by its parents and by the roots pointing at it, and is deleted once nothing refers to it
+type nodeBatch struct {
+	forest  *Forest
+	changes map[string][]byte
+}
+
+func newNodeBatch(f *Forest) *nodeBatch {
+	return &nodeBatch{
+		forest:  f,
+		changes: make(map[string][]byte),
+	}
+}
+
+func (b *nodeBatch) get(key []byte) ([]byte, bool, error) {
+	if value, ok := b.changes[string(key)]; ok {
+		return value, value != nil, nil
+	}
+	return b.forest.store.Get(key)
+}
+
+func (b *nodeBatch) refCount(hash primitives.MerkleSha256) (uint32, bool, error) {
+	value, ok, err := b.get(refCountKey(hash))
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	count, err := decodeRefCount(value)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "merkle node %s", hash)
+	}
+	return count, true, nil
+}
+
+// adds a reference to the node, storing it (and referencing its children) if it is not in the store yet
+func (b *nodeBatch) retain(n *node) error {
+	count, exists, err := b.refCount(n.hash)
+	if err != nil {
+		return err
+	}
+	if exists {
+		b.changes[string(refCountKey(n.hash))] = encodeRefCount(count + 1)
+		return nil
+	}
+	if n.unresolved {
+		return errors.Errorf("missing merkle node %s", n.hash)
+	}
+
+	b.changes[string(nodeKey(n.hash))] = encodeNode(n)
+	b.changes[string(refCountKey(n.hash))] = encodeRefCount(1)
+	if !n.isLeaf {
+		for _, child := range n.branches {
+			if child != nil {
+				if err := b.retain(child); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// removes a reference to the node, deleting it (and releasing its children) once it is no longer referenced
+func (b *nodeBatch) release(hash primitives.MerkleSha256) error {
+	count, exists, err := b.refCount(hash)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("missing merkle node %s", hash)
+	}
+	if count > 1 {
+		b.changes[string(refCountKey(hash))] = encodeRefCount(count - 1)
+		return nil
+	}
+
+	n, err := b.forest.loadNode(hash)
+	if err != nil {
+		return err
+	}
+
+	b.changes[string(nodeKey(hash))] = nil
+	b.changes[string(refCountKey(hash))] = nil
+	b.forest.cache.remove(hash.KeyForMap())
+
+	if !n.isLeaf {
+		for _, child := range n.branches {
+			if child != nil {
+				if err := b.release(child.hash); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (b *nodeBatch) setRoots(roots []*node) {
+	b.changes[string(rootsKey)] = encodeRoots(roots)
+}
+
+func (b *nodeBatch) write() error {
+	return b.forest.store.Write(b.changes)
+}
@@ -0,0 +1,77 @@
+package merkle
+
+import (
+	"container/list"
+	"sync"
+)
+
+// an LRU cache of nodes loaded from the node store, nodes are immutable once stored so they never need to be invalidated on update
+type nodeCache struct {
+	mutex   sync.Mutex
+	maxSize int
+	items   map[string]*list.Element
+	order   *list.List
+}
+
+type nodeCacheEntry struct {
+	key  string
+	node *node
+}
+
+func newNodeCache(maxSize int) *nodeCache {
+	return &nodeCache{
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *nodeCache) get(key string) (*node, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*nodeCacheEntry).node, true
+}
+
+func (c *nodeCache) add(key string, n *node) {
+	if c.maxSize <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&nodeCacheEntry{key: key, node: n})
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*nodeCacheEntry).key)
+	}
+}
+
+func (c *nodeCache) remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+}
+
+func (c *nodeCache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,153 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/pkg/errors"
+)
+
+// key layout in the node store
+var (
+	rootsKey = []byte("roots")
+)
+
+const (
+	nodeKeyPrefix     = byte('n')
+	refCountKeyPrefix = byte('c')
+
+	nodeFlagLeaf = byte(1)
+)
+
+func nodeKey(hash primitives.MerkleSha256) []byte {
+	return append([]byte{nodeKeyPrefix}, hash...)
+}
+
+func refCountKey(hash primitives.MerkleSha256) []byte {
+	return append([]byte{refCountKeyPrefix}, hash...)
+}
+
+// a stored node is [flags][path][value][branches bitmap][hash of every non empty branch], children are referenced by hash only
+func encodeNode(n *node) []byte {
+	result := make([]byte, 0, 1+len(n.path)+len(n.value)+2+trieRadix*(len(n.hash)+1)+8)
+
+	flags := byte(0)
+	if n.isLeaf {
+		flags |= nodeFlagLeaf
+	}
+	result = append(result, flags)
+	result = appendSizedBytes(result, n.path)
+	result = appendSizedBytes(result, n.value)
+
+	bitmap := uint16(0)
+	if !n.isLeaf {
+		for arc, child := range n.branches {
+			if child != nil {
+				bitmap |= 1 << uint(arc)
+			}
+		}
+	}
+	result = append(result, byte(bitmap>>8), byte(bitmap))
+	for arc := 0; arc < trieRadix; arc++ {
+		if bitmap&(1<<uint(arc)) != 0 {
+			result = appendSizedBytes(result, n.branches[arc].hash)
+		}
+	}
+
+	return result
+}
+
+func decodeNode(hash primitives.MerkleSha256, data []byte) (*node, error) {
+	if len(data) < 1 {
+		return nil, errors.Errorf("merkle node %s is empty", hash)
+	}
+
+	n := &node{
+		hash:   hash,
+		isLeaf: data[0]&nodeFlagLeaf != 0,
+	}
+	offset := 1
+
+	var path, value []byte
+	var err error
+	if path, offset, err = readSizedBytes(data, offset); err != nil {
+		return nil, errors.Wrapf(err, "merkle node %s has a corrupt path", hash)
+	}
+	if value, offset, err = readSizedBytes(data, offset); err != nil {
+		return nil, errors.Wrapf(err, "merkle node %s has a corrupt value", hash)
+	}
+	n.path = path
+	n.value = primitives.Sha256(value)
+
+	if len(data) < offset+2 {
+		return nil, errors.Errorf("merkle node %s has a corrupt branches bitmap", hash)
+	}
+	bitmap := uint16(data[offset])<<8 | uint16(data[offset+1])
+	offset += 2
+
+	for arc := 0; arc < trieRadix; arc++ {
+		if bitmap&(1<<uint(arc)) == 0 {
+			continue
+		}
+		var childHash []byte
+		if childHash, offset, err = readSizedBytes(data, offset); err != nil {
+			return nil, errors.Wrapf(err, "merkle node %s has a corrupt branch %d", hash, arc)
+		}
+		n.branches[arc] = createNodeReference(primitives.MerkleSha256(childHash))
+	}
+
+	return n, nil
+}
+
+func encodeRefCount(count uint32) []byte {
+	result := make([]byte, 4)
+	binary.BigEndian.PutUint32(result, count)
+	return result
+}
+
+func decodeRefCount(data []byte) (uint32, error) {
+	if len(data) != 4 {
+		return 0, errors.Errorf("corrupt reference count of %d bytes", len(data))
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func encodeRoots(roots []*node) []byte {
+	var result []byte
+	for _, root := range roots {
+		result = appendSizedBytes(result, root.hash)
+	}
+	return result
+}
+
+func decodeRoots(data []byte) ([]primitives.MerkleSha256, error) {
+	var result []primitives.MerkleSha256
+	for offset := 0; offset < len(data); {
+		var hash []byte
+		var err error
+		if hash, offset, err = readSizedBytes(data, offset); err != nil {
+			return nil, errors.Wrap(err, "corrupt list of merkle roots")
+		}
+		result = append(result, primitives.MerkleSha256(hash))
+	}
+	return result, nil
+}
+
+func appendSizedBytes(buf []byte, value []byte) []byte {
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(value)))
+	buf = append(buf, size[:n]...)
+	return append(buf, value...)
+}
+
+func readSizedBytes(data []byte, offset int) ([]byte, int, error) {
+	size, n := binary.Uvarint(data[offset:])
+	if n <= 0 {
+		return nil, 0, errors.New("invalid size")
+	}
+	offset += n
+	if uint64(len(data)-offset) < size {
+		return nil, 0, errors.Errorf("expected %d bytes but only %d left", size, len(data)-offset)
+	}
+	end := offset + int(size)
+	return data[offset:end:end], end, nil
+}
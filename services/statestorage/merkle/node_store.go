@@ -0,0 +1,52 @@
+package merkle
+
+import (
+	"sync"
+)
+
+// NodeStore is where the forest keeps its nodes (addressed by their hash) along with their reference counts
+type NodeStore interface {
+	Get(key []byte) ([]byte, bool, error)
+	// Write applies all changes atomically, a nil value deletes the key
+	Write(changes map[string][]byte) error
+}
+
+type InMemoryNodeStore struct {
+	mutex sync.RWMutex
+	items map[string][]byte
+}
+
+func NewInMemoryNodeStore() *InMemoryNodeStore {
+	return &InMemoryNodeStore{
+		items: make(map[string][]byte),
+	}
+}
+
+func (s *InMemoryNodeStore) Get(key []byte) ([]byte, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok := s.items[string(key)]
+	return value, ok, nil
+}
+
+func (s *InMemoryNodeStore) Write(changes map[string][]byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, value := range changes {
+		if value == nil {
+			delete(s.items, key)
+		} else {
+			s.items[key] = value
+		}
+	}
+	return nil
+}
+
+func (s *InMemoryNodeStore) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.items)
+}
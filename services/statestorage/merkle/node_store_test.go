@@ -0,0 +1,90 @@
+package merkle
+
+import (
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newForestWithStoreForTests(t *testing.T, store NodeStore) (*Forest, primitives.MerkleSha256) {
+	f, root, err := NewForestWithNodeStore(store, 0) // no cache, every node is read back from the store
+	require.NoError(t, err, "failed to open forest")
+	return f, root
+}
+
+func TestForestWithoutCacheMatchesInMemoryForest(t *testing.T) {
+	f1, root1 := NewForest()
+	f2, root2 := newForestWithStoreForTests(t, NewInMemoryNodeStore())
+
+	for _, keyValues := range [][]string{
+		{"abcd", "1", "abce", "2", "12", "3"},
+		{"abcd", "4", "1234", "5"},
+		{"abce", "", "ab", "6"},
+		{"abcd", "", "12", ""},
+	} {
+		root1 = updateStringEntries(f1, root1, keyValues...)
+		root2 = updateStringEntries(f2, root2, keyValues...)
+		require.Equal(t, root1, root2, "forest backed by a node store should produce the same roots")
+	}
+
+	proof := getProofRequireHeight(t, f2, root2, "1234", 2)
+	verifyProof(t, f2, root2, proof, "1234", "5", true)
+}
+
+func TestForgetDeletesUnreachableNodes(t *testing.T) {
+	store := NewInMemoryNodeStore()
+	f, emptyRoot := newForestWithStoreForTests(t, store)
+
+	root1 := updateStringEntries(f, emptyRoot, "abcd", "1", "abce", "2", "1234", "3")
+	root2 := updateStringEntries(f, root1, "abcd", "4", "12", "5")
+	require.NoError(t, f.Forget(emptyRoot))
+	require.NoError(t, f.Forget(root1))
+
+	expectedStore := NewInMemoryNodeStore()
+	expected, expectedEmptyRoot := newForestWithStoreForTests(t, expectedStore)
+	expectedRoot := updateStringEntries(expected, expectedEmptyRoot, "abcd", "4", "abce", "2", "1234", "3", "12", "5")
+	require.NoError(t, expected.Forget(expectedEmptyRoot))
+
+	require.Equal(t, expectedRoot, root2)
+	require.Equal(t, expectedStore.Len(), store.Len(), "only nodes reachable from the remaining root should be kept")
+
+	require.NoError(t, f.Forget(root2))
+	require.Zero(t, store.Len(), "store should be empty once all roots are forgotten")
+}
+
+func TestSameRootTwiceIsKeptUntilForgottenTwice(t *testing.T) {
+	store := NewInMemoryNodeStore()
+	f, emptyRoot := newForestWithStoreForTests(t, store)
+
+	root1 := updateStringEntries(f, emptyRoot, "abcd", "1")
+	root2 := updateStringEntries(f, root1)
+	require.Equal(t, root1, root2, "an empty update should not change the root")
+
+	require.NoError(t, f.Forget(root1))
+	getProofRequireHeight(t, f, root2, "abcd", 1)
+
+	require.NoError(t, f.Forget(root2))
+	_, err := f.GetProof(root2, hexStringToBytes("abcd"))
+	require.Error(t, err, "root should be gone after forgetting both occurrences")
+}
+
+func TestForestReloadsFromNodeStore(t *testing.T) {
+	store := NewInMemoryNodeStore()
+	f, emptyRoot := newForestWithStoreForTests(t, store)
+	root1 := updateStringEntries(f, emptyRoot, "abcd", "1", "abce", "2")
+	root2 := updateStringEntries(f, root1, "abcd", "3")
+
+	reloaded, _ := newForestWithStoreForTests(t, store)
+	require.True(t, reloaded.HasRoot(emptyRoot))
+	require.True(t, reloaded.HasRoot(root1))
+	require.True(t, reloaded.HasRoot(root2))
+
+	require.NoError(t, reloaded.ForgetAllBut(root1))
+	require.False(t, reloaded.HasRoot(root2), "newer roots should be dropped")
+
+	proof := getProofRequireHeight(t, reloaded, root1, "abcd", 2)
+	verifyProof(t, reloaded, root1, proof, "abcd", "1", true)
+
+	root3 := updateStringEntries(reloaded, root1, "abcd", "3")
+	require.Equal(t, root2, root3, "reloaded forest should continue from the same trie")
+}
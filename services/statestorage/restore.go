@@ -0,0 +1,90 @@
+package statestorage
+
+import (
+	"github.com/orbs-network/orbs-network-go/services/statestorage/adapter"
+	"github.com/orbs-network/orbs-network-go/services/statestorage/merkle"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+const restoreBatchSize = 1000
+
+// the forest resumes from the root of the persisted state, revisions that were never persisted are dropped
+func openMerkleForest(persistence adapter.StatePersistence, cacheSize int) (*merkle.Forest, error) {
+	height, _, persistedRoot, err := persistence.ReadMetadata()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read state metadata")
+	}
+
+	forest, emptyRoot, err := merkle.NewForestWithNodeStore(persistence.NodeStore(), cacheSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load merkle forest")
+	}
+
+	if !forest.HasRoot(persistedRoot) {
+		// the trie was not stored alongside this state, rebuild it from the state itself
+		if err := rebuildMerkleForest(forest, emptyRoot, persistence); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := forest.ForgetAllBut(persistedRoot); err != nil {
+		return nil, errors.Wrapf(err, "failed to resume merkle forest from block height %d", height)
+	}
+	return forest, nil
+}
+
+func rebuildMerkleForest(forest *merkle.Forest, emptyRoot primitives.MerkleSha256, persistence adapter.StatePersistence) error {
+	height, _, expectedRoot, err := persistence.ReadMetadata()
+	if err != nil {
+		return errors.Wrap(err, "failed to read state metadata")
+	}
+	if !forest.HasRoot(emptyRoot) {
+		return errors.Errorf("cannot rebuild merkle tree for block height %d, the node store holds an unrelated trie", height)
+	}
+
+	root := emptyRoot
+	batch := make(adapter.ChainState)
+	batchSize := 0
+
+	flush := func() error {
+		if batchSize == 0 {
+			return nil
+		}
+		newRoot, err := forest.Update(root, toMerkleInput(batch))
+		if err != nil {
+			return err
+		}
+		if err := forest.Forget(root); err != nil { // only the rebuilt root should be left in the forest
+			return err
+		}
+		root = newRoot
+		batch = make(adapter.ChainState)
+		batchSize = 0
+		return nil
+	}
+
+	err = persistence.Each(func(contract primitives.ContractName, record *protocol.StateRecord) error {
+		if _, ok := batch[contract]; !ok {
+			batch[contract] = make(adapter.ContractState)
+		}
+		batch[contract][string(record.Key())] = record
+		batchSize++
+		if batchSize >= restoreBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to rebuild merkle tree for block height %d", height)
+	}
+
+	if !root.Equal(expectedRoot) {
+		return errors.Errorf("persisted state is inconsistent, rebuilt merkle root %s does not match persisted root %s for block height %d", root, expectedRoot, height)
+	}
+	return nil
+}
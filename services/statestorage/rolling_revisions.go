@@ -13,7 +13,7 @@ import (
 
 type merkleRevisions interface {
 	Update(rootMerkle primitives.MerkleSha256, diffs merkle.MerkleDiffs) (primitives.MerkleSha256, error)
-	Forget(rootHash primitives.MerkleSha256)
+	Forget(rootHash primitives.MerkleSha256) error
 }
 
 type revisionDiff struct {
@@ -106,12 +106,16 @@ func (ls *rollingRevisions) evictRevisions() error {
 		if err != nil {
 			return err
 		}
-		ls.merkle.Forget(ls.persistedRoot)
+		previousRoot := ls.persistedRoot
 
 		ls.persistedHeight = d.height
 		ls.persistedTs = d.ts
 		ls.persistedRoot = d.merkleRoot
 		ls.revisions = ls.revisions[1:]
+
+		if err := ls.merkle.Forget(previousRoot); err != nil {
+			return errors.Wrapf(err, "failed to forget merkle root of block height %d", d.height-1)
+		}
 	}
 	return nil
 }
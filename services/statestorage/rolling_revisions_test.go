@@ -222,6 +222,12 @@ func (spm *StatePersistenceMock) Read(contract primitives.ContractName, key stri
 func (spm *StatePersistenceMock) ReadMetadata() (primitives.BlockHeight, primitives.TimestampNano, primitives.MerkleSha256, error) {
 	return 0, 0, primitives.MerkleSha256{}, nil
 }
+func (spm *StatePersistenceMock) Each(callback func(contract primitives.ContractName, record *protocol.StateRecord) error) error {
+	return nil
+}
+func (spm *StatePersistenceMock) NodeStore() merkle.NodeStore {
+	return merkle.NewInMemoryNodeStore()
+}
 
 type MerkleMock struct {
 	mock.Mock
@@ -243,6 +249,7 @@ func (mm *MerkleMock) Update(rootMerkle primitives.MerkleSha256, diffs merkle.Me
 	ret := mm.Mock.Called(rootMerkle, diffs)
 	return ret.Get(0).(primitives.MerkleSha256), ret.Error(1)
 }
-func (mm *MerkleMock) Forget(rootHash primitives.MerkleSha256) {
+func (mm *MerkleMock) Forget(rootHash primitives.MerkleSha256) error {
 	mm.Mock.Called(rootHash)
+	return nil
 }
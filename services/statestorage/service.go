@@ -6,7 +6,6 @@ import (
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/statestorage/adapter"
-	"github.com/orbs-network/orbs-network-go/services/statestorage/merkle"
 	"github.com/orbs-network/orbs-network-go/synchronization"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -26,16 +25,23 @@ type service struct {
 	revisions *rollingRevisions
 }
 
-func NewStateStorage(config config.StateStorageConfig, persistence adapter.StatePersistence, logger log.BasicLogger) services.StateStorage {
+func NewStateStorage(config config.StateStorageConfig, persistence adapter.StatePersistence, parentLogger log.BasicLogger) services.StateStorage {
+	logger := parentLogger.WithTags(LogTag)
 
-	forest, _ := merkle.NewForest()
+	forest, err := openMerkleForest(persistence, int(config.StateStorageMerkleCacheSize()))
+	if err != nil {
+		logger.Error("failed to restore state from persistence", log.Error(err))
+		panic(err)
+	}
+
+	revisions := newRollingRevisions(persistence, int(config.StateStorageHistorySnapshotNum()), forest)
 	return &service{
 		config:       config,
-		blockTracker: synchronization.NewBlockTracker(0, uint16(config.BlockTrackerGraceDistance())),
-		logger:       logger.WithTags(LogTag),
+		blockTracker: synchronization.NewBlockTracker(uint64(revisions.getCurrentHeight()), uint16(config.BlockTrackerGraceDistance())),
+		logger:       logger,
 
 		mutex:     sync.RWMutex{},
-		revisions: newRollingRevisions(persistence, int(config.StateStorageHistorySnapshotNum()), forest),
+		revisions: revisions,
 	}
 }
 
@@ -39,6 +39,13 @@ func newStateStorageDriverWithGrace(numOfStateRevisionsToRetain uint32, graceBlo
 	return &Driver{service: statestorage.NewStateStorage(cfg, p, logger)}
 }
 
+func newStateStorageDriverWithPersistence(numOfStateRevisionsToRetain uint32, p adapter.StatePersistence) *Driver {
+	cfg := config.ForStateStorageTest(numOfStateRevisionsToRetain, 0, 0)
+	logger := log.GetLogger().WithOutput() // a mute logger
+
+	return &Driver{service: statestorage.NewStateStorage(cfg, p, logger)}
+}
+
 func (d *Driver) ReadSingleKey(ctx context.Context, contract string, key string) ([]byte, error) {
 	h, _, _ := d.GetBlockHeightAndTimestamp(ctx)
 	return d.ReadSingleKeyFromRevision(ctx, h, contract, key)
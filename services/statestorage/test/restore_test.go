@@ -0,0 +1,48 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/services/statestorage/adapter"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRestartResumesFromPersistedState(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		p := adapter.NewInMemoryStatePersistence()
+		d := newStateStorageDriverWithPersistence(1, p)
+
+		d.CommitValuePairsAtHeight(ctx, 1, "foo", "a", "1")
+		d.CommitValuePairsAtHeight(ctx, 2, "foo", "b", "2")
+		d.CommitValuePairsAtHeight(ctx, 3, "bar", "a", "3")
+
+		rootAtTwo, err := d.service.GetStateHash(ctx, &services.GetStateHashInput{BlockHeight: 2})
+		require.NoError(t, err)
+		rootAtThree, err := d.service.GetStateHash(ctx, &services.GetStateHashInput{BlockHeight: 3})
+		require.NoError(t, err)
+
+		restarted := newStateStorageDriverWithPersistence(1, p)
+
+		h, _, err := restarted.GetBlockHeightAndTimestamp(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, h, "restarted state storage should resume from the last persisted block height")
+
+		root, err := restarted.service.GetStateHash(ctx, &services.GetStateHashInput{BlockHeight: 2})
+		require.NoError(t, err)
+		require.EqualValues(t, rootAtTwo.StateRootHash, root.StateRootHash, "restored merkle root should match the persisted one")
+
+		value, err := restarted.ReadSingleKey(ctx, "foo", "b")
+		require.NoError(t, err)
+		require.EqualValues(t, "2", value)
+
+		out, err := restarted.CommitValuePairsAtHeight(ctx, 3, "bar", "a", "3")
+		require.NoError(t, err)
+		require.EqualValues(t, 4, out.NextDesiredBlockHeight)
+
+		root, err = restarted.service.GetStateHash(ctx, &services.GetStateHashInput{BlockHeight: 3})
+		require.NoError(t, err)
+		require.EqualValues(t, rootAtThree.StateRootHash, root.StateRootHash, "replaying a block after restart should reach the same merkle root")
+	})
+}
@@ -0,0 +1,162 @@
+package adapter
+
+import (
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/statestorage/adapter/kvstore"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"sort"
+	"sync"
+)
+
+var LogTag = log.String("adapter", "transaction-pool-journal")
+
+// entries are keyed by tx hash, the sequence number in the value keeps the order in which transactions were appended
+type FilesystemPendingTransactionJournal struct {
+	db     *kvstore.Store
+	logger log.BasicLogger
+
+	mutex        sync.Mutex
+	nextSequence uint64
+}
+
+func NewFilesystemPendingTransactionJournal(conf config.FilesystemPendingTransactionJournalConfig, parentLogger log.BasicLogger) (*FilesystemPendingTransactionJournal, error) {
+	logger := parentLogger.WithTags(LogTag)
+
+	db, err := kvstore.Open(conf.TransactionPoolJournalDataDir(), kvstore.DefaultCompactionThreshold)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open transaction pool journal in %s", conf.TransactionPoolJournalDataDir())
+	}
+
+	j := &FilesystemPendingTransactionJournal{
+		db:     db,
+		logger: logger,
+	}
+
+	entries, err := j.readAll()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.sequence >= j.nextSequence {
+			j.nextSequence = entry.sequence + 1
+		}
+	}
+	logger.Info("loaded transaction pool journal from disk", log.Int("pending-transactions", len(entries)), log.String("path", conf.TransactionPoolJournalDataDir()))
+
+	return j, nil
+}
+
+func (j *FilesystemPendingTransactionJournal) Append(transaction *protocol.SignedTransaction, gatewayPublicKey primitives.Ed25519PublicKey) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	txHash := digest.CalcTxHash(transaction.Transaction())
+	if err := j.write(kvstore.NewBatch().Put(txHash, encodeJournalEntry(j.nextSequence, transaction, gatewayPublicKey))); err != nil {
+		return errors.Wrapf(err, "failed to journal transaction %s", txHash)
+	}
+	j.nextSequence++
+	return nil
+}
+
+func (j *FilesystemPendingTransactionJournal) Remove(txHashes ...primitives.Sha256) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	batch := kvstore.NewBatch()
+	for _, txHash := range txHashes {
+		if j.db.Has(txHash) {
+			batch.Delete(txHash)
+		}
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	if err := j.write(batch); err != nil {
+		return errors.Wrapf(err, "failed to remove %d transactions from journal", batch.Len())
+	}
+	return nil
+}
+
+func (j *FilesystemPendingTransactionJournal) write(batch *kvstore.Batch) error {
+	if err := j.db.Write(batch); err != nil {
+		return err
+	}
+
+	if j.db.NeedsCompaction() {
+		if err := j.db.Compact(); err != nil {
+			// the data itself is safely in the log, we'll try again on the next write
+			j.logger.Error("failed to snapshot transaction pool journal", log.Error(err))
+		}
+	}
+	return nil
+}
+
+func (j *FilesystemPendingTransactionJournal) ReadAll() ([]*JournalEntry, error) {
+	sorted, err := j.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*JournalEntry, len(sorted))
+	for i, entry := range sorted {
+		entries[i] = entry.JournalEntry
+	}
+	return entries, nil
+}
+
+func (j *FilesystemPendingTransactionJournal) readAll() ([]*sequencedJournalEntry, error) {
+	var entries []*sequencedJournalEntry
+	err := j.db.Iterate(nil, func(key []byte, value []byte) error {
+		entry, err := decodeJournalEntry(value)
+		if err != nil {
+			return errors.Wrapf(err, "corrupt journal entry of transaction %x", key)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(a, b int) bool { return entries[a].sequence < entries[b].sequence })
+	return entries, nil
+}
+
+func (j *FilesystemPendingTransactionJournal) Close() error {
+	return j.db.Close()
+}
+
+// [sequence][gateway public key size][gateway public key][signed transaction]
+func encodeJournalEntry(sequence uint64, transaction *protocol.SignedTransaction, gatewayPublicKey primitives.Ed25519PublicKey) []byte {
+	result := make([]byte, 12, 12+len(gatewayPublicKey)+len(transaction.Raw()))
+	binary.LittleEndian.PutUint64(result, sequence)
+	binary.LittleEndian.PutUint32(result[8:], uint32(len(gatewayPublicKey)))
+	result = append(result, gatewayPublicKey...)
+	result = append(result, transaction.Raw()...)
+	return result
+}
+
+func decodeJournalEntry(value []byte) (*sequencedJournalEntry, error) {
+	if len(value) < 12 {
+		return nil, errors.Errorf("entry of %d bytes is too short", len(value))
+	}
+	sequence := binary.LittleEndian.Uint64(value)
+	keySize := binary.LittleEndian.Uint32(value[8:])
+	if uint32(len(value)-12) < keySize {
+		return nil, errors.Errorf("gateway public key of %d bytes is truncated", keySize)
+	}
+
+	return &sequencedJournalEntry{
+		JournalEntry: &JournalEntry{
+			Transaction:      protocol.SignedTransactionReader(value[12+keySize:]),
+			GatewayPublicKey: primitives.Ed25519PublicKey(value[12 : 12+keySize]),
+		},
+		sequence: sequence,
+	}, nil
+}
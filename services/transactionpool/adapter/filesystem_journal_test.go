@@ -0,0 +1,90 @@
+package adapter
+
+import (
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func newFilesystemJournalForTests(t *testing.T, dir string) *FilesystemPendingTransactionJournal {
+	j, err := NewFilesystemPendingTransactionJournal(config.ForFilesystemPendingTransactionJournalTests(dir), log.GetLogger())
+	require.NoError(t, err, "failed to open transaction pool journal")
+	return j
+}
+
+func requireEntries(t *testing.T, j PendingTransactionJournal, expected ...*protocol.SignedTransaction) {
+	entries, err := j.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, entries, len(expected))
+	for i, entry := range entries {
+		require.Equal(t, expected[i].Raw(), entry.Transaction.Raw(), "entry %d should be in append order", i)
+	}
+}
+
+func TestFilesystemPendingTransactionJournal_EntriesSurviveReopen(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	gatewayPublicKey := keys.Ed25519KeyPairForTests(1).PublicKey()
+	tx1 := builders.TransferTransaction().Build()
+	tx2 := builders.TransferTransaction().Build()
+	tx3 := builders.TransferTransaction().Build()
+
+	j := newFilesystemJournalForTests(t, dir)
+	require.NoError(t, j.Append(tx1, gatewayPublicKey))
+	require.NoError(t, j.Append(tx2, gatewayPublicKey))
+	require.NoError(t, j.Append(tx3, gatewayPublicKey))
+	require.NoError(t, j.Remove(digest.CalcTxHash(tx2.Transaction())))
+	require.NoError(t, j.Close())
+
+	reopened := newFilesystemJournalForTests(t, dir)
+	defer reopened.Close()
+	requireEntries(t, reopened, tx1, tx3)
+
+	entries, err := reopened.ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, gatewayPublicKey, entries[0].GatewayPublicKey, "gateway public key should be journaled with the transaction")
+
+	tx4 := builders.TransferTransaction().Build()
+	require.NoError(t, reopened.Append(tx4, gatewayPublicKey))
+	requireEntries(t, reopened, tx1, tx3, tx4)
+}
+
+func TestFilesystemPendingTransactionJournal_RemovingUnknownTransactionIsANoop(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	j := newFilesystemJournalForTests(t, dir)
+	defer j.Close()
+
+	tx := builders.TransferTransaction().Build()
+	require.NoError(t, j.Remove(digest.CalcTxHash(tx.Transaction())))
+	requireEntries(t, j)
+}
+
+func TestFilesystemPendingTransactionJournal_RemovesSeveralTransactionsAtOnce(t *testing.T) {
+	dir := test.CreateTempDirForTest(t)
+	defer os.RemoveAll(dir)
+
+	j := newFilesystemJournalForTests(t, dir)
+	defer j.Close()
+
+	gatewayPublicKey := keys.Ed25519KeyPairForTests(1).PublicKey()
+	tx1 := builders.TransferTransaction().Build()
+	tx2 := builders.TransferTransaction().Build()
+	tx3 := builders.TransferTransaction().Build()
+	unknownTx := builders.TransferTransaction().Build()
+	require.NoError(t, j.Append(tx1, gatewayPublicKey))
+	require.NoError(t, j.Append(tx2, gatewayPublicKey))
+	require.NoError(t, j.Append(tx3, gatewayPublicKey))
+
+	require.NoError(t, j.Remove(digest.CalcTxHash(tx1.Transaction()), digest.CalcTxHash(unknownTx.Transaction()), digest.CalcTxHash(tx3.Transaction())))
+	requireEntries(t, j, tx2)
+}
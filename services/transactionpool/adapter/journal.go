@@ -0,0 +1,28 @@
+package adapter
+
+import (
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+)
+
+type JournalEntry struct {
+	Transaction      *protocol.SignedTransaction
+	GatewayPublicKey primitives.Ed25519PublicKey
+}
+
+type sequencedJournalEntry struct {
+	*JournalEntry
+	sequence uint64
+}
+
+// a write-ahead journal of the pending pool, so that transactions acknowledged as pending survive a restart
+type PendingTransactionJournal interface {
+	Append(transaction *protocol.SignedTransaction, gatewayPublicKey primitives.Ed25519PublicKey) error
+	Remove(txHashes ...primitives.Sha256) error // in a single write
+	ReadAll() ([]*JournalEntry, error)          // oldest first
+}
+
+// used on replay to drop journaled transactions that were committed in a block before the node went down
+type CommittedTransactionLookup interface {
+	IsTransactionCommitted(txHash primitives.Sha256, txTimestamp primitives.TimestampNano) bool
+}
@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"sort"
+	"sync"
+)
+
+type InMemoryPendingTransactionJournal struct {
+	mutex        sync.Mutex
+	entries      map[string]*sequencedJournalEntry
+	nextSequence uint64
+}
+
+func NewInMemoryPendingTransactionJournal() *InMemoryPendingTransactionJournal {
+	return &InMemoryPendingTransactionJournal{
+		entries: make(map[string]*sequencedJournalEntry),
+	}
+}
+
+func (j *InMemoryPendingTransactionJournal) Append(transaction *protocol.SignedTransaction, gatewayPublicKey primitives.Ed25519PublicKey) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.entries[digest.CalcTxHash(transaction.Transaction()).KeyForMap()] = &sequencedJournalEntry{
+		JournalEntry: &JournalEntry{Transaction: transaction, GatewayPublicKey: gatewayPublicKey},
+		sequence:     j.nextSequence,
+	}
+	j.nextSequence++
+	return nil
+}
+
+func (j *InMemoryPendingTransactionJournal) Remove(txHashes ...primitives.Sha256) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, txHash := range txHashes {
+		delete(j.entries, txHash.KeyForMap())
+	}
+	return nil
+}
+
+func (j *InMemoryPendingTransactionJournal) ReadAll() ([]*JournalEntry, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	sorted := make([]*sequencedJournalEntry, 0, len(j.entries))
+	for _, entry := range j.entries {
+		sorted = append(sorted, entry)
+	}
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].sequence < sorted[b].sequence })
+
+	entries := make([]*JournalEntry, len(sorted))
+	for i, entry := range sorted {
+		entries[i] = entry.JournalEntry
+	}
+	return entries, nil
+}
@@ -33,7 +33,7 @@ func (s *service) AddNewTransaction(ctx context.Context, input *services.AddNewT
 		return s.addTransactionOutputFor(nil, status), err
 	}
 
-	if _, err := s.pendingPool.add(input.SignedTransaction, s.config.NodePublicKey()); err != nil {
+	if _, err := s.addToPendingPool(input.SignedTransaction, s.config.NodePublicKey()); err != nil {
 		s.logger.Error("error adding transaction to pending pool", log.Error(err))
 		return s.addTransactionOutputFor(nil, err.TransactionStatus), err
 
@@ -46,13 +46,15 @@ func (s *service) AddNewTransaction(ctx context.Context, input *services.AddNewT
 
 func (s *service) validateSingleTransactionForPreOrder(ctx context.Context, transaction *protocol.SignedTransaction) error {
 	bh, _ := s.currentBlockHeightAndTime()
-	//TODO handle error from vm call
-	preOrderCheckResults, _ := s.virtualMachine.TransactionSetPreOrder(ctx, &services.TransactionSetPreOrderInput{
+	preOrderCheckResults, err := s.virtualMachine.TransactionSetPreOrder(ctx, &services.TransactionSetPreOrderInput{
 		SignedTransactions: Transactions{transaction},
 		BlockHeight:        bh,
 	})
+	if err != nil {
+		return errors.Wrap(err, "failed to run pre-order check")
+	}
 
-	if len(preOrderCheckResults.PreOrderResults) != 1 {
+	if preOrderCheckResults == nil || len(preOrderCheckResults.PreOrderResults) != 1 {
 		return errors.Errorf("expected exactly one result from pre-order check, got %+v", preOrderCheckResults)
 	}
 
@@ -24,8 +24,14 @@ func (s *service) CommitTransactionReceipts(ctx context.Context, input *services
 
 	var myReceipts []*protocol.TransactionReceipt
 
-	for _, receipt := range input.TransactionReceipts {
-		removedTx := s.pendingPool.remove(ctx, receipt.Txhash(), protocol.TRANSACTION_STATUS_COMMITTED)
+	txHashes := make([]primitives.Sha256, len(input.TransactionReceipts))
+	for i, receipt := range input.TransactionReceipts {
+		txHashes[i] = receipt.Txhash()
+	}
+	removedTxs := s.pendingPool.removeAll(ctx, txHashes, protocol.TRANSACTION_STATUS_COMMITTED)
+
+	for i, receipt := range input.TransactionReceipts {
+		removedTx := removedTxs[i]
 		if s.originatedFromMyPublicApi(removedTx) {
 			myReceipts = append(myReceipts, receipt)
 		}
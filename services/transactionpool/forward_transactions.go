@@ -44,10 +44,17 @@ func (s *service) HandleForwardedTransactions(ctx context.Context, input *gossip
 		return nil, errors.Errorf("invalid signature in relay message from sender %s", sender.SenderPublicKey())
 	}
 
+	s.signatureVerifier.verifyBatch(input.Message.SignedTransactions)
+
 	for _, tx := range input.Message.SignedTransactions {
 		txHash := digest.CalcTxHash(tx.Transaction())
+		if !tx.Transaction().Signer().IsSchemeEddsa() || !s.signatureVerifier.verify(tx) {
+			logger.Info("dropping forwarded transaction with invalid signature", log.String("flow", "checkpoint"), log.Transaction(txHash), log.Stringable("sender", sender.SenderPublicKey()))
+			continue
+		}
+
 		logger.Info("adding forwarded transaction to the pool", log.String("flow", "checkpoint"), log.Stringable("transaction", tx), log.Transaction(txHash))
-		if _, err := s.pendingPool.add(tx, sender.SenderPublicKey()); err != nil {
+		if _, err := s.addToPendingPool(tx, sender.SenderPublicKey()); err != nil {
 			logger.Error("error adding forwarded transaction to pending pool", log.Error(err), log.Stringable("transaction", tx), log.Transaction(txHash))
 		}
 	}
@@ -2,10 +2,11 @@ package transactionpool
 
 import (
 	"context"
-	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"time"
 )
 
 func (s *service) GetTransactionsForOrdering(ctx context.Context, input *services.GetTransactionsForOrderingInput) (*services.GetTransactionsForOrderingOutput, error) {
@@ -18,39 +19,52 @@ func (s *service) GetTransactionsForOrdering(ctx context.Context, input *service
 	}
 
 	out := &services.GetTransactionsForOrderingOutput{}
-	transactions := s.pendingPool.getBatch(input.MaxNumberOfTransactions, input.MaxTransactionsSetSizeKb*1024)
-	vctx := s.createValidationContext()
-
-	transactionsForPreOrder := make(Transactions, 0, input.MaxNumberOfTransactions)
-	for _, tx := range transactions {
-		txHash := digest.CalcTxHash(tx.Transaction())
-		if err := vctx.validateTransaction(tx); err != nil {
-			s.logger.Info("dropping invalid transaction", log.Error(err), log.String("flow", "checkpoint"), log.Transaction(txHash))
-			s.pendingPool.remove(ctx, txHash, err.TransactionStatus)
-		} else if alreadyCommitted := s.committedPool.get(txHash); alreadyCommitted != nil {
-			s.logger.Info("dropping committed transaction", log.String("flow", "checkpoint"), log.Transaction(txHash))
-			s.pendingPool.remove(ctx, txHash, protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED)
+	if s.preOrderBackoff.isBackingOff(time.Now()) {
+		s.logger.Info("virtual machine recently failed to pre-order transactions, returning an empty batch", log.String("flow", "checkpoint"))
+		return out, nil
+	}
+
+	batch := s.pendingPool.getPendingBatch(input.MaxNumberOfTransactions, input.MaxTransactionsSetSizeKb*1024)
+	validationErrors := s.createValidationContext().validateBatch(batch)
+
+	batchForPreOrder := make([]*pendingTransaction, 0, len(batch))
+	for i, pendingTx := range batch {
+		if err := validationErrors[i]; err != nil {
+			s.logger.Info("dropping invalid transaction", log.Error(err), log.String("flow", "checkpoint"), log.Transaction(pendingTx.txHash))
+			s.pendingPool.remove(ctx, pendingTx.txHash, err.TransactionStatus)
+		} else if alreadyCommitted := s.committedPool.get(pendingTx.txHash); alreadyCommitted != nil {
+			s.logger.Info("dropping committed transaction", log.String("flow", "checkpoint"), log.Transaction(pendingTx.txHash))
+			s.pendingPool.remove(ctx, pendingTx.txHash, protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED)
 
 		} else {
-			transactionsForPreOrder = append(transactionsForPreOrder, tx)
+			batchForPreOrder = append(batchForPreOrder, pendingTx)
 		}
 	}
 
-	//TODO handle error from vm
 	bh, _ := s.currentBlockHeightAndTime()
-	preOrderResults, _ := s.virtualMachine.TransactionSetPreOrder(ctx, &services.TransactionSetPreOrderInput{
-		SignedTransactions: transactionsForPreOrder,
+	preOrderResults, err := s.virtualMachine.TransactionSetPreOrder(ctx, &services.TransactionSetPreOrderInput{
+		SignedTransactions: transactionsOf(batchForPreOrder),
 		BlockHeight:        bh,
 	})
+	if err == nil && (preOrderResults == nil || len(preOrderResults.PreOrderResults) != len(batchForPreOrder)) {
+		err = errors.Errorf("expected %d results from pre-order check, got %+v", len(batchForPreOrder), preOrderResults)
+	}
+
+	// the transactions are not at fault, so they stay pending and an empty batch lets consensus carry on without them
+	if err != nil {
+		backoff := s.preOrderBackoff.failed(time.Now())
+		s.metrics.preOrderFailures.Inc()
+		s.logger.Error("failed to pre-order transactions, returning an empty batch", log.Error(err), log.Int("batch-size", len(batchForPreOrder)), log.Stringable("backoff", backoff))
+		return out, nil
+	}
+	s.preOrderBackoff.succeeded()
 
-	for i := range transactionsForPreOrder {
-		tx := transactionsForPreOrder[i]
+	for i, pendingTx := range batchForPreOrder {
 		if preOrderResults.PreOrderResults[i] == protocol.TRANSACTION_STATUS_PRE_ORDER_VALID {
-			out.SignedTransactions = append(out.SignedTransactions, tx)
+			out.SignedTransactions = append(out.SignedTransactions, pendingTx.transaction)
 		} else {
-			txHash := digest.CalcTxHash(tx.Transaction()) //TODO we calculate TX hash again even though we calculated it above while iterating. Consider memoization.
-			s.logger.Info("dropping transaction that failed pre-order validation", log.String("flow", "checkpoint"), log.Transaction(txHash))
-			s.pendingPool.remove(ctx, txHash, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER)
+			s.logger.Info("dropping transaction that failed pre-order validation", log.String("flow", "checkpoint"), log.Transaction(pendingTx.txHash))
+			s.pendingPool.remove(ctx, pendingTx.txHash, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER)
 		}
 	}
 
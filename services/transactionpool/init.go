@@ -5,6 +5,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
 	"github.com/orbs-network/orbs-network-go/synchronization"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -17,11 +18,18 @@ import (
 func NewTransactionPool(ctx context.Context,
 	gossip gossiptopics.TransactionRelay,
 	virtualMachine services.VirtualMachine,
+	journal adapter.PendingTransactionJournal,
+	committedTransactions adapter.CommittedTransactionLookup,
 	config config.TransactionPoolConfig,
 	logger log.BasicLogger,
 	metricFactory metric.Factory) services.TransactionPool {
 
-	pendingPool := NewPendingPool(config.TransactionPoolPendingPoolSizeInBytes, metricFactory)
+	orderingPolicy, err := newOrderingPolicy(config.TransactionPoolOrderingPolicy(), config.TransactionPoolPrioritySigners())
+	if err != nil {
+		logger.Error("falling back to fifo transaction ordering", log.Error(err))
+		orderingPolicy = &fifoOrderingPolicy{}
+	}
+	pendingPool := NewPendingPool(config.TransactionPoolPendingPoolSizeInBytes, config.TransactionPoolMaxPendingTransactionsPerSigner, orderingPolicy, metricFactory)
 	committedPool := NewCommittedPool(metricFactory)
 
 	txForwarder := NewTransactionForwarder(ctx, logger, config, gossip)
@@ -29,6 +37,7 @@ func NewTransactionPool(ctx context.Context,
 	s := &service{
 		gossip:         gossip,
 		virtualMachine: virtualMachine,
+		journal:        journal,
 		config:         config,
 		logger:         logger.WithTags(LogTag),
 
@@ -36,12 +45,20 @@ func NewTransactionPool(ctx context.Context,
 		committedPool:        committedPool,
 		blockTracker:         synchronization.NewBlockTracker(0, uint16(config.BlockTrackerGraceDistance())),
 		transactionForwarder: txForwarder,
+		signatureVerifier:    newSignatureVerifier(int(config.TransactionPoolSignatureCacheSize())),
+		preOrderBackoff:      newPreOrderBackoff(config.TransactionPoolPreOrderFailureMinBackoff, config.TransactionPoolPreOrderFailureMaxBackoff),
+		metrics:              newServiceMetrics(metricFactory),
 	}
 
 	s.mu.lastCommittedBlockTimestamp = primitives.TimestampNano(time.Now().UnixNano()) // this is so that we do not reject transactions on startup, before any block has been committed
 
+	// a nil journal means the pending pool is not persisted across restarts
+	if journal != nil {
+		s.replayJournal(committedTransactions)
+	}
+
 	gossip.RegisterTransactionRelayHandler(s)
-	pendingPool.onTransactionRemoved = s.onTransactionError
+	pendingPool.onTransactionsRemoved = s.onTransactionsRemoved
 
 	startCleaningProcess(ctx, config.TransactionPoolCommittedPoolClearExpiredInterval, config.TransactionPoolTransactionExpirationWindow, s.committedPool, logger)
 	startCleaningProcess(ctx, config.TransactionPoolPendingPoolClearExpiredInterval, config.TransactionPoolTransactionExpirationWindow, s.pendingPool, logger)
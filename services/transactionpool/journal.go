@@ -0,0 +1,98 @@
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+)
+
+// re-adds the transactions that were pending when the node went down, dropping the ones that expired or got committed meanwhile
+func (s *service) replayJournal(committedTransactions adapter.CommittedTransactionLookup) {
+	entries, err := s.journal.ReadAll()
+	if err != nil {
+		s.logger.Error("failed to read transaction pool journal, transactions pending before the restart are lost", log.Error(err))
+		return
+	}
+
+	var dropped []primitives.Sha256
+	for _, entry := range entries {
+		txHash := digest.CalcTxHash(entry.Transaction.Transaction())
+
+		if reason := s.reasonToDropJournaledTransaction(entry.Transaction, txHash, committedTransactions); reason != "" {
+			s.logger.Info("dropping journaled transaction", log.Transaction(txHash), log.String("reason", reason))
+			dropped = append(dropped, txHash)
+			continue
+		}
+
+		if _, err := s.pendingPool.add(entry.Transaction, entry.GatewayPublicKey); err != nil {
+			s.logger.Info("dropping journaled transaction", log.Transaction(txHash), log.Error(err))
+			dropped = append(dropped, txHash)
+			continue
+		}
+	}
+
+	if len(dropped) > 0 {
+		s.removeFromJournal(dropped...)
+	}
+
+	s.logger.Info("replayed transaction pool journal", log.Int("replayed-transactions", len(entries)-len(dropped)), log.Int("dropped-transactions", len(dropped)))
+}
+
+func (s *service) reasonToDropJournaledTransaction(transaction *protocol.SignedTransaction, txHash primitives.Sha256, committedTransactions adapter.CommittedTransactionLookup) string {
+	if err := s.createValidationContext().validateTransaction(transaction); err != nil {
+		return err.Error()
+	}
+
+	if s.committedPool.get(txHash) != nil {
+		return "already in committed pool"
+	}
+
+	if committedTransactions != nil && committedTransactions.IsTransactionCommitted(txHash, transaction.Transaction().Timestamp()) {
+		return "already in a persisted block"
+	}
+
+	return ""
+}
+
+// the transaction is journaled before it enters the pending pool and outside of its lock, a transaction that can not be
+// journaled is rejected since it would not survive a restart after being acknowledged as pending
+func (s *service) addToPendingPool(transaction *protocol.SignedTransaction, gatewayPublicKey primitives.Ed25519PublicKey) (primitives.Sha256, *ErrTransactionRejected) {
+	if s.journal == nil {
+		return s.pendingPool.add(transaction, gatewayPublicKey)
+	}
+
+	txHash := digest.CalcTxHash(transaction.Transaction())
+	if err := s.journal.Append(transaction, gatewayPublicKey); err != nil {
+		s.logger.Error("failed to journal transaction, rejecting it", log.Error(err), log.Transaction(txHash))
+		return nil, &ErrTransactionRejected{
+			TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_CONGESTION,
+			Expected:          log.String("journal", "appended"),
+			Actual:            log.Error(err),
+		}
+	}
+
+	key, rejected := s.pendingPool.add(transaction, gatewayPublicKey)
+	// a duplicate shares its journal entry with the transaction that is already pending
+	if rejected != nil && rejected.TransactionStatus != protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_PENDING && !s.pendingPool.has(transaction) {
+		s.removeFromJournal(txHash)
+	}
+	return key, rejected
+}
+
+func (s *service) onTransactionsRemoved(ctx context.Context, txHashes []primitives.Sha256, removalReason protocol.TransactionStatus) {
+	if s.journal != nil {
+		s.removeFromJournal(txHashes...)
+	}
+	for _, txHash := range txHashes {
+		s.onTransactionError(ctx, txHash, removalReason)
+	}
+}
+
+func (s *service) removeFromJournal(txHashes ...primitives.Sha256) {
+	if err := s.journal.Remove(txHashes...); err != nil {
+		s.logger.Error("failed to remove transactions from journal, they may be replayed on restart", log.Error(err), log.Int("num-transactions", len(txHashes)))
+	}
+}
@@ -0,0 +1,126 @@
+package transactionpool
+
+import (
+	"encoding/hex"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+)
+
+const (
+	ORDERING_POLICY_FIFO                = "fifo"
+	ORDERING_POLICY_ROUND_ROBIN_SIGNERS = "round-robin-signers"
+	ORDERING_POLICY_PRIORITY_SIGNERS    = "priority-signers"
+)
+
+// decides the order in which pending transactions are offered for ordering, getPendingBatch takes from the front
+// until the block is full so the policy decides which transactions make it into the block
+type orderingPolicy interface {
+	order(oldestFirst []*pendingTransaction) []*pendingTransaction
+}
+
+// prioritySigners is a comma separated list of hex encoded signer public keys, only used by the priority-signers policy
+func newOrderingPolicy(name string, prioritySigners string) (orderingPolicy, error) {
+	switch name {
+	case ORDERING_POLICY_FIFO, "":
+		return &fifoOrderingPolicy{}, nil
+	case ORDERING_POLICY_ROUND_ROBIN_SIGNERS:
+		return &roundRobinSignersOrderingPolicy{}, nil
+	case ORDERING_POLICY_PRIORITY_SIGNERS:
+		priority, err := prioritySignersPriority(prioritySigners)
+		if err != nil {
+			return nil, err
+		}
+		return newPriorityOrderingPolicy(priority, &roundRobinSignersOrderingPolicy{}), nil
+	default:
+		return nil, errors.Errorf("unknown transaction pool ordering policy %s", name)
+	}
+}
+
+type fifoOrderingPolicy struct{}
+
+func (p *fifoOrderingPolicy) order(oldestFirst []*pendingTransaction) []*pendingTransaction {
+	return oldestFirst
+}
+
+// takes one transaction of every signer in turn so a single signer can not fill a block, signers take turns
+// by the arrival of their oldest pending transaction and the transactions of each signer keep their arrival order
+type roundRobinSignersOrderingPolicy struct{}
+
+func (p *roundRobinSignersOrderingPolicy) order(oldestFirst []*pendingTransaction) []*pendingTransaction {
+	var signers []string
+	transactionsBySigner := make(map[string][]*pendingTransaction)
+	for _, pendingTx := range oldestFirst {
+		if _, found := transactionsBySigner[pendingTx.signer]; !found {
+			signers = append(signers, pendingTx.signer)
+		}
+		transactionsBySigner[pendingTx.signer] = append(transactionsBySigner[pendingTx.signer], pendingTx)
+	}
+
+	ordered := make([]*pendingTransaction, 0, len(oldestFirst))
+	for round := 0; len(ordered) < len(oldestFirst); round++ {
+		for _, signer := range signers {
+			if round < len(transactionsBySigner[signer]) {
+				ordered = append(ordered, transactionsBySigner[signer][round])
+			}
+		}
+	}
+	return ordered
+}
+
+// higher priority transactions go first, transactions of equal priority keep the order of the wrapped policy,
+// the protocol has no fee field yet so the priority is supplied by the caller
+type priorityOrderingPolicy struct {
+	priority   func(tx *protocol.SignedTransaction) uint64
+	tieBreaker orderingPolicy
+}
+
+func newPriorityOrderingPolicy(priority func(tx *protocol.SignedTransaction) uint64, tieBreaker orderingPolicy) *priorityOrderingPolicy {
+	return &priorityOrderingPolicy{
+		priority:   priority,
+		tieBreaker: tieBreaker,
+	}
+}
+
+func (p *priorityOrderingPolicy) order(oldestFirst []*pendingTransaction) []*pendingTransaction {
+	ordered := append([]*pendingTransaction{}, p.tieBreaker.order(oldestFirst)...)
+	priorities := make(map[*pendingTransaction]uint64)
+	for _, pendingTx := range ordered {
+		priorities[pendingTx] = p.priority(pendingTx.transaction)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return priorities[ordered[i]] > priorities[ordered[j]]
+	})
+	return ordered
+}
+
+// transactions of the listed signers come before all others
+func prioritySignersPriority(prioritySigners string) (func(tx *protocol.SignedTransaction) uint64, error) {
+	signers := make(map[string]bool)
+	for _, encoded := range strings.Split(prioritySigners, ",") {
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			continue
+		}
+		publicKey, err := hex.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid transaction pool priority signer %s", encoded)
+		}
+		signers[string(publicKey)] = true
+	}
+	if len(signers) == 0 {
+		return nil, errors.New("no transaction pool priority signers configured")
+	}
+
+	return func(tx *protocol.SignedTransaction) uint64 {
+		if signers[string(tx.Transaction().Signer().Eddsa().SignerPublicKey())] {
+			return 1
+		}
+		return 0
+	}, nil
+}
+
+func signerKeyOf(tx *protocol.SignedTransaction) string {
+	return string(tx.Transaction().Signer().Raw())
+}
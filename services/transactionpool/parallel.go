@@ -0,0 +1,32 @@
+package transactionpool
+
+import (
+	"runtime"
+	"sync"
+)
+
+// calls f with every index in [0, count) on at most runtime.NumCPU() goroutines and returns when all calls returned
+func forEachIndexOnBoundedWorkers(count int, f func(index int)) {
+	workers := runtime.NumCPU()
+	if count < workers {
+		workers = count
+	}
+
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				f(index)
+			}
+		}()
+	}
+
+	for index := 0; index < count; index++ {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,24 @@
+package transactionpool
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachIndexOnBoundedWorkersCallsEveryIndexOnce(t *testing.T) {
+	calls := make([]int32, 100)
+	forEachIndexOnBoundedWorkers(len(calls), func(index int) {
+		atomic.AddInt32(&calls[index], 1)
+	})
+
+	for index, count := range calls {
+		require.EqualValues(t, 1, count, "index %d should be called exactly once", index)
+	}
+}
+
+func TestForEachIndexOnBoundedWorkersWithNothingToDo(t *testing.T) {
+	forEachIndexOnBoundedWorkers(0, func(index int) {
+		require.Fail(t, "should not be called without indices")
+	})
+}
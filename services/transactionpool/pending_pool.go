@@ -4,6 +4,7 @@ import (
 	"container/list"
 	"context"
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -11,22 +12,29 @@ import (
 	"time"
 )
 
-type transactionRemovedListener func(ctx context.Context, txHash primitives.Sha256, reason protocol.TransactionStatus)
+type transactionsRemovedListener func(ctx context.Context, txHashes []primitives.Sha256, reason protocol.TransactionStatus)
 
-func NewPendingPool(pendingPoolSizeInBytes func() uint32, metricFactory metric.Factory) *pendingTxPool {
+func NewPendingPool(pendingPoolSizeInBytes func() uint32, maxPendingTransactionsPerSigner func() uint32, orderingPolicy orderingPolicy, metricFactory metric.Factory) *pendingTxPool {
 	return &pendingTxPool{
-		pendingPoolSizeInBytes: pendingPoolSizeInBytes,
-		transactionsByHash:     make(map[string]*pendingTransaction),
-		transactionList:        list.New(),
-		lock:                   &sync.RWMutex{},
+		pendingPoolSizeInBytes:          pendingPoolSizeInBytes,
+		maxPendingTransactionsPerSigner: maxPendingTransactionsPerSigner,
+		orderingPolicy:                  orderingPolicy,
+		transactionsByHash:              make(map[string]*pendingTransaction),
+		transactionCountBySigner:        make(map[string]uint32),
+		transactionList:                 list.New(),
+		lock:                            &sync.RWMutex{},
 
 		metrics: newPendingPoolMetrics(metricFactory),
 	}
 }
 
+// the hash, size and signer are calculated once when the transaction is added and carried along the ordering pipeline
 type pendingTransaction struct {
 	gatewayPublicKey primitives.Ed25519PublicKey
 	transaction      *protocol.SignedTransaction
+	txHash           primitives.Sha256
+	size             uint32
+	signer           string
 	listElement      *list.Element
 	timeAdded        time.Time
 }
@@ -48,14 +56,17 @@ func newPendingPoolMetrics(factory metric.Factory) *pendingPoolMetrics {
 }
 
 type pendingTxPool struct {
-	currentSizeInBytes uint32
-	transactionsByHash map[string]*pendingTransaction
-	transactionList    *list.List
-	lock               *sync.RWMutex
+	currentSizeInBytes       uint32
+	transactionsByHash       map[string]*pendingTransaction
+	transactionCountBySigner map[string]uint32
+	transactionList          *list.List
+	lock                     *sync.RWMutex
 
 	//FIXME get rid of it
-	pendingPoolSizeInBytes func() uint32
-	onTransactionRemoved   transactionRemovedListener
+	pendingPoolSizeInBytes          func() uint32
+	maxPendingTransactionsPerSigner func() uint32 // zero means no limit
+	orderingPolicy                  orderingPolicy
+	onTransactionsRemoved           transactionsRemovedListener // called outside the lock
 
 	metrics *pendingPoolMetrics
 }
@@ -75,13 +86,28 @@ func (p *pendingTxPool) add(transaction *protocol.SignedTransaction, gatewayPubl
 		return nil, &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_PENDING}
 	}
 
+	// a signer over its quota would congest the pool for everyone else
+	signer := signerKeyOf(transaction)
+	if maxPerSigner := p.maxPendingTransactionsPerSigner(); maxPerSigner > 0 && p.transactionCountBySigner[signer] >= maxPerSigner {
+		return nil, &ErrTransactionRejected{
+			TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_CONGESTION,
+			Expected:          log.Uint32("max-pending-transactions-per-signer", maxPerSigner),
+			Actual:            log.Uint32("pending-transactions-of-signer", p.transactionCountBySigner[signer]),
+		}
+	}
+
 	p.currentSizeInBytes += size
-	p.transactionsByHash[key.KeyForMap()] = &pendingTransaction{
+	p.transactionCountBySigner[signer]++
+	pendingTx := &pendingTransaction{
 		transaction:      transaction,
 		gatewayPublicKey: gatewayPublicKey,
-		listElement:      p.transactionList.PushFront(transaction),
+		txHash:           key,
+		size:             size,
+		signer:           signer,
 		timeAdded:        time.Now(),
 	}
+	pendingTx.listElement = p.transactionList.PushFront(pendingTx)
+	p.transactionsByHash[key.KeyForMap()] = pendingTx
 
 	p.metrics.transactionCountGauge.Inc()
 	p.metrics.poolSizeInBytesGauge.AddUint32(size)
@@ -99,60 +125,75 @@ func (p *pendingTxPool) has(transaction *protocol.SignedTransaction) bool {
 }
 
 func (p *pendingTxPool) remove(ctx context.Context, txhash primitives.Sha256, removalReason protocol.TransactionStatus) *pendingTransaction {
-	p.lock.Lock()
-	defer p.lock.Unlock()
+	return p.removeAll(ctx, []primitives.Sha256{txhash}, removalReason)[0]
+}
 
-	pendingTx, ok := p.transactionsByHash[txhash.KeyForMap()]
-	if ok {
-		delete(p.transactionsByHash, txhash.KeyForMap())
-		p.currentSizeInBytes -= sizeOfSignedTransaction(pendingTx.transaction)
-		p.transactionList.Remove(pendingTx.listElement)
+// the returned slice matches txHashes and holds nil for transactions that were not pending, the listener is notified
+// once for all the removed transactions
+func (p *pendingTxPool) removeAll(ctx context.Context, txHashes []primitives.Sha256, removalReason protocol.TransactionStatus) []*pendingTransaction {
+	removed := make([]*pendingTransaction, len(txHashes))
+	var removedHashes []primitives.Sha256
 
-		if p.onTransactionRemoved != nil {
-			p.onTransactionRemoved(ctx, txhash, removalReason)
+	p.lock.Lock()
+	for i, txHash := range txHashes {
+		pendingTx, ok := p.transactionsByHash[txHash.KeyForMap()]
+		if !ok {
+			continue
 		}
 
+		delete(p.transactionsByHash, txHash.KeyForMap())
+		p.currentSizeInBytes -= pendingTx.size
+		p.transactionList.Remove(pendingTx.listElement)
+		p.decreaseSignerCountUnderMutex(pendingTx.signer)
+
 		p.metrics.transactionCountGauge.Dec()
-		p.metrics.poolSizeInBytesGauge.SubUint32(sizeOfSignedTransaction(pendingTx.transaction))
+		p.metrics.poolSizeInBytesGauge.SubUint32(pendingTx.size)
 
-		return pendingTx
+		removed[i] = pendingTx
+		removedHashes = append(removedHashes, txHash)
 	}
+	p.lock.Unlock()
 
-	return nil
+	if len(removedHashes) > 0 && p.onTransactionsRemoved != nil {
+		p.onTransactionsRemoved(ctx, removedHashes, removalReason)
+	}
+
+	return removed
 }
 
 func (p *pendingTxPool) getBatch(maxNumOfTransactions uint32, sizeLimitInBytes uint32) Transactions {
-	txs := make(Transactions, 0, maxNumOfTransactions)
+	return transactionsOf(p.getPendingBatch(maxNumOfTransactions, sizeLimitInBytes))
+}
+
+// the returned pending transactions must not be modified, they are shared with the pool
+func (p *pendingTxPool) getPendingBatch(maxNumOfTransactions uint32, sizeLimitInBytes uint32) []*pendingTransaction {
+	batch := make([]*pendingTransaction, 0, maxNumOfTransactions)
 	accumulatedSize := uint32(0)
 
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 
-	e := p.transactionList.Back()
-	for {
-		if e == nil {
-			break
-		}
+	oldestFirst := make([]*pendingTransaction, 0, p.transactionList.Len())
+	for e := p.transactionList.Back(); e != nil; e = e.Prev() {
+		oldestFirst = append(oldestFirst, e.Value.(*pendingTransaction))
+	}
 
-		if uint32(len(txs)) >= maxNumOfTransactions {
+	for _, pendingTx := range p.orderingPolicy.order(oldestFirst) {
+		if uint32(len(batch)) >= maxNumOfTransactions {
 			break
 		}
 
-		tx := e.Value.(*protocol.SignedTransaction)
-		//
-		accumulatedSize += sizeOfSignedTransaction(tx)
+		accumulatedSize += pendingTx.size
 		if sizeLimitInBytes > 0 && accumulatedSize > sizeLimitInBytes {
 			break
 		}
 
-		txs = append(txs, tx)
-
-		e = e.Prev()
+		batch = append(batch, pendingTx)
 
-		p.transactionPickedFromQueueUnderMutex(tx)
+		p.metrics.transactionNanosSpentInQueue.RecordSince(pendingTx.timeAdded)
 	}
 
-	return txs
+	return batch
 }
 
 func (p *pendingTxPool) get(txHash primitives.Sha256) *protocol.SignedTransaction {
@@ -167,33 +208,38 @@ func (p *pendingTxPool) get(txHash primitives.Sha256) *protocol.SignedTransactio
 }
 
 func (p *pendingTxPool) clearTransactionsOlderThan(ctx context.Context, time time.Time) {
-	p.lock.RLock()
-	e := p.transactionList.Back()
-	p.lock.RUnlock()
+	var expired []primitives.Sha256
 
-	for {
-		if e == nil {
-			break
+	p.lock.RLock()
+	for e := p.transactionList.Back(); e != nil; e = e.Prev() {
+		pendingTx := e.Value.(*pendingTransaction)
+		if int64(pendingTx.transaction.Transaction().Timestamp()) < time.UnixNano() {
+			expired = append(expired, pendingTx.txHash)
 		}
+	}
+	p.lock.RUnlock()
 
-		tx := e.Value.(*protocol.SignedTransaction)
-
-		e = e.Prev()
-
-		if int64(tx.Transaction().Timestamp()) < time.UnixNano() {
-			p.remove(ctx, digest.CalcTxHash(tx.Transaction()), protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED)
-		}
+	if len(expired) > 0 {
+		p.removeAll(ctx, expired, protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED)
 	}
 }
 
-func (p *pendingTxPool) transactionPickedFromQueueUnderMutex(tx *protocol.SignedTransaction) {
-	txHash := digest.CalcTxHash(tx.Transaction())
-	ptx, found := p.transactionsByHash[txHash.KeyForMap()]
-	if found {
-		p.metrics.transactionNanosSpentInQueue.RecordSince(ptx.timeAdded)
+func (p *pendingTxPool) decreaseSignerCountUnderMutex(signer string) {
+	if p.transactionCountBySigner[signer] <= 1 {
+		delete(p.transactionCountBySigner, signer)
+	} else {
+		p.transactionCountBySigner[signer]--
 	}
 }
 
 func sizeOfSignedTransaction(transaction *protocol.SignedTransaction) uint32 {
 	return uint32(len(transaction.Raw()))
 }
+
+func transactionsOf(batch []*pendingTransaction) Transactions {
+	txs := make(Transactions, len(batch))
+	for i, pendingTx := range batch {
+		txs[i] = pendingTx.transaction
+	}
+	return txs
+}
@@ -2,6 +2,7 @@ package transactionpool
 
 import (
 	"context"
+	"encoding/hex"
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/test"
@@ -150,12 +151,12 @@ func TestPendingTransactionPoolDoesNotAddTheSameTransactionTwiceRegardlessOfPubl
 
 func TestPendingTransactionPoolCallsRemovalListenerWhenRemovingTransaction(t *testing.T) {
 	test.WithContext(func(ctx context.Context) {
-		var removedTxHash primitives.Sha256
+		var removedTxHashes []primitives.Sha256
 		var removalReason protocol.TransactionStatus
 
 		p := makePendingPool()
-		p.onTransactionRemoved = func(ctx context.Context, txHash primitives.Sha256, reason protocol.TransactionStatus) {
-			removedTxHash = txHash
+		p.onTransactionsRemoved = func(ctx context.Context, txHashes []primitives.Sha256, reason protocol.TransactionStatus) {
+			removedTxHashes = txHashes
 			removalReason = reason
 		}
 
@@ -164,11 +165,93 @@ func TestPendingTransactionPoolCallsRemovalListenerWhenRemovingTransaction(t *te
 		txHash := digest.CalcTxHash(tx.Transaction())
 		p.remove(ctx, txHash, protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED)
 
-		require.Equal(t, txHash, removedTxHash, "removed txhash didn't equal expected txhash")
+		require.Equal(t, []primitives.Sha256{txHash}, removedTxHashes, "removed txhash didn't equal expected txhash")
 		require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED, removalReason, "removal reason didn't equal expected reason")
 	})
 }
 
+func TestPendingTransactionPoolGetBatchTakesTurnsBetweenSigners(t *testing.T) {
+	t.Parallel()
+	p := makePendingPoolWithOrderingPolicy(&roundRobinSignersOrderingPolicy{}, 0)
+
+	spammer, other := keys.Ed25519KeyPairForTests(1), keys.Ed25519KeyPairForTests(2)
+	spam1 := builders.TransferTransaction().WithEd25519Signer(spammer).Build()
+	spam2 := builders.TransferTransaction().WithEd25519Signer(spammer).Build()
+	spam3 := builders.TransferTransaction().WithEd25519Signer(spammer).Build()
+	fair := builders.TransferTransaction().WithEd25519Signer(other).Build()
+	add(p, spam1, spam2, spam3, fair)
+
+	require.Equal(t, Transactions{spam1, fair}, p.getBatch(2, 0), "other signer should get a turn before the spammer's second transaction")
+	require.Equal(t, Transactions{spam1, fair, spam2, spam3}, p.getBatch(4, 0), "transactions of each signer should retain insertion order")
+}
+
+func TestPendingTransactionPoolRejectsTransactionsOverSignerQuota(t *testing.T) {
+	t.Parallel()
+	test.WithContext(func(ctx context.Context) {
+		p := makePendingPoolWithOrderingPolicy(&fifoOrderingPolicy{}, 2)
+
+		spammer, other := keys.Ed25519KeyPairForTests(1), keys.Ed25519KeyPairForTests(2)
+		k1, err := p.add(builders.TransferTransaction().WithEd25519Signer(spammer).Build(), pk)
+		require.Nil(t, err, "first transaction of signer should be added")
+		_, err = p.add(builders.TransferTransaction().WithEd25519Signer(spammer).Build(), pk)
+		require.Nil(t, err, "second transaction of signer should be added")
+
+		_, err = p.add(builders.TransferTransaction().WithEd25519Signer(spammer).Build(), pk)
+		require.NotNil(t, err, "transaction over the signer quota should be rejected")
+		require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_CONGESTION, err.TransactionStatus)
+		require.Equal(t, "max-pending-transactions-per-signer", err.Expected.Key, "rejection should be told apart from a full pool by its expected field")
+
+		_, err = p.add(builders.TransferTransaction().WithEd25519Signer(other).Build(), pk)
+		require.Nil(t, err, "transaction of another signer should be added")
+
+		p.remove(ctx, k1, protocol.TRANSACTION_STATUS_COMMITTED)
+		_, err = p.add(builders.TransferTransaction().WithEd25519Signer(spammer).Build(), pk)
+		require.Nil(t, err, "signer should be below quota once its transaction was removed")
+	})
+}
+
+func TestPendingTransactionPoolGetBatchPrefersHigherPriority(t *testing.T) {
+	t.Parallel()
+
+	tx1 := builders.TransferTransaction().Build()
+	tx2 := builders.TransferTransaction().Build()
+	urgent := builders.TransferTransaction().Build()
+	priority := func(tx *protocol.SignedTransaction) uint64 {
+		if tx == urgent {
+			return 10
+		}
+		return 0
+	}
+	p := makePendingPoolWithOrderingPolicy(newPriorityOrderingPolicy(priority, &fifoOrderingPolicy{}), 0)
+	add(p, tx1, tx2, urgent)
+
+	require.Equal(t, Transactions{urgent, tx1, tx2}, p.getBatch(3, 0), "higher priority should come first and equal priority should retain insertion order")
+}
+
+func TestPendingTransactionPoolGetBatchPrefersConfiguredPrioritySigners(t *testing.T) {
+	t.Parallel()
+
+	regular, priority := keys.Ed25519KeyPairForTests(1), keys.Ed25519KeyPairForTests(2)
+	orderingPolicy, err := newOrderingPolicy(ORDERING_POLICY_PRIORITY_SIGNERS, hex.EncodeToString(priority.PublicKey()))
+	require.NoError(t, err, "priority signers ordering policy should be created")
+	p := makePendingPoolWithOrderingPolicy(orderingPolicy, 0)
+
+	tx1 := builders.TransferTransaction().WithEd25519Signer(regular).Build()
+	tx2 := builders.TransferTransaction().WithEd25519Signer(regular).Build()
+	urgent := builders.TransferTransaction().WithEd25519Signer(priority).Build()
+	add(p, tx1, tx2, urgent)
+
+	require.Equal(t, Transactions{urgent, tx1, tx2}, p.getBatch(3, 0), "transactions of priority signers should come first")
+}
+
+func TestNewOrderingPolicyRejectsPrioritySignersWithoutSigners(t *testing.T) {
+	_, err := newOrderingPolicy(ORDERING_POLICY_PRIORITY_SIGNERS, "")
+	require.Error(t, err, "priority signers ordering policy without signers should not be created")
+
+	_, err = newOrderingPolicy(ORDERING_POLICY_PRIORITY_SIGNERS, "not-hex")
+	require.Error(t, err, "priority signers ordering policy with an invalid signer should not be created")
+}
+
 func add(p *pendingTxPool, txs ...*protocol.SignedTransaction) {
 	for _, tx := range txs {
 		p.add(tx, pk)
@@ -176,6 +259,10 @@ func add(p *pendingTxPool, txs ...*protocol.SignedTransaction) {
 }
 
 func makePendingPool() *pendingTxPool {
+	return makePendingPoolWithOrderingPolicy(&fifoOrderingPolicy{}, 0)
+}
+
+func makePendingPoolWithOrderingPolicy(orderingPolicy orderingPolicy, maxPendingTransactionsPerSigner uint32) *pendingTxPool {
 	metricFactory := metric.NewRegistry()
-	return NewPendingPool(func() uint32 { return 100000 }, metricFactory)
+	return NewPendingPool(func() uint32 { return 100000 }, func() uint32 { return maxPendingTransactionsPerSigner }, orderingPolicy, metricFactory)
 }
@@ -0,0 +1,56 @@
+package transactionpool
+
+import (
+	"sync"
+	"time"
+)
+
+// after the virtual machine fails to pre-order a batch, ordering stops calling it for a period that doubles on every consecutive failure
+type preOrderBackoff struct {
+	minBackoff func() time.Duration
+	maxBackoff func() time.Duration
+
+	mu struct {
+		sync.Mutex
+		current time.Duration
+		until   time.Time
+	}
+}
+
+func newPreOrderBackoff(minBackoff func() time.Duration, maxBackoff func() time.Duration) *preOrderBackoff {
+	return &preOrderBackoff{
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+	}
+}
+
+func (b *preOrderBackoff) isBackingOff(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return now.Before(b.mu.until)
+}
+
+// returns the period during which the virtual machine will not be called
+func (b *preOrderBackoff) failed(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.mu.current == 0 {
+		b.mu.current = b.minBackoff()
+	} else {
+		b.mu.current *= 2
+	}
+	if max := b.maxBackoff(); b.mu.current > max {
+		b.mu.current = max
+	}
+
+	b.mu.until = now.Add(b.mu.current)
+	return b.mu.current
+}
+
+func (b *preOrderBackoff) succeeded() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.current = 0
+	b.mu.until = time.Time{}
+}
@@ -0,0 +1,44 @@
+package transactionpool
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func aPreOrderBackoff(min time.Duration, max time.Duration) *preOrderBackoff {
+	return newPreOrderBackoff(func() time.Duration { return min }, func() time.Duration { return max })
+}
+
+func TestPreOrderBackoffDoublesOnConsecutiveFailuresUpToMax(t *testing.T) {
+	b := aPreOrderBackoff(100*time.Millisecond, 350*time.Millisecond)
+	now := time.Now()
+
+	require.Equal(t, 100*time.Millisecond, b.failed(now))
+	require.Equal(t, 200*time.Millisecond, b.failed(now))
+	require.Equal(t, 350*time.Millisecond, b.failed(now), "backoff should not exceed max")
+	require.Equal(t, 350*time.Millisecond, b.failed(now), "backoff should not exceed max")
+}
+
+func TestPreOrderBackoffIsActiveUntilPeriodElapses(t *testing.T) {
+	b := aPreOrderBackoff(100*time.Millisecond, time.Second)
+	now := time.Now()
+
+	require.False(t, b.isBackingOff(now), "should not back off before any failure")
+
+	b.failed(now)
+	require.True(t, b.isBackingOff(now.Add(99*time.Millisecond)), "should back off right after a failure")
+	require.False(t, b.isBackingOff(now.Add(100*time.Millisecond)), "should stop backing off once the period elapsed")
+}
+
+func TestPreOrderBackoffResetsOnSuccess(t *testing.T) {
+	b := aPreOrderBackoff(100*time.Millisecond, time.Second)
+	now := time.Now()
+
+	b.failed(now)
+	b.failed(now)
+	b.succeeded()
+
+	require.False(t, b.isBackingOff(now), "should not back off after a success")
+	require.Equal(t, 100*time.Millisecond, b.failed(now), "backoff should start over after a success")
+}
@@ -3,6 +3,8 @@ package transactionpool
 import (
 	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
 	"github.com/orbs-network/orbs-network-go/synchronization"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/services"
@@ -16,6 +18,7 @@ var LogTag = log.Service("transaction-pool")
 type service struct {
 	gossip                     gossiptopics.TransactionRelay
 	virtualMachine             services.VirtualMachine
+	journal                    adapter.PendingTransactionJournal
 	transactionResultsHandlers []handlers.TransactionResultsHandler
 	logger                     log.BasicLogger
 	config                     config.TransactionPoolConfig
@@ -30,6 +33,20 @@ type service struct {
 	committedPool        *committedTxPool
 	blockTracker         *synchronization.BlockTracker
 	transactionForwarder *transactionForwarder
+	signatureVerifier    *signatureVerifier
+	preOrderBackoff      *preOrderBackoff
+
+	metrics *serviceMetrics
+}
+
+type serviceMetrics struct {
+	preOrderFailures *metric.Counter
+}
+
+func newServiceMetrics(factory metric.Factory) *serviceMetrics {
+	return &serviceMetrics{
+		preOrderFailures: factory.NewCounter("TransactionPool.PreOrder.FailureCount"),
+	}
 }
 
 func (s *service) currentBlockHeightAndTime() (primitives.BlockHeight, primitives.TimestampNano) {
@@ -49,5 +66,6 @@ func (s *service) createValidationContext() *validationContext {
 		lastCommittedBlockTimestamp: s.mu.lastCommittedBlockTimestamp,
 		futureTimestampGrace:        s.config.TransactionPoolFutureTimestampGraceTimeout(),
 		virtualChainId:              s.config.VirtualChainId(),
+		signatureVerifier:           s.signatureVerifier,
 	}
 }
@@ -0,0 +1,90 @@
+package transactionpool
+
+import (
+	"container/list"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/crypto/signature"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"sync"
+)
+
+// verifies ed25519 transaction signatures and remembers the ones that passed, so a transaction
+// is verified once when added to the pool and not again when ordered or validated in a block
+type signatureVerifier struct {
+	mutex   sync.Mutex
+	maxSize int
+	items   map[string]*list.Element
+	order   *list.List
+}
+
+func newSignatureVerifier(maxSize int) *signatureVerifier {
+	return &signatureVerifier{
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (v *signatureVerifier) verify(transaction *protocol.SignedTransaction) bool {
+	return v.verifyWithHash(transaction, digest.CalcTxHash(transaction.Transaction()))
+}
+
+// for callers that already calculated the tx hash
+func (v *signatureVerifier) verifyWithHash(transaction *protocol.SignedTransaction, txHash primitives.Sha256) bool {
+	tx := transaction.Transaction()
+
+	// the tx hash does not cover the signature, so it is part of the key to keep a forged copy of a verified transaction from hitting the cache
+	key := txHash.KeyForMap() + string(transaction.Signature())
+	if v.isCached(key) {
+		return true
+	}
+
+	if !signature.VerifyEd25519(tx.Signer().Eddsa().SignerPublicKey(), txHash, transaction.Signature()) {
+		return false
+	}
+
+	v.add(key)
+	return true
+}
+
+// verifies all eddsa signed transactions in parallel so that following calls to verify are served from the cache
+func (v *signatureVerifier) verifyBatch(transactions []*protocol.SignedTransaction) {
+	forEachIndexOnBoundedWorkers(len(transactions), func(index int) {
+		if transactions[index].Transaction().Signer().IsSchemeEddsa() {
+			v.verify(transactions[index])
+		}
+	})
+}
+
+func (v *signatureVerifier) isCached(key string) bool {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	element, ok := v.items[key]
+	if ok {
+		v.order.MoveToFront(element)
+	}
+	return ok
+}
+
+func (v *signatureVerifier) add(key string) {
+	if v.maxSize <= 0 {
+		return
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if element, ok := v.items[key]; ok {
+		v.order.MoveToFront(element)
+		return
+	}
+
+	v.items[key] = v.order.PushFront(key)
+	for v.order.Len() > v.maxSize {
+		oldest := v.order.Back()
+		v.order.Remove(oldest)
+		delete(v.items, oldest.Value.(string))
+	}
+}
@@ -0,0 +1,72 @@
+package transactionpool
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func cacheKeyFor(tx *protocol.SignedTransaction) string {
+	return digest.CalcTxHash(tx.Transaction()).KeyForMap() + string(tx.Signature())
+}
+
+func TestSignatureVerifier_AcceptsAndCachesValidSignature(t *testing.T) {
+	v := newSignatureVerifier(10)
+	tx := builders.TransferTransaction().Build()
+
+	require.True(t, v.verify(tx), "valid signature was rejected")
+	require.True(t, v.isCached(cacheKeyFor(tx)), "valid signature was not cached")
+}
+
+func TestSignatureVerifier_RejectsAndDoesNotCacheInvalidSignature(t *testing.T) {
+	v := newSignatureVerifier(10)
+	tx := builders.TransferTransaction().WithInvalidEd25519Signer(testKeys.Ed25519KeyPairForTests(1)).Build()
+
+	require.False(t, v.verify(tx), "invalid signature was accepted")
+	require.False(t, v.isCached(cacheKeyFor(tx)), "invalid signature was cached")
+}
+
+func TestSignatureVerifier_RejectsForgedCopyOfVerifiedTransaction(t *testing.T) {
+	v := newSignatureVerifier(10)
+	tx := builders.TransferTransaction().Build()
+	require.True(t, v.verify(tx), "valid signature was rejected")
+
+	forged := protocol.SignedTransactionReader(append([]byte{}, tx.Raw()...))
+	require.NoError(t, forged.MutateSignature(make([]byte, len(tx.Signature()))))
+
+	require.False(t, v.verify(forged), "forged signature was accepted because the transaction was already verified")
+}
+
+func TestSignatureVerifier_EvictsLeastRecentlyUsed(t *testing.T) {
+	v := newSignatureVerifier(2)
+	tx1 := builders.TransferTransaction().WithAmountAndTargetAddress(1, builders.AddressForEd25519SignerForTests(2)).Build()
+	tx2 := builders.TransferTransaction().WithAmountAndTargetAddress(2, builders.AddressForEd25519SignerForTests(2)).Build()
+	tx3 := builders.TransferTransaction().WithAmountAndTargetAddress(3, builders.AddressForEd25519SignerForTests(2)).Build()
+
+	v.verify(tx1)
+	v.verify(tx2)
+	v.verify(tx1) // touch tx1 so tx2 becomes the oldest
+	v.verify(tx3)
+
+	require.True(t, v.isCached(cacheKeyFor(tx1)), "recently used signature was evicted")
+	require.False(t, v.isCached(cacheKeyFor(tx2)), "least recently used signature was not evicted")
+	require.True(t, v.isCached(cacheKeyFor(tx3)), "newest signature was evicted")
+}
+
+func TestSignatureVerifier_VerifyBatchCachesValidSignatures(t *testing.T) {
+	v := newSignatureVerifier(10)
+	valid1 := builders.TransferTransaction().Build()
+	valid2 := builders.GetBalanceTransaction().Build()
+	invalid := builders.TransferTransaction().WithInvalidEd25519Signer(testKeys.Ed25519KeyPairForTests(1)).Build()
+	unknownScheme := builders.TransferTransaction().WithInvalidSignerScheme().Build()
+
+	v.verifyBatch(Transactions{valid1, invalid, unknownScheme, valid2})
+
+	require.True(t, v.isCached(cacheKeyFor(valid1)), "valid signature was not cached")
+	require.True(t, v.isCached(cacheKeyFor(valid2)), "valid signature was not cached")
+	require.False(t, v.isCached(cacheKeyFor(invalid)), "invalid signature was cached")
+	require.False(t, v.isCached(cacheKeyFor(unknownScheme)), "signature of unknown scheme was cached")
+}
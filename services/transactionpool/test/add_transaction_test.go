@@ -7,8 +7,10 @@ import (
 	"github.com/orbs-network/orbs-network-go/services/transactionpool"
 	"github.com/orbs-network/orbs-network-go/test"
 	"github.com/orbs-network/orbs-network-go/test/builders"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
@@ -45,6 +47,22 @@ func TestDoesNotForwardInvalidTransactionsUsingGossip(t *testing.T) {
 	})
 }
 
+func TestDoesNotAddTransactionsWithForgedSignature(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+
+		tx := builders.TransferTransaction().WithInvalidEd25519Signer(testKeys.Ed25519KeyPairForTests(1)).Build()
+		h.expectNoTransactionsToBeForwarded()
+
+		_, err := h.addNewTransaction(ctx, tx)
+
+		require.Error(t, err, "a transaction with a forged signature was added to the pool")
+		require.IsType(t, &transactionpool.ErrTransactionRejected{}, err, "error was not of the expected type")
+		require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, err.(*transactionpool.ErrTransactionRejected).TransactionStatus, "error did not contain expected transaction status")
+		require.NoError(t, test.ConsistentlyVerify(10*time.Millisecond, h.gossip), "mocks were not called as expected")
+	})
+}
+
 func TestDoesNotAddTransactionsThatFailedPreOrderChecks(t *testing.T) {
 	test.WithContext(func(ctx context.Context) {
 		h := newHarness()
@@ -131,3 +149,19 @@ func TestDoesNotAddTransactionIfPoolIsFull(t *testing.T) {
 		require.NoError(t, h.verifyMocks(), "mocks were not called as expected")
 	})
 }
+
+func TestDoesNotAddTransactionWhenVirtualMachineFailsToPreOrder(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+
+		tx := builders.TransferTransaction().Build()
+		h.failPreOrderWithError(errors.New("virtual machine is down"))
+		h.expectNoTransactionsToBeForwarded()
+
+		out, err := h.addNewTransaction(ctx, tx)
+
+		require.Error(t, err, "a transaction was added to the pool without passing pre-order checks")
+		require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER, out.TransactionStatus, "transaction was not rejected")
+		require.NoError(t, test.ConsistentlyVerify(10*time.Millisecond, h.gossip), "mocks were not called as expected")
+	})
+}
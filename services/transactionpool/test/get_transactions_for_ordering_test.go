@@ -195,3 +195,48 @@ func TestGetTransactionsForOrderingAsOfFutureBlockHeightResolvesOutWhenBlockIsCo
 		require.NoError(t, <-doneWait, "did not resolve after block has been committed")
 	})
 }
+
+func TestGetTransactionsForOrderingReturnsAnEmptyBatchAndKeepsTransactionsPendingWhenVirtualMachineFails(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.ignoringForwardMessages()
+
+		tx1 := builders.TransferTransaction().Build()
+		tx2 := builders.TransferTransaction().Build()
+		h.addTransactions(ctx, tx1, tx2)
+
+		h.failPreOrderWithError(errors.New("virtual machine is down"))
+
+		txSet, err := h.getTransactionsForOrdering(ctx, 2)
+		require.NoError(t, err, "a virtual machine failure should not fail the block proposal")
+		require.Empty(t, txSet.SignedTransactions, "got transactions that were not pre-ordered")
+		require.Contains(t, h.metricRegistry.String(), "metric TransactionPool.PreOrder.FailureCount: 1\n", "pre-order failure was not counted")
+
+		h.passAllPreOrderChecks()
+
+		require.True(t, test.Eventually(2*h.config.TransactionPoolPreOrderFailureMaxBackoff(), func() bool {
+			txSet, err := h.getTransactionsForOrdering(ctx, 2)
+			return err == nil && len(txSet.SignedTransactions) == 2
+		}), "transactions should remain pending and be ordered once the virtual machine recovers")
+	})
+}
+
+func TestGetTransactionsForOrderingDoesNotCallVirtualMachineWhileBackingOff(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.ignoringForwardMessages()
+
+		h.addTransactions(ctx, builders.TransferTransaction().Build())
+
+		h.failPreOrderWithError(errors.New("virtual machine is down"))
+		h.getTransactionsForOrdering(ctx, 1)
+
+		h.expectNoPreOrderCheck()
+
+		txSet, err := h.getTransactionsForOrdering(ctx, 1)
+		require.NoError(t, err, "failed getting transactions unexpectedly")
+		require.Empty(t, txSet.SignedTransactions, "got transactions while backing off")
+
+		require.NoError(t, h.verifyMocks(), "virtual machine was called while backing off")
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/orbs-network/orbs-network-go/services/transactionpool"
 	"github.com/orbs-network/orbs-network-go/test"
 	"github.com/orbs-network/orbs-network-go/test/builders"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
 	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
 	"github.com/stretchr/testify/require"
@@ -60,3 +62,16 @@ func TestHandleForwardedTransactionsDoesNotAddToFullPool(t *testing.T) {
 		require.Equal(t, 0, len(out.SignedTransactions), "forwarded transaction was added to full pool")
 	})
 }
+
+func TestHandleForwardedTransactionsDoesNotAddTransactionsWithForgedSignature(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+
+		validTx := builders.TransferTransaction().Build()
+		forgedTx := builders.TransferTransaction().WithInvalidEd25519Signer(testKeys.Ed25519KeyPairForTests(1)).Build()
+
+		h.handleForwardFrom(ctx, otherNodeKeyPair, validTx, forgedTx)
+		out, _ := h.getTransactionsForOrdering(ctx, 2)
+		require.Equal(t, []*protocol.SignedTransaction{validTx}, out.SignedTransactions, "forwarded transaction with forged signature was added to pool")
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/services/transactionpool"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
 	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -28,6 +29,7 @@ type harness struct {
 	lastBlockHeight    primitives.BlockHeight
 	lastBlockTimestamp primitives.TimestampNano
 	config             config.TransactionPoolConfig
+	metricRegistry     metric.Registry
 }
 
 var (
@@ -170,6 +172,15 @@ func (h *harness) passAllPreOrderChecks() {
 		return false
 	})
 }
+
+func (h *harness) failPreOrderWithError(err error) {
+	h.vm.Reset().When("TransactionSetPreOrder", mock.Any, mock.Any).Return(nil, err)
+}
+
+func (h *harness) expectNoPreOrderCheck() {
+	h.vm.Reset().Never("TransactionSetPreOrder", mock.Any, mock.Any)
+}
+
 func (h *harness) goToBlock(ctx context.Context, height primitives.BlockHeight, timestamp primitives.TimestampNano) {
 	h.ignoringTransactionResults()
 	currentBlock := primitives.BlockHeight(0)
@@ -197,6 +208,11 @@ func newHarness() *harness {
 }
 
 func newHarnessWithSizeLimit(sizeLimit uint32) *harness {
+	return newHarnessWithJournal(sizeLimit, nil, nil)
+}
+
+// a harness sharing a journal with a previous one simulates a node restart
+func newHarnessWithJournal(sizeLimit uint32, journal adapter.PendingTransactionJournal, committedTransactions adapter.CommittedTransactionLookup) *harness {
 	ctx := context.Background()
 
 	gossip := &gossiptopics.MockTransactionRelay{}
@@ -207,7 +223,7 @@ func newHarnessWithSizeLimit(sizeLimit uint32) *harness {
 	cfg := config.ForTransactionPoolTests(sizeLimit, thisNodeKeyPair)
 	metricFactory := metric.NewRegistry()
 
-	service := transactionpool.NewTransactionPool(ctx, gossip, virtualMachine, cfg, log.GetLogger(), metricFactory)
+	service := transactionpool.NewTransactionPool(ctx, gossip, virtualMachine, journal, committedTransactions, cfg, log.GetLogger(), metricFactory)
 
 	transactionResultHandler := &handlers.MockTransactionResultsHandler{}
 	service.RegisterTransactionResultsHandler(transactionResultHandler)
@@ -219,6 +235,7 @@ func newHarnessWithSizeLimit(sizeLimit uint32) *harness {
 		trh:                transactionResultHandler,
 		lastBlockTimestamp: primitives.TimestampNano(time.Now().UnixNano()),
 		config:             cfg,
+		metricRegistry:     metricFactory,
 	}
 
 	h.passAllPreOrderChecks()
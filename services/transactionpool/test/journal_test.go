@@ -0,0 +1,124 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type fakeCommittedTransactionLookup struct {
+	committed map[string]bool
+}
+
+func (l *fakeCommittedTransactionLookup) IsTransactionCommitted(txHash primitives.Sha256, txTimestamp primitives.TimestampNano) bool {
+	return l.committed[txHash.KeyForMap()]
+}
+
+type failingJournal struct {
+	*adapter.InMemoryPendingTransactionJournal
+}
+
+func (j *failingJournal) Append(transaction *protocol.SignedTransaction, gatewayPublicKey primitives.Ed25519PublicKey) error {
+	return errors.New("disk full")
+}
+
+func requireJournaled(t *testing.T, journal adapter.PendingTransactionJournal, expected transactionpool.Transactions, msg string) {
+	entries, err := journal.ReadAll()
+	require.NoError(t, err)
+	var journaled transactionpool.Transactions
+	for _, entry := range entries {
+		journaled = append(journaled, entry.Transaction)
+	}
+	require.Equal(t, expected, journaled, msg)
+}
+
+func TestPendingTransactionsSurviveRestart(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		journal := adapter.NewInMemoryPendingTransactionJournal()
+		h := newHarnessWithJournal(20*1024*1024, journal, nil)
+		h.ignoringForwardMessages()
+
+		tx1 := builders.TransferTransaction().Build()
+		tx2 := builders.TransferTransaction().Build()
+		forwardedTx := builders.TransferTransaction().Build()
+		h.addTransactions(ctx, tx1, tx2)
+		h.handleForwardFrom(ctx, otherNodeKeyPair, forwardedTx)
+		requireJournaled(t, journal, transactionpool.Transactions{tx1, tx2, forwardedTx}, "added and forwarded transactions should be journaled")
+
+		restarted := newHarnessWithJournal(20*1024*1024, journal, nil)
+
+		txSet, err := restarted.getTransactionsForOrdering(ctx, 3)
+		require.NoError(t, err, "expected transaction set but got an error")
+		require.Equal(t, transactionpool.Transactions{tx1, tx2, forwardedTx}, txSet.SignedTransactions, "pending transactions should be replayed in their original order after restart")
+	})
+}
+
+func TestJournalReplayDropsExpiredAndCommittedTransactions(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		journal := adapter.NewInMemoryPendingTransactionJournal()
+		h := newHarness()
+
+		validTx := builders.TransferTransaction().Build()
+		expiredTx := builders.TransferTransaction().WithTimestamp(time.Now().Add(-h.config.TransactionPoolTransactionExpirationWindow() - time.Minute)).Build()
+		committedTx := builders.TransferTransaction().Build()
+		for _, tx := range []*protocol.SignedTransaction{validTx, expiredTx, committedTx} {
+			require.NoError(t, journal.Append(tx, thisNodeKeyPair.PublicKey()))
+		}
+
+		committedTransactions := &fakeCommittedTransactionLookup{committed: map[string]bool{
+			digest.CalcTxHash(committedTx.Transaction()).KeyForMap(): true,
+		}}
+		restarted := newHarnessWithJournal(20*1024*1024, journal, committedTransactions)
+
+		txSet, err := restarted.getTransactionsForOrdering(ctx, 3)
+		require.NoError(t, err, "expected transaction set but got an error")
+		require.Equal(t, transactionpool.Transactions{validTx}, txSet.SignedTransactions, "expired and committed transactions should not be replayed")
+		requireJournaled(t, journal, transactionpool.Transactions{validTx}, "dropped transactions should be removed from the journal")
+	})
+}
+
+func TestCommittedTransactionsAreRemovedFromJournal(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		journal := adapter.NewInMemoryPendingTransactionJournal()
+		h := newHarnessWithJournal(20*1024*1024, journal, nil)
+		h.ignoringForwardMessages()
+		h.ignoringTransactionResults()
+
+		tx1 := builders.TransferTransaction().Build()
+		tx2 := builders.TransferTransaction().Build()
+		h.addTransactions(ctx, tx1, tx2)
+
+		_, err := h.reportTransactionsAsCommitted(ctx, tx1)
+		require.NoError(t, err)
+
+		requireJournaled(t, journal, transactionpool.Transactions{tx2}, "committed transaction should be removed from the journal")
+	})
+}
+
+func TestTransactionThatCannotBeJournaledIsRejected(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		journal := &failingJournal{adapter.NewInMemoryPendingTransactionJournal()}
+		h := newHarnessWithJournal(20*1024*1024, journal, nil)
+		h.expectNoTransactionsToBeForwarded()
+
+		tx := builders.TransferTransaction().Build()
+		out, err := h.addNewTransaction(ctx, tx)
+
+		require.Error(t, err, "a transaction that was not journaled should not be acknowledged")
+		require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_CONGESTION, out.TransactionStatus, "a transaction that was not journaled should be rejected")
+
+		txSet, err := h.getTransactionsForOrdering(ctx, 1)
+		require.NoError(t, err, "expected transaction set but got an error")
+		require.Empty(t, txSet.SignedTransactions, "a transaction that was not journaled should not be pending")
+		require.NoError(t, h.verifyMocks(), "mocks were not called as expected")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/test"
 	"github.com/orbs-network/orbs-network-go/test/builders"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -56,6 +57,21 @@ func TestValidateTransactionsForOrderingRejectsTransactionsFailingValidation(t *
 	})
 }
 
+func TestValidateTransactionsForOrderingRejectsTransactionsWithForgedSignature(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+
+		forgedTx := builders.TransferTransaction().WithInvalidEd25519Signer(testKeys.Ed25519KeyPairForTests(1)).Build()
+
+		err := h.validateTransactionsForOrdering(ctx, 0, builders.Transaction().Build(), forgedTx)
+
+		require.Contains(t,
+			err.Error(),
+			fmt.Sprintf("transaction with hash %s is invalid: transaction rejected: %s", digest.CalcTxHash(forgedTx.Transaction()), protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH),
+			"did not reject a transaction with a forged signature")
+	})
+}
+
 func TestValidateTransactionsForOrderingRejectsTransactionsFailingPreOrderChecks(t *testing.T) {
 	test.WithContext(func(ctx context.Context) {
 		h := newHarness()
@@ -1,6 +1,7 @@
 package transactionpool
 
 import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/crypto/keys"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
@@ -10,28 +11,33 @@ import (
 
 const ProtocolVersion = primitives.ProtocolVersion(1)
 
-type validator func(transaction *protocol.SignedTransaction) *ErrTransactionRejected
+type validator func(vctx *validationContext, transaction *protocol.SignedTransaction, txHash primitives.Sha256) *ErrTransactionRejected
+
+// built once, the validation context carries everything that changes between calls
+var validators = []validator{
+	validateProtocolVersion,
+	validateContractName,
+	validateSignature,
+	validateTransactionNotExpired,
+	validateTransactionNotInFuture,
+	validateTransactionVirtualChainId,
+}
 
 type validationContext struct {
 	expiryWindow                time.Duration
 	lastCommittedBlockTimestamp primitives.TimestampNano
 	futureTimestampGrace        time.Duration
 	virtualChainId              primitives.VirtualChainId
+	signatureVerifier           *signatureVerifier
 }
 
 func (c *validationContext) validateTransaction(transaction *protocol.SignedTransaction) *ErrTransactionRejected {
-	//TODO can we create the list of validators once on system startup; this will save on performance in the critical path
-	validators := []validator{
-		validateProtocolVersion,
-		validateContractName,
-		validateSignature,
-		validateTransactionNotExpired(c),
-		validateTransactionNotInFuture(c),
-		validateTransactionVirtualChainId(c),
-	}
+	return c.validateTransactionWithHash(transaction, digest.CalcTxHash(transaction.Transaction()))
+}
 
+func (c *validationContext) validateTransactionWithHash(transaction *protocol.SignedTransaction, txHash primitives.Sha256) *ErrTransactionRejected {
 	for _, validate := range validators {
-		err := validate(transaction)
+		err := validate(c, transaction, txHash)
 		if err != nil {
 			return err
 		}
@@ -40,14 +46,23 @@ func (c *validationContext) validateTransaction(transaction *protocol.SignedTran
 	return nil
 }
 
-func validateProtocolVersion(tx *protocol.SignedTransaction) *ErrTransactionRejected {
+// validates the pending transactions on a bounded number of workers, the result at each index belongs to the transaction at the same index
+func (c *validationContext) validateBatch(batch []*pendingTransaction) []*ErrTransactionRejected {
+	results := make([]*ErrTransactionRejected, len(batch))
+	forEachIndexOnBoundedWorkers(len(batch), func(index int) {
+		results[index] = c.validateTransactionWithHash(batch[index].transaction, batch[index].txHash)
+	})
+	return results
+}
+
+func validateProtocolVersion(vctx *validationContext, tx *protocol.SignedTransaction, txHash primitives.Sha256) *ErrTransactionRejected {
 	if tx.Transaction().ProtocolVersion() != ProtocolVersion {
 		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_UNSUPPORTED_VERSION, log.Stringable("protocol-version", ProtocolVersion), log.Stringable("protocol-version", tx.Transaction().ProtocolVersion())}
 	}
 	return nil
 }
 
-func validateSignature(transaction *protocol.SignedTransaction) *ErrTransactionRejected {
+func validateSignature(vctx *validationContext, transaction *protocol.SignedTransaction, txHash primitives.Sha256) *ErrTransactionRejected {
 	tx := transaction.Transaction()
 	if !tx.Signer().IsSchemeEddsa() {
 		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME, log.String("signer-scheme", "Eddsa"), log.Stringable("signer", tx.Signer())}
@@ -57,12 +72,14 @@ func validateSignature(transaction *protocol.SignedTransaction) *ErrTransactionR
 		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, log.Int("signature-length", keys.ED25519_PUBLIC_KEY_SIZE_BYTES), log.Int("signature-length", len(tx.Signer().Eddsa().SignerPublicKey()))}
 	}
 
-	//TODO actually verify the signature
+	if !vctx.signatureVerifier.verifyWithHash(transaction, txHash) {
+		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, log.String("signature", "valid"), log.Stringable("signature", transaction.Signature())}
+	}
 
 	return nil
 }
 
-func validateContractName(transaction *protocol.SignedTransaction) *ErrTransactionRejected {
+func validateContractName(vctx *validationContext, transaction *protocol.SignedTransaction, txHash primitives.Sha256) *ErrTransactionRejected {
 	tx := transaction.Transaction()
 	if tx.ContractName() == "" {
 		//TODO what is the expected status?
@@ -72,34 +89,28 @@ func validateContractName(transaction *protocol.SignedTransaction) *ErrTransacti
 	return nil
 }
 
-func validateTransactionNotExpired(vctx *validationContext) validator {
-	return func(transaction *protocol.SignedTransaction) *ErrTransactionRejected {
-		threshold := primitives.TimestampNano(time.Now().Add(vctx.expiryWindow * -1).UnixNano())
-		if transaction.Transaction().Timestamp() < threshold {
-			return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED, log.TimestampNano("min-timestamp", threshold), log.TimestampNano("tx-timestamp", transaction.Transaction().Timestamp())}
-		}
-
-		return nil
+func validateTransactionNotExpired(vctx *validationContext, transaction *protocol.SignedTransaction, txHash primitives.Sha256) *ErrTransactionRejected {
+	threshold := primitives.TimestampNano(time.Now().Add(vctx.expiryWindow * -1).UnixNano())
+	if transaction.Transaction().Timestamp() < threshold {
+		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED, log.TimestampNano("min-timestamp", threshold), log.TimestampNano("tx-timestamp", transaction.Transaction().Timestamp())}
 	}
-}
 
-func validateTransactionNotInFuture(vctx *validationContext) validator {
-	return func(transaction *protocol.SignedTransaction) *ErrTransactionRejected {
-		tsWithGrace := vctx.lastCommittedBlockTimestamp + primitives.TimestampNano(vctx.futureTimestampGrace.Nanoseconds())
-		if transaction.Transaction().Timestamp() > tsWithGrace {
-			return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_AHEAD_OF_NODE_TIME, log.TimestampNano("max-timestamp", tsWithGrace), log.TimestampNano("tx-timestamp", transaction.Transaction().Timestamp())}
-		}
+	return nil
+}
 
-		return nil
+func validateTransactionNotInFuture(vctx *validationContext, transaction *protocol.SignedTransaction, txHash primitives.Sha256) *ErrTransactionRejected {
+	tsWithGrace := vctx.lastCommittedBlockTimestamp + primitives.TimestampNano(vctx.futureTimestampGrace.Nanoseconds())
+	if transaction.Transaction().Timestamp() > tsWithGrace {
+		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_AHEAD_OF_NODE_TIME, log.TimestampNano("max-timestamp", tsWithGrace), log.TimestampNano("tx-timestamp", transaction.Transaction().Timestamp())}
 	}
+
+	return nil
 }
 
-func validateTransactionVirtualChainId(vctx *validationContext) validator {
-	return func(transaction *protocol.SignedTransaction) *ErrTransactionRejected {
-		if !transaction.Transaction().VirtualChainId().Equal(vctx.virtualChainId) {
-			return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH, log.VirtualChainId(vctx.virtualChainId), log.VirtualChainId(transaction.Transaction().VirtualChainId())}
+func validateTransactionVirtualChainId(vctx *validationContext, transaction *protocol.SignedTransaction, txHash primitives.Sha256) *ErrTransactionRejected {
+	if !transaction.Transaction().VirtualChainId().Equal(vctx.virtualChainId) {
+		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH, log.VirtualChainId(vctx.virtualChainId), log.VirtualChainId(transaction.Transaction().VirtualChainId())}
 
-		}
-		return nil
 	}
+	return nil
 }
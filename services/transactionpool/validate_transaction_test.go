@@ -2,7 +2,9 @@ package transactionpool
 
 import (
 	"fmt"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/test/builders"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/stretchr/testify/require"
@@ -21,6 +23,7 @@ func aValidationContext() *validationContext {
 		lastCommittedBlockTimestamp: lastCommittedBlockTimestamp,
 		futureTimestampGrace:        futureTimestampGrace,
 		virtualChainId:              builders.DEFAULT_TEST_VIRTUAL_CHAIN_ID,
+		signatureVerifier:           newSignatureVerifier(10),
 	}
 }
 
@@ -45,6 +48,7 @@ func TestValidateTransaction_InvalidTransactions(t *testing.T) {
 		{"protocol version", aTransactionAtNodeTimestamp().WithProtocolVersion(ProtocolVersion + 1), protocol.TRANSACTION_STATUS_REJECTED_UNSUPPORTED_VERSION},
 		{"signer scheme", aTransactionAtNodeTimestamp().WithInvalidSignerScheme(), protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME},
 		{"signer public key (wrong length)", aTransactionAtNodeTimestamp().WithInvalidPublicKey(), protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH},
+		{"signature (not signed by the signer)", aTransactionAtNodeTimestamp().WithInvalidEd25519Signer(testKeys.Ed25519KeyPairForTests(1)), protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH},
 		{"contract name", aTransactionAtNodeTimestamp().WithContract(""), protocol.TRANSACTION_STATUS_RESERVED},
 		{"timestamp (created prior to the expiry window)", builders.TransferTransaction().WithTimestamp(time.Now().Add(expirationWindowInterval * -2)), protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED},
 		{"timestamp (ahead of timestamp for last committed block)", builders.TransferTransaction().WithTimestamp(futureTimeAfterGracePeriod()), protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_AHEAD_OF_NODE_TIME},
@@ -65,3 +69,62 @@ func TestValidateTransaction_InvalidTransactions(t *testing.T) {
 func aTransactionAtNodeTimestamp() *builders.TransactionBuilder {
 	return builders.TransferTransaction().WithTimestamp(time.Unix(0, int64(lastCommittedBlockTimestamp+1000)))
 }
+
+func TestValidateBatch_ReturnsResultOfEachTransactionAtItsIndex(t *testing.T) {
+	t.Parallel()
+	batch := []*pendingTransaction{
+		aPendingTransaction(aTransactionAtNodeTimestamp().Build()),
+		aPendingTransaction(aTransactionAtNodeTimestamp().WithInvalidEd25519Signer(testKeys.Ed25519KeyPairForTests(1)).Build()),
+		aPendingTransaction(aTransactionAtNodeTimestamp().Build()),
+		aPendingTransaction(aTransactionAtNodeTimestamp().WithVirtualChainId(primitives.VirtualChainId(1)).Build()),
+	}
+
+	errs := aValidationContext().validateBatch(batch)
+
+	require.Len(t, errs, len(batch))
+	require.Nil(t, errs[0], "a valid transaction was rejected")
+	require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, errs[1].TransactionStatus)
+	require.Nil(t, errs[2], "a valid transaction was rejected")
+	require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH, errs[3].TransactionStatus)
+}
+
+func aPendingTransaction(tx *protocol.SignedTransaction) *pendingTransaction {
+	return &pendingTransaction{transaction: tx, txHash: digest.CalcTxHash(tx.Transaction()), size: sizeOfSignedTransaction(tx)}
+}
+
+func aPendingBatchOfSize(size int) []*pendingTransaction {
+	batch := make([]*pendingTransaction, size)
+	for i := range batch {
+		batch[i] = aPendingTransaction(aTransactionAtNodeTimestamp().Build())
+	}
+	return batch
+}
+
+// the signature cache is disabled so that every iteration pays for signature verification like a batch of fresh transactions would
+func aValidationContextWithoutSignatureCache() *validationContext {
+	vctx := aValidationContext()
+	vctx.signatureVerifier = newSignatureVerifier(0)
+	return vctx
+}
+
+func BenchmarkValidateTransaction_Sequential10k(b *testing.B) {
+	b.StopTimer()
+	batch := aPendingBatchOfSize(10000)
+	vctx := aValidationContextWithoutSignatureCache()
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for _, pendingTx := range batch {
+			vctx.validateTransaction(pendingTx.transaction)
+		}
+	}
+}
+
+func BenchmarkValidateBatch_Concurrent10k(b *testing.B) {
+	b.StopTimer()
+	batch := aPendingBatchOfSize(10000)
+	vctx := aValidationContextWithoutSignatureCache()
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		vctx.validateBatch(batch)
+	}
+}
@@ -18,6 +18,7 @@ func (s *service) ValidateTransactionsForOrdering(ctx context.Context, input *se
 	}
 
 	vctx := s.createValidationContext()
+	s.signatureVerifier.verifyBatch(input.SignedTransactions)
 
 	for _, tx := range input.SignedTransactions {
 		txHash := digest.CalcTxHash(tx.Transaction())
@@ -30,12 +31,18 @@ func (s *service) ValidateTransactionsForOrdering(ctx context.Context, input *se
 		}
 	}
 
-	//TODO handle error from vm
 	bh, _ := s.currentBlockHeightAndTime()
-	preOrderResults, _ := s.virtualMachine.TransactionSetPreOrder(ctx, &services.TransactionSetPreOrderInput{
+	preOrderResults, err := s.virtualMachine.TransactionSetPreOrder(ctx, &services.TransactionSetPreOrderInput{
 		SignedTransactions: input.SignedTransactions,
 		BlockHeight:        bh,
 	})
+	if err == nil && (preOrderResults == nil || len(preOrderResults.PreOrderResults) != len(input.SignedTransactions)) {
+		err = errors.Errorf("expected %d results from pre-order check, got %+v", len(input.SignedTransactions), preOrderResults)
+	}
+	if err != nil {
+		s.metrics.preOrderFailures.Inc()
+		return nil, errors.Wrap(err, "failed to pre-order transactions")
+	}
 
 	for i, tx := range input.SignedTransactions {
 		if status := preOrderResults.PreOrderResults[i]; status != protocol.TRANSACTION_STATUS_PRE_ORDER_VALID {
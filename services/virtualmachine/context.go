@@ -1,19 +1,42 @@
 package virtualmachine
 
 import (
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
 	"sync"
 )
 
+type ErrExecutionBudgetExceeded struct {
+	Reason string
+}
+
+func (e *ErrExecutionBudgetExceeded) Error() string {
+	return "execution budget exceeded: " + e.Reason
+}
+
 type executionContext struct {
 	contextId           primitives.ExecutionContextId
 	blockHeight         primitives.BlockHeight
+	blockTimestamp      primitives.TimestampNano // consensus data that pins crosschain reads, unlike the local clock
 	serviceStack        []primitives.ContractName
 	transientState      *transientState
 	accessScope         protocol.ExecutionAccessScope
 	batchTransientState *transientState
 	transaction         *protocol.Transaction
+	eventList           []*protocol.EventBuilder
+	stateReadSet        stateKeySet
+
+	config            config.VirtualMachineConfig
+	sdkCallsMade      uint32
+	stateBytesRead    uint64
+	stateBytesWritten uint64
+	instructionsUsed  uint64
+	bytesAllocated    uint64
+	budgetExceededErr *ErrExecutionBudgetExceeded
+	aborted           <-chan struct{} // closed when the transaction set ran out of time, which is not part of the budget
 }
 
 func (c *executionContext) serviceStackTop() primitives.ContractName {
@@ -24,8 +47,12 @@ func (c *executionContext) serviceStackTop() primitives.ContractName {
 	return res
 }
 
-func (c *executionContext) serviceStackPush(service primitives.ContractName) {
+func (c *executionContext) serviceStackPush(service primitives.ContractName) error {
+	if uint32(len(c.serviceStack)) >= c.config.VirtualMachineMaxCallStackDepth() {
+		return c.exceedBudget("service call stack depth of %d reached when calling %s", c.config.VirtualMachineMaxCallStackDepth(), service)
+	}
 	c.serviceStack = append(c.serviceStack, service)
+	return nil
 }
 
 func (c *executionContext) serviceStackPop() {
@@ -53,29 +80,115 @@ func (c *executionContext) serviceStackPeekCaller() primitives.ContractName {
 	return c.serviceStack[len(c.serviceStack)-2]
 }
 
+func (c *executionContext) eventListAdd(contractName primitives.ContractName, eventName string, outputArgumentArray []byte) {
+	c.eventList = append(c.eventList, &protocol.EventBuilder{
+		ContractName:        contractName,
+		EventName:           eventName,
+		OutputArgumentArray: outputArgumentArray,
+	})
+}
+
+func (c *executionContext) eventListBuild() *protocol.EventsArray {
+	return (&protocol.EventsArrayBuilder{
+		Events: c.eventList,
+	}).Build()
+}
+
+// go cannot stop a running contract, so one that is still running when its execution is aborted is stopped at its next sdk call
+func (c *executionContext) verifyNotAborted() error {
+	select {
+	case <-c.aborted:
+		return errors.New("execution aborted")
+	default:
+		return nil
+	}
+}
+
+// sdk calls are counted rather than timed so every node exhausts the budget of a transaction at the same point
+func (c *executionContext) meterSdkCall() error {
+	c.sdkCallsMade++
+	if c.sdkCallsMade > c.config.VirtualMachineSdkCallBudget() {
+		return c.exceedBudget("sdk call budget of %d calls used up", c.config.VirtualMachineSdkCallBudget())
+	}
+	return nil
+}
+
+func (c *executionContext) meterStateRead(key []byte, value []byte) error {
+	c.stateBytesRead += uint64(len(key) + len(value))
+	if c.stateBytesRead > uint64(c.config.VirtualMachineStateReadBudgetInBytes()) {
+		return c.exceedBudget("state read budget of %d bytes used up", c.config.VirtualMachineStateReadBudgetInBytes())
+	}
+	return nil
+}
+
+func (c *executionContext) meterStateWrite(key []byte, value []byte) error {
+	c.stateBytesWritten += uint64(len(key) + len(value))
+	if c.stateBytesWritten > uint64(c.config.VirtualMachineStateWriteBudgetInBytes()) {
+		return c.exceedBudget("state write budget of %d bytes used up", c.config.VirtualMachineStateWriteBudgetInBytes())
+	}
+	return nil
+}
+
+// deployed contract code is charged by the metering the native compiler injects into it, which also works for a
+// contract that makes no sdk calls
+func (c *executionContext) meterInstructions(count uint32) error {
+	c.instructionsUsed += uint64(count)
+	if c.instructionsUsed > uint64(c.config.VirtualMachineInstructionBudget()) {
+		return c.exceedBudget("instruction budget of %d instructions used up", c.config.VirtualMachineInstructionBudget())
+	}
+	return nil
+}
+
+// allocations are charged before they are made, so one that does not fit the budget is never made
+func (c *executionContext) meterAllocation(bytes uint64) error {
+	if bytes > uint64(c.config.VirtualMachineAllocationBudgetInBytes())-c.bytesAllocated {
+		c.bytesAllocated = uint64(c.config.VirtualMachineAllocationBudgetInBytes())
+		return c.exceedBudget("allocation budget of %d bytes used up", c.config.VirtualMachineAllocationBudgetInBytes())
+	}
+	c.bytesAllocated += bytes
+	return nil
+}
+
+// exceeding the budget fails the whole transaction even if the contract ignores the error it got from the sdk
+func (c *executionContext) exceedBudget(format string, args ...interface{}) error {
+	if c.budgetExceededErr == nil {
+		c.budgetExceededErr = &ErrExecutionBudgetExceeded{fmt.Sprintf(format, args...)}
+	}
+	return c.budgetExceededErr
+}
+
+func (c *executionContext) budgetExceeded() *ErrExecutionBudgetExceeded {
+	return c.budgetExceededErr
+}
+
 type executionContextProvider struct {
 	mutex          *sync.RWMutex
 	activeContexts map[primitives.ExecutionContextId]*executionContext
 	lastContextId  primitives.ExecutionContextId
+	config         config.VirtualMachineConfig
 }
 
-func newExecutionContextProvider() *executionContextProvider {
+func newExecutionContextProvider(config config.VirtualMachineConfig) *executionContextProvider {
 	return &executionContextProvider{
+		config:         config,
 		mutex:          &sync.RWMutex{},
 		activeContexts: make(map[primitives.ExecutionContextId]*executionContext),
 	}
 }
 
-func (cp *executionContextProvider) allocateExecutionContext(blockHeight primitives.BlockHeight, accessScope protocol.ExecutionAccessScope, transaction *protocol.Transaction) (primitives.ExecutionContextId, *executionContext) {
+func (cp *executionContextProvider) allocateExecutionContext(blockHeight primitives.BlockHeight, blockTimestamp primitives.TimestampNano, accessScope protocol.ExecutionAccessScope, transaction *protocol.Transaction) (primitives.ExecutionContextId, *executionContext) {
 	cp.mutex.Lock()
 	defer cp.mutex.Unlock()
 
 	newContext := &executionContext{
 		blockHeight:    blockHeight,
+		blockTimestamp: blockTimestamp,
 		serviceStack:   []primitives.ContractName{},
 		transientState: newTransientState(),
 		accessScope:    accessScope,
 		transaction:    transaction,
+		eventList:      []*protocol.EventBuilder{},
+		config:         cp.config,
 	}
 
 	// TODO: improve this mechanism because it wraps around on overflow
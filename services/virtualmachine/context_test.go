@@ -1,18 +1,20 @@
 package virtualmachine
 
 import (
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestContext_Load(t *testing.T) {
-	cp := newExecutionContextProvider()
+	cp := newExecutionContextProvider(config.ForVirtualMachineTests(16, 1000, 1024, 1024, 1000000, 1024*1024, 1, 1*time.Second))
 
-	contextId1, _ := cp.allocateExecutionContext(1, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	contextId1, _ := cp.allocateExecutionContext(1, 0, protocol.ACCESS_SCOPE_READ_ONLY, nil)
 	defer cp.destroyExecutionContext(contextId1)
 
-	contextId2, _ := cp.allocateExecutionContext(2, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	contextId2, _ := cp.allocateExecutionContext(2, 0, protocol.ACCESS_SCOPE_READ_ONLY, nil)
 	defer cp.destroyExecutionContext(contextId1)
 
 	require.NotEqual(t, contextId1, contextId2, "contextId1 should be different from contextId2")
@@ -25,18 +27,18 @@ func TestContext_Load(t *testing.T) {
 }
 
 func TestContext_ServiceStack(t *testing.T) {
-	cp := newExecutionContextProvider()
-	executionContextId, c := cp.allocateExecutionContext(1, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	cp := newExecutionContextProvider(config.ForVirtualMachineTests(16, 1000, 1024, 1024, 1000000, 1024*1024, 1, 1*time.Second))
+	executionContextId, c := cp.allocateExecutionContext(1, 0, protocol.ACCESS_SCOPE_READ_ONLY, nil)
 	defer cp.destroyExecutionContext(executionContextId)
 
-	c.serviceStackPush("Service1")
+	require.NoError(t, c.serviceStackPush("Service1"), "push should succeed")
 	service := c.serviceStackTop()
 	require.EqualValues(t, "Service1", service, "service top should be initialized")
 	require.Equal(t, 1, c.serviceStackDepth(), "service stack depth should match")
 	require.EqualValues(t, "Service1", c.serviceStackPeekCurrent(), "current service should match")
 	require.Zero(t, c.serviceStackPeekCaller(), "calling service should be empty")
 
-	c.serviceStackPush("Service2")
+	require.NoError(t, c.serviceStackPush("Service2"), "push should succeed")
 	service = c.serviceStackTop()
 	require.EqualValues(t, "Service2", service, "service top should change after push")
 	require.Equal(t, 2, c.serviceStackDepth(), "service stack depth should match")
@@ -50,3 +52,73 @@ func TestContext_ServiceStack(t *testing.T) {
 	require.EqualValues(t, "Service1", c.serviceStackPeekCurrent(), "current service should match")
 	require.Zero(t, c.serviceStackPeekCaller(), "calling service should be empty")
 }
+
+func TestContext_ServiceStackDepthIsLimited(t *testing.T) {
+	cp := newExecutionContextProvider(config.ForVirtualMachineTests(2, 1000, 1024, 1024, 1000000, 1024*1024, 1, 1*time.Second))
+	executionContextId, c := cp.allocateExecutionContext(1, 0, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	defer cp.destroyExecutionContext(executionContextId)
+
+	require.NoError(t, c.serviceStackPush("Service1"), "push within the depth limit should succeed")
+	require.NoError(t, c.serviceStackPush("Service2"), "push within the depth limit should succeed")
+	err := c.serviceStackPush("Service3")
+	require.Error(t, err, "push beyond the depth limit should fail")
+	require.Equal(t, 2, c.serviceStackDepth(), "failed push should not change the service stack")
+	require.Equal(t, err, c.budgetExceeded(), "failed push should exceed the budget")
+}
+
+func TestContext_StateBudgets(t *testing.T) {
+	cp := newExecutionContextProvider(config.ForVirtualMachineTests(16, 1000, 10, 5, 1000000, 1024*1024, 1, 1*time.Second))
+	executionContextId, c := cp.allocateExecutionContext(1, 0, protocol.ACCESS_SCOPE_READ_WRITE, nil)
+	defer cp.destroyExecutionContext(executionContextId)
+
+	require.NoError(t, c.meterStateRead([]byte{0x01}, []byte{0x02, 0x03}), "read within the budget should succeed")
+	require.NoError(t, c.meterStateWrite([]byte{0x01}, []byte{0x02, 0x03, 0x04, 0x05}), "write using the entire budget should succeed")
+	require.Nil(t, c.budgetExceeded(), "budget should not be exceeded yet")
+
+	require.Error(t, c.meterStateWrite([]byte{0x01}, []byte{}), "write beyond the budget should fail")
+	require.Error(t, c.meterStateRead([]byte{0x01}, []byte{0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}), "read beyond the budget should fail")
+	require.Contains(t, c.budgetExceeded().Error(), "state write budget of 5 bytes", "first exhausted budget should be reported")
+}
+
+func TestContext_SdkCallBudget(t *testing.T) {
+	cp := newExecutionContextProvider(config.ForVirtualMachineTests(16, 2, 1024, 1024, 1000000, 1024*1024, 1, 1*time.Second))
+	executionContextId, c := cp.allocateExecutionContext(1, 0, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	defer cp.destroyExecutionContext(executionContextId)
+
+	require.NoError(t, c.meterSdkCall(), "sdk call within the budget should succeed")
+	require.NoError(t, c.meterSdkCall(), "sdk call using the entire budget should succeed")
+	require.Nil(t, c.budgetExceeded(), "budget should not be exceeded yet")
+
+	require.Error(t, c.meterSdkCall(), "sdk call beyond the budget should fail")
+	require.Contains(t, c.budgetExceeded().Error(), "sdk call budget of 2 calls", "exhausted budget should be reported")
+}
+
+func TestContext_InstructionAndAllocationBudgets(t *testing.T) {
+	cp := newExecutionContextProvider(config.ForVirtualMachineTests(16, 1000, 1024, 1024, 2000, 100, 1, 1*time.Second))
+	executionContextId, c := cp.allocateExecutionContext(1, 0, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	defer cp.destroyExecutionContext(executionContextId)
+
+	require.NoError(t, c.meterInstructions(1000), "instructions within the budget should succeed")
+	require.NoError(t, c.meterInstructions(1000), "instructions using the entire budget should succeed")
+	require.NoError(t, c.meterAllocation(60), "allocation within the budget should succeed")
+	require.NoError(t, c.meterAllocation(40), "allocation using the entire budget should succeed")
+	require.Nil(t, c.budgetExceeded(), "budget should not be exceeded yet")
+
+	require.Error(t, c.meterAllocation(1<<63), "allocation beyond the budget should fail")
+	require.Error(t, c.meterInstructions(1000), "instructions beyond the budget should fail")
+	require.Contains(t, c.budgetExceeded().Error(), "allocation budget of 100 bytes", "first exhausted budget should be reported")
+}
+
+func TestContext_AbortIsNotPartOfTheBudget(t *testing.T) {
+	cp := newExecutionContextProvider(config.ForVirtualMachineTests(16, 1000, 1024, 1024, 1000000, 1024*1024, 1, 1*time.Second))
+	executionContextId, c := cp.allocateExecutionContext(1, 0, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	defer cp.destroyExecutionContext(executionContextId)
+
+	require.NoError(t, c.verifyNotAborted(), "execution that cannot be aborted should not fail")
+	aborted := make(chan struct{})
+	c.aborted = aborted
+	require.NoError(t, c.verifyNotAborted(), "execution should not fail before it is aborted")
+	close(aborted)
+	require.Error(t, c.verifyNotAborted(), "execution should fail once aborted")
+	require.Nil(t, c.budgetExceeded(), "abort should not exceed the budget")
+}
@@ -15,6 +15,9 @@ func (s *service) getServiceDeployment(ctx context.Context, executionContext *ex
 
 	// on failure (contract not deployed), attempt to auto deploy native contract
 	if err != nil {
+		if executionContext.budgetExceeded() != nil {
+			return nil, err
+		}
 		processorType, err = s.attemptToAutoDeployNativeContract(ctx, executionContext, serviceName)
 		if err != nil {
 			return nil, err
@@ -59,7 +62,10 @@ func (s *service) callGetInfoOfDeploymentSystemContract(ctx context.Context, exe
 	systemMethodName := primitives.MethodName(deployments_systemcontract.METHOD_GET_INFO.Name)
 
 	// modify execution context
-	executionContext.serviceStackPush(systemContractName)
+	err := executionContext.serviceStackPush(systemContractName)
+	if err != nil {
+		return 0, err
+	}
 	defer executionContext.serviceStackPop()
 
 	// execute the call
@@ -100,7 +106,10 @@ func (s *service) callDeployServiceOfDeploymentSystemContract(ctx context.Contex
 	systemMethodName := primitives.MethodName(deployments_systemcontract.METHOD_DEPLOY_SERVICE.Name)
 
 	// modify execution context
-	executionContext.serviceStackPush(systemContractName)
+	err := executionContext.serviceStackPush(systemContractName)
+	if err != nil {
+		return err
+	}
 	defer executionContext.serviceStackPop()
 
 	// execute the call
@@ -123,7 +132,7 @@ func (s *service) callDeployServiceOfDeploymentSystemContract(ctx context.Contex
 			},
 		},
 	}).Build()
-	_, err := s.processors[protocol.PROCESSOR_TYPE_NATIVE].ProcessCall(ctx, &services.ProcessCallInput{
+	_, err = s.processors[protocol.PROCESSOR_TYPE_NATIVE].ProcessCall(ctx, &services.ProcessCallInput{
 		ContextId:              executionContext.contextId,
 		ContractName:           systemContractName,
 		MethodName:             systemMethodName,
@@ -1,38 +1,50 @@
 package virtualmachine
 
 import (
+	"bytes"
 	"context"
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
-	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"sort"
 )
 
 func (s *service) runMethod(
 	ctx context.Context,
 	blockHeight primitives.BlockHeight,
+	blockTimestamp primitives.TimestampNano,
 	transaction *protocol.Transaction,
 	accessScope protocol.ExecutionAccessScope,
 	batchTransientState *transientState,
-) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+	stateReadSet stateKeySet,
+) (protocol.ExecutionResult, *protocol.MethodArgumentArray, *protocol.EventsArray, error) {
 
 	// create execution context
-	executionContextId, executionContext := s.contexts.allocateExecutionContext(blockHeight, accessScope, transaction)
+	executionContextId, executionContext := s.contexts.allocateExecutionContext(blockHeight, blockTimestamp, accessScope, transaction)
 	defer s.contexts.destroyExecutionContext(executionContextId)
+	executionContext.aborted = ctx.Done()
 
 	// get deployment info
 	processor, err := s.getServiceDeployment(ctx, executionContext, transaction.ContractName())
+	if executionContext.budgetExceeded() != nil {
+		return s.executionBudgetExceeded(executionContext, transaction)
+	}
 	if err != nil {
 		s.logger.Info("get deployment info for contract failed", log.Error(err), log.Stringable("transaction", transaction))
-		return protocol.EXECUTION_RESULT_ERROR_UNEXPECTED, nil, err
+		return protocol.EXECUTION_RESULT_ERROR_UNEXPECTED, nil, nil, err
 	}
 
 	// modify execution context
-	executionContext.serviceStackPush(transaction.ContractName())
+	err = executionContext.serviceStackPush(transaction.ContractName())
+	if err != nil {
+		return s.executionBudgetExceeded(executionContext, transaction)
+	}
 	defer executionContext.serviceStackPop()
 	executionContext.batchTransientState = batchTransientState
+	executionContext.stateReadSet = stateReadSet
 
 	// execute the call
 	inputArgs := protocol.MethodArgumentArrayReader(transaction.RawInputArgumentArrayWithHeader())
@@ -45,44 +57,80 @@ func (s *service) runMethod(
 		CallingPermissionScope: protocol.PERMISSION_SCOPE_SERVICE,
 		CallingService:         transaction.ContractName(),
 	})
+	if ctx.Err() != nil {
+		return protocol.EXECUTION_RESULT_ERROR_UNEXPECTED, nil, nil, errors.Wrap(ctx.Err(), "transaction execution aborted")
+	}
+	if executionContext.budgetExceeded() != nil {
+		return s.executionBudgetExceeded(executionContext, transaction)
+	}
 	if err != nil {
 		s.logger.Info("transaction execution failed", log.Stringable("result", output.CallResult), log.Error(err), log.Stringable("transaction", transaction))
 	}
 
-	if batchTransientState != nil && output.CallResult == protocol.EXECUTION_RESULT_SUCCESS {
+	if output.CallResult != protocol.EXECUTION_RESULT_SUCCESS {
+		return output.CallResult, output.OutputArgumentArray, nil, err
+	}
+
+	if batchTransientState != nil {
 		executionContext.transientState.mergeIntoTransientState(batchTransientState)
 	}
 
-	return output.CallResult, output.OutputArgumentArray, err
+	return output.CallResult, output.OutputArgumentArray, executionContext.eventListBuild(), err
+}
+
+// the transaction fails with a result of its own, its transient state and events are dropped so nothing it did before
+// running out of budget is kept
+func (s *service) executionBudgetExceeded(executionContext *executionContext, transaction *protocol.Transaction) (protocol.ExecutionResult, *protocol.MethodArgumentArray, *protocol.EventsArray, error) {
+	err := executionContext.budgetExceeded()
+	s.logger.Info("transaction execution budget exceeded", log.Error(err), log.Stringable("transaction", transaction))
+
+	outputArgs := (&protocol.MethodArgumentArrayBuilder{
+		Arguments: []*protocol.MethodArgumentBuilder{
+			{Name: "string", Type: protocol.METHOD_ARGUMENT_TYPE_STRING_VALUE, StringValue: err.Error()},
+		},
+	}).Build()
+	return protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED, outputArgs, nil, err
 }
 
 func (s *service) processTransactionSet(
 	ctx context.Context,
 	blockHeight primitives.BlockHeight,
+	blockTimestamp primitives.TimestampNano,
 	signedTransactions []*protocol.SignedTransaction,
-) ([]*protocol.TransactionReceipt, []*protocol.ContractStateDiff) {
-	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
-
+) ([]*protocol.TransactionReceipt, []*protocol.ContractStateDiff, error) {
 	// create batch transient state
 	batchTransientState := newTransientState()
 
 	// receipts for result
 	receipts := make([]*protocol.TransactionReceipt, 0, len(signedTransactions))
 
-	for _, signedTransaction := range signedTransactions {
+	// run the transactions in parallel first, then commit them in block order repeating those that read an earlier write
+	speculativeExecutions := s.executeTransactionsSpeculatively(ctx, blockHeight, blockTimestamp, signedTransactions)
+	if ctx.Err() != nil {
+		return nil, nil, errors.Wrap(ctx.Err(), "transaction set execution aborted while executing speculatively")
+	}
+
+	for i, signedTransaction := range signedTransactions {
 
-		logger.Info("processing transaction", log.Stringable("contract", signedTransaction.Transaction().ContractName()), log.Stringable("method", signedTransaction.Transaction().MethodName()), log.BlockHeight(blockHeight))
-		callResult, outputArgs, _ := s.runMethod(ctx, blockHeight, signedTransaction.Transaction(), protocol.ACCESS_SCOPE_READ_WRITE, batchTransientState)
-		if outputArgs == nil {
-			outputArgs = (&protocol.MethodArgumentArrayBuilder{}).Build()
+		var execution *transactionExecution
+		if speculativeExecutions != nil && speculativeExecutions[i].isValidAfter(batchTransientState) {
+			execution = speculativeExecutions[i]
+			execution.stateDiff.mergeIntoTransientState(batchTransientState)
+		} else {
+			execution = s.executeTransaction(ctx, blockHeight, blockTimestamp, signedTransaction.Transaction(), batchTransientState, nil)
 		}
 
-		receipt := s.encodeTransactionReceipt(signedTransaction.Transaction(), callResult, outputArgs)
+		// a receipt depends on the node being fast enough, so the block is not built rather than have the nodes disagree
+		if ctx.Err() != nil {
+			return nil, nil, errors.Wrapf(ctx.Err(), "transaction set execution aborted after %d of %d transactions", i, len(signedTransactions))
+		}
+
+		receipt := s.encodeTransactionReceipt(signedTransaction.Transaction(), execution.callResult, execution.outputArgs, execution.outputEvents)
 		receipts = append(receipts, receipt)
 	}
 
 	stateDiffs := s.encodeBatchTransientStateToStateDiffs(batchTransientState)
-	return receipts, stateDiffs
+	return receipts, stateDiffs, nil
 }
 
 func (s *service) getRecentBlockHeight(ctx context.Context) (primitives.BlockHeight, primitives.TimestampNano, error) {
@@ -93,17 +141,25 @@ func (s *service) getRecentBlockHeight(ctx context.Context) (primitives.BlockHei
 	return output.LastCommittedBlockHeight, output.LastCommittedBlockTimestamp, nil
 }
 
-func (s *service) encodeTransactionReceipt(transaction *protocol.Transaction, result protocol.ExecutionResult, outputArgs *protocol.MethodArgumentArray) *protocol.TransactionReceipt {
+func (s *service) encodeTransactionReceipt(transaction *protocol.Transaction, result protocol.ExecutionResult, outputArgs *protocol.MethodArgumentArray, outputEvents *protocol.EventsArray) *protocol.TransactionReceipt {
 	return (&protocol.TransactionReceiptBuilder{
 		Txhash:              digest.CalcTxHash(transaction),
 		ExecutionResult:     result,
 		OutputArgumentArray: outputArgs.RawArgumentsArray(),
+		OutputEventsArray:   outputEvents.RawEventsArray(),
 	}).Build()
 }
 
+// contracts and keys are sorted so every node encodes the same diffs no matter how the batch was executed
 func (s *service) encodeBatchTransientStateToStateDiffs(batchTransientState *transientState) []*protocol.ContractStateDiff {
+	contractNames := make([]primitives.ContractName, 0, len(batchTransientState.contracts))
+	for contractName := range batchTransientState.contracts {
+		contractNames = append(contractNames, contractName)
+	}
+	sort.Slice(contractNames, func(i, j int) bool { return contractNames[i] < contractNames[j] })
+
 	res := []*protocol.ContractStateDiff{}
-	for contractName, _ := range batchTransientState.contracts {
+	for _, contractName := range contractNames {
 		stateDiffs := []*protocol.StateRecordBuilder{}
 		batchTransientState.forDirty(contractName, func(key []byte, value []byte) {
 			stateDiffs = append(stateDiffs, &protocol.StateRecordBuilder{
@@ -111,6 +167,7 @@ func (s *service) encodeBatchTransientStateToStateDiffs(batchTransientState *tra
 				Value: value,
 			})
 		})
+		sort.Slice(stateDiffs, func(i, j int) bool { return bytes.Compare(stateDiffs[i].Key, stateDiffs[j].Key) < 0 })
 		if len(stateDiffs) > 0 {
 			res = append(res, (&protocol.ContractStateDiffBuilder{
 				ContractName: contractName,
@@ -0,0 +1,109 @@
+package virtualmachine
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/synchronization/supervised"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"strconv"
+	"sync"
+)
+
+// keys a transaction read from the batch transient state or from state storage, per contract
+type stateKeySet map[primitives.ContractName]map[string]bool
+
+func newStateKeySet() stateKeySet {
+	return make(stateKeySet)
+}
+
+func (ks stateKeySet) add(contract primitives.ContractName, key []byte) {
+	keys, found := ks[contract]
+	if !found {
+		keys = make(map[string]bool)
+		ks[contract] = keys
+	}
+	keys[keyForMap(key)] = true
+}
+
+// true if any of the keys was written to the transient state
+func (ks stateKeySet) intersects(t *transientState) bool {
+	for contract, keys := range ks {
+		for key := range keys {
+			if _, found := t.getValue(contract, []byte(key)); found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type transactionExecution struct {
+	callResult   protocol.ExecutionResult
+	outputArgs   *protocol.MethodArgumentArray
+	outputEvents *protocol.EventsArray
+	stateReadSet stateKeySet
+	stateDiff    *transientState
+}
+
+func (s *service) executeTransaction(ctx context.Context, blockHeight primitives.BlockHeight, blockTimestamp primitives.TimestampNano, transaction *protocol.Transaction, batchTransientState *transientState, stateReadSet stateKeySet) *transactionExecution {
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
+
+	logger.Info("processing transaction", log.Stringable("contract", transaction.ContractName()), log.Stringable("method", transaction.MethodName()), log.BlockHeight(blockHeight), log.String("speculative", strconv.FormatBool(stateReadSet != nil)))
+	callResult, outputArgs, outputEvents, _ := s.runMethod(ctx, blockHeight, blockTimestamp, transaction, protocol.ACCESS_SCOPE_READ_WRITE, batchTransientState, stateReadSet)
+	if outputArgs == nil {
+		outputArgs = (&protocol.MethodArgumentArrayBuilder{}).Build()
+	}
+	if outputEvents == nil {
+		outputEvents = (&protocol.EventsArrayBuilder{}).Build()
+	}
+	return &transactionExecution{
+		callResult:   callResult,
+		outputArgs:   outputArgs,
+		outputEvents: outputEvents,
+		stateReadSet: stateReadSet,
+		stateDiff:    batchTransientState,
+	}
+}
+
+// every transaction runs against the state of the previous block only, each with a transient state of its own which
+// holds its writes once it succeeds; nil means the set is small enough or the node is configured to run it sequentially
+func (s *service) executeTransactionsSpeculatively(ctx context.Context, blockHeight primitives.BlockHeight, blockTimestamp primitives.TimestampNano, signedTransactions []*protocol.SignedTransaction) []*transactionExecution {
+	maxParallel := int(s.config.VirtualMachineMaxParallelTransactions())
+	if maxParallel <= 1 || len(signedTransactions) <= 1 {
+		return nil
+	}
+	if maxParallel > len(signedTransactions) {
+		maxParallel = len(signedTransactions)
+	}
+
+	executions := make([]*transactionExecution, len(signedTransactions))
+	indexes := make(chan int, len(signedTransactions))
+	for i := range signedTransactions {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(maxParallel)
+	for w := 0; w < maxParallel; w++ {
+		supervised.GoOnce(s.logger, func() {
+			defer wg.Done()
+			for i := range indexes {
+				executions[i] = s.executeTransaction(ctx, blockHeight, blockTimestamp, signedTransactions[i].Transaction(), newTransientState(), newStateKeySet())
+			}
+		})
+	}
+	wg.Wait()
+
+	return executions
+}
+
+// a speculative execution is only kept if it could not have observed anything the transactions before it wrote
+func (e *transactionExecution) isValidAfter(batchTransientState *transientState) bool {
+	if e == nil {
+		return false
+	}
+	return !e.stateReadSet.intersects(batchTransientState)
+}
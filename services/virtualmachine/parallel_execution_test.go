@@ -0,0 +1,22 @@
+package virtualmachine
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStateKeySetIntersectsOnlyWrittenKeysOfSameContract(t *testing.T) {
+	ks := newStateKeySet()
+	ks.add("Contract1", []byte{0x01})
+	ks.add("Contract2", []byte{0x02})
+
+	s := newTransientState()
+	require.False(t, ks.intersects(s), "empty transient state should not intersect")
+
+	s.setValue("Contract1", []byte{0x02}, []byte{0x77}, true)
+	s.setValue("Contract2", []byte{0x01}, []byte{0x77}, true)
+	require.False(t, ks.intersects(s), "same keys of other contracts should not intersect")
+
+	s.setValue("Contract2", []byte{0x02}, []byte{0x88}, true)
+	require.True(t, ks.intersects(s), "written key should intersect")
+}
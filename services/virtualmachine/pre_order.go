@@ -12,16 +12,19 @@ func (s *service) callGlobalPreOrderSystemContract(ctx context.Context, blockHei
 	systemContractName := primitives.ContractName(globalpreorder_systemcontract.CONTRACT.Name)
 	systemMethodName := primitives.MethodName(globalpreorder_systemcontract.METHOD_APPROVE.Name)
 
-	// create execution context
-	executionContextId, executionContext := s.contexts.allocateExecutionContext(blockHeight, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	// create execution context (the system contract never calls ethereum so it needs no reference timestamp)
+	executionContextId, executionContext := s.contexts.allocateExecutionContext(blockHeight, 0, protocol.ACCESS_SCOPE_READ_ONLY, nil)
 	defer s.contexts.destroyExecutionContext(executionContextId)
 
 	// modify execution context
-	executionContext.serviceStackPush(systemContractName)
+	err := executionContext.serviceStackPush(systemContractName)
+	if err != nil {
+		return err
+	}
 	defer executionContext.serviceStackPop()
 
 	// execute the call
-	_, err := s.processors[protocol.PROCESSOR_TYPE_NATIVE].ProcessCall(ctx, &services.ProcessCallInput{
+	_, err = s.processors[protocol.PROCESSOR_TYPE_NATIVE].ProcessCall(ctx, &services.ProcessCallInput{
 		ContextId:              executionContextId,
 		ContractName:           systemContractName,
 		MethodName:             systemMethodName,
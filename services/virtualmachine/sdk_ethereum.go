@@ -0,0 +1,143 @@
+package virtualmachine
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/instrumentation/log"
+	"github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/abi"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+func (s *service) handleSdkEthereumCall(ctx context.Context, executionContext *executionContext, methodName primitives.MethodName, args []*protocol.MethodArgument, permissionScope protocol.ExecutionPermissionScope) ([]*protocol.MethodArgument, error) {
+	switch methodName {
+
+	case "callMethod":
+		outputArgumentArrayRaw, err := s.handleSdkEthereumCallMethod(ctx, executionContext, args)
+		return []*protocol.MethodArgument{(&protocol.MethodArgumentBuilder{
+			Name:       "outputArgs",
+			Type:       protocol.METHOD_ARGUMENT_TYPE_BYTES_VALUE,
+			BytesValue: outputArgumentArrayRaw,
+		}).Build()}, err
+
+	default:
+		return nil, errors.Errorf("unknown SDK ethereum call method: %s", methodName)
+	}
+}
+
+// inputArg0: contractAddress (string)
+// inputArg1: jsonAbi (string)
+// inputArg2: methodName (string)
+// inputArg3: inputArgumentArray ([]byte of raw MethodArgumentArray)
+// outputArg0: outputArgumentArray ([]byte of raw MethodArgumentArray)
+func (s *service) handleSdkEthereumCallMethod(ctx context.Context, executionContext *executionContext, args []*protocol.MethodArgument) ([]byte, error) {
+	if len(args) != 4 || !args[0].IsTypeStringValue() || !args[1].IsTypeStringValue() || !args[2].IsTypeStringValue() || !args[3].IsTypeBytesValue() {
+		return nil, errors.Errorf("invalid SDK ethereum callMethod args: %v", args)
+	}
+	contractAddress := args[0].StringValue()
+	jsonAbi := args[1].StringValue()
+	methodName := args[2].StringValue()
+	inputArgumentArray := protocol.MethodArgumentArrayReader(args[3].BytesValue())
+
+	parsedAbi, err := abi.Parse(jsonAbi)
+	if err != nil {
+		return nil, err
+	}
+	method, err := parsedAbi.Method(methodName)
+	if err != nil {
+		return nil, err
+	}
+
+	packedInput, err := abi.PackArguments(method.Inputs, methodArgumentArrayToAbiValues(inputArgumentArray)...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to pack arguments of ethereum method %s", method.Signature())
+	}
+
+	connector, found := s.crosschainConnectors[protocol.CROSSCHAIN_CONNECTOR_TYPE_ETHEREUM]
+	if !found {
+		return nil, errors.New("ethereum crosschain connector is not configured")
+	}
+
+	output, err := connector.EthereumCallContract(ctx, &services.EthereumCallContractInput{
+		ReferenceTimestamp:              executionContext.blockTimestamp,
+		EthereumContractAddress:         contractAddress,
+		EthereumFunctionName:            methodName,
+		EthereumJsonAbi:                 jsonAbi,
+		EthereumAbiPackedInputArguments: packedInput,
+	})
+	if err != nil {
+		s.logger.Info("Sdk.Ethereum.CallMethod failed", log.Error(err), log.Stringable("caller", executionContext.serviceStackTop()), log.String("ethereum-contract", contractAddress))
+		return nil, err
+	}
+
+	outputValues, err := method.UnpackOutputs(output.EthereumAbiPackedOutput)
+	if err != nil {
+		return nil, err
+	}
+	outputArgumentArray, err := abiValuesToMethodArgumentArray(outputValues)
+	if err != nil {
+		return nil, err
+	}
+	return outputArgumentArray.Raw(), nil
+}
+
+func methodArgumentArrayToAbiValues(argumentArray *protocol.MethodArgumentArray) []interface{} {
+	values := []interface{}{}
+	for i := argumentArray.ArgumentsIterator(); i.HasNext(); {
+		argument := i.NextArguments()
+		switch {
+		case argument.IsTypeUint32Value():
+			values = append(values, argument.Uint32Value())
+		case argument.IsTypeUint64Value():
+			values = append(values, argument.Uint64Value())
+		case argument.IsTypeStringValue():
+			values = append(values, argument.StringValue())
+		case argument.IsTypeBytesValue():
+			values = append(values, argument.BytesValue())
+		}
+	}
+	return values
+}
+
+// bools become uint32, wide or signed integers become their 32 byte two's complement representation
+func abiValuesToMethodArgumentArray(values []interface{}) (*protocol.MethodArgumentArray, error) {
+	arguments := make([]*protocol.MethodArgumentBuilder, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case uint32:
+			arguments[i] = &protocol.MethodArgumentBuilder{Name: "uint32", Type: protocol.METHOD_ARGUMENT_TYPE_UINT_32_VALUE, Uint32Value: v}
+		case uint64:
+			arguments[i] = &protocol.MethodArgumentBuilder{Name: "uint64", Type: protocol.METHOD_ARGUMENT_TYPE_UINT_64_VALUE, Uint64Value: v}
+		case bool:
+			arguments[i] = &protocol.MethodArgumentBuilder{Name: "uint32", Type: protocol.METHOD_ARGUMENT_TYPE_UINT_32_VALUE, Uint32Value: boolToUint32(v)}
+		case string:
+			arguments[i] = &protocol.MethodArgumentBuilder{Name: "string", Type: protocol.METHOD_ARGUMENT_TYPE_STRING_VALUE, StringValue: v}
+		case []byte:
+			arguments[i] = &protocol.MethodArgumentBuilder{Name: "bytes", Type: protocol.METHOD_ARGUMENT_TYPE_BYTES_VALUE, BytesValue: v}
+		case *big.Int:
+			arguments[i] = &protocol.MethodArgumentBuilder{Name: "bytes", Type: protocol.METHOD_ARGUMENT_TYPE_BYTES_VALUE, BytesValue: bigIntToWord(v)}
+		default:
+			return nil, errors.Errorf("unsupported ethereum output type %T", value)
+		}
+	}
+	return (&protocol.MethodArgumentArrayBuilder{Arguments: arguments}).Build(), nil
+}
+
+func boolToUint32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func bigIntToWord(n *big.Int) []byte {
+	if n.Sign() < 0 {
+		n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), abi.WORD_SIZE_BYTES*8))
+	}
+	word := make([]byte, abi.WORD_SIZE_BYTES)
+	b := n.Bytes()
+	copy(word[abi.WORD_SIZE_BYTES-len(b):], b)
+	return word
+}
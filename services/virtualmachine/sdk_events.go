@@ -0,0 +1,49 @@
+package virtualmachine
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+func (s *service) handleSdkEventsCall(ctx context.Context, executionContext *executionContext, methodName primitives.MethodName, args []*protocol.MethodArgument, permissionScope protocol.ExecutionPermissionScope) ([]*protocol.MethodArgument, error) {
+	switch methodName {
+
+	case "emitEvent":
+		err := s.handleSdkEventsEmitEvent(executionContext, args)
+		if err != nil {
+			return nil, err
+		}
+		return []*protocol.MethodArgument{}, nil
+
+	default:
+		return nil, errors.Errorf("unknown SDK events call method: %s", methodName)
+	}
+}
+
+// inputArg0: eventName (string)
+// inputArg1: inputArgumentArray ([]byte of raw MethodArgumentArray)
+func (s *service) handleSdkEventsEmitEvent(executionContext *executionContext, args []*protocol.MethodArgument) error {
+	if executionContext.accessScope != protocol.ACCESS_SCOPE_READ_WRITE {
+		return errors.Errorf("event emitted without write access: %s", executionContext.accessScope)
+	}
+
+	if len(args) != 2 || !args[0].IsTypeStringValue() || !args[1].IsTypeBytesValue() {
+		return errors.Errorf("invalid SDK events emitEvent args: %v", args)
+	}
+	eventName := args[0].StringValue()
+	eventArgumentArray := protocol.MethodArgumentArrayReader(args[1].BytesValue()).RawArgumentsArray()
+	if eventName == "" {
+		return errors.Errorf("event emitted without a name")
+	}
+
+	// events end up in the receipt so they are paid for like state writes
+	err := executionContext.meterStateWrite([]byte(eventName), eventArgumentArray)
+	if err != nil {
+		return err
+	}
+
+	executionContext.eventListAdd(executionContext.serviceStackTop(), eventName, eventArgumentArray)
+	return nil
+}
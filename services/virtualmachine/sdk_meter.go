@@ -0,0 +1,38 @@
+package virtualmachine
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+// charges made by the metering the native compiler injects into deployed contracts, they are not sdk calls made by the
+// contract so they are not counted against the sdk call budget
+func (s *service) handleSdkMeterCall(ctx context.Context, executionContext *executionContext, methodName primitives.MethodName, args []*protocol.MethodArgument, permissionScope protocol.ExecutionPermissionScope) ([]*protocol.MethodArgument, error) {
+	switch methodName {
+
+	case "useInstructions":
+		if len(args) != 1 || !args[0].IsTypeUint32Value() {
+			return nil, errors.Errorf("invalid SDK meter useInstructions args: %v", args)
+		}
+		err := executionContext.meterInstructions(args[0].Uint32Value())
+		if err != nil {
+			return nil, err
+		}
+		return []*protocol.MethodArgument{}, nil
+
+	case "allocate":
+		if len(args) != 1 || !args[0].IsTypeUint64Value() {
+			return nil, errors.Errorf("invalid SDK meter allocate args: %v", args)
+		}
+		err := executionContext.meterAllocation(args[0].Uint64Value())
+		if err != nil {
+			return nil, err
+		}
+		return []*protocol.MethodArgument{}, nil
+
+	default:
+		return nil, errors.Errorf("unknown SDK meter call method: %s", methodName)
+	}
+}
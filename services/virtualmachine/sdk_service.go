@@ -46,7 +46,10 @@ func (s *service) handleSdkServiceCallMethod(ctx context.Context, executionConte
 
 	// modify execution context
 	callingService := executionContext.serviceStackTop()
-	executionContext.serviceStackPush(primitives.ContractName(serviceName))
+	err = executionContext.serviceStackPush(primitives.ContractName(serviceName))
+	if err != nil {
+		return nil, err
+	}
 	defer executionContext.serviceStackPop()
 
 	// execute the call
@@ -48,14 +48,19 @@ func (s *service) handleSdkStateRead(ctx context.Context, executionContext *exec
 	// try from transient state first
 	value, found := executionContext.transientState.getValue(currentService, key)
 	if found {
-		return value, nil
+		return value, executionContext.meterStateRead(key, value)
+	}
+
+	// values from here on depend on the transactions before this one
+	if executionContext.stateReadSet != nil {
+		executionContext.stateReadSet.add(currentService, key)
 	}
 
 	// try from batch transient state first
 	if executionContext.batchTransientState != nil {
 		value, found = executionContext.batchTransientState.getValue(currentService, key)
 		if found {
-			return value, nil
+			return value, executionContext.meterStateRead(key, value)
 		}
 	}
 
@@ -76,7 +81,7 @@ func (s *service) handleSdkStateRead(ctx context.Context, executionContext *exec
 	// store in transient state (cache)
 	executionContext.transientState.setValue(currentService, key, value, false)
 
-	return value, nil
+	return value, executionContext.meterStateRead(key, value)
 }
 
 // inputArg0: key ([]byte)
@@ -92,6 +97,11 @@ func (s *service) handleSdkStateWrite(executionContext *executionContext, args [
 	key := args[0].BytesValue()
 	value := args[1].BytesValue()
 
+	err := executionContext.meterStateWrite(key, value)
+	if err != nil {
+		return err
+	}
+
 	// get current running service
 	currentService := executionContext.serviceStackTop()
 
@@ -2,6 +2,7 @@ package virtualmachine
 
 import (
 	"context"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/processor/native"
@@ -14,6 +15,7 @@ import (
 var LogTag = log.Service("virtual-machine")
 
 type service struct {
+	config               config.VirtualMachineConfig
 	stateStorage         services.StateStorage
 	processors           map[protocol.ProcessorType]services.Processor
 	crosschainConnectors map[protocol.CrosschainConnectorType]services.CrosschainConnector
@@ -23,6 +25,7 @@ type service struct {
 }
 
 func NewVirtualMachine(
+	config config.VirtualMachineConfig,
 	stateStorage services.StateStorage,
 	processors map[protocol.ProcessorType]services.Processor,
 	crosschainConnectors map[protocol.CrosschainConnectorType]services.CrosschainConnector,
@@ -30,12 +33,13 @@ func NewVirtualMachine(
 ) services.VirtualMachine {
 
 	s := &service{
+		config:               config,
 		processors:           processors,
 		crosschainConnectors: crosschainConnectors,
 		stateStorage:         stateStorage,
 		logger:               logger.WithTags(LogTag),
 
-		contexts: newExecutionContextProvider(),
+		contexts: newExecutionContextProvider(config),
 	}
 
 	for _, processor := range processors {
@@ -59,7 +63,7 @@ func (s *service) RunLocalMethod(ctx context.Context, input *services.RunLocalMe
 	}
 
 	logger.Info("running local method", log.Stringable("contract", input.Transaction.ContractName()), log.Stringable("method", input.Transaction.MethodName()), log.BlockHeight(blockHeight))
-	callResult, outputArgs, err := s.runMethod(ctx, blockHeight, input.Transaction, protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	callResult, outputArgs, _, err := s.runMethod(ctx, blockHeight, blockTimestamp, input.Transaction, protocol.ACCESS_SCOPE_READ_ONLY, nil, nil)
 	if outputArgs == nil {
 		outputArgs = (&protocol.MethodArgumentArrayBuilder{}).Build()
 	}
@@ -77,7 +81,13 @@ func (s *service) ProcessTransactionSet(ctx context.Context, input *services.Pro
 	previousBlockHeight := input.BlockHeight - 1 // our contracts rely on this block's state for execution
 
 	logger.Info("processing transaction set", log.Int("num-transactions", len(input.SignedTransactions)))
-	receipts, stateDiffs := s.processTransactionSet(ctx, previousBlockHeight, input.SignedTransactions)
+	ctx, cancel := context.WithTimeout(ctx, s.config.VirtualMachineProcessTransactionSetTimeout())
+	defer cancel()
+	receipts, stateDiffs, err := s.processTransactionSet(ctx, previousBlockHeight, input.BlockTimestamp, input.SignedTransactions)
+	if err != nil {
+		logger.Info("processing transaction set failed", log.Error(err), log.Int("num-transactions", len(input.SignedTransactions)))
+		return nil, err
+	}
 
 	return &services.ProcessTransactionSetOutput{
 		TransactionReceipts: receipts,
@@ -123,6 +133,24 @@ func (s *service) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCa
 		return nil, errors.Errorf("invalid execution context %s", input.ContextId)
 	}
 
+	err = executionContext.verifyNotAborted()
+	if err != nil {
+		return nil, err
+	}
+	if input.OperationName == native.SDK_OPERATION_NAME_METER {
+		output, err = s.handleSdkMeterCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
+		if err != nil {
+			return nil, err
+		}
+		return &handlers.HandleSdkCallOutput{
+			OutputArguments: output,
+		}, nil
+	}
+	err = executionContext.meterSdkCall()
+	if err != nil {
+		return nil, err
+	}
+
 	switch input.OperationName {
 	case native.SDK_OPERATION_NAME_STATE:
 		output, err = s.handleSdkStateCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
@@ -130,6 +158,10 @@ func (s *service) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCa
 		output, err = s.handleSdkServiceCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
 	case native.SDK_OPERATION_NAME_ADDRESS:
 		output, err = s.handleSdkAddressCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
+	case native.SDK_OPERATION_NAME_ETHEREUM:
+		output, err = s.handleSdkEthereumCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
+	case native.SDK_OPERATION_NAME_EVENTS:
+		output, err = s.handleSdkEventsCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
 	default:
 		return nil, errors.Errorf("unknown SDK call operation: %s", input.OperationName)
 	}
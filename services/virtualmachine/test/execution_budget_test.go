@@ -0,0 +1,216 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/services/processor/native"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestProcessTransactionSet_ExceedingStateWriteBudgetFailsOnlyThatTransaction(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithConfig(config.ForVirtualMachineTests(16, 1000, 1024, 10, 1000000, 1024*1024, 1, 1*time.Second))
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 1: write within the budget")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x02, 0x03})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 2: write beyond the budget and ignore the error")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x04, 0x05})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x02}, []byte{0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d})
+			require.Error(t, err, "handleSdkCall should fail")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+
+		results, _, sd := h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+			{"Contract1", "method2"},
+		})
+		require.Equal(t, []protocol.ExecutionResult{
+			protocol.EXECUTION_RESULT_SUCCESS,
+			protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED,
+		}, results, "processTransactionSet returned receipts should match")
+		require.ElementsMatch(t, sd["Contract1"], []*keyValuePair{
+			{[]byte{0x01}, []byte{0x02, 0x03}},
+		}, "writes of the transaction that exceeded its budget should be rolled back")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
+
+func TestRunLocalMethod_ExceedingStateReadBudget(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithConfig(config.ForVirtualMachineTests(16, 1000, 4, 1024, 1000000, 1024*1024, 1, 1*time.Second))
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectStateStorageBlockHeightRequested(12)
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Reads from state storage and from cache are both metered")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+			require.Error(t, err, "handleSdkCall should fail")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectStateStorageRead(12, "Contract1", []byte{0x01}, []byte{0x02})
+
+		result, _, _, err := h.runLocalMethod(ctx, "Contract1", "method1")
+		require.Error(t, err, "run local method should fail")
+		require.Equal(t, protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED, result, "run local method should exceed its budget")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+		h.verifyStateStorageRead(t)
+	})
+}
+
+func TestProcessTransactionSet_ExceedingCallStackDepth(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithConfig(config.ForVirtualMachineTests(2, 1000, 1024, 1024, 1000000, 1024*1024, 1, 1*time.Second))
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_SERVICE, "callMethod", "Contract2", "method1", builders.MethodArgumentsArray().Raw())
+			require.NoError(t, err, "handleSdkCall within the depth limit should succeed")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectNativeContractMethodCalled("Contract2", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_SERVICE, "callMethod", "Contract3", "method1", builders.MethodArgumentsArray().Raw())
+			require.Error(t, err, "handleSdkCall beyond the depth limit should fail")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectNativeContractMethodNotCalled("Contract3", "method1")
+
+		results, _, _ := h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+		})
+		require.Equal(t, []protocol.ExecutionResult{protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED}, results, "processTransactionSet returned receipts should match")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
+
+func TestProcessTransactionSet_ExceedingSdkCallBudget(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithConfig(config.ForVirtualMachineTests(16, 2, 1024, 1024, 1000000, 1024*1024, 1, 1*time.Second))
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 1: makes one sdk call more than its budget and ignores the error")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x02})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x02}, []byte{0x03})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x03}, []byte{0x04})
+			require.Error(t, err, "handleSdkCall beyond the budget should fail")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 2: gets a budget of its own")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x05})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+
+		results, _, sd := h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+			{"Contract1", "method2"},
+		})
+		require.Equal(t, []protocol.ExecutionResult{
+			protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED,
+			protocol.EXECUTION_RESULT_SUCCESS,
+		}, results, "processTransactionSet returned receipts should match")
+		require.ElementsMatch(t, sd["Contract1"], []*keyValuePair{
+			{[]byte{0x01}, []byte{0x05}},
+		}, "writes of the transaction that exceeded its budget should be rolled back")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
+
+func TestProcessTransactionSet_ExceedingInstructionAndAllocationBudgets(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithConfig(config.ForVirtualMachineTests(16, 1, 1024, 1024, 2000, 100, 1, 1*time.Second))
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 1: charges are not sdk calls of the contract, so it may still write once")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_METER, "useInstructions", uint32(1000))
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x02})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_METER, "useInstructions", uint32(1000))
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_METER, "useInstructions", uint32(1000))
+			require.Error(t, err, "handleSdkCall beyond the instruction budget should fail")
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.MethodArgumentsArray(), errors.New("contract panic")
+		})
+		h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 2: an allocation beyond the budget is refused before it is made")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_METER, "allocate", uint64(60))
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_METER, "allocate", uint64(41))
+			require.Error(t, err, "handleSdkCall beyond the allocation budget should fail")
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.MethodArgumentsArray(), errors.New("contract panic")
+		})
+
+		results, _, sd := h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+			{"Contract1", "method2"},
+		})
+		require.Equal(t, []protocol.ExecutionResult{
+			protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED,
+			protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED,
+		}, results, "processTransactionSet returned receipts should match")
+		require.Empty(t, sd["Contract1"], "writes of the transaction that exceeded its budget should be rolled back")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
+
+func TestProcessTransactionSet_RunningOutOfTimeAbortsTheWholeSet(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithConfig(config.ForVirtualMachineTests(16, 1000, 1024, 1024, 1000000, 1024*1024, 1, 10*time.Millisecond))
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalledUntilAborted("Contract1", "method1", func(executionContextId primitives.ExecutionContextId) {
+			t.Log("Transaction 1: calls the sdk after the transaction set ran out of time")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x02})
+			require.Error(t, err, "handleSdkCall after the abort should fail")
+		})
+		h.expectNativeContractMethodNotCalled("Contract1", "method2")
+
+		_, err := h.service.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
+			BlockHeight:    12,
+			BlockTimestamp: blockTimestampOfProcessedSet,
+			SignedTransactions: []*protocol.SignedTransaction{
+				builders.Transaction().WithMethod("Contract1", "method1").Build(),
+				builders.Transaction().WithMethod("Contract1", "method2").Build(),
+			},
+		})
+		require.Error(t, err, "processTransactionSet should fail rather than write a receipt that depends on time")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
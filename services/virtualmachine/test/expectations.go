@@ -22,6 +22,10 @@ func (h *harness) verifyHandlerRegistrations(t *testing.T) {
 }
 
 func (h *harness) expectNativeContractMethodCalled(expectedContractName primitives.ContractName, expectedMethodName primitives.MethodName, contractFunction func(primitives.ExecutionContextId, *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error)) {
+	h.expectNativeContractMethodCalledTimes(expectedContractName, expectedMethodName, 1, contractFunction)
+}
+
+func (h *harness) expectNativeContractMethodCalledTimes(expectedContractName primitives.ContractName, expectedMethodName primitives.MethodName, times int, contractFunction func(primitives.ExecutionContextId, *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error)) {
 	contractMethodMatcher := func(i interface{}) bool {
 		input, ok := i.(*services.ProcessCallInput)
 		return ok &&
@@ -36,7 +40,7 @@ func (h *harness) expectNativeContractMethodCalled(expectedContractName primitiv
 			OutputArgumentArray: outputArgsArray,
 			CallResult:          callResult,
 		}, err
-	}).Times(1)
+	}).Times(times)
 }
 
 func (h *harness) expectNativeContractMethodCalledWithSystemPermissions(expectedContractName primitives.ContractName, expectedMethodName primitives.MethodName, contractFunction func(primitives.ExecutionContextId) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error)) {
@@ -57,6 +61,24 @@ func (h *harness) expectNativeContractMethodCalledWithSystemPermissions(expected
 	}).Times(1)
 }
 
+func (h *harness) expectNativeContractMethodCalledUntilAborted(expectedContractName primitives.ContractName, expectedMethodName primitives.MethodName, afterAbort func(primitives.ExecutionContextId)) {
+	contractMethodMatcher := func(i interface{}) bool {
+		input, ok := i.(*services.ProcessCallInput)
+		return ok &&
+			input.ContractName == expectedContractName &&
+			input.MethodName == expectedMethodName
+	}
+
+	h.processors[protocol.PROCESSOR_TYPE_NATIVE].When("ProcessCall", mock.Any, mock.AnyIf(fmt.Sprintf("Contract equals %s and Method %s", expectedContractName, expectedMethodName), contractMethodMatcher)).Call(func(ctx context.Context, input *services.ProcessCallInput) (*services.ProcessCallOutput, error) {
+		<-ctx.Done()
+		afterAbort(input.ContextId)
+		return &services.ProcessCallOutput{
+			OutputArgumentArray: builders.MethodArgumentsArray(),
+			CallResult:          protocol.EXECUTION_RESULT_ERROR_UNEXPECTED,
+		}, ctx.Err()
+	}).Times(1)
+}
+
 func (h *harness) expectNativeContractMethodNotCalled(expectedContractName primitives.ContractName, expectedMethodName primitives.MethodName) {
 	contractMethodMatcher := func(i interface{}) bool {
 		input, ok := i.(*services.ProcessCallInput)
@@ -159,3 +181,24 @@ func (h *harness) verifyStateStorageRead(t *testing.T) {
 func (h *harness) expectStateStorageNotRead() {
 	h.stateStorage.When("ReadKeys", mock.Any, mock.Any).Return(&services.ReadKeysOutput{}, nil).Times(0)
 }
+
+func (h *harness) expectEthereumConnectorMethodCalled(expectedContractAddress string, expectedFunctionName string, returnError error, returnOutput []byte) {
+	contractMethodMatcher := func(i interface{}) bool {
+		input, ok := i.(*services.EthereumCallContractInput)
+		return ok &&
+			input.ReferenceTimestamp == blockTimestampOfProcessedSet &&
+			input.EthereumContractAddress == expectedContractAddress &&
+			input.EthereumFunctionName == expectedFunctionName
+	}
+
+	outputToReturn := &services.EthereumCallContractOutput{
+		EthereumAbiPackedOutput: returnOutput,
+	}
+
+	h.crosschainConnectors[protocol.CROSSCHAIN_CONNECTOR_TYPE_ETHEREUM].When("EthereumCallContract", mock.Any, mock.AnyIf(fmt.Sprintf("Contract equals %s and Function %s at the processed block timestamp", expectedContractAddress, expectedFunctionName), contractMethodMatcher)).Return(outputToReturn, returnError).Times(1)
+}
+
+func (h *harness) verifyEthereumConnectorMethodCalled(t *testing.T) {
+	ok, err := h.crosschainConnectors[protocol.CROSSCHAIN_CONNECTOR_TYPE_ETHEREUM].Verify()
+	require.True(t, ok, "did not call ethereum connector: %v", err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/log"
 	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
 	"github.com/orbs-network/orbs-network-go/test/builders"
@@ -12,8 +13,12 @@ import (
 	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
 	"os"
+	"time"
 )
 
+// the timestamp of the block whose transaction set is processed
+const blockTimestampOfProcessedSet = primitives.TimestampNano(5678)
+
 type harness struct {
 	blockStorage         *services.MockBlockStorage
 	stateStorage         *services.MockStateStorage
@@ -24,6 +29,10 @@ type harness struct {
 }
 
 func newHarness() *harness {
+	return newHarnessWithConfig(config.ForVirtualMachineTests(16, 1000, 1024*1024, 1024*1024, 1000000, 1024*1024, 1, 1*time.Second))
+}
+
+func newHarnessWithConfig(cfg config.VirtualMachineConfig) *harness {
 	log := log.GetLogger().WithOutput(log.NewFormattingOutput(os.Stdout, log.NewHumanReadableFormatter()))
 
 	blockStorage := &services.MockBlockStorage{}
@@ -47,6 +56,7 @@ func newHarness() *harness {
 	}
 
 	service := virtualmachine.NewVirtualMachine(
+		cfg,
 		stateStorage,
 		processorsForService,
 		crosschainConnectorsForService,
@@ -126,6 +136,7 @@ func (h *harness) processTransactionSet(ctx context.Context, contractAndMethods
 
 	output, _ := h.service.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
 		BlockHeight:        12,
+		BlockTimestamp:     blockTimestampOfProcessedSet,
 		SignedTransactions: transactions,
 	})
 
@@ -152,6 +163,42 @@ func (h *harness) processTransactionSet(ctx context.Context, contractAndMethods
 	return results, outputArgsOfAllTransactions, resultKeyValuePairsPerContract
 }
 
+func (h *harness) processTransactionSetAndReturnEvents(ctx context.Context, contractAndMethods []*contractAndMethod) ([]protocol.ExecutionResult, [][]*protocol.Event) {
+	transactions := []*protocol.SignedTransaction{}
+	for _, contractAndMethod := range contractAndMethods {
+		tx := builders.Transaction().WithMethod(contractAndMethod.contractName, contractAndMethod.methodName).Build()
+		transactions = append(transactions, tx)
+	}
+
+	output, _ := h.service.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
+		BlockHeight:        12,
+		BlockTimestamp:     blockTimestampOfProcessedSet,
+		SignedTransactions: transactions,
+	})
+
+	results := []protocol.ExecutionResult{}
+	eventsOfAllTransactions := [][]*protocol.Event{}
+	for _, transactionReceipt := range output.TransactionReceipts {
+		results = append(results, transactionReceipt.ExecutionResult())
+		events := []*protocol.Event{}
+		for i := protocol.EventsArrayReader(transactionReceipt.RawOutputEventsArrayWithHeader()).EventsIterator(); i.HasNext(); {
+			events = append(events, i.NextEvents())
+		}
+		eventsOfAllTransactions = append(eventsOfAllTransactions, events)
+	}
+
+	return results, eventsOfAllTransactions
+}
+
+func (h *harness) processSignedTransactionSet(ctx context.Context, signedTransactions []*protocol.SignedTransaction) *services.ProcessTransactionSetOutput {
+	output, _ := h.service.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
+		BlockHeight:        12,
+		BlockTimestamp:     blockTimestampOfProcessedSet,
+		SignedTransactions: signedTransactions,
+	})
+	return output
+}
+
 func (h *harness) transactionSetPreOrder(ctx context.Context, signedTransactions []*protocol.SignedTransaction) ([]protocol.TransactionStatus, error) {
 	output, err := h.service.TransactionSetPreOrder(ctx, &services.TransactionSetPreOrderInput{
 		BlockHeight:        12,
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/services/processor/native"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newHarnessWithParallelExecution() *harness {
+	return newHarnessWithConfig(config.ForVirtualMachineTests(16, 1000, 1024*1024, 1024*1024, 1000000, 1024*1024, 4, 1*time.Second))
+}
+
+// transaction 2 reads what transaction 1 writes, transaction 3 touches another contract
+func expectTransactionsReadingEachOthersWrites(t *testing.T, ctx context.Context, h *harness, timesSecondTransactionExecuted int) {
+	h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+	h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+		_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x0a})
+		require.NoError(t, err, "handleSdkCall should succeed")
+		return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+	})
+	h.expectNativeContractMethodCalledTimes("Contract1", "method2", timesSecondTransactionExecuted, func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+		res, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+		require.NoError(t, err, "handleSdkCall should succeed")
+		_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x02}, res[0].BytesValue())
+		require.NoError(t, err, "handleSdkCall should succeed")
+		return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(res[0].BytesValue()), nil
+	})
+	h.expectNativeContractMethodCalled("Contract2", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+		res, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+		require.NoError(t, err, "handleSdkCall should succeed")
+		_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x02}, res[0].BytesValue())
+		require.NoError(t, err, "handleSdkCall should succeed")
+		return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(res[0].BytesValue()), nil
+	})
+	h.expectStateStorageRead(12, "Contract2", []byte{0x01}, []byte{0x03})
+}
+
+func TestProcessTransactionSet_ParallelExecutionRepeatsConflictingTransactions(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithParallelExecution()
+		expectTransactionsReadingEachOthersWrites(t, ctx, h, 2)
+		h.expectStateStorageRead(12, "Contract1", []byte{0x01}, []byte{0x02}) // speculative execution of transaction 2
+
+		results, outputArgs, sd := h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+			{"Contract1", "method2"},
+			{"Contract2", "method1"},
+		})
+		require.Equal(t, []protocol.ExecutionResult{
+			protocol.EXECUTION_RESULT_SUCCESS,
+			protocol.EXECUTION_RESULT_SUCCESS,
+			protocol.EXECUTION_RESULT_SUCCESS,
+		}, results, "processTransactionSet returned receipts should match")
+		require.Equal(t, builders.MethodArgumentsArray([]byte{0x0a}).RawArgumentsArray(), outputArgs[1], "transaction 2 should see the write of transaction 1")
+		require.ElementsMatch(t, sd["Contract1"], []*keyValuePair{
+			{[]byte{0x01}, []byte{0x0a}},
+			{[]byte{0x02}, []byte{0x0a}},
+		}, "processTransactionSet returned contract state diffs should match")
+		require.ElementsMatch(t, sd["Contract2"], []*keyValuePair{
+			{[]byte{0x02}, []byte{0x03}},
+		}, "processTransactionSet returned contract state diffs should match")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+		h.verifyStateStorageRead(t)
+	})
+}
+
+func TestProcessTransactionSet_ParallelExecutionMatchesSequentialExecution(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		signedTransactions := []*protocol.SignedTransaction{
+			builders.Transaction().WithMethod("Contract1", "method1").Build(),
+			builders.Transaction().WithMethod("Contract1", "method2").Build(),
+			builders.Transaction().WithMethod("Contract2", "method1").Build(),
+		}
+
+		sequential := newHarness()
+		expectTransactionsReadingEachOthersWrites(t, ctx, sequential, 1)
+		sequentialOutput := sequential.processSignedTransactionSet(ctx, signedTransactions)
+
+		parallel := newHarnessWithParallelExecution()
+		expectTransactionsReadingEachOthersWrites(t, ctx, parallel, 2)
+		parallel.expectStateStorageRead(12, "Contract1", []byte{0x01}, []byte{0x02}) // speculative execution of transaction 2
+		parallelOutput := parallel.processSignedTransactionSet(ctx, signedTransactions)
+
+		require.Len(t, parallelOutput.TransactionReceipts, len(sequentialOutput.TransactionReceipts))
+		for i := range sequentialOutput.TransactionReceipts {
+			require.Equal(t, sequentialOutput.TransactionReceipts[i].Raw(), parallelOutput.TransactionReceipts[i].Raw(), "receipt %d should be identical to the sequential one", i)
+		}
+		require.Len(t, parallelOutput.ContractStateDiffs, len(sequentialOutput.ContractStateDiffs))
+		for i := range sequentialOutput.ContractStateDiffs {
+			require.Equal(t, sequentialOutput.ContractStateDiffs[i].Raw(), parallelOutput.ContractStateDiffs[i].Raw(), "state diff %d should be identical to the sequential one", i)
+		}
+
+		sequential.verifyNativeContractMethodCalled(t)
+		parallel.verifyNativeContractMethodCalled(t)
+	})
+}
+
+func TestProcessTransactionSet_ParallelExecutionIgnoresWritesOfFailedTransactions(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithParallelExecution()
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 1: write and fail")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x0a})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.MethodArgumentsArray(), errors.New("contract error")
+		})
+		h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 2: read what transaction 1 wrote and was rolled back")
+			res, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			require.Equal(t, []byte{0x02}, res[0].BytesValue(), "handleSdkCall result should be equal")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectStateStorageRead(12, "Contract1", []byte{0x01}, []byte{0x02})
+
+		results, _, sd := h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+			{"Contract1", "method2"},
+		})
+		require.Equal(t, []protocol.ExecutionResult{
+			protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT,
+			protocol.EXECUTION_RESULT_SUCCESS,
+		}, results, "processTransactionSet returned receipts should match")
+		require.Empty(t, sd["Contract1"], "writes of the failed transaction should be rolled back")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+		h.verifyStateStorageRead(t)
+	})
+}
+
+func TestProcessTransactionSet_ParallelExecutionKeepsTransactionsThatRanOutOfBudget(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarnessWithConfig(config.ForVirtualMachineTests(16, 1, 1024*1024, 1024*1024, 1000000, 1024*1024, 4, 1*time.Second))
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 1: runs out of sdk calls the same way in every execution, so it is executed only once")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x0a})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x02}, []byte{0x0b})
+			require.Error(t, err, "handleSdkCall beyond the budget should fail")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectNativeContractMethodCalled("Contract2", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x0c})
+			require.NoError(t, err, "handleSdkCall should succeed")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+
+		results, _, sd := h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+			{"Contract2", "method1"},
+		})
+		require.Equal(t, []protocol.ExecutionResult{
+			protocol.EXECUTION_RESULT_ERROR_BUDGET_EXCEEDED,
+			protocol.EXECUTION_RESULT_SUCCESS,
+		}, results, "processTransactionSet returned receipts should match")
+		require.Empty(t, sd["Contract1"], "writes of the transaction that ran out of budget should be rolled back")
+		require.ElementsMatch(t, sd["Contract2"], []*keyValuePair{
+			{[]byte{0x01}, []byte{0x0c}},
+		}, "processTransactionSet returned contract state diffs should match")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
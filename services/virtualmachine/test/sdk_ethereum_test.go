@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/abi"
+	"github.com/orbs-network/orbs-network-go/services/processor/native"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const (
+	EXAMPLE_ETHEREUM_CONTRACT_ADDRESS = "0x00112233445566778899aabbccddeeff00112233"
+	EXAMPLE_ETHEREUM_JSON_ABI         = `[{"inputs":[{"name":"key","type":"uint64"},{"name":"suffix","type":"string"}],"name":"lookup","outputs":[{"name":"value","type":"uint64"},{"name":"found","type":"bool"}],"type":"function"}]`
+)
+
+func packedEthereumOutput(t *testing.T, values ...interface{}) []byte {
+	packed, err := abi.PackArguments([]abi.Argument{{Type: "uint64"}, {Type: "bool"}}, values...)
+	require.NoError(t, err, "packing ethereum output should succeed")
+	return packed
+}
+
+func TestSdkEthereum_CallMethod(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("CallMethod on an ethereum contract")
+			sdkCallInputArgs := builders.MethodArgumentsArray(uint64(17), "hello").Raw()
+			res, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_ETHEREUM, "callMethod", EXAMPLE_ETHEREUM_CONTRACT_ADDRESS, EXAMPLE_ETHEREUM_JSON_ABI, "lookup", sdkCallInputArgs)
+			require.NoError(t, err, "handleSdkCall should not fail")
+			require.Equal(t, builders.MethodArgumentsArray(uint64(18), uint32(1)).Raw(), res[0].BytesValue(), "handleSdkCall result should be the unpacked ethereum output")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectEthereumConnectorMethodCalled(EXAMPLE_ETHEREUM_CONTRACT_ADDRESS, "lookup", nil, packedEthereumOutput(t, uint64(18), true))
+
+		h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+		})
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+		h.verifyEthereumConnectorMethodCalled(t)
+	})
+}
+
+func TestSdkEthereum_CallMethodWithMismatchingArgumentsDoesNotCallConnector(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("CallMethod on an ethereum contract with too few arguments")
+			sdkCallInputArgs := builders.MethodArgumentsArray(uint64(17)).Raw()
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_ETHEREUM, "callMethod", EXAMPLE_ETHEREUM_CONTRACT_ADDRESS, EXAMPLE_ETHEREUM_JSON_ABI, "lookup", sdkCallInputArgs)
+			require.Error(t, err, "handleSdkCall should fail")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+
+		h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+		})
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
+
+func TestSdkEthereum_CallMethodFailingCall(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("CallMethod on a failing ethereum contract")
+			sdkCallInputArgs := builders.MethodArgumentsArray(uint64(17), "hello").Raw()
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_ETHEREUM, "callMethod", EXAMPLE_ETHEREUM_CONTRACT_ADDRESS, EXAMPLE_ETHEREUM_JSON_ABI, "lookup", sdkCallInputArgs)
+			require.Error(t, err, "handleSdkCall should fail")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectEthereumConnectorMethodCalled(EXAMPLE_ETHEREUM_CONTRACT_ADDRESS, "lookup", errors.New("execution reverted"), nil)
+
+		h.processTransactionSet(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+		})
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+		h.verifyEthereumConnectorMethodCalled(t)
+	})
+}
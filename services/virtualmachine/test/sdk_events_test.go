@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/services/processor/native"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSdkEvents_EmitEventWithLocalMethodReadOnlyAccess(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectStateStorageBlockHeightRequested(12)
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Attempt to emit an event without proper access")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_EVENTS, "emitEvent", "Transfer", builders.MethodArgumentsArray(uint64(17)).Raw())
+			require.Error(t, err, "handleSdkCall should fail")
+			return protocol.EXECUTION_RESULT_ERROR_UNEXPECTED, builders.MethodArgumentsArray(), errors.New("unexpected error")
+		})
+
+		h.runLocalMethod(ctx, "Contract1", "method1")
+
+		h.verifySystemContractCalled(t)
+		h.verifyStateStorageBlockHeightRequested(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
+
+func TestSdkEvents_EmitEventWithTransactionSetReadWriteAccess(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 1: emit two events and succeed")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_EVENTS, "emitEvent", "Transfer", builders.MethodArgumentsArray(uint64(17)).Raw())
+			require.NoError(t, err, "handleSdkCall should succeed")
+			_, err = h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_EVENTS, "emitEvent", "Approval", builders.MethodArgumentsArray("hello").Raw())
+			require.NoError(t, err, "handleSdkCall should succeed")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+		h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			t.Log("Transaction 2: emit an event and fail")
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_EVENTS, "emitEvent", "Transfer", builders.MethodArgumentsArray(uint64(18)).Raw())
+			require.NoError(t, err, "handleSdkCall should succeed")
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.MethodArgumentsArray(), errors.New("contract error")
+		})
+
+		results, events := h.processTransactionSetAndReturnEvents(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+			{"Contract1", "method2"},
+		})
+		require.Equal(t, []protocol.ExecutionResult{
+			protocol.EXECUTION_RESULT_SUCCESS,
+			protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT,
+		}, results, "processTransactionSet returned receipts should match")
+
+		require.Len(t, events[0], 2, "events of the successful transaction should be in its receipt")
+		require.EqualValues(t, "Contract1", events[0][0].ContractName(), "event should be tagged with the emitting contract")
+		require.Equal(t, "Transfer", events[0][0].EventName(), "event name should match")
+		require.Equal(t, builders.MethodArgumentsArray(uint64(17)).RawArgumentsArray(), events[0][0].OutputArgumentArray(), "event arguments should match")
+		require.Equal(t, "Approval", events[0][1].EventName(), "events should keep the order they were emitted in")
+		require.Empty(t, events[1], "events of the failed transaction should be rolled back")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
+
+func TestSdkEvents_EmitEventWithoutName(t *testing.T) {
+	test.WithContext(func(ctx context.Context) {
+		h := newHarness()
+		h.expectSystemContractCalled(deployments_systemcontract.CONTRACT.Name, deployments_systemcontract.METHOD_GET_INFO.Name, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+		h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.MethodArgumentArray) (protocol.ExecutionResult, *protocol.MethodArgumentArray, error) {
+			_, err := h.handleSdkCall(ctx, executionContextId, native.SDK_OPERATION_NAME_EVENTS, "emitEvent", "", builders.MethodArgumentsArray().Raw())
+			require.Error(t, err, "handleSdkCall should fail")
+			return protocol.EXECUTION_RESULT_SUCCESS, builders.MethodArgumentsArray(), nil
+		})
+
+		_, events := h.processTransactionSetAndReturnEvents(ctx, []*contractAndMethod{
+			{"Contract1", "method1"},
+		})
+		require.Empty(t, events[0], "unnamed event should not be recorded")
+
+		h.verifySystemContractCalled(t)
+		h.verifyNativeContractMethodCalled(t)
+	})
+}
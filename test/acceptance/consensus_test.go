@@ -14,34 +14,65 @@ import (
 )
 
 func TestLeanHelixLeaderGetsValidationsBeforeCommit(t *testing.T) {
-	t.Skip("putting lean helix on hold until external library is integrated")
-	harness.Network(t).WithConsensusAlgos(consensus.CONSENSUS_ALGO_TYPE_LEAN_HELIX).Start(func(ctx context.Context, network harness.TestNetworkDriver) {
+	harness.Network(t).
+		WithLogFilters(log.ExcludeField(sync.LogTag), log.ExcludeEntryPoint("BlockSync")).
+		WithConsensusAlgos(consensus.CONSENSUS_ALGO_TYPE_LEAN_HELIX).
+		Start(func(parent context.Context, network harness.TestNetworkDriver) {
+			ctx, cancel := context.WithTimeout(parent, 2*time.Second)
+			defer cancel()
 
-		contract := network.GetBenchmarkTokenContract()
-		contract.DeployBenchmarkToken(ctx, 5)
+			contract := network.GetBenchmarkTokenContract()
+			contract.DeployBenchmarkToken(ctx, 5)
 
-		prePrepareLatch := network.TransportTamperer().LatchOn(adapter.LeanHelixMessage(consensus.LEAN_HELIX_PRE_PREPARE))
-		prePrepareTamper := network.TransportTamperer().Fail(adapter.LeanHelixMessage(consensus.LEAN_HELIX_PRE_PREPARE))
-		<-contract.SendTransfer(ctx, 0, 17, 5, 6)
+			prePrepareTamper := network.TransportTamperer().Fail(adapter.LeanHelixMessage(consensus.LEAN_HELIX_PRE_PREPARE))
+			blockSyncTamper := network.TransportTamperer().Fail(adapter.BlockSyncMessage(gossipmessages.BLOCK_SYNC_AVAILABILITY_REQUEST)) // block sync discovery message so it does not add the blocks in a 'back door'
+			prePrepareLatch := network.TransportTamperer().LatchOn(adapter.LeanHelixMessage(consensus.LEAN_HELIX_PRE_PREPARE))
 
-		prePrepareLatch.Wait()
-		require.EqualValues(t, 0, <-contract.CallGetBalance(ctx, 0, 6), "initial getBalance result on leader")
-		require.EqualValues(t, 0, <-contract.CallGetBalance(ctx, 1, 6), "initial getBalance result on non leader")
+			txHash := contract.SendTransferInBackground(ctx, 0, 17, 5, 6)
 
-		prePrepareTamper.Release(ctx)
-		prePrepareLatch.Remove()
+			prePrepareLatch.Wait() // the leader proposed a block but nobody got to validate it
+			require.EqualValues(t, 0, <-contract.CallGetBalance(ctx, 0, 6), "initial getBalance result on node 0")
+			require.EqualValues(t, 0, <-contract.CallGetBalance(ctx, 1, 6), "initial getBalance result on node 1")
 
-		if err := network.BlockPersistence(0).GetBlockTracker().WaitForBlock(ctx, 1); err != nil {
-			t.Errorf("waiting for block on node 0 failed: %s", err)
-		}
-		require.EqualValues(t, 17, <-contract.CallGetBalance(ctx, 0, 6), "eventual getBalance result on leader")
+			prePrepareLatch.Remove()
+			prePrepareTamper.Release(ctx)
 
-		if err := network.BlockPersistence(1).GetBlockTracker().WaitForBlock(ctx, 1); err != nil {
-			t.Errorf("waiting for block on node 1 failed: %s", err)
-		}
-		require.EqualValues(t, 17, <-contract.CallGetBalance(ctx, 1, 6), "eventual getBalance result on non leader")
+			network.WaitForTransactionInNodeState(ctx, txHash, 0)
+			require.EqualValues(t, 17, <-contract.CallGetBalance(ctx, 0, 6), "eventual getBalance result on node 0")
 
-	})
+			network.WaitForTransactionInNodeState(ctx, txHash, 1)
+			require.EqualValues(t, 17, <-contract.CallGetBalance(ctx, 1, 6), "eventual getBalance result on node 1")
+
+			blockSyncTamper.Release(ctx)
+		})
+}
+
+func TestLeanHelixElectsNewLeaderWhenRoundTimesOut(t *testing.T) {
+	harness.Network(t).
+		WithLogFilters(log.ExcludeField(sync.LogTag), log.ExcludeEntryPoint("BlockSync")).
+		WithConsensusAlgos(consensus.CONSENSUS_ALGO_TYPE_LEAN_HELIX).
+		Start(func(parent context.Context, network harness.TestNetworkDriver) {
+			ctx, cancel := context.WithTimeout(parent, 2*time.Second)
+			defer cancel()
+
+			contract := network.GetBenchmarkTokenContract()
+			contract.DeployBenchmarkToken(ctx, 5)
+
+			prePrepareTamper := network.TransportTamperer().Fail(adapter.LeanHelixMessage(consensus.LEAN_HELIX_PRE_PREPARE))
+			viewChangeLatch := network.TransportTamperer().LatchOn(adapter.LeanHelixMessage(consensus.LEAN_HELIX_VIEW_CHANGE))
+
+			txHash := contract.SendTransferInBackground(ctx, 0, 17, 5, 6)
+
+			viewChangeLatch.Wait() // no proposal reached the nodes within the round timeout so they started an election
+			viewChangeLatch.Remove()
+			prePrepareTamper.Release(ctx)
+
+			network.WaitForTransactionInNodeState(ctx, txHash, 0)
+			require.EqualValues(t, 17, <-contract.CallGetBalance(ctx, 0, 6), "getBalance result on node 0 after the election")
+
+			network.WaitForTransactionInNodeState(ctx, txHash, 1)
+			require.EqualValues(t, 17, <-contract.CallGetBalance(ctx, 1, 6), "getBalance result on node 1 after the election")
+		})
 }
 
 func TestBenchmarkConsensusLeaderGetsVotesBeforeNextBlock(t *testing.T) {
@@ -3,6 +3,7 @@ package builders
 import (
 	"github.com/orbs-network/orbs-network-go/crypto/bloom"
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	cryptoKeys "github.com/orbs-network/orbs-network-go/crypto/keys"
 	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -21,12 +22,14 @@ type blockPair struct {
 	sdiffs           []*protocol.ContractStateDiff
 	rxProof          *protocol.ResultsBlockProofBuilder
 	blockProofSigner primitives.Ed25519PrivateKey
+	blockProofNodes  []*cryptoKeys.Ed25519KeyPair
 }
 
 func BlockPair() *blockPair {
 	createdDate := time.Now()
 	b := &blockPair{
 		txHeader: &protocol.TransactionsBlockHeaderBuilder{
+			VirtualChainId:        DEFAULT_TEST_VIRTUAL_CHAIN_ID,
 			BlockHeight:           1,
 			Timestamp:             primitives.TimestampNano(createdDate.UnixNano()),
 			ProtocolVersion:       primitives.ProtocolVersion(1),
@@ -38,6 +41,7 @@ func BlockPair() *blockPair {
 		},
 		txProof: nil,
 		rxHeader: &protocol.ResultsBlockHeaderBuilder{
+			VirtualChainId:         DEFAULT_TEST_VIRTUAL_CHAIN_ID,
 			BlockHeight:            1,
 			Timestamp:              primitives.TimestampNano(createdDate.UnixNano()),
 			ProtocolVersion:        primitives.ProtocolVersion(1),
@@ -56,12 +60,16 @@ func BlockPair() *blockPair {
 }
 
 func (b *blockPair) Build() *protocol.BlockPairContainer {
+	b.rxHeader.ReceiptsRootHash = digest.CalcReceiptsRootHash(b.receipts)
 	txHeaderBuilt := b.txHeader.Build()
 	rxHeaderBuilt := b.rxHeader.Build()
 
 	if b.rxProof.Type == protocol.RESULTS_BLOCK_PROOF_TYPE_BENCHMARK_CONSENSUS {
 		b.buildBenchmarkConsensusBlockProof(txHeaderBuilt, rxHeaderBuilt)
 	}
+	if b.rxProof.Type == protocol.RESULTS_BLOCK_PROOF_TYPE_LEAN_HELIX {
+		b.buildLeanHelixBlockProof(txHeaderBuilt, rxHeaderBuilt)
+	}
 
 	return &protocol.BlockPairContainer{
 		TransactionsBlock: &protocol.TransactionsBlockContainer{
@@ -99,6 +107,17 @@ func (b *blockPair) WithBlockCreated(time time.Time) *blockPair {
 	return b
 }
 
+func (b *blockPair) WithVirtualChainId(virtualChainId primitives.VirtualChainId) *blockPair {
+	b.txHeader.VirtualChainId = virtualChainId
+	b.rxHeader.VirtualChainId = virtualChainId
+	return b
+}
+
+func (b *blockPair) WithPreExecutionStateRootHash(stateRootHash primitives.MerkleSha256) *blockPair {
+	b.rxHeader.PreExecutionStateRootHash = stateRootHash
+	return b
+}
+
 func (b *blockPair) WithProtocolVersion(version primitives.ProtocolVersion) *blockPair {
 	b.txHeader.ProtocolVersion = version
 	b.rxHeader.ProtocolVersion = version
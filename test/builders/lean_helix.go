@@ -1,6 +1,11 @@
 package builders
 
 import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/crypto/logic"
+	"github.com/orbs-network/orbs-network-go/crypto/signature"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
 )
@@ -11,7 +16,17 @@ func LeanHelixBlockPair() *blockPair {
 	return BlockPair().WithLeanHelixBlockProof()
 }
 
+// signed by a quorum of the default four node test federation
 func (b *blockPair) WithLeanHelixBlockProof() *blockPair {
+	return b.WithLeanHelixBlockProofSigners(
+		testKeys.Ed25519KeyPairForTests(0),
+		testKeys.Ed25519KeyPairForTests(1),
+		testKeys.Ed25519KeyPairForTests(2),
+	)
+}
+
+func (b *blockPair) WithLeanHelixBlockProofSigners(keyPairs ...*keys.Ed25519KeyPair) *blockPair {
+	b.blockProofNodes = keyPairs
 	b.txProof = &protocol.TransactionsBlockProofBuilder{
 		Type:      protocol.TRANSACTIONS_BLOCK_PROOF_TYPE_LEAN_HELIX,
 		LeanHelix: &consensus.LeanHelixBlockProofBuilder{},
@@ -22,3 +37,34 @@ func (b *blockPair) WithLeanHelixBlockProof() *blockPair {
 	}
 	return b
 }
+
+func (b *blockPair) buildLeanHelixBlockProof(txHeaderBuilt *protocol.TransactionsBlockHeader, rxHeaderBuilt *protocol.ResultsBlockHeader) {
+	txHash := digest.CalcTransactionsBlockHash(&protocol.TransactionsBlockContainer{Header: txHeaderBuilt})
+	rxHash := digest.CalcResultsBlockHash(&protocol.ResultsBlockContainer{Header: rxHeaderBuilt})
+	blockRef := &consensus.LeanHelixBlockRefBuilder{
+		MessageType: consensus.LEAN_HELIX_COMMIT,
+		BlockHeight: txHeaderBuilt.BlockHeight(),
+		View:        0,
+		BlockHash:   logic.CalcXor(txHash, rxHash),
+	}
+	signedData := blockRef.Build().Raw()
+
+	nodes := make([]*consensus.LeanHelixSenderSignatureBuilder, 0, len(b.blockProofNodes))
+	for _, keyPair := range b.blockProofNodes {
+		sig, err := signature.SignEd25519(keyPair.PrivateKey(), signedData)
+		if err != nil {
+			panic(err)
+		}
+		nodes = append(nodes, &consensus.LeanHelixSenderSignatureBuilder{
+			SenderPublicKey: keyPair.PublicKey(),
+			Signature:       sig,
+		})
+	}
+
+	blockProof := &consensus.LeanHelixBlockProofBuilder{
+		BlockRef: blockRef,
+		Nodes:    nodes,
+	}
+	b.txProof.LeanHelix = blockProof
+	b.rxProof.LeanHelix = blockProof
+}
@@ -2,6 +2,7 @@ package builders
 
 import (
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"math/rand"
 )
@@ -10,6 +11,7 @@ import (
 
 type receipt struct {
 	builder *protocol.TransactionReceiptBuilder
+	events  []*protocol.EventBuilder
 }
 
 func TransactionReceipt() *receipt {
@@ -26,6 +28,16 @@ func (r *receipt) WithTransaction(t *protocol.Transaction) *receipt {
 	return r
 }
 
+func (r *receipt) WithEvent(contractName primitives.ContractName, eventName string, args ...interface{}) *receipt {
+	r.events = append(r.events, &protocol.EventBuilder{
+		ContractName:        contractName,
+		EventName:           eventName,
+		OutputArgumentArray: MethodArgumentsArray(args...).RawArgumentsArray(),
+	})
+	r.builder.OutputEventsArray = (&protocol.EventsArrayBuilder{Events: r.events}).Build().RawEventsArray()
+	return r
+}
+
 func (r *receipt) Build() *protocol.TransactionReceipt {
 	return r.builder.Build()
 }
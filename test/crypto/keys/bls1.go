@@ -0,0 +1,42 @@
+package keys
+
+import (
+	"encoding/hex"
+	"github.com/orbs-network/orbs-network-go/crypto/keys"
+)
+
+type bls1KeyPairHex struct {
+	publicKey  string
+	privateKey string
+}
+
+var bls1KeyPairs = []bls1KeyPairHex{
+	{"6edcd5c7cbe6fd24f9f3355b9dcc1f839022a00072c82559b7b93612e713d89a47d4e96c5c930a8b1721cf3a5becb550af6c59d5b86b8bf72f996e040a528770097219145f01591cf25507628d80eb1f527333b860c9a0f0f954f23c8cc8dc0762273276dc82b9b5afe3a7a7c761b2caa0b3081382e99208f3997da729809479", "47760736ab2abeed146686d8e37b961376cb2337f2228e35ac60466b047a4127"},
+	{"0bb7616e991227700ad24e7180edd0c4b7badd8e306f6a6c109e24a1d04836df3a1c98619d364e3f8f58845626721394786fb843e823df936a39aae990711bc70970e588cb59a3a36769d3e6a8bfbc3513f7329bdbcca59c348f3a0e8b0d1c83895c85df8b6266996e79111e0c58842dc6299d5a8114f5f21cbf3e4b31d248d3", "52e33ea1f0ae2d4b2ef81a8ce2169e9507a14e7dc43db8fdc99ecd941cd47e35"},
+	{"4f9b4739fbbf2894a22ac464f4b810b107e677202b13bb05b944af74e71d52f37736974b342bd1a914c8f79a295cfe782a9a82deec975bacfa4b67d1420dc2ea55178a2b803268069bb2bf414fd1bbcc98641ac80eede167ff11031f6a39d91f65493e4abc3681eebeaed00901729ab135de6954159e950c7419f04d7958b9dc", "84bfd1733d3018a6aa27f363382bc41aa051d8919668aac94db6301e8c1c43c6"},
+	{"29f8dfa456ae746707ecc25c2d788d8846bb36ec076a752036462a5e53f696c818275535594c58932d8fe3b2d2cb6f38f3c5c5e9c591edf42f1ef812c917b9ec06daa47f2f0bd51ff2cc6ec3682009ae6f76c21129ce87f74ca7d4a918cb96e507a97b01b2dd0e0b7ea178368a25936f18bb7c585e523bfdc7f201c195a290bf", "3d4ef1359f21975a67a61448392a350e28dee3712c7216fa470aa7d6b17faeed"},
+	{"1e7df52e444c9024a21e37b49b2a852b9eec4cfc84b195a6956349f570837ab97000bb26de7641846aa2ee3e6739ce1475f3f714ba28c2168f45f5e4b6ff7094282ab3f8d71d8f021bfd840bf00da0a122e9270b5469d63efac5faa0a34acc900ea8cb96ca44153f2ce85a20e493c2f949987675b8541ca4669a121ebd4abe56", "24f92beffc32a74480c2895cd2c5c2703424af773e02706ca65a0c87a4588593"},
+	{"5de20b3d49500759e0a8523fef6ed5b9b6b910ede59d74ee5094d1e37bb1facc842a7c7265f070c03fa4992cc15c953db22c91b2218435ec2ef93e343608104411e70efe2367472439d625b9db2e81bc646b89f061ab6cb985a76c465dbe817c7197f96e8d9279b0de5b7d77dcaa66cd27815c7cfc01d1ab96ba96052127ad3d", "61351f2c12dc93b88f8363a22aeaa36cb7da49f0e99ac7b529b4205c5e1afe4b"},
+	{"7f7758521957815d5f273e35a75065fefa4753df00a8469190899b85ea8e29426f4adcab8f85b19af0231c56668565b2290031ad10783f0d5a3ae469f7f95ca4412e9e0a922ba215eb5b255313a9a53f03fd9e508533ebd28b1e6094e01dcfb831ab8dc58fe796c82f875c3a02d61b7e681199733a63086fa4991b9b09a92d8e", "574a095cc443379848278710068650766a53b10ae56354ccd1911f714733a997"},
+	{"3a08512119f5699c361fdf78cbcd6d06fdd4ce0422cb559c3a3aebf177895b7875f4c4e4dc5f42da1bba93ddffc9c157fe1714a40031226cd21a3bbae41626484b0781dc61769a1099c692b16df0e9b963b3282b3142075620c5acbad710924514448f8257a08818f5c81c5422120cb279b3d5bdea5837a4dea912b58234e3ae", "57bf66b9d3f5ecb1a10be3a030773ddaf0cf3c07aac72625c2660b25cb7dd927"},
+	{"76efdfecb2dd5981d69b434a371e9d2054de78a51c7b7a0da68a5fbabd973dcf7dfb662695e9c382325b19039f49770be69b814431a6482f627c2f99dc9705507a5a92f9b32e6774b65ed3951a21a32812766c5d5a8e1ac6d8b9d2f41341dc5d6b516a998705f75ffd6fee9d8e6d74410ba42d046355ab0726c1424a65807a90", "25b7c90a5ccce4fcc976200c6c71bdd4e4db56c7f70689974b41f830bdc7139e"},
+	{"02fbd21e18a67d53ad1a0edfde11b03ac568fe98220371fcd934beb8b0e1d76814914096a3ed3af704bf26b76e99847f91ce6e62bb54a5ccfa7fde0f0f0d399c0c953a8c93901cc1fcd188e0b9be301acfc93c85eacc867e9aeddbb75ad598191e6a5ff7dc4c1475d06338061f891e7dc0e3c6cb348a8bd5efa7a62b1d1dfa6a", "21bbba3ed5f636b78a37b0225dbc2941bb6b0a05d7dd421ab1d959c45c8973d7"},
+}
+
+func Bls1KeyPairForTests(setIndex int) *keys.Bls1KeyPair {
+	if setIndex > len(bls1KeyPairs) {
+		return nil
+	}
+
+	pub, err := hex.DecodeString(bls1KeyPairs[setIndex].publicKey)
+	if err != nil {
+		return nil
+	}
+
+	pri, err := hex.DecodeString(bls1KeyPairs[setIndex].privateKey)
+	if err != nil {
+		return nil
+	}
+
+	return keys.NewBls1KeyPair(pub, pri)
+}
@@ -119,7 +119,14 @@ func newHarness() *harness {
 			nodeLogger := logger.WithOutput(log.NewFormattingOutput(logFile, log.NewJsonFormatter()))
 			processorArtifactPath, _ := getProcessorArtifactPath()
 
+			blockStorageDataDir := filepath.Join(config.GetProjectSourceTmpPath(), "e2e-blocks", fmt.Sprintf("node%d", i+1))
+			stateStorageDataDir := filepath.Join(config.GetProjectSourceTmpPath(), "e2e-state", fmt.Sprintf("node%d", i+1))
+			os.RemoveAll(blockStorageDataDir) // every e2e run starts from an empty chain
+			os.RemoveAll(stateStorageDataDir)
+
 			cfg := config.ForE2E(processorArtifactPath)
+			cfg.SetString(config.BLOCK_STORAGE_DATA_DIR, blockStorageDataDir)
+			cfg.SetString(config.STATE_STORAGE_DATA_DIR, stateStorageDataDir)
 			cfg.OverrideNodeSpecificValues(
 				federationNodes,
 				gossipPeers,
@@ -11,6 +11,7 @@ import (
 	gossipAdapter "github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	blockStorageAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/blockstorage/adapter"
+	ethereumAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/crosschainconnector/ethereum/adapter"
 	testGossipAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/gossip/adapter"
 	nativeProcessorAdapter "github.com/orbs-network/orbs-network-go/test/harness/services/processor/native/adapter"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
@@ -26,6 +27,7 @@ type TestNetworkDriver interface {
 	DumpState()
 	WaitForTransactionInNodeState(ctx context.Context, txhash primitives.Sha256, nodeIndex int,)
 	MockContract(fakeContractInfo *sdk.ContractInfo, code string)
+	EthereumSimulator() ethereumAdapter.SimulatedEthereumConnection
 }
 
 func NewAcceptanceTestNetwork(ctx context.Context, numNodes int, testLogger log.BasicLogger, consensusAlgo consensus.ConsensusAlgoType, maxTxPerBlock uint32) *acceptanceNetwork {
@@ -46,9 +48,12 @@ func NewAcceptanceTestNetwork(ctx context.Context, numNodes int, testLogger log.
 
 	sharedTamperingTransport := testGossipAdapter.NewTamperingTransport(testLogger, gossipAdapter.NewMemoryTransport(ctx, testLogger, federationNodes))
 
+	ethereumSimulator := ethereumAdapter.NewSimulatedEthereumConnection()
+
 	network := &acceptanceNetwork{
-		Network:            inmemory.NewNetwork(testLogger, sharedTamperingTransport),
+		Network:            inmemory.NewNetwork(testLogger, sharedTamperingTransport, ethereumSimulator),
 		tamperingTransport: sharedTamperingTransport,
+		ethereumSimulator:  ethereumSimulator,
 		description:        description,
 	}
 
@@ -76,6 +81,7 @@ type acceptanceNetwork struct {
 	inmemory.Network
 
 	tamperingTransport testGossipAdapter.Tamperer
+	ethereumSimulator  ethereumAdapter.SimulatedEthereumConnection
 	description        string
 }
 
@@ -95,6 +101,10 @@ func (n *acceptanceNetwork) TransportTamperer() testGossipAdapter.Tamperer {
 	return n.tamperingTransport
 }
 
+func (n *acceptanceNetwork) EthereumSimulator() ethereumAdapter.SimulatedEthereumConnection {
+	return n.ethereumSimulator
+}
+
 func (n *acceptanceNetwork) BlockPersistence(nodeIndex int) blockStorageAdapter.InMemoryBlockPersistence {
 	return n.GetBlockPersistence(nodeIndex)
 }
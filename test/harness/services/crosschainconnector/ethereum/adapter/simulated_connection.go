@@ -0,0 +1,96 @@
+package adapter
+
+import (
+	"context"
+	"encoding/hex"
+	"github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum/adapter"
+	"github.com/pkg/errors"
+	"math/big"
+	"sync"
+	"time"
+)
+
+type ContractHandler func(packedInput []byte, blockNumber *big.Int) ([]byte, error)
+
+type SimulatedEthereumConnection interface {
+	adapter.EthereumConnection
+	ProvideContract(contractAddress []byte, handler ContractHandler)
+	SetBlockNumber(blockNumber uint64)
+	SetBlockNumberAt(blockNumber uint64, timestamp time.Time)
+}
+
+type simulatedConnection struct {
+	mutex           *sync.RWMutex
+	contracts       map[string]ContractHandler
+	blockTimestamps []uint64 // indexed by block number, the last one is the tip
+}
+
+func NewSimulatedEthereumConnection() SimulatedEthereumConnection {
+	return &simulatedConnection{
+		mutex:           &sync.RWMutex{},
+		contracts:       make(map[string]ContractHandler),
+		blockTimestamps: []uint64{0},
+	}
+}
+
+func (c *simulatedConnection) ProvideContract(contractAddress []byte, handler ContractHandler) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.contracts[hex.EncodeToString(contractAddress)] = handler
+}
+
+// blocks mined by moving the tip forward are stamped with the current time
+func (c *simulatedConnection) SetBlockNumber(blockNumber uint64) {
+	c.SetBlockNumberAt(blockNumber, time.Now())
+}
+
+func (c *simulatedConnection) SetBlockNumberAt(blockNumber uint64, timestamp time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if blockNumber < uint64(len(c.blockTimestamps)) {
+		c.blockTimestamps = c.blockTimestamps[:blockNumber+1]
+		return
+	}
+	for uint64(len(c.blockTimestamps)) <= blockNumber {
+		c.blockTimestamps = append(c.blockTimestamps, uint64(timestamp.Unix()))
+	}
+}
+
+func (c *simulatedConnection) tip() *big.Int {
+	return new(big.Int).SetUint64(uint64(len(c.blockTimestamps) - 1))
+}
+
+func (c *simulatedConnection) CallContract(ctx context.Context, contractAddress []byte, packedInput []byte, blockNumber *big.Int) ([]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if blockNumber.Cmp(c.tip()) > 0 {
+		return nil, errors.Errorf("block %s is ahead of the simulated chain at block %s", blockNumber, c.tip())
+	}
+
+	handler, found := c.contracts[hex.EncodeToString(contractAddress)]
+	if !found {
+		// like a real node, calling an address without code returns empty output
+		return []byte{}, nil
+	}
+	return handler(packedInput, blockNumber)
+}
+
+func (c *simulatedConnection) BlockNumber(ctx context.Context) (*big.Int, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.tip(), nil
+}
+
+func (c *simulatedConnection) BlockTimestamp(ctx context.Context, blockNumber *big.Int) (uint64, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if blockNumber.Cmp(c.tip()) > 0 {
+		return 0, errors.Errorf("block %s is ahead of the simulated chain at block %s", blockNumber, c.tip())
+	}
+	return c.blockTimestamps[blockNumber.Uint64()], nil
+}